@@ -4,11 +4,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"strings"
+	"time"
 
 	pdk "github.com/extism/go-pdk"
 )
 
+// Prices are cached in plugin vars for a short time so that agents polling
+// crypto-check-threshold in a loop don't hammer the CoinGecko API.
+const priceCacheTTL = 30 * time.Second
+
 func Call(input CallToolRequest) (CallToolResult, error) {
 	args := input.Params.Arguments
 	if args == nil {
@@ -17,7 +23,12 @@ func Call(input CallToolRequest) (CallToolResult, error) {
 
 	argsMap := args.(map[string]interface{})
 	fmt.Println("argsMap", argsMap)
-	return getCryptoPrice(argsMap)
+	switch input.Params.Name {
+	case "crypto-check-threshold":
+		return checkThresholds(argsMap)
+	default:
+		return getCryptoPrice(argsMap)
+	}
 }
 
 func getCryptoPrice(args map[string]interface{}) (CallToolResult, error) {
@@ -29,32 +40,226 @@ func getCryptoPrice(args map[string]interface{}) (CallToolResult, error) {
 	// Convert symbol to uppercase
 	symbol = strings.ToUpper(symbol)
 
+	price, err := fetchPrice(symbol)
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	priceStr := fmt.Sprintf("%.2f USD", price)
+	return CallToolResult{
+		Content: []Content{
+			{
+				Type: ContentTypeText,
+				Text: &priceStr,
+			},
+		},
+	}, nil
+}
+
+type cachedPrice struct {
+	Price     float64 `json:"price"`
+	FetchedAt int64   `json:"fetched_at"`
+}
+
+// fetchPrice returns the USD price of a coin, served from the var cache when
+// it is younger than priceCacheTTL.
+func fetchPrice(symbol string) (float64, error) {
+	id := strings.ToLower(symbol)
+	key := "price:" + id
+	now := time.Now()
+
+	if raw := pdk.GetVar(key); raw != nil {
+		var cached cachedPrice
+		if err := json.Unmarshal(raw, &cached); err == nil && now.Sub(time.Unix(cached.FetchedAt, 0)) < priceCacheTTL {
+			return cached.Price, nil
+		}
+	}
+
 	// Use CoinGecko API to get the price
-	url := fmt.Sprintf("https://api.coingecko.com/api/v3/simple/price?ids=%s&vs_currencies=usd", strings.ToLower(symbol))
+	url := fmt.Sprintf("https://api.coingecko.com/api/v3/simple/price?ids=%s&vs_currencies=usd", id)
 	req := pdk.NewHTTPRequest(pdk.MethodGet, url)
 	resp := req.Send()
 
 	var result map[string]map[string]float64
 	if err := json.Unmarshal(resp.Body(), &result); err != nil {
-		return CallToolResult{}, fmt.Errorf("failed to parse response: %v", err)
+		return 0, fmt.Errorf("failed to parse response: %v", err)
 	}
 
-	if price, ok := result[strings.ToLower(symbol)]["usd"]; ok {
-		priceStr := fmt.Sprintf("%.2f USD", price)
-		return CallToolResult{
-			Content: []Content{
-				{
-					Type: ContentTypeText,
-					Text: &priceStr,
-				},
-			},
-		}, nil
+	price, ok := result[id]["usd"]
+	if !ok {
+		return 0, fmt.Errorf("price not found for %s", strings.ToUpper(symbol))
+	}
+
+	if raw, err := json.Marshal(cachedPrice{Price: price, FetchedAt: now.Unix()}); err == nil {
+		pdk.SetVar(key, raw)
+	}
+	return price, nil
+}
+
+type Threshold struct {
+	Symbol     string  `json:"symbol"`
+	Comparison string  `json:"comparison"`
+	Target     float64 `json:"target"`
+}
+
+type ThresholdResult struct {
+	Symbol          string  `json:"symbol"`
+	Comparison      string  `json:"comparison"`
+	Triggered       bool    `json:"triggered"`
+	Current         float64 `json:"current"`
+	Target          float64 `json:"target"`
+	DistancePercent float64 `json:"distance_percent"`
+}
+
+// thresholdsFromArgs accepts either a single symbol/comparison/target triple
+// or a `thresholds` array of them.
+func thresholdsFromArgs(args map[string]interface{}) ([]Threshold, error) {
+	entries := []map[string]interface{}{}
+	if list, ok := args["thresholds"]; ok {
+		items, ok := list.([]interface{})
+		if !ok || len(items) == 0 {
+			return nil, errors.New("thresholds must be a non-empty array")
+		}
+		for i, item := range items {
+			entry, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("thresholds[%d] must be an object", i)
+			}
+			entries = append(entries, entry)
+		}
+	} else {
+		entries = append(entries, args)
+	}
+
+	thresholds := make([]Threshold, 0, len(entries))
+	for i, entry := range entries {
+		field := func(name string) string {
+			if _, ok := args["thresholds"]; ok {
+				return fmt.Sprintf("thresholds[%d].%s", i, name)
+			}
+			return name
+		}
+
+		symbol, ok := entry["symbol"].(string)
+		if !ok || symbol == "" {
+			return nil, fmt.Errorf("%s must be provided", field("symbol"))
+		}
+		comparison, _ := entry["comparison"].(string)
+		if comparison != "above" && comparison != "below" {
+			return nil, fmt.Errorf("%s must be \"above\" or \"below\", got %q", field("comparison"), comparison)
+		}
+		target, ok := entry["target"].(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a number, got %v", field("target"), entry["target"])
+		}
+		if math.IsNaN(target) || math.IsInf(target, 0) || target <= 0 {
+			return nil, fmt.Errorf("%s must be a positive finite number, got %v", field("target"), target)
+		}
+		thresholds = append(thresholds, Threshold{Symbol: strings.ToLower(symbol), Comparison: comparison, Target: target})
+	}
+	return thresholds, nil
+}
+
+// evaluateThreshold compares the current price against the target. The
+// distance is how far the price still has to move, as a percentage of the
+// current price, and is negative once the threshold has been crossed. A
+// price of zero or less means there is no current price to compare.
+func evaluateThreshold(t Threshold, current float64) (ThresholdResult, error) {
+	if current <= 0 || math.IsNaN(current) {
+		return ThresholdResult{}, fmt.Errorf("no current price for %s", t.Symbol)
+	}
+	triggered := current > t.Target
+	distance := (t.Target - current) / current * 100
+	if t.Comparison == "below" {
+		triggered = current < t.Target
+		distance = -distance
+	}
+	return ThresholdResult{
+		Symbol:          t.Symbol,
+		Comparison:      t.Comparison,
+		Triggered:       triggered,
+		Current:         current,
+		Target:          t.Target,
+		DistancePercent: math.Round(distance*100) / 100,
+	}, nil
+}
+
+func checkThresholds(args map[string]interface{}) (CallToolResult, error) {
+	thresholds, err := thresholdsFromArgs(args)
+	if err != nil {
+		return CallToolResult{}, err
+	}
+
+	results := make([]ThresholdResult, 0, len(thresholds))
+	for _, t := range thresholds {
+		price, err := fetchPrice(t.Symbol)
+		if err != nil {
+			return CallToolResult{}, err
+		}
+		result, err := evaluateThreshold(t, price)
+		if err != nil {
+			return CallToolResult{}, err
+		}
+		results = append(results, result)
 	}
 
-	return CallToolResult{}, fmt.Errorf("price not found for %s", symbol)
+	out, err := json.Marshal(results)
+	if err != nil {
+		return CallToolResult{}, fmt.Errorf("failed to marshal results: %v", err)
+	}
+	summary := thresholdSummary(results)
+	text := string(out)
+	return CallToolResult{
+		Content: []Content{
+			{
+				Type: ContentTypeText,
+				Text: &summary,
+			},
+			{
+				Type: ContentTypeText,
+				Text: &text,
+			},
+		},
+	}, nil
+}
+
+// thresholdSummary is the first line of the result, so callers polling in a
+// loop can check its prefix: "TRIGGERED: " followed by the thresholds that
+// were crossed, or "not triggered: " followed by all of them.
+func thresholdSummary(results []ThresholdResult) string {
+	describe := func(r ThresholdResult) string {
+		return fmt.Sprintf("%s %s %g (current %g)", r.Symbol, r.Comparison, r.Target, r.Current)
+	}
+	var triggered, all []string
+	for _, r := range results {
+		all = append(all, describe(r))
+		if r.Triggered {
+			triggered = append(triggered, describe(r))
+		}
+	}
+	if len(triggered) > 0 {
+		return "TRIGGERED: " + strings.Join(triggered, "; ")
+	}
+	return "not triggered: " + strings.Join(all, "; ")
 }
 
 func Describe() (ListToolsResult, error) {
+	thresholdProperties := map[string]interface{}{
+		"symbol": map[string]interface{}{
+			"type":        "string",
+			"description": "the cryptocurrency symbol/id (e.g., bitcoin, ethereum)",
+		},
+		"comparison": map[string]interface{}{
+			"type":        "string",
+			"enum":        []string{"above", "below"},
+			"description": "trigger when the price is above or below the target",
+		},
+		"target": map[string]interface{}{
+			"type":        "number",
+			"description": "the target price in USD, must be positive",
+		},
+	}
+
 	return ListToolsResult{
 		Tools: []ToolDescription{
 			{
@@ -71,6 +276,27 @@ func Describe() (ListToolsResult, error) {
 					},
 				},
 			},
+			{
+				Name:        "crypto-check-threshold",
+				Description: "Check whether cryptocurrency prices crossed a target. The first content item starts with \"TRIGGERED:\" when any threshold was crossed and with \"not triggered:\" otherwise; the second is JSON with triggered, current, target and distance_percent for each threshold. Prices are cached for 30 seconds so this is cheap to poll. Pass either symbol/comparison/target or a thresholds array.",
+				InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"symbol":     thresholdProperties["symbol"],
+						"comparison": thresholdProperties["comparison"],
+						"target":     thresholdProperties["target"],
+						"thresholds": map[string]interface{}{
+							"type":        "array",
+							"description": "check several thresholds in one call",
+							"items": map[string]interface{}{
+								"type":       "object",
+								"required":   []string{"symbol", "comparison", "target"},
+								"properties": thresholdProperties,
+							},
+						},
+					},
+				},
+			},
 		},
 	}, nil
 }
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestThresholdsFromArgsSingle(t *testing.T) {
+	thresholds, err := thresholdsFromArgs(map[string]interface{}{
+		"symbol":     "Bitcoin",
+		"comparison": "above",
+		"target":     50000.0,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(thresholds) != 1 || thresholds[0] != (Threshold{Symbol: "bitcoin", Comparison: "above", Target: 50000}) {
+		t.Fatalf("unexpected thresholds %+v", thresholds)
+	}
+}
+
+func TestThresholdsFromArgsMultiple(t *testing.T) {
+	thresholds, err := thresholdsFromArgs(map[string]interface{}{
+		"thresholds": []interface{}{
+			map[string]interface{}{"symbol": "bitcoin", "comparison": "above", "target": 1.0},
+			map[string]interface{}{"symbol": "ethereum", "comparison": "below", "target": 2.5},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(thresholds) != 2 || thresholds[1].Symbol != "ethereum" || thresholds[1].Target != 2.5 {
+		t.Fatalf("unexpected thresholds %+v", thresholds)
+	}
+}
+
+func TestThresholdsFromArgsErrors(t *testing.T) {
+	tests := []struct {
+		args map[string]interface{}
+		want string
+	}{
+		{map[string]interface{}{"comparison": "above", "target": 1.0}, "symbol must be provided"},
+		{map[string]interface{}{"symbol": "bitcoin", "comparison": "over", "target": 1.0}, `comparison must be "above" or "below", got "over"`},
+		{map[string]interface{}{"symbol": "bitcoin", "comparison": "above", "target": "100"}, "target must be a number, got 100"},
+		{map[string]interface{}{"symbol": "bitcoin", "comparison": "above", "target": 0.0}, "target must be a positive finite number, got 0"},
+		{map[string]interface{}{"symbol": "bitcoin", "comparison": "above", "target": -3.0}, "target must be a positive finite number, got -3"},
+		{map[string]interface{}{"thresholds": []interface{}{}}, "thresholds must be a non-empty array"},
+		{map[string]interface{}{"thresholds": []interface{}{
+			map[string]interface{}{"symbol": "bitcoin", "comparison": "above", "target": 1.0},
+			map[string]interface{}{"symbol": "ethereum", "comparison": "below", "target": -1.0},
+		}}, "thresholds[1].target must be a positive finite number, got -1"},
+	}
+	for _, tt := range tests {
+		_, err := thresholdsFromArgs(tt.args)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("thresholdsFromArgs(%v) error = %v, want %q", tt.args, err, tt.want)
+		}
+	}
+}
+
+func TestEvaluateThreshold(t *testing.T) {
+	tests := []struct {
+		threshold Threshold
+		current   float64
+		triggered bool
+		distance  float64
+	}{
+		{Threshold{"bitcoin", "above", 110}, 100, false, 10},
+		{Threshold{"bitcoin", "above", 90}, 100, true, -10},
+		{Threshold{"bitcoin", "below", 90}, 100, false, 10},
+		{Threshold{"bitcoin", "below", 110}, 100, true, -10},
+		{Threshold{"bitcoin", "above", 100}, 100, false, 0},
+	}
+	for _, tt := range tests {
+		got, err := evaluateThreshold(tt.threshold, tt.current)
+		if err != nil {
+			t.Errorf("evaluateThreshold(%+v, %v) error = %v", tt.threshold, tt.current, err)
+			continue
+		}
+		if got.Triggered != tt.triggered || got.DistancePercent != tt.distance {
+			t.Errorf("evaluateThreshold(%+v, %v) = %+v, want triggered=%v distance=%v", tt.threshold, tt.current, got, tt.triggered, tt.distance)
+		}
+	}
+	for _, current := range []float64{0, -1} {
+		_, err := evaluateThreshold(Threshold{"bitcoin", "above", 100}, current)
+		if err == nil || err.Error() != "no current price for bitcoin" {
+			t.Errorf("evaluateThreshold with current %v error = %v, want no current price", current, err)
+		}
+	}
+}
+
+func TestThresholdSummary(t *testing.T) {
+	crossed := ThresholdResult{Symbol: "bitcoin", Comparison: "above", Triggered: true, Current: 51000, Target: 50000}
+	waiting := ThresholdResult{Symbol: "ethereum", Comparison: "below", Current: 2100, Target: 2000}
+
+	tests := []struct {
+		results []ThresholdResult
+		want    string
+	}{
+		{[]ThresholdResult{crossed}, "TRIGGERED: bitcoin above 50000 (current 51000)"},
+		{[]ThresholdResult{waiting, crossed}, "TRIGGERED: bitcoin above 50000 (current 51000)"},
+		{[]ThresholdResult{waiting}, "not triggered: ethereum below 2000 (current 2100)"},
+	}
+	for _, tt := range tests {
+		if got := thresholdSummary(tt.results); got != tt.want {
+			t.Errorf("thresholdSummary(%+v) = %q, want %q", tt.results, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// numberArg converts a numeric argument to the float64 the handlers read.
+// JSON numbers decode as float64, but some clients send numbers as strings
+// ("42"), and other decoders produce int or json.Number.
+func numberArg(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, false
+		}
+		return f, true
+	default:
+		return 0, false
+	}
+}
+
+// numericProperties returns the top-level properties a tool declares as
+// integer or number, with their declared type.
+func numericProperties(tool ToolDescription) map[string]string {
+	s, ok := tool.InputSchema.(schema)
+	if !ok {
+		return nil
+	}
+	numeric := map[string]string{}
+	add := func(name, tpe string) {
+		if tpe == "integer" || tpe == "number" {
+			numeric[name] = tpe
+		}
+	}
+	switch properties := s["properties"].(type) {
+	case props:
+		for name, p := range properties {
+			tpe, _ := p.Type.(string)
+			add(name, tpe)
+		}
+	case schema:
+		for name, p := range properties {
+			switch p := p.(type) {
+			case SchemaProperty:
+				tpe, _ := p.Type.(string)
+				add(name, tpe)
+			case schema:
+				tpe, _ := p["type"].(string)
+				add(name, tpe)
+			}
+		}
+	}
+	return numeric
+}
+
+// normalizeNumericArgs rewrites the numeric arguments of a call to float64
+// in place, so `args[key].(float64)` sees "42" the same as 42. It reports
+// every argument that isn't a number, or isn't whole where the tool
+// declares an integer, and every required numeric argument that is missing,
+// instead of letting them turn into requests for issue 0.
+func normalizeNumericArgs(tool ToolDescription, args map[string]interface{}) error {
+	numeric := numericProperties(tool)
+	required := map[string]bool{}
+	if s, ok := tool.InputSchema.(schema); ok {
+		names, _ := s["required"].([]string)
+		for _, name := range names {
+			required[name] = true
+		}
+	}
+
+	var problems []string
+	for name, tpe := range numeric {
+		value, present := args[name]
+		if !present || value == nil {
+			if required[name] {
+				problems = append(problems, fmt.Sprintf("%s is required", name))
+			}
+			continue
+		}
+		f, ok := numberArg(value)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s must be a number, got %#v", name, value))
+			continue
+		}
+		if tpe == "integer" && f != math.Trunc(f) {
+			problems = append(problems, fmt.Sprintf("%s must be a whole number, got %v", name, f))
+			continue
+		}
+		args[name] = f
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// findTool looks up a tool by name among the ones Describe lists.
+func findTool(name string) (ToolDescription, bool) {
+	described, _ := Describe()
+	for _, tool := range described.Tools {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+	return ToolDescription{}, false
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNumberArg(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  float64
+		ok    bool
+	}{
+		{"float64", float64(42), 42, true},
+		{"int", 42, 42, true},
+		{"int64", int64(42), 42, true},
+		{"json.Number", json.Number("42"), 42, true},
+		{"string", "42", 42, true},
+		{"padded string", " 42 ", 42, true},
+		{"fractional string", "2.5", 2.5, true},
+		{"word", "forty-two", 0, false},
+		{"empty string", "", 0, false},
+		{"NaN string", "NaN", 0, false},
+		{"bool", true, 0, false},
+		{"array", []interface{}{float64(1)}, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := numberArg(tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: numberArg(%#v) = %v, %v, want %v, %v", tt.name, tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNormalizeNumericArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		tool    ToolDescription
+		args    map[string]interface{}
+		want    map[string]interface{}
+		wantErr string
+	}{
+		{
+			name: "number",
+			tool: GetIssueTool,
+			args: map[string]interface{}{"owner": "o", "repo": "r", "issue": float64(42)},
+			want: map[string]interface{}{"owner": "o", "repo": "r", "issue": float64(42)},
+		},
+		{
+			name: "string",
+			tool: GetIssueTool,
+			args: map[string]interface{}{"owner": "o", "repo": "r", "issue": "42"},
+			want: map[string]interface{}{"owner": "o", "repo": "r", "issue": float64(42)},
+		},
+		{
+			name: "int and json.Number",
+			tool: ListIssuesTool,
+			args: map[string]interface{}{"owner": "o", "repo": "r", "per_page": 50, "page": json.Number("2")},
+			want: map[string]interface{}{"owner": "o", "repo": "r", "per_page": float64(50), "page": float64(2)},
+		},
+		{
+			name: "optional numbers may be left out or null",
+			tool: ListIssuesTool,
+			args: map[string]interface{}{"owner": "o", "repo": "r", "page": nil},
+			want: map[string]interface{}{"owner": "o", "repo": "r", "page": nil},
+		},
+		{
+			name:    "not a number",
+			tool:    GetIssueTool,
+			args:    map[string]interface{}{"owner": "o", "repo": "r", "issue": "#42"},
+			wantErr: `issue must be a number, got "#42"`,
+		},
+		{
+			name:    "not whole",
+			tool:    GetIssueTool,
+			args:    map[string]interface{}{"owner": "o", "repo": "r", "issue": "4.2"},
+			wantErr: "issue must be a whole number, got 4.2",
+		},
+		{
+			name:    "missing required number",
+			tool:    GetIssueTool,
+			args:    map[string]interface{}{"owner": "o", "repo": "r"},
+			wantErr: "issue is required",
+		},
+		{
+			name:    "every problem is listed",
+			tool:    ListIssuesTool,
+			args:    map[string]interface{}{"owner": "o", "repo": "r", "per_page": "lots", "page": true},
+			wantErr: `page must be a number, got true; per_page must be a number, got "lots"`,
+		},
+	}
+	for _, tt := range tests {
+		err := normalizeNumericArgs(tt.tool, tt.args)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("%s: error %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		got, _ := json.Marshal(tt.args)
+		want, _ := json.Marshal(tt.want)
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: args %s, want %s", tt.name, got, want)
+		}
+	}
+}
+
+// Every integer property of every tool goes through the coercion, so a
+// client sending "42" reaches the handlers as 42.
+func TestNumericArgumentsAreCoercedForEveryTool(t *testing.T) {
+	described, err := Describe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(numericProperties(ReviewPullRequestChunkedTool)) != 2 {
+		t.Errorf("numeric properties of %s: %v", ReviewPullRequestChunkedTool.Name, numericProperties(ReviewPullRequestChunkedTool))
+	}
+	for _, tool := range described.Tools {
+		numeric := numericProperties(tool)
+		args := map[string]interface{}{}
+		for name := range numeric {
+			args[name] = "7"
+		}
+		if err := normalizeNumericArgs(tool, args); err != nil {
+			t.Errorf("%s: %v", tool.Name, err)
+			continue
+		}
+		for name, value := range args {
+			if value != float64(7) {
+				t.Errorf("%s: %s = %#v after coercion", tool.Name, name, value)
+			}
+		}
+	}
+}
+
+func TestNormalizeNumericArgsFromDecodedRequest(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(`{"owner":"o","repo":"r","pull_number":"17","chunk_id":"abc:1"}`))
+	dec.UseNumber()
+	var args map[string]interface{}
+	if err := dec.Decode(&args); err != nil {
+		t.Fatal(err)
+	}
+	if err := normalizeNumericArgs(ReviewPullRequestChunkedTool, args); err != nil {
+		t.Fatal(err)
+	}
+	if pullNumber, _ := args["pull_number"].(float64); pullNumber != 17 {
+		t.Errorf("pull_number = %#v, want 17", args["pull_number"])
+	}
+	if args["chunk_id"] != "abc:1" {
+		t.Errorf("string argument changed: %#v", args["chunk_id"])
+	}
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ListArtifactsTool = ToolDescription{
+		Name:        "gh-list-artifacts",
+		Description: "List the GitHub Actions artifacts of a workflow run, or of the whole repository",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":    prop("string", "The owner of the repository"),
+				"repo":     prop("string", "The repository name"),
+				"run_id":   prop("integer", "Only artifacts of this workflow run"),
+				"name":     prop("string", "Only artifacts with this name"),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	DownloadArtifactTool = ToolDescription{
+		Name:        "gh-download-artifact",
+		Description: "Download a GitHub Actions artifact, or one file from it. Text files are returned as text, anything else as an embedded base64 blob.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":       prop("string", "The owner of the repository"),
+				"repo":        prop("string", "The repository name"),
+				"artifact_id": prop("integer", "The artifact id"),
+				"path":        prop("string", "Return only this file from the artifact's zip instead of the whole zip"),
+				"max_size":    prop("integer", "Refuse artifacts larger than this many bytes (default 5242880, at most 20971520)"),
+			},
+			"required": []string{"owner", "repo", "artifact_id"},
+		},
+	}
+)
+
+// Files listed when the requested path isn't in the artifact
+const artifactListedFiles = 50
+
+type Artifact struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	SizeInBytes int    `json:"size_in_bytes"`
+	Expired     bool   `json:"expired"`
+	CreatedAt   string `json:"created_at"`
+	ExpiresAt   string `json:"expires_at"`
+	WorkflowRun *struct {
+		ID         int    `json:"id"`
+		HeadBranch string `json:"head_branch"`
+	} `json:"workflow_run,omitempty"`
+}
+
+type ArtifactsPage struct {
+	TotalCount int        `json:"total_count"`
+	Artifacts  []Artifact `json:"artifacts"`
+}
+
+func artifactsURL(owner, repo string, args map[string]interface{}) string {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/artifacts", owner, repo)
+	if runID, _ := args["run_id"].(float64); runID != 0 {
+		u = fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/runs/%d/artifacts", owner, repo, int(runID))
+	}
+	params := []string{}
+	if name, _ := args["name"].(string); name != "" {
+		params = append(params, "name="+url.QueryEscape(name))
+	}
+	params = append(params, paginationParams(args)...)
+	return fmt.Sprintf("%s?%s", u, strings.Join(params, "&"))
+}
+
+// artifactFile extracts one file from the artifact's zip, refusing it when
+// it is larger than maxSize uncompressed.
+func artifactFile(archive []byte, name string, maxSize int) ([]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("reading artifact zip: %w", err)
+	}
+	name = strings.TrimPrefix(name, "/")
+	names := []string{}
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if f.Name == name {
+			if f.UncompressedSize64 > uint64(maxSize) {
+				return nil, fmt.Errorf("%s is %d bytes uncompressed, more than max_size %d", name, f.UncompressedSize64, maxSize)
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("opening %s: %w", name, err)
+			}
+			defer rc.Close()
+			data, err := io.ReadAll(io.LimitReader(rc, int64(maxSize)+1))
+			if err == nil && len(data) > maxSize {
+				err = fmt.Errorf("%s is more than max_size %d bytes uncompressed", name, maxSize)
+			}
+			return data, err
+		}
+		if len(names) < artifactListedFiles {
+			names = append(names, f.Name)
+		}
+	}
+	return nil, fmt.Errorf("the artifact has no file %s; it has %s", name, strings.Join(names, ", "))
+}
+
+// sniffContent picks the media type of a file from its extension and
+// whether it can be returned as text.
+func sniffContent(name string, data []byte) (string, bool) {
+	mimeType := mime.TypeByExtension(path.Ext(name))
+	text := utf8.Valid(data) && !bytes.ContainsRune(data, 0)
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+		if text {
+			mimeType = "text/plain; charset=utf-8"
+		}
+	}
+	return mimeType, text
+}
+
+// sniffedContent returns data as text when it is text, and as a base64
+// blob resource otherwise.
+func sniffedContent(uri, name string, data []byte) Content {
+	mimeType, text := sniffContent(name, data)
+	if text {
+		return Content{Type: ContentTypeText, Text: some(string(data)), MimeType: some(mimeType)}
+	}
+	return Content{
+		Type: ContentTypeResource,
+		Resource: &BlobResourceContents{
+			Uri:      uri,
+			MimeType: some(mimeType),
+			Blob:     base64.StdEncoding.EncodeToString(data),
+		},
+	}
+}
+
+func artifactsList(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	u := artifactsURL(owner, repo, args)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing artifacts: ", u))
+
+	var page ArtifactsPage
+	if _, err := githubGetJSON(apiKey, u, &page); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list artifacts: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(page)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func artifactsDownload(apiKey, owner, repo string, id int, args map[string]interface{}) CallToolResult {
+	maxSize, err := assetMaxSize(args)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid download: %s", err)),
+			}},
+		}
+	}
+
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/artifacts/%d", owner, repo, id)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting artifact: ", u))
+	var artifact Artifact
+	if _, err := githubGetJSON(apiKey, u, &artifact); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to get artifact: %s", err)),
+			}},
+		}
+	}
+	if artifact.Expired {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Artifact %s expired on %s and can no longer be downloaded", artifact.Name, artifact.ExpiresAt)),
+			}},
+		}
+	}
+	if artifact.SizeInBytes > maxSize {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Artifact %s is %d bytes, more than max_size %d", artifact.Name, artifact.SizeInBytes, maxSize)),
+			}},
+		}
+	}
+
+	archive, err := githubDownload(apiKey, u+"/zip")
+	if err == nil && len(archive) > maxSize {
+		err = fmt.Errorf("got %d bytes, more than max_size %d", len(archive), maxSize)
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to download artifact %s: %s", artifact.Name, err)),
+			}},
+		}
+	}
+
+	name, _ := args["path"].(string)
+	if name == "" {
+		return CallToolResult{
+			Content: []Content{{
+				Type: ContentTypeResource,
+				Resource: &BlobResourceContents{
+					Uri:      u + "/zip",
+					MimeType: some("application/zip"),
+					Blob:     base64.StdEncoding.EncodeToString(archive),
+				},
+			}},
+		}
+	}
+
+	data, err := artifactFile(archive, name, maxSize)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(err.Error()),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{sniffedContent(fmt.Sprintf("%s/zip#%s", u, name), name, data)},
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestArtifactsURL(t *testing.T) {
+	if got := artifactsURL("acme", "api", map[string]interface{}{}); got != "https://api.github.com/repos/acme/api/actions/artifacts?per_page=30&page=1" {
+		t.Errorf("repository: got %s", got)
+	}
+	got := artifactsURL("acme", "api", map[string]interface{}{"run_id": float64(42), "name": "coverage report"})
+	if got != "https://api.github.com/repos/acme/api/actions/runs/42/artifacts?name=coverage+report&per_page=30&page=1" {
+		t.Errorf("run: got %s", got)
+	}
+}
+
+func TestArtifactFile(t *testing.T) {
+	archive := logsArchive(t, map[string]string{
+		"report/summary.txt": "all good",
+		"report/big.txt":     strings.Repeat("x", 100),
+	})
+	if got, err := artifactFile(archive, "/report/summary.txt", 50); err != nil || string(got) != "all good" {
+		t.Errorf("got %q, %v", got, err)
+	}
+	if _, err := artifactFile(archive, "report/big.txt", 50); err == nil || !strings.Contains(err.Error(), "more than max_size 50") {
+		t.Errorf("big file: %v", err)
+	}
+	if _, err := artifactFile(archive, "missing.txt", 50); err == nil || !strings.Contains(err.Error(), "has no file missing.txt") {
+		t.Errorf("missing file: %v", err)
+	}
+}
+
+func TestArtifactContent(t *testing.T) {
+	text := sniffedContent("u", "coverage.json", []byte(`{"total":93}`))
+	if text.Type != ContentTypeText || *text.Text != `{"total":93}` || *text.MimeType != "application/json" {
+		t.Errorf("json: %+v", text)
+	}
+	plain := sniffedContent("u", "output", []byte("hello"))
+	if plain.Type != ContentTypeText || *plain.MimeType != "text/plain; charset=utf-8" {
+		t.Errorf("plain: %+v", plain)
+	}
+	blob, _ := json.Marshal(sniffedContent("u#a.bin", "a.bin", []byte{0, 1, 2}))
+	if string(blob) != `{"resource":{"blob":"AAEC","mimeType":"application/octet-stream","uri":"u#a.bin"},"type":"resource"}` {
+		t.Errorf("binary: %s", blob)
+	}
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	UploadReleaseAssetTool = ToolDescription{
+		Name:        "gh-upload-release-asset",
+		Description: "Upload a file to a release as a downloadable asset",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":        prop("string", "The owner of the repository"),
+				"repo":         prop("string", "The repository name"),
+				"release_id":   prop("integer", "The release id; give this or tag"),
+				"tag":          prop("string", "The tag of the release; give this or release_id"),
+				"name":         prop("string", "The file name of the asset, e.g. app-linux-amd64.tar.gz"),
+				"content":      prop("string", "The file content"),
+				"encoding":     prop("string", "How content is encoded: text or base64 (default text)"),
+				"content_type": prop("string", "The media type of the file (default application/octet-stream)"),
+				"overwrite":    prop("boolean", "Replace an asset of the same name instead of failing (default false)"),
+			},
+			"required": []string{"owner", "repo", "name", "content"},
+		},
+	}
+	DownloadReleaseAssetTool = ToolDescription{
+		Name:        "gh-download-release-asset",
+		Description: "Download a release asset and return it as an embedded base64 blob",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":    prop("string", "The owner of the repository"),
+				"repo":     prop("string", "The repository name"),
+				"asset_id": prop("integer", "The asset id; give this or tag and name"),
+				"tag":      prop("string", "The tag of the release the asset belongs to"),
+				"name":     prop("string", "The file name of the asset in that release"),
+				"max_size": prop("integer", "Refuse assets larger than this many bytes (default 5242880, at most 20971520)"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+)
+
+const (
+	assetDefaultMaxSize = 5 << 20
+	// Upper bound for max_size. The whole asset is held in plugin memory,
+	// and again base64-encoded in the result.
+	assetMaxMaxSize = 20 << 20
+)
+
+// releaseURL addresses a release by id, or by tag when id is 0.
+func releaseURL(owner, repo string, id int, tag string) (string, error) {
+	if id != 0 {
+		return fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/%d", owner, repo, id), nil
+	}
+	if tag == "" {
+		return "", fmt.Errorf("give release_id or tag")
+	}
+	return fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/tags/%s", owner, repo, url.PathEscape(tag)), nil
+}
+
+// releaseUploadURL fills in the upload_url template GitHub returns, e.g.
+// https://uploads.github.com/repos/o/r/releases/1/assets{?name,label}.
+func releaseUploadURL(template, name string) string {
+	if i := strings.Index(template, "{"); i >= 0 {
+		template = template[:i]
+	}
+	return fmt.Sprintf("%s?name=%s", template, url.QueryEscape(name))
+}
+
+func assetContent(args map[string]interface{}) ([]byte, error) {
+	content, _ := args["content"].(string)
+	encoding, _ := args["encoding"].(string)
+	switch encoding {
+	case "", "text":
+		return []byte(content), nil
+	case "base64":
+		data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(content), ""))
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 content: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("encoding must be text or base64, got %q", encoding)
+	}
+}
+
+// assetAlreadyExists reports whether a 422 from the upload is GitHub
+// refusing a second asset with the same name.
+func assetAlreadyExists(body []byte) bool {
+	var apiErr struct {
+		Errors []struct {
+			Code string `json:"code"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &apiErr); err != nil {
+		return false
+	}
+	for _, e := range apiErr.Errors {
+		if e.Code == "already_exists" {
+			return true
+		}
+	}
+	return false
+}
+
+func assetUpload(apiKey, u, contentType string, data []byte) pdk.HTTPResponse {
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Uploading release asset: ", u))
+	req := githubRequest(apiKey, pdk.MethodPost, u, githubMediaType)
+	req.SetHeader("Content-Type", contentType)
+	req.SetBody(data)
+	return req.Send()
+}
+
+func assetDelete(apiKey, owner, repo string, id int) error {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/assets/%d", owner, repo, id)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Deleting release asset: ", u))
+	_, err := githubSendExpect(apiKey, pdk.MethodDelete, u, nil, 204)
+	return err
+}
+
+func releaseUploadAsset(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	id, _ := args["release_id"].(float64)
+	tag, _ := args["tag"].(string)
+	name, _ := args["name"].(string)
+	contentType, _ := args["content_type"].(string)
+	overwrite, _ := args["overwrite"].(bool)
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	u, err := releaseURL(owner, repo, int(id), tag)
+	if err == nil && strings.TrimSpace(name) == "" {
+		err = fmt.Errorf("name is required")
+	}
+	var data []byte
+	if err == nil {
+		data, err = assetContent(args)
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid upload: %s", err)),
+			}},
+		}
+	}
+
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting release: ", u))
+	var release Release
+	if _, err := githubGetJSON(apiKey, u, &release); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to get release: %s", err)),
+			}},
+		}
+	}
+
+	uploadURL := releaseUploadURL(release.UploadURL, name)
+	resp := assetUpload(apiKey, uploadURL, contentType, data)
+	if resp.Status() == 422 && assetAlreadyExists(resp.Body()) {
+		existing := -1
+		for _, a := range release.Assets {
+			if a.Name == name {
+				existing = a.ID
+			}
+		}
+		if !overwrite || existing == -1 {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Release %s already has an asset named %s; set overwrite to replace it", release.TagName, name)),
+				}},
+			}
+		}
+		if err := assetDelete(apiKey, owner, repo, existing); err != nil {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Failed to delete the existing %s: %s", name, err)),
+				}},
+			}
+		}
+		resp = assetUpload(apiKey, uploadURL, contentType, data)
+	}
+	if resp.Status() != 201 {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to upload %s: %s", name, githubStatusError(resp.Status(), resp.Body()))),
+			}},
+		}
+	}
+
+	var asset ReleaseAsset
+	if err := json.Unmarshal(resp.Body(), &asset); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to parse response: %s", err)),
+			}},
+		}
+	}
+	responseJSON, err := json.Marshal(asset)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func assetMaxSize(args map[string]interface{}) (int, error) {
+	value, ok := args["max_size"]
+	if !ok || value == nil {
+		return assetDefaultMaxSize, nil
+	}
+	size, ok := value.(float64)
+	if !ok || size != float64(int(size)) || size < 1 || size > assetMaxMaxSize {
+		return 0, fmt.Errorf("max_size must be a whole number of bytes from 1 to %d, got %v", assetMaxMaxSize, value)
+	}
+	return int(size), nil
+}
+
+func findAsset(release Release, name string) (ReleaseAsset, error) {
+	names := []string{}
+	for _, a := range release.Assets {
+		if a.Name == name {
+			return a, nil
+		}
+		names = append(names, a.Name)
+	}
+	if len(names) == 0 {
+		return ReleaseAsset{}, fmt.Errorf("release %s has no assets", release.TagName)
+	}
+	return ReleaseAsset{}, fmt.Errorf("release %s has no asset named %s; it has %s", release.TagName, name, strings.Join(names, ", "))
+}
+
+// redirectLocation returns where a 3xx response points, if it does.
+func redirectLocation(status uint16, headers map[string]string) string {
+	if status < 300 || status > 399 {
+		return ""
+	}
+	location, _ := responseHeader(headers, "Location")
+	return location
+}
+
+func assetBlob(asset ReleaseAsset, data []byte) Content {
+	mimeType := asset.ContentType
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	return Content{
+		Type: ContentTypeResource,
+		Resource: &BlobResourceContents{
+			Uri:      asset.BrowserDownloadURL,
+			MimeType: some(mimeType),
+			Blob:     base64.StdEncoding.EncodeToString(data),
+		},
+	}
+}
+
+func releaseDownloadAsset(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	id, _ := args["asset_id"].(float64)
+	tag, _ := args["tag"].(string)
+	name, _ := args["name"].(string)
+	maxSize, err := assetMaxSize(args)
+	if err == nil && id == 0 && (tag == "" || name == "") {
+		err = fmt.Errorf("give asset_id, or tag and name")
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid download: %s", err)),
+			}},
+		}
+	}
+
+	// Look the asset up first so its size is known before downloading it
+	var asset ReleaseAsset
+	if id != 0 {
+		u := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/assets/%d", owner, repo, int(id))
+		pdk.Log(pdk.LogDebug, fmt.Sprint("Getting release asset: ", u))
+		_, err = githubGetJSON(apiKey, u, &asset)
+	} else {
+		u, _ := releaseURL(owner, repo, 0, tag)
+		pdk.Log(pdk.LogDebug, fmt.Sprint("Getting release: ", u))
+		var release Release
+		if _, err = githubGetJSON(apiKey, u, &release); err == nil {
+			asset, err = findAsset(release, name)
+		}
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to find asset: %s", err)),
+			}},
+		}
+	}
+	if asset.Size > maxSize {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("%s is %d bytes, more than max_size %d", asset.Name, asset.Size, maxSize)),
+			}},
+		}
+	}
+
+	data, err := githubDownload(apiKey, fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/assets/%d", owner, repo, asset.ID))
+	if err == nil && len(data) > maxSize {
+		err = fmt.Errorf("got %d bytes, more than max_size %d", len(data), maxSize)
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to download %s: %s", asset.Name, err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{assetBlob(asset, data)},
+	}
+}
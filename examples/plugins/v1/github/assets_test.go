@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestReleaseURL(t *testing.T) {
+	if got, _ := releaseURL("acme", "api", 42, "v1"); got != "https://api.github.com/repos/acme/api/releases/42" {
+		t.Errorf("by id: got %s", got)
+	}
+	if got, _ := releaseURL("acme", "api", 0, "release/1.0"); got != "https://api.github.com/repos/acme/api/releases/tags/release%2F1.0" {
+		t.Errorf("by tag: got %s", got)
+	}
+	if _, err := releaseURL("acme", "api", 0, ""); err == nil {
+		t.Error("expected an error without id or tag")
+	}
+}
+
+func TestReleaseUploadURL(t *testing.T) {
+	got := releaseUploadURL("https://uploads.github.com/repos/acme/api/releases/42/assets{?name,label}", "app linux.tar.gz")
+	want := "https://uploads.github.com/repos/acme/api/releases/42/assets?name=app+linux.tar.gz"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestAssetContent(t *testing.T) {
+	tests := []struct {
+		args    string
+		want    string
+		wantErr bool
+	}{
+		{args: `{"content":"hello"}`, want: "hello"},
+		{args: `{"content":"hello","encoding":"text"}`, want: "hello"},
+		{args: `{"content":"aGVs\nbG8=","encoding":"base64"}`, want: "hello"},
+		{args: `{"content":"not base64!","encoding":"base64"}`, wantErr: true},
+		{args: `{"content":"hello","encoding":"hex"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		got, err := assetContent(args)
+		if (err != nil) != tt.wantErr || string(got) != tt.want {
+			t.Errorf("%s: got %q, %v", tt.args, got, err)
+		}
+	}
+}
+
+func TestAssetAlreadyExists(t *testing.T) {
+	if !assetAlreadyExists([]byte(`{"message":"Validation Failed","errors":[{"resource":"ReleaseAsset","code":"already_exists","field":"name"}]}`)) {
+		t.Error("already_exists not detected")
+	}
+	if assetAlreadyExists([]byte(`{"message":"Validation Failed","errors":[{"code":"invalid"}]}`)) || assetAlreadyExists([]byte("oops")) {
+		t.Error("other errors reported as already_exists")
+	}
+}
+
+func TestAssetMaxSize(t *testing.T) {
+	if got, err := assetMaxSize(map[string]interface{}{}); err != nil || got != assetDefaultMaxSize {
+		t.Errorf("default: got %d, %v", got, err)
+	}
+	if got, err := assetMaxSize(map[string]interface{}{"max_size": float64(1024)}); err != nil || got != 1024 {
+		t.Errorf("1024: got %d, %v", got, err)
+	}
+	for _, value := range []interface{}{float64(0), float64(1.5), float64(assetMaxMaxSize + 1), "big"} {
+		if _, err := assetMaxSize(map[string]interface{}{"max_size": value}); err == nil {
+			t.Errorf("%v: expected an error", value)
+		}
+	}
+}
+
+func TestFindAsset(t *testing.T) {
+	release := Release{TagName: "v1", Assets: []ReleaseAsset{{ID: 1, Name: "a.zip"}, {ID: 2, Name: "b.zip"}}}
+	if got, err := findAsset(release, "b.zip"); err != nil || got.ID != 2 {
+		t.Errorf("got %+v, %v", got, err)
+	}
+	if _, err := findAsset(release, "c.zip"); err == nil || err.Error() != "release v1 has no asset named c.zip; it has a.zip, b.zip" {
+		t.Errorf("missing asset: %v", err)
+	}
+	if _, err := findAsset(Release{TagName: "v0"}, "a.zip"); err == nil || err.Error() != "release v0 has no assets" {
+		t.Errorf("no assets: %v", err)
+	}
+}
+
+func TestRedirectLocation(t *testing.T) {
+	headers := map[string]string{"location": "https://objects.githubusercontent.com/x"}
+	if got := redirectLocation(302, headers); got != "https://objects.githubusercontent.com/x" {
+		t.Errorf("302: got %q", got)
+	}
+	if got := redirectLocation(200, headers); got != "" {
+		t.Errorf("200: got %q", got)
+	}
+}
+
+func TestAssetBlob(t *testing.T) {
+	content := assetBlob(ReleaseAsset{Name: "a.bin", BrowserDownloadURL: "https://github.com/acme/api/releases/download/v1/a.bin"}, []byte("hi"))
+	got, _ := json.Marshal(content)
+	want := `{"resource":{"blob":"aGk=","mimeType":"application/octet-stream","uri":"https://github.com/acme/api/releases/download/v1/a.bin"},"type":"resource"}`
+	if string(got) != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	AddIssueAssigneesTool = ToolDescription{
+		Name:        "gh-add-issue-assignees",
+		Description: "Assign users to an issue or pull request, keeping its current assignees. Users who can't be assigned in the repository are reported and skipped; the others are still assigned.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":     prop("string", "The owner of the repository"),
+				"repo":      prop("string", "The repository name"),
+				"issue":     prop("integer", "The issue or pull request number"),
+				"assignees": arrprop("array", "Usernames to assign", "string"),
+			},
+			"required": []string{"owner", "repo", "issue", "assignees"},
+		},
+	}
+	RemoveIssueAssigneesTool = ToolDescription{
+		Name:        "gh-remove-issue-assignees",
+		Description: "Unassign users from an issue or pull request, keeping its other assignees",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":     prop("string", "The owner of the repository"),
+				"repo":      prop("string", "The repository name"),
+				"issue":     prop("integer", "The issue or pull request number"),
+				"assignees": arrprop("array", "Usernames to unassign", "string"),
+			},
+			"required": []string{"owner", "repo", "issue", "assignees"},
+		},
+	}
+)
+
+// AssigneesUpdate is the tool output: the issue's assignees after the
+// change, and the requested users that could not be assigned.
+type AssigneesUpdate struct {
+	Issue         int      `json:"issue"`
+	Assignees     []string `json:"assignees"`
+	NotAssignable []string `json:"not_assignable,omitempty"`
+}
+
+// loginPattern matches a GitHub login: letters, digits and hyphens, not
+// starting with a hyphen, at most 39 characters.
+var loginPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]{0,38}$`)
+
+// assigneesFromArgs reads the usernames, dropping a leading @ and
+// duplicates.
+func assigneesFromArgs(args map[string]interface{}) ([]string, error) {
+	list, _ := args["assignees"].([]interface{})
+	usernames := []string{}
+	seen := map[string]bool{}
+	for _, a := range list {
+		name, ok := a.(string)
+		if !ok {
+			return nil, fmt.Errorf("assignees must be usernames, got %#v", a)
+		}
+		name = strings.TrimPrefix(strings.TrimSpace(name), "@")
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		if !loginPattern.MatchString(name) {
+			return nil, fmt.Errorf("%q is not a GitHub username", name)
+		}
+		seen[strings.ToLower(name)] = true
+		usernames = append(usernames, name)
+	}
+	if len(usernames) == 0 {
+		return nil, fmt.Errorf("assignees must list at least one username")
+	}
+	return usernames, nil
+}
+
+// partitionAssignable splits usernames into the ones check accepts and
+// the ones it rejects. An error from check stops the whole call, since it
+// says nothing about the user.
+func partitionAssignable(usernames []string, check func(string) (bool, error)) ([]string, []string, error) {
+	var assignable, notAssignable []string
+	for _, name := range usernames {
+		ok, err := check(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			assignable = append(assignable, name)
+		} else {
+			notAssignable = append(notAssignable, name)
+		}
+	}
+	return assignable, notAssignable, nil
+}
+
+// canBeAssigned asks GitHub whether the user can be assigned to issues in
+// the repository: 204 means yes, 404 no.
+func canBeAssigned(apiKey, owner, repo, username string) (bool, error) {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/assignees/%s", owner, repo, url.PathEscape(username))
+	status, body, err := githubSend(apiKey, pdk.MethodGet, u, nil)
+	if err != nil {
+		return false, err
+	}
+	switch status {
+	case 204:
+		return true, nil
+	case 404:
+		return false, nil
+	default:
+		return false, fmt.Errorf("checking whether %s can be assigned: %w", username, githubStatusError(status, body))
+	}
+}
+
+func assigneesResult(number int, issue ExportedIssue, notAssignable []string) CallToolResult {
+	update := AssigneesUpdate{Issue: number, Assignees: []string{}, NotAssignable: notAssignable}
+	for _, a := range issue.Assignees {
+		update.Assignees = append(update.Assignees, a.Login)
+	}
+	responseJSON, err := json.Marshal(update)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+// issueChangeAssignees adds the users to the issue, or removes them when
+// remove is set.
+func issueChangeAssignees(apiKey, owner, repo string, number int, args map[string]interface{}, remove bool) CallToolResult {
+	usernames, err := assigneesFromArgs(args)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid assignees: %s", err)),
+			}},
+		}
+	}
+
+	var notAssignable []string
+	if !remove {
+		usernames, notAssignable, err = partitionAssignable(usernames, func(username string) (bool, error) {
+			return canBeAssigned(apiKey, owner, repo, username)
+		})
+		if err != nil {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Failed to check assignees: %s", err)),
+				}},
+			}
+		}
+		if len(usernames) == 0 {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("None of %s can be assigned in %s/%s: they need push access, or to have commented on the issue in a public repository", strings.Join(notAssignable, ", "), owner, repo)),
+				}},
+			}
+		}
+	}
+
+	method, want, action := pdk.MethodPost, uint16(201), "add"
+	if remove {
+		method, want, action = pdk.MethodDelete, 200, "remove"
+	}
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d/assignees", owner, repo, number)
+	pdk.Log(pdk.LogDebug, fmt.Sprintf("Assignees %s: %s", action, u))
+	var issue ExportedIssue
+	if err := githubSendJSON(apiKey, method, u, map[string][]string{"assignees": usernames}, want, &issue); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to %s assignees on #%d: %s", action, number, err)),
+			}},
+		}
+	}
+	return assigneesResult(number, issue, notAssignable)
+}
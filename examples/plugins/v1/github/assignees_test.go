@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAssigneesFromArgs(t *testing.T) {
+	tests := []struct {
+		args    string
+		want    string
+		wantErr string
+	}{
+		{args: `{"assignees":["octocat","@hubot"]}`, want: "octocat,hubot"},
+		{args: `{"assignees":["octocat","OctoCat"," ",""]}`, want: "octocat"},
+		{args: `{"assignees":[]}`, wantErr: "at least one username"},
+		{args: `{"assignees":[42]}`, wantErr: "must be usernames"},
+		{args: `{"assignees":["a/../../x"]}`, wantErr: `"a/../../x" is not a GitHub username`},
+		{args: `{"assignees":["octocat?per_page=1"]}`, wantErr: "is not a GitHub username"},
+		{args: `{"assignees":["-octocat"]}`, wantErr: "is not a GitHub username"},
+		{args: `{"assignees":["` + strings.Repeat("a", 40) + `"]}`, wantErr: "is not a GitHub username"},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		got, err := assigneesFromArgs(args)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error %v, want one containing %q", tt.args, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || strings.Join(got, ",") != tt.want {
+			t.Errorf("%s: got %v, %v, want %s", tt.args, got, err, tt.want)
+		}
+	}
+}
+
+func TestPartitionAssignable(t *testing.T) {
+	members := map[string]bool{"octocat": true, "hubot": true}
+	check := func(name string) (bool, error) { return members[name], nil }
+
+	ok, rejected, err := partitionAssignable([]string{"octocat", "stranger", "hubot", "ghost"}, check)
+	if err != nil || strings.Join(ok, ",") != "octocat,hubot" || strings.Join(rejected, ",") != "stranger,ghost" {
+		t.Errorf("got %v %v %v", ok, rejected, err)
+	}
+
+	failing := func(string) (bool, error) { return false, errors.New("502 bad gateway") }
+	if _, _, err := partitionAssignable([]string{"octocat"}, failing); err == nil {
+		t.Error("check error was treated as not assignable")
+	}
+}
+
+func TestAssigneesResult(t *testing.T) {
+	var issue ExportedIssue
+	if err := json.Unmarshal([]byte(`{"number":5,"assignees":[{"login":"octocat"},{"login":"hubot"}]}`), &issue); err != nil {
+		t.Fatal(err)
+	}
+	result := assigneesResult(5, issue, []string{"stranger"})
+	want := `{"issue":5,"assignees":["octocat","hubot"],"not_assignable":["stranger"]}`
+	if result.IsError != nil || *result.Content[0].Text != want {
+		t.Errorf("got %s, want %s", *result.Content[0].Text, want)
+	}
+}
@@ -61,12 +61,119 @@ var (
 			"required": []string{"owner", "repo", "title", "body", "head", "base"},
 		},
 	}
+	ListPullRequestCommitsTool = ToolDescription{
+		Name:        "gh-list-pr-commits",
+		Description: "List the commits of a pull request, returning sha, author login, date and the first line of each commit message",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":       prop("string", "The owner of the repository"),
+				"repo":        prop("string", "The repository name"),
+				"pull_number": prop("integer", "The pull request number"),
+				"per_page":    prop("integer", "Number of results per page (max 100)"),
+				"page":        prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo", "pull_number"},
+		},
+	}
+	SetPullRequestDraftTool = ToolDescription{
+		Name:        "gh-set-pr-draft",
+		Description: "Mark a draft pull request as ready for review, or convert a pull request back to a draft",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":       prop("string", "The owner of the repository"),
+				"repo":        prop("string", "The repository name"),
+				"pull_number": prop("integer", "The pull request number"),
+				"draft":       prop("boolean", "true to convert the pull request to a draft, false to mark it ready for review"),
+			},
+			"required": []string{"owner", "repo", "pull_number", "draft"},
+		},
+	}
+	ClosePullRequestTool = ToolDescription{
+		Name:        "gh-close-pull-request",
+		Description: "Close a pull request without merging it",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":       prop("string", "The owner of the repository"),
+				"repo":        prop("string", "The repository name"),
+				"pull_number": prop("integer", "The pull request number"),
+			},
+			"required": []string{"owner", "repo", "pull_number"},
+		},
+	}
+	EnableAutoMergeTool = ToolDescription{
+		Name:        "gh-enable-auto-merge",
+		Description: "Enable auto-merge on a pull request so it merges once all requirements are met, or disable it again",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":        prop("string", "The owner of the repository"),
+				"repo":         prop("string", "The repository name"),
+				"pull_number":  prop("integer", "The pull request number"),
+				"merge_method": prop("string", "The merge method to use once checks pass: merge, squash or rebase (defaults to merge)"),
+				"disable":      prop("boolean", "Disable auto-merge instead of enabling it"),
+			},
+			"required": []string{"owner", "repo", "pull_number"},
+		},
+	}
+	DeleteBranchTool = ToolDescription{
+		Name:        "gh-delete-branch",
+		Description: "Delete a branch. Refuses to delete the repository's default branch and reports when the branch is protected. Asks the user to confirm first.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":  prop("string", "The owner of the repository"),
+				"repo":   prop("string", "The repository name"),
+				"branch": prop("string", "The branch to delete"),
+			},
+			"required": []string{"owner", "repo", "branch"},
+		},
+	}
+	RenameBranchTool = ToolDescription{
+		Name:        "gh-rename-branch",
+		Description: "Rename a branch. Open pull requests targeting the branch are retargeted to the new name automatically. Renaming the default branch requires admin rights on the repository.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":    prop("string", "The owner of the repository"),
+				"repo":     prop("string", "The repository name"),
+				"branch":   prop("string", "The current branch name"),
+				"new_name": prop("string", "The new branch name"),
+			},
+			"required": []string{"owner", "repo", "branch", "new_name"},
+		},
+	}
+	ReopenPullRequestTool = ToolDescription{
+		Name:        "gh-reopen-pull-request",
+		Description: "Reopen a closed pull request",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":       prop("string", "The owner of the repository"),
+				"repo":        prop("string", "The repository name"),
+				"pull_number": prop("integer", "The pull request number"),
+			},
+			"required": []string{"owner", "repo", "pull_number"},
+		},
+	}
 )
 
 var BranchTools = []ToolDescription{
 	CreateBranchTool,
 	ListPullRequestsTool,
 	CreatePullRequestTool,
+	ListPullRequestCommitsTool,
+	SetPullRequestDraftTool,
+	ClosePullRequestTool,
+	ReopenPullRequestTool,
+	EnableAutoMergeTool,
+	DeleteBranchTool,
+	RenameBranchTool,
+	GetBranchProtectionTool,
+	UpdateBranchProtectionTool,
+	ReviewPullRequestChunkedTool,
 }
 
 type RefObjectSchema struct {
@@ -98,43 +205,146 @@ func branchCreate(apiKey, owner, repo, branch string, fromBranch *string) CallTo
 	}
 
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/refs", owner, repo)
-	req := pdk.NewHTTPRequest(pdk.MethodPost, url)
-	req.SetHeader("Authorization", fmt.Sprintf("token %s", apiKey))
-	req.SetHeader("Content-Type", "application/json")
-	req.SetHeader("Accept", "application/vnd.github.v3+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
-
 	data := map[string]interface{}{
 		"ref": fmt.Sprintf("refs/heads/%s", branch),
 		"sha": sha,
 	}
-	res, err := json.Marshal(data)
+	body, err := githubSendExpect(apiKey, pdk.MethodPost, url, data, 201)
 	if err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprintf("Failed to marshal branch data: %s", err)),
+				Text: some(fmt.Sprintf("Failed to create branch: %s", err)),
 			}},
 		}
 	}
 
-	req.SetBody([]byte(res))
-	resp := req.Send()
-	if resp.Status() != 201 {
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(body)),
+		}},
+	}
+}
+
+// branchDeleteFailure explains a failed ref deletion. GitHub answers 422
+// (or 403 for some rule sets) when a branch protection rule forbids it.
+func branchDeleteFailure(branch string, status uint16, body string) string {
+	switch status {
+	case 403, 422:
+		return fmt.Sprintf("Failed to delete branch %s: it is protected or a repository rule forbids deleting it (%d %s)", branch, status, body)
+	case 404:
+		return fmt.Sprintf("Failed to delete branch %s: branch not found", branch)
+	default:
+		return fmt.Sprintf("Failed to delete branch: %d %s", status, body)
+	}
+}
+
+func branchDelete(apiKey, owner, repo, branch string) CallToolResult {
+	var details RepositoryDetails
+	if _, err := githubGetJSON(apiKey, fmt.Sprintf("https://api.github.com/repos/%s/%s", owner, repo), &details); err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprintf("Failed to create branch: %d %s", resp.Status(), string(resp.Body()))),
+				Text: some(fmt.Sprintf("Failed to fetch repository details: %s", err)),
+			}},
+		}
+	}
+	if branch == details.DefaultBranch {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Refusing to delete %s: it is the default branch of %s", branch, details.FullName)),
 			}},
 		}
 	}
 
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/refs/heads/%s", owner, repo, branch)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Deleting branch: ", url))
+	status, body, err := githubSend(apiKey, pdk.MethodDelete, url, nil)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to delete branch %s: %s", branch, err)),
+			}},
+		}
+	}
+	if status != 204 {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(branchDeleteFailure(branch, status, githubErrorMessage(body))),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(fmt.Sprintf("Deleted refs/heads/%s from %s", branch, details.FullName)),
+		}},
+	}
+}
+
+type RenamedBranch struct {
+	Name      string `json:"name"`
+	Protected bool   `json:"protected"`
+}
+
+func branchRename(apiKey, owner, repo, branch, newName string) CallToolResult {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/branches/%s/rename", owner, repo, branch)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Renaming branch: ", url))
+	status, body, err := githubSend(apiKey, pdk.MethodPost, url, map[string]string{"new_name": newName})
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to rename branch: %s", err)),
+			}},
+		}
+	}
+	if status == 403 {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to rename branch %s: permission denied. Renaming the default branch requires admin rights (403 %s)", branch, githubErrorMessage(body))),
+			}},
+		}
+	}
+	if status != 201 {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to rename branch: %s", githubStatusError(status, body))),
+			}},
+		}
+	}
+
+	var renamed RenamedBranch
+	if err := json.Unmarshal(body, &renamed); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to parse renamed branch: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, _ := json.Marshal(renamed)
 	return CallToolResult{
 		Content: []Content{{
 			Type: ContentTypeText,
-			Text: some(string(resp.Body())),
+			Text: some(string(responseJSON)),
 		}},
 	}
 }
@@ -232,12 +442,8 @@ func pullRequestList(apiKey string, owner, repo string, args map[string]interfac
 	url := fmt.Sprintf("%s?%s", baseURL, strings.Join(params, "&"))
 	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing pull requests: ", url))
 
-	// Make request
-	req := pdk.NewHTTPRequest(pdk.MethodGet, url)
-	req.SetHeader("Authorization", fmt.Sprint("token ", apiKey))
-
 	// Handle Accept header based on requested format
-	acceptHeader := "application/vnd.github+json" // Default recommended header
+	acceptHeader := githubMediaType // Default recommended header
 	if format, ok := args["accept"].(string); ok {
 		switch format {
 		case "raw":
@@ -250,10 +456,9 @@ func pullRequestList(apiKey string, owner, repo string, args map[string]interfac
 			acceptHeader = "application/vnd.github.full+json"
 		}
 	}
-	req.SetHeader("Accept", acceptHeader)
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
 
-	resp := req.Send()
+	// Make request
+	resp := githubRequest(apiKey, pdk.MethodGet, url, acceptHeader).Send()
 
 	// Handle response status codes
 	switch resp.Status() {
@@ -293,31 +498,200 @@ func pullRequestList(apiKey string, owner, repo string, args map[string]interfac
 
 func branchCreatePullRequest(apiKey, owner, repo string, pr PullRequestSchema) CallToolResult {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls", owner, repo)
-	req := pdk.NewHTTPRequest(pdk.MethodPost, url)
-	req.SetHeader("Authorization", fmt.Sprintf("token %s", apiKey))
-	req.SetHeader("Accept", "application/vnd.github.v3+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
-	req.SetHeader("Content-Type", "application/json")
+	body, err := githubSendExpect(apiKey, pdk.MethodPost, url, pr, 201)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to create pull request: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(body)),
+		}},
+	}
+}
+
+func branchGetSha(apiKey, owner, repo, ref string) (string, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/refs/heads/%s", owner, repo, ref)
+	var refDetail RefSchema
+	if _, err := githubGetJSON(apiKey, url, &refDetail); err != nil {
+		return "", fmt.Errorf("Failed to get main branch sha: %s", err)
+	}
+	return refDetail.Object.Sha, nil
+}
+
+type PullRequestCommit struct {
+	Sha     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Author  Author `json:"author"`
+		Message string `json:"message"`
+	} `json:"commit"`
+	Author *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+type CommitSummary struct {
+	Sha     string `json:"sha"`
+	Author  string `json:"author"`
+	Date    string `json:"date"`
+	Message string `json:"message"`
+	HTMLURL string `json:"html_url,omitempty"`
+}
+
+// summarizeCommits trims commit list responses down to the fields an agent needs.
+func summarizeCommits(commits []PullRequestCommit, includeURL bool) []CommitSummary {
+	summaries := make([]CommitSummary, 0, len(commits))
+	for _, c := range commits {
+		// The top level author is null when the commit email isn't linked to a GitHub account
+		author := c.Commit.Author.Name
+		if c.Author != nil && c.Author.Login != "" {
+			author = c.Author.Login
+		}
+		summary := CommitSummary{
+			Sha:     c.Sha,
+			Author:  author,
+			Date:    c.Commit.Author.Date,
+			Message: firstLine(c.Commit.Message),
+		}
+		if includeURL {
+			summary.HTMLURL = c.HTMLURL
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+func pullRequestListCommits(apiKey, owner, repo string, pullNumber int, args map[string]interface{}) (CallToolResult, error) {
+	baseURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/commits", owner, repo, pullNumber)
+	url := fmt.Sprintf("%s?%s", baseURL, strings.Join(paginationParams(args), "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing pull request commits: ", url))
+
+	var commits []PullRequestCommit
+	if _, err := githubGetJSON(apiKey, url, &commits); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list pull request commits: %s", err)),
+			}},
+		}, nil
+	}
 
-	res, err := json.Marshal(pr)
+	responseJSON, err := json.Marshal(summarizeCommits(commits, false))
 	if err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprintf("Failed to marshal pull request data: %s", err)),
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}, nil
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}, nil
+}
+
+type PullRequestDetails struct {
+	NodeID  string `json:"node_id"`
+	Number  int    `json:"number"`
+	State   string `json:"state"`
+	Draft   bool   `json:"draft"`
+	HTMLURL string `json:"html_url"`
+	Head    struct {
+		Sha string `json:"sha"`
+	} `json:"head"`
+}
+
+func pullRequestGetDetails(apiKey, owner, repo string, pullNumber int) (PullRequestDetails, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d", owner, repo, pullNumber)
+	var pr PullRequestDetails
+	if _, err := githubGetJSON(apiKey, url, &pr); err != nil {
+		return PullRequestDetails{}, fmt.Errorf("Failed to get pull request: %w", err)
+	}
+	return pr, nil
+}
+
+const (
+	markReadyForReviewMutation = `mutation($id: ID!) {
+  markPullRequestReadyForReview(input: {pullRequestId: $id}) {
+    pullRequest { number isDraft url }
+  }
+}`
+	convertToDraftMutation = `mutation($id: ID!) {
+  convertPullRequestToDraft(input: {pullRequestId: $id}) {
+    pullRequest { number isDraft url }
+  }
+}`
+)
+
+type DraftStateResult struct {
+	Number  int    `json:"number"`
+	IsDraft bool   `json:"isDraft"`
+	URL     string `json:"url"`
+}
+
+func pullRequestSetDraft(apiKey, owner, repo string, pullNumber int, draft bool) CallToolResult {
+	pr, err := pullRequestGetDetails(apiKey, owner, repo, pullNumber)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(err.Error()),
 			}},
 		}
 	}
 
-	req.SetBody([]byte(res))
-	resp := req.Send()
-	if resp.Status() != 201 {
+	var data struct {
+		MarkReady *struct {
+			PullRequest DraftStateResult `json:"pullRequest"`
+		} `json:"markPullRequestReadyForReview"`
+		ConvertToDraft *struct {
+			PullRequest DraftStateResult `json:"pullRequest"`
+		} `json:"convertPullRequestToDraft"`
+	}
+	mutation := markReadyForReviewMutation
+	if draft {
+		mutation = convertToDraftMutation
+	}
+	if err := graphqlQuery(apiKey, mutation, map[string]interface{}{"id": pr.NodeID}, &data); err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprintf("Failed to create pull request: %d %s", resp.Status(), string(resp.Body()))),
+				Text: some(fmt.Sprintf("Failed to update draft state: %s", err)),
+			}},
+		}
+	}
+
+	var result DraftStateResult
+	switch {
+	case data.MarkReady != nil:
+		result = data.MarkReady.PullRequest
+	case data.ConvertToDraft != nil:
+		result = data.ConvertToDraft.PullRequest
+	}
+
+	responseJSON, err := json.Marshal(result)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
 			}},
 		}
 	}
@@ -325,24 +699,147 @@ func branchCreatePullRequest(apiKey, owner, repo string, pr PullRequestSchema) C
 	return CallToolResult{
 		Content: []Content{{
 			Type: ContentTypeText,
-			Text: some(string(resp.Body())),
+			Text: some(string(responseJSON)),
 		}},
 	}
 }
 
-func branchGetSha(apiKey, owner, repo, ref string) (string, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/refs/heads/%s", owner, repo, ref)
-	req := pdk.NewHTTPRequest(pdk.MethodGet, url)
-	req.SetHeader("Authorization", fmt.Sprintf("token %s", apiKey))
-	req.SetHeader("Accept", "application/vnd.github.v3+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
+type PullRequestStateResult struct {
+	Number   int     `json:"number"`
+	State    string  `json:"state"`
+	ClosedAt *string `json:"closed_at"`
+	HTMLURL  string  `json:"html_url"`
+}
 
-	resp := req.Send()
-	if resp.Status() != 200 {
-		return "", fmt.Errorf("Failed to get main branch sha: %d", resp.Status())
+func pullRequestSetState(apiKey, owner, repo string, pullNumber int, state string) CallToolResult {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d", owner, repo, pullNumber)
+	var result PullRequestStateResult
+	if err := githubSendJSON(apiKey, pdk.MethodPatch, url, map[string]string{"state": state}, 200, &result); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to set pull request state to %s: %s", state, err)),
+			}},
+		}
 	}
 
-	var refDetail RefSchema
-	json.Unmarshal(resp.Body(), &refDetail)
-	return refDetail.Object.Sha, nil
+	responseJSON, err := json.Marshal(result)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+const (
+	enableAutoMergeMutation = `mutation($id: ID!, $method: PullRequestMergeMethod!) {
+  enablePullRequestAutoMerge(input: {pullRequestId: $id, mergeMethod: $method}) {
+    pullRequest { number url autoMergeRequest { enabledAt mergeMethod } }
+  }
+}`
+	disableAutoMergeMutation = `mutation($id: ID!) {
+  disablePullRequestAutoMerge(input: {pullRequestId: $id}) {
+    pullRequest { number url autoMergeRequest { enabledAt mergeMethod } }
+  }
+}`
+)
+
+type AutoMergeResult struct {
+	Number           int    `json:"number"`
+	URL              string `json:"url"`
+	AutoMergeRequest *struct {
+		EnabledAt   string `json:"enabledAt"`
+		MergeMethod string `json:"mergeMethod"`
+	} `json:"autoMergeRequest"`
+}
+
+func pullRequestSetAutoMerge(apiKey, owner, repo string, pullNumber int, mergeMethod string, disable bool) CallToolResult {
+	method := strings.ToUpper(mergeMethod)
+	if method == "" {
+		method = "MERGE"
+	}
+	switch method {
+	case "MERGE", "SQUASH", "REBASE":
+	default:
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid merge_method %q: must be one of merge, squash or rebase", mergeMethod)),
+			}},
+		}
+	}
+
+	pr, err := pullRequestGetDetails(apiKey, owner, repo, pullNumber)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(err.Error()),
+			}},
+		}
+	}
+
+	var data struct {
+		Enable *struct {
+			PullRequest AutoMergeResult `json:"pullRequest"`
+		} `json:"enablePullRequestAutoMerge"`
+		Disable *struct {
+			PullRequest AutoMergeResult `json:"pullRequest"`
+		} `json:"disablePullRequestAutoMerge"`
+	}
+	mutation := enableAutoMergeMutation
+	variables := map[string]interface{}{"id": pr.NodeID, "method": method}
+	if disable {
+		mutation = disableAutoMergeMutation
+		variables = map[string]interface{}{"id": pr.NodeID}
+	}
+	if err := graphqlQuery(apiKey, mutation, variables, &data); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to update auto-merge: %s", err)),
+			}},
+		}
+	}
+
+	var result AutoMergeResult
+	switch {
+	case data.Enable != nil:
+		result = data.Enable.PullRequest
+	case data.Disable != nil:
+		result = data.Disable.PullRequest
+	}
+
+	responseJSON, err := json.Marshal(result)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
 }
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBranchDeleteFailure(t *testing.T) {
+	tests := []struct {
+		status uint16
+		want   string
+	}{
+		{422, "Failed to delete branch release: it is protected"},
+		{403, "Failed to delete branch release: it is protected"},
+		{404, "Failed to delete branch release: branch not found"},
+		{500, "Failed to delete branch: 500 boom"},
+	}
+	for _, tt := range tests {
+		if got := branchDeleteFailure("release", tt.status, "boom"); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("status %d: got %q, want prefix %q", tt.status, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ListCheckRunsTool = ToolDescription{
+		Name:        "gh-list-check-runs",
+		Description: "List check runs (e.g. GitHub Actions jobs) for a commit, branch or tag, with counts per conclusion",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":      prop("string", "The owner of the repository"),
+				"repo":       prop("string", "The repository name"),
+				"ref":        prop("string", "The commit sha, branch name or tag name"),
+				"check_name": prop("string", "Only return check runs with this name"),
+				"status":     prop("string", "Only return check runs with this status (queued, in_progress, completed)"),
+				"per_page":   prop("integer", "Number of results per page (max 100)"),
+				"page":       prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo", "ref"},
+		},
+	}
+	ListCheckSuitesTool = ToolDescription{
+		Name:        "gh-list-check-suites",
+		Description: "List the check suites for a commit, branch or tag: one per app reporting on it (GitHub Actions, third-party CI), with status, conclusion and check run counts",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":          prop("string", "The owner of the repository"),
+				"repo":           prop("string", "The repository name"),
+				"ref":            prop("string", "The commit sha, branch name or tag name"),
+				"app_name":       prop("string", "Only return suites from the app with this name or slug, e.g. GitHub Actions"),
+				"expand_failing": prop("boolean", "Also list the failing check runs of each suite that didn't pass (default false)"),
+			},
+			"required": []string{"owner", "repo", "ref"},
+		},
+	}
+	CheckTools = []ToolDescription{
+		ListCheckRunsTool,
+		ListCheckSuitesTool,
+		CreateCommitStatusTool,
+		GetCombinedStatusTool,
+	}
+)
+
+type CheckRun struct {
+	Name        string  `json:"name"`
+	Status      string  `json:"status"`
+	Conclusion  *string `json:"conclusion"`
+	StartedAt   *string `json:"started_at"`
+	CompletedAt *string `json:"completed_at"`
+	DetailsURL  string  `json:"details_url"`
+}
+
+type CheckRunsSummary struct {
+	TotalCount int            `json:"total_count"`
+	Counts     map[string]int `json:"counts"`
+	CheckRuns  []CheckRun     `json:"check_runs"`
+}
+
+// summarizeCheckRuns counts runs by conclusion. Runs that haven't completed
+// have no conclusion yet and are counted under their status instead.
+func summarizeCheckRuns(totalCount int, runs []CheckRun) CheckRunsSummary {
+	counts := map[string]int{}
+	for _, r := range runs {
+		if r.Conclusion != nil {
+			counts[*r.Conclusion]++
+		} else {
+			counts[r.Status]++
+		}
+	}
+	return CheckRunsSummary{TotalCount: totalCount, Counts: counts, CheckRuns: runs}
+}
+
+type CheckSuite struct {
+	ID  int64 `json:"id"`
+	App struct {
+		Name string `json:"name"`
+		Slug string `json:"slug"`
+	} `json:"app"`
+	HeadBranch           *string `json:"head_branch"`
+	Status               string  `json:"status"`
+	Conclusion           *string `json:"conclusion"`
+	LatestCheckRunsCount int     `json:"latest_check_runs_count"`
+}
+
+type CheckSuiteSummary struct {
+	ID          int64      `json:"id"`
+	App         string     `json:"app"`
+	Status      string     `json:"status"`
+	Conclusion  *string    `json:"conclusion"`
+	CheckRuns   int        `json:"check_runs"`
+	FailingRuns []CheckRun `json:"failing_runs,omitempty"`
+}
+
+// Suites whose failing runs are listed at most, one request each
+const checkSuitesExpandMax = 10
+
+// checkSuitePassed reports whether a suite needs no expansion: it finished
+// without failures, or has no check runs to list.
+func checkSuitePassed(suite CheckSuite) bool {
+	if suite.LatestCheckRunsCount == 0 {
+		return true
+	}
+	if suite.Conclusion == nil {
+		return false
+	}
+	switch *suite.Conclusion {
+	case "success", "neutral", "skipped":
+		return true
+	}
+	return false
+}
+
+// filterCheckSuites keeps the suites of the app with the name or slug,
+// which the API can only filter by app ID.
+func filterCheckSuites(suites []CheckSuite, appName string) []CheckSuite {
+	if appName == "" {
+		return suites
+	}
+	filtered := []CheckSuite{}
+	for _, s := range suites {
+		if strings.EqualFold(s.App.Name, appName) || strings.EqualFold(s.App.Slug, appName) {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered
+}
+
+func summarizeCheckSuites(suites []CheckSuite) []CheckSuiteSummary {
+	summaries := []CheckSuiteSummary{}
+	for _, s := range suites {
+		summaries = append(summaries, CheckSuiteSummary{
+			ID:         s.ID,
+			App:        s.App.Name,
+			Status:     s.Status,
+			Conclusion: s.Conclusion,
+			CheckRuns:  s.LatestCheckRunsCount,
+		})
+	}
+	return summaries
+}
+
+func failingCheckRuns(runs []CheckRun) []CheckRun {
+	failing := []CheckRun{}
+	for _, r := range runs {
+		if r.Conclusion != nil && (failedConclusion(r.Conclusion) || *r.Conclusion == "action_required" || *r.Conclusion == "cancelled") {
+			failing = append(failing, r)
+		}
+	}
+	return failing
+}
+
+func checksListSuites(apiKey, owner, repo, ref string, args map[string]interface{}) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/commits/%s/check-suites?per_page=100", owner, repo, ref)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing check suites: ", u))
+
+	var response struct {
+		TotalCount  int          `json:"total_count"`
+		CheckSuites []CheckSuite `json:"check_suites"`
+	}
+	if _, err := githubGetJSON(apiKey, u, &response); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list check suites: %s", err)),
+			}},
+		}
+	}
+
+	appName, _ := args["app_name"].(string)
+	suites := filterCheckSuites(response.CheckSuites, appName)
+	summaries := summarizeCheckSuites(suites)
+	if expand, _ := args["expand_failing"].(bool); expand {
+		expanded := 0
+		for i, suite := range suites {
+			if checkSuitePassed(suite) || expanded == checkSuitesExpandMax {
+				continue
+			}
+			expanded++
+			u := fmt.Sprintf("https://api.github.com/repos/%s/%s/check-suites/%d/check-runs?filter=latest&per_page=100", owner, repo, suite.ID)
+			pdk.Log(pdk.LogDebug, fmt.Sprint("Listing suite check runs: ", u))
+			var runs struct {
+				CheckRuns []CheckRun `json:"check_runs"`
+			}
+			if _, err := githubGetJSON(apiKey, u, &runs); err != nil {
+				return CallToolResult{
+					IsError: some(true),
+					Content: []Content{{
+						Type: ContentTypeText,
+						Text: some(fmt.Sprintf("Failed to list check runs of suite %d: %s", suite.ID, err)),
+					}},
+				}
+			}
+			summaries[i].FailingRuns = failingCheckRuns(runs.CheckRuns)
+		}
+	}
+
+	responseJSON, err := json.Marshal(map[string]interface{}{
+		"total_count":  len(summaries),
+		"check_suites": summaries,
+	})
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func checksListRuns(apiKey, owner, repo, ref string, args map[string]interface{}) (CallToolResult, error) {
+	baseURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/commits/%s/check-runs", owner, repo, ref)
+	params := make([]string, 0)
+	if value, ok := args["check_name"].(string); ok && value != "" {
+		params = append(params, fmt.Sprintf("check_name=%s", url.QueryEscape(value)))
+	}
+	if value, ok := args["status"].(string); ok && value != "" {
+		params = append(params, fmt.Sprintf("status=%s", value))
+	}
+	params = append(params, paginationParams(args)...)
+
+	u := fmt.Sprintf("%s?%s", baseURL, strings.Join(params, "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing check runs: ", u))
+
+	var response struct {
+		TotalCount int        `json:"total_count"`
+		CheckRuns  []CheckRun `json:"check_runs"`
+	}
+	if _, err := githubGetJSON(apiKey, u, &response); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list check runs: %s", err)),
+			}},
+		}, nil
+	}
+
+	responseJSON, err := json.Marshal(summarizeCheckRuns(response.TotalCount, response.CheckRuns))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}, nil
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}, nil
+}
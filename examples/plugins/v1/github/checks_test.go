@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCheckSuites(t *testing.T) {
+	var suites []CheckSuite
+	if err := json.Unmarshal([]byte(`[
+		{"id":1,"app":{"name":"GitHub Actions","slug":"github-actions"},"status":"completed","conclusion":"failure","latest_check_runs_count":4},
+		{"id":2,"app":{"name":"CircleCI Checks","slug":"circleci-checks"},"status":"completed","conclusion":"success","latest_check_runs_count":2},
+		{"id":3,"app":{"name":"Dependabot","slug":"dependabot"},"status":"queued","conclusion":null,"latest_check_runs_count":0},
+		{"id":4,"app":{"name":"Buildkite","slug":"buildkite"},"status":"in_progress","conclusion":null,"latest_check_runs_count":3}
+	]`), &suites); err != nil {
+		t.Fatal(err)
+	}
+
+	passed := []bool{}
+	for _, s := range suites {
+		passed = append(passed, checkSuitePassed(s))
+	}
+	if got, _ := json.Marshal(passed); string(got) != "[false,true,true,false]" {
+		t.Errorf("passed: got %s", got)
+	}
+
+	got, _ := json.Marshal(summarizeCheckSuites(filterCheckSuites(suites, "github-actions")))
+	want := `[{"id":1,"app":"GitHub Actions","status":"completed","conclusion":"failure","check_runs":4}]`
+	if string(got) != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+	if got := filterCheckSuites(suites, "circleci checks"); len(got) != 1 || got[0].ID != 2 {
+		t.Errorf("by name: got %+v", got)
+	}
+	if got := filterCheckSuites(suites, ""); len(got) != 4 {
+		t.Errorf("no filter: got %d suites", len(got))
+	}
+}
+
+func TestFailingCheckRuns(t *testing.T) {
+	var runs []CheckRun
+	if err := json.Unmarshal([]byte(`[
+		{"name":"lint","status":"completed","conclusion":"success"},
+		{"name":"test","status":"completed","conclusion":"failure"},
+		{"name":"e2e","status":"completed","conclusion":"timed_out"},
+		{"name":"deploy","status":"completed","conclusion":"cancelled"},
+		{"name":"docs","status":"in_progress","conclusion":null}
+	]`), &runs); err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, r := range failingCheckRuns(runs) {
+		names = append(names, r.Name)
+	}
+	if got, _ := json.Marshal(names); string(got) != `["test","e2e","deploy"]` {
+		t.Errorf("got %s", got)
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ListCodeScanningAlertsTool = ToolDescription{
+		Name:        "gh-list-code-scanning-alerts",
+		Description: "List code scanning alerts of a repository (from CodeQL or other SARIF tools) with their rule, severity and location",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":     prop("string", "The owner of the repository"),
+				"repo":      prop("string", "The repository name"),
+				"state":     prop("string", "open, closed, dismissed or fixed"),
+				"severity":  prop("string", "critical, high, medium, low, warning, note or error"),
+				"tool_name": prop("string", "Only alerts from this tool, e.g. CodeQL"),
+				"ref":       prop("string", "Only alerts for this Git reference, e.g. refs/heads/main or refs/pull/42/merge"),
+				"per_page":  prop("integer", "Number of results per page (max 100)"),
+				"page":      prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	GetCodeScanningAlertTool = ToolDescription{
+		Name:        "gh-get-code-scanning-alert",
+		Description: "Get a code scanning alert, including the location and message of its most recent instance",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":        prop("string", "The owner of the repository"),
+				"repo":         prop("string", "The repository name"),
+				"alert_number": prop("integer", "The alert number"),
+			},
+			"required": []string{"owner", "repo", "alert_number"},
+		},
+	}
+	SecurityTools = []ToolDescription{
+		ListCodeScanningAlertsTool,
+		GetCodeScanningAlertTool,
+		ListDependabotAlertsTool,
+		UpdateDependabotAlertTool,
+	}
+)
+
+type CodeScanningLocation struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+}
+
+type CodeScanningInstance struct {
+	Ref       string `json:"ref"`
+	State     string `json:"state"`
+	CommitSHA string `json:"commit_sha"`
+	Message   struct {
+		Text string `json:"text"`
+	} `json:"message"`
+	Location CodeScanningLocation `json:"location"`
+}
+
+type CodeScanningAlert struct {
+	Number int    `json:"number"`
+	State  string `json:"state"`
+	Rule   struct {
+		ID                    string   `json:"id"`
+		Name                  string   `json:"name"`
+		Severity              string   `json:"severity"`
+		SecuritySeverityLevel *string  `json:"security_severity_level"`
+		Description           string   `json:"description"`
+		Tags                  []string `json:"tags"`
+	} `json:"rule"`
+	Tool struct {
+		Name    string  `json:"name"`
+		Version *string `json:"version"`
+	} `json:"tool"`
+	MostRecentInstance CodeScanningInstance `json:"most_recent_instance"`
+	DismissedReason    *string              `json:"dismissed_reason"`
+	DismissedComment   *string              `json:"dismissed_comment"`
+	CreatedAt          string               `json:"created_at"`
+	HTMLURL            string               `json:"html_url"`
+}
+
+type CodeScanningAlertSummary struct {
+	Number   int    `json:"number"`
+	State    string `json:"state"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Tool     string `json:"tool"`
+	Location string `json:"location"`
+	HTMLURL  string `json:"html_url"`
+}
+
+// codeScanningSeverity prefers the security severity (critical to low)
+// over the rule's own error/warning/note.
+func codeScanningSeverity(alert CodeScanningAlert) string {
+	if alert.Rule.SecuritySeverityLevel != nil && *alert.Rule.SecuritySeverityLevel != "" {
+		return *alert.Rule.SecuritySeverityLevel
+	}
+	return alert.Rule.Severity
+}
+
+func codeScanningLocation(location CodeScanningLocation) string {
+	if location.Path == "" {
+		return ""
+	}
+	if location.StartLine == 0 {
+		return location.Path
+	}
+	if location.EndLine > location.StartLine {
+		return fmt.Sprintf("%s:%d-%d", location.Path, location.StartLine, location.EndLine)
+	}
+	return fmt.Sprintf("%s:%d", location.Path, location.StartLine)
+}
+
+func summarizeCodeScanningAlerts(alerts []CodeScanningAlert) []CodeScanningAlertSummary {
+	summaries := []CodeScanningAlertSummary{}
+	for _, a := range alerts {
+		summaries = append(summaries, CodeScanningAlertSummary{
+			Number:   a.Number,
+			State:    a.State,
+			Rule:     a.Rule.ID,
+			Severity: codeScanningSeverity(a),
+			Tool:     a.Tool.Name,
+			Location: codeScanningLocation(a.MostRecentInstance.Location),
+			HTMLURL:  a.HTMLURL,
+		})
+	}
+	return summaries
+}
+
+func codeScanningAlertsURL(owner, repo string, args map[string]interface{}) string {
+	params := paginationParams(args)
+	for _, key := range []string{"state", "severity", "tool_name", "ref"} {
+		if value, _ := args[key].(string); value != "" {
+			params = append(params, fmt.Sprintf("%s=%s", key, url.QueryEscape(value)))
+		}
+	}
+	return fmt.Sprintf("https://api.github.com/repos/%s/%s/code-scanning/alerts?%s", owner, repo, strings.Join(params, "&"))
+}
+
+// codeScanningError explains the answers GitHub gives when code scanning
+// can't be used on the repository, rather than passing on its message.
+func codeScanningError(owner, repo string, status uint16, err error) string {
+	switch status {
+	case 403:
+		return fmt.Sprintf("Code scanning is not available for %s/%s: it needs GitHub Advanced Security enabled on private repositories, and a token with the security_events scope (or Code scanning alerts read for fine-grained tokens). GitHub said: %s", owner, repo, err)
+	case 404:
+		return fmt.Sprintf("No code scanning results for %s/%s: code scanning isn't set up, or the alert doesn't exist. GitHub said: %s", owner, repo, err)
+	}
+	return err.Error()
+}
+
+func codeScanningList(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	u := codeScanningAlertsURL(owner, repo, args)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing code scanning alerts: ", u))
+
+	alerts := []CodeScanningAlert{}
+	if status, err := githubGetJSON(apiKey, u, &alerts); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list code scanning alerts: %s", codeScanningError(owner, repo, status, err))),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(summarizeCodeScanningAlerts(alerts))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func codeScanningGet(apiKey, owner, repo string, number int) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/code-scanning/alerts/%d", owner, repo, number)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting code scanning alert: ", u))
+
+	var alert CodeScanningAlert
+	if status, err := githubGetJSON(apiKey, u, &alert); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to get code scanning alert: %s", codeScanningError(owner, repo, status, err))),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(alert)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSummarizeCodeScanningAlerts(t *testing.T) {
+	var alerts []CodeScanningAlert
+	if err := json.Unmarshal([]byte(`[
+		{"number":3,"state":"open","rule":{"id":"go/sql-injection","severity":"error","security_severity_level":"high"},"tool":{"name":"CodeQL"},
+		 "most_recent_instance":{"location":{"path":"db/query.go","start_line":42,"end_line":44}},"html_url":"https://github.com/o/r/security/code-scanning/3"},
+		{"number":1,"state":"dismissed","rule":{"id":"SC2086","severity":"warning","security_severity_level":null},"tool":{"name":"ShellCheck"},
+		 "most_recent_instance":{"location":{"path":"build.sh","start_line":7,"end_line":7}},"html_url":"https://github.com/o/r/security/code-scanning/1"}
+	]`), &alerts); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := json.Marshal(summarizeCodeScanningAlerts(alerts))
+	want := `[{"number":3,"state":"open","rule":"go/sql-injection","severity":"high","tool":"CodeQL","location":"db/query.go:42-44","html_url":"https://github.com/o/r/security/code-scanning/3"},` +
+		`{"number":1,"state":"dismissed","rule":"SC2086","severity":"warning","tool":"ShellCheck","location":"build.sh:7","html_url":"https://github.com/o/r/security/code-scanning/1"}]`
+	if string(got) != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+	if got := codeScanningLocation(CodeScanningLocation{Path: "go.sum"}); got != "go.sum" {
+		t.Errorf("no line: got %q", got)
+	}
+}
+
+func TestCodeScanningAlertsURL(t *testing.T) {
+	got := codeScanningAlertsURL("o", "r", map[string]interface{}{"state": "open", "tool_name": "CodeQL", "ref": "refs/heads/main", "per_page": float64(50)})
+	want := "https://api.github.com/repos/o/r/code-scanning/alerts?per_page=50&page=1&state=open&tool_name=CodeQL&ref=refs%2Fheads%2Fmain"
+	if got != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestCodeScanningError(t *testing.T) {
+	err := errors.New("403 Advanced Security must be enabled for this repository to use code scanning.")
+	if got := codeScanningError("o", "r", 403, err); !strings.Contains(got, "GitHub Advanced Security") || !strings.Contains(got, "security_events") {
+		t.Errorf("403: got %q", got)
+	}
+	if got := codeScanningError("o", "r", 404, errors.New("404 no analysis found")); !strings.Contains(got, "isn't set up") {
+		t.Errorf("404: got %q", got)
+	}
+	if got := codeScanningError("o", "r", 500, errors.New("500 boom")); got != "500 boom" {
+		t.Errorf("500: got %q", got)
+	}
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	AddCollaboratorTool = ToolDescription{
+		Name:        "gh-add-collaborator",
+		Description: "Invite a user to collaborate on a repository, or change the permission of an existing collaborator",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":      prop("string", "The owner of the repository"),
+				"repo":       prop("string", "The repository name"),
+				"username":   prop("string", "The user to add"),
+				"permission": prop("string", "pull, triage, push, maintain or admin (default push)"),
+			},
+			"required": []string{"owner", "repo", "username"},
+		},
+	}
+	ListRepoInvitationsTool = ToolDescription{
+		Name:        "gh-list-repo-invitations",
+		Description: "List the pending invitations to collaborate on a repository",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":    prop("string", "The owner of the repository"),
+				"repo":     prop("string", "The repository name"),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	DeleteRepoInvitationTool = ToolDescription{
+		Name:        "gh-delete-repo-invitation",
+		Description: "Withdraw a pending invitation to collaborate on a repository",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":         prop("string", "The owner of the repository"),
+				"repo":          prop("string", "The repository name"),
+				"invitation_id": prop("integer", "The invitation id"),
+			},
+			"required": []string{"owner", "repo", "invitation_id"},
+		},
+	}
+	AcceptInvitationTool = ToolDescription{
+		Name:        "gh-accept-invitation",
+		Description: "Accept an invitation the authenticated user received to collaborate on a repository",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"invitation_id": prop("integer", "The invitation id"),
+			},
+			"required": []string{"invitation_id"},
+		},
+	}
+	RemoveCollaboratorTool = ToolDescription{
+		Name:        "gh-remove-collaborator",
+		Description: "Remove a collaborator from a repository",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":    prop("string", "The owner of the repository"),
+				"repo":     prop("string", "The repository name"),
+				"username": prop("string", "The collaborator to remove"),
+			},
+			"required": []string{"owner", "repo", "username"},
+		},
+	}
+)
+
+var collaboratorPermissions = []string{"pull", "triage", "push", "maintain", "admin"}
+
+func collaboratorPermission(args map[string]interface{}) (string, error) {
+	permission, _ := args["permission"].(string)
+	permission = strings.ToLower(strings.TrimSpace(permission))
+	if permission == "" {
+		return "push", nil
+	}
+	if !containsString(collaboratorPermissions, permission) {
+		return "", fmt.Errorf("permission must be one of %s, got %q", strings.Join(collaboratorPermissions, ", "), args["permission"])
+	}
+	return permission, nil
+}
+
+type RepositoryInvitation struct {
+	ID      int `json:"id"`
+	Invitee struct {
+		Login string `json:"login"`
+	} `json:"invitee"`
+	Permissions string `json:"permissions"`
+	CreatedAt   string `json:"created_at"`
+	HTMLURL     string `json:"html_url"`
+}
+
+// collaboratorAddMessage describes what adding a collaborator did. GitHub
+// answers 201 with an invitation for a new collaborator, and 204 when an
+// existing collaborator's permission was changed.
+func collaboratorAddMessage(status uint16, body []byte, owner, repo, username, permission string) (string, error) {
+	switch status {
+	case 201:
+		var invitation RepositoryInvitation
+		if err := json.Unmarshal(body, &invitation); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Invited %s to %s/%s with %s permission (invitation %d). They are a collaborator once they accept it.",
+			invitation.Invitee.Login, owner, repo, invitation.Permissions, invitation.ID), nil
+	case 204:
+		return fmt.Sprintf("%s already collaborates on %s/%s; their permission is now %s", username, owner, repo, permission), nil
+	}
+	return "", githubStatusError(status, body)
+}
+
+func collaboratorsAdd(apiKey, owner, repo, username string, args map[string]interface{}) CallToolResult {
+	permission, err := collaboratorPermission(args)
+	if err == nil && username == "" {
+		err = fmt.Errorf("username is required")
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid collaborator: %s", err)),
+			}},
+		}
+	}
+
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/collaborators/%s", owner, repo, username)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Adding collaborator: ", u))
+	status, body, err := githubSend(apiKey, pdk.MethodPut, u, map[string]string{"permission": permission})
+	message := ""
+	if err == nil {
+		message, err = collaboratorAddMessage(status, body, owner, repo, username, permission)
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to add %s to %s/%s: %s", username, owner, repo, err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(message),
+		}},
+	}
+}
+
+type InvitationSummary struct {
+	ID          int    `json:"id"`
+	Invitee     string `json:"invitee"`
+	Permissions string `json:"permissions"`
+	CreatedAt   string `json:"created_at"`
+}
+
+func summarizeInvitations(invitations []RepositoryInvitation) []InvitationSummary {
+	summaries := make([]InvitationSummary, 0, len(invitations))
+	for _, i := range invitations {
+		summaries = append(summaries, InvitationSummary{
+			ID:          i.ID,
+			Invitee:     i.Invitee.Login,
+			Permissions: i.Permissions,
+			CreatedAt:   i.CreatedAt,
+		})
+	}
+	return summaries
+}
+
+func invitationsList(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/invitations?%s", owner, repo, strings.Join(paginationParams(args), "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing invitations: ", u))
+
+	var invitations []RepositoryInvitation
+	if _, err := githubGetJSON(apiKey, u, &invitations); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list invitations: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(summarizeInvitations(invitations))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+// invitationsRespond deletes an invitation to the repository, or accepts
+// one made to the authenticated user when owner is empty. Both answer 204.
+func invitationsRespond(apiKey, owner, repo string, id int) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/user/repository_invitations/%d", id)
+	method, action, done := pdk.MethodPatch, "accept", fmt.Sprintf("Accepted invitation %d", id)
+	if owner != "" {
+		u = fmt.Sprintf("https://api.github.com/repos/%s/%s/invitations/%d", owner, repo, id)
+		method, action, done = pdk.MethodDelete, "delete", fmt.Sprintf("Withdrew invitation %d to %s/%s", id, owner, repo)
+	}
+	pdk.Log(pdk.LogDebug, fmt.Sprintf("Invitation %s: %s", action, u))
+	_, err := githubSendExpect(apiKey, method, u, nil, 204)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to %s invitation %d: %s", action, id, err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(done),
+		}},
+	}
+}
+
+func collaboratorsRemove(apiKey, owner, repo, username string) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/collaborators/%s", owner, repo, username)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Removing collaborator: ", u))
+	_, err := githubSendExpect(apiKey, pdk.MethodDelete, u, nil, 204)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to remove %s from %s/%s: %s", username, owner, repo, err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(fmt.Sprintf("Removed %s from %s/%s", username, owner, repo)),
+		}},
+	}
+}
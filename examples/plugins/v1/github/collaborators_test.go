@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCollaboratorPermission(t *testing.T) {
+	if got, err := collaboratorPermission(map[string]interface{}{}); err != nil || got != "push" {
+		t.Errorf("default: got %q, %v", got, err)
+	}
+	if got, err := collaboratorPermission(map[string]interface{}{"permission": " Maintain "}); err != nil || got != "maintain" {
+		t.Errorf("maintain: got %q, %v", got, err)
+	}
+	if _, err := collaboratorPermission(map[string]interface{}{"permission": "write"}); err == nil || !strings.Contains(err.Error(), "pull, triage, push, maintain, admin") {
+		t.Errorf("write: %v", err)
+	}
+}
+
+func TestCollaboratorAddMessage(t *testing.T) {
+	invitation := `{"id":77,"invitee":{"login":"octocat"},"permissions":"write","created_at":"2024-05-01T00:00:00Z"}`
+	got, err := collaboratorAddMessage(201, []byte(invitation), "acme", "api", "octocat", "push")
+	if err != nil || !strings.Contains(got, "Invited octocat to acme/api") || !strings.Contains(got, "invitation 77") {
+		t.Errorf("invitation: got %q, %v", got, err)
+	}
+
+	got, err = collaboratorAddMessage(204, nil, "acme", "api", "octocat", "admin")
+	if err != nil || got != "octocat already collaborates on acme/api; their permission is now admin" {
+		t.Errorf("update: got %q, %v", got, err)
+	}
+
+	if _, err := collaboratorAddMessage(422, []byte(`{"message":"Validation Failed"}`), "acme", "api", "octocat", "push"); err == nil || !strings.Contains(err.Error(), "422") {
+		t.Errorf("failure: %v", err)
+	}
+}
+
+func TestSummarizeInvitations(t *testing.T) {
+	var invitations []RepositoryInvitation
+	err := json.Unmarshal([]byte(`[{"id":77,"invitee":{"login":"octocat","id":1},"inviter":{"login":"admin"},"permissions":"write","created_at":"2024-05-01T00:00:00Z","html_url":"https://github.com/acme/api/invitations"}]`), &invitations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(summarizeInvitations(invitations))
+	if string(got) != `[{"id":77,"invitee":"octocat","permissions":"write","created_at":"2024-05-01T00:00:00Z"}]` {
+		t.Errorf("got %s", got)
+	}
+	if got, _ := json.Marshal(summarizeInvitations(nil)); string(got) != "[]" {
+		t.Errorf("no invitations: got %s", got)
+	}
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ListCommitsTool = ToolDescription{
+		Name:        "gh-list-commits",
+		Description: "List commits on a repository, optionally filtered by branch, path, author and date range. Returns sha, author, date, message subject and html_url for each commit.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":    prop("string", "The owner of the repository"),
+				"repo":     prop("string", "The repository name"),
+				"sha":      prop("string", "Branch name or commit sha to start listing commits from (defaults to the default branch)"),
+				"branch":   prop("string", "Alias for sha when listing from a branch"),
+				"path":     prop("string", "Only commits containing this file path"),
+				"author":   prop("string", "GitHub login or email address to filter by commit author"),
+				"since":    prop("string", "Only commits after this date (ISO 8601 timestamp YYYY-MM-DDTHH:MM:SSZ)"),
+				"until":    prop("string", "Only commits before this date (ISO 8601 timestamp YYYY-MM-DDTHH:MM:SSZ)"),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	GetCommitTool = ToolDescription{
+		Name:        "gh-get-commit",
+		Description: "Get a single commit with its message, author, committer, stats and the list of changed files. Patches are included up to a total size budget.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":         prop("string", "The owner of the repository"),
+				"repo":          prop("string", "The repository name"),
+				"sha":           prop("string", "The commit sha, branch or tag"),
+				"include_patch": prop("boolean", "Include the per-file patch (defaults to true)"),
+			},
+			"required": []string{"owner", "repo", "sha"},
+		},
+	}
+	CompareRefsTool = ToolDescription{
+		Name:        "gh-compare-refs",
+		Description: "Compare two refs (branches, tags or commit shas). Returns how far head is ahead of and behind base, the commit subjects and the changed files with their status.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner": prop("string", "The owner of the repository"),
+				"repo":  prop("string", "The repository name"),
+				"base":  prop("string", "The base ref, e.g. main"),
+				"head":  prop("string", "The head ref, e.g. my-feature. Use owner:branch for a fork"),
+			},
+			"required": []string{"owner", "repo", "base", "head"},
+		},
+	}
+	CommitTools = []ToolDescription{
+		ListCommitsTool,
+		GetCommitTool,
+		CompareRefsTool,
+	}
+)
+
+func commitsListURL(owner, repo string, args map[string]interface{}) string {
+	baseURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/commits", owner, repo)
+	params := make([]string, 0)
+
+	// Optional parameters, `branch` is accepted as an alias of `sha`
+	if _, ok := args["sha"]; !ok {
+		if branch, ok := args["branch"].(string); ok && branch != "" {
+			params = append(params, fmt.Sprintf("sha=%s", url.QueryEscape(branch)))
+		}
+	}
+	for _, key := range []string{"sha", "path", "author", "since", "until"} {
+		if value, ok := args[key].(string); ok && value != "" {
+			params = append(params, fmt.Sprintf("%s=%s", key, url.QueryEscape(value)))
+		}
+	}
+
+	params = append(params, paginationParams(args)...)
+	return fmt.Sprintf("%s?%s", baseURL, strings.Join(params, "&"))
+}
+
+func commitsList(apiKey, owner, repo string, args map[string]interface{}) (CallToolResult, error) {
+	u := commitsListURL(owner, repo, args)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing commits: ", u))
+
+	var commits []PullRequestCommit
+	if _, err := githubGetJSON(apiKey, u, &commits); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list commits: %s", err)),
+			}},
+		}, nil
+	}
+
+	responseJSON, err := json.Marshal(summarizeCommits(commits, true))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}, nil
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}, nil
+}
+
+// Total number of patch bytes returned by gh-get-commit across all files.
+const commitPatchBudget = 30000
+
+type CommitStats struct {
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+	Total     int `json:"total"`
+}
+
+type CommitFile struct {
+	Filename       string `json:"filename"`
+	Status         string `json:"status"`
+	Additions      int    `json:"additions"`
+	Deletions      int    `json:"deletions"`
+	Changes        int    `json:"changes"`
+	PreviousName   string `json:"previous_filename,omitempty"`
+	Patch          string `json:"patch,omitempty"`
+	PatchTruncated bool   `json:"patch_truncated,omitempty"`
+}
+
+type CommitDetails struct {
+	Sha     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Author    Author `json:"author"`
+		Committer Author `json:"committer"`
+		Message   string `json:"message"`
+	} `json:"commit"`
+	Stats CommitStats  `json:"stats"`
+	Files []CommitFile `json:"files"`
+}
+
+type CommitDetailsResult struct {
+	Sha       string       `json:"sha"`
+	HTMLURL   string       `json:"html_url"`
+	Message   string       `json:"message"`
+	Author    Author       `json:"author"`
+	Committer Author       `json:"committer"`
+	Stats     CommitStats  `json:"stats"`
+	Files     []CommitFile `json:"files"`
+}
+
+// budgetPatches drops or cuts file patches so their combined size stays
+// within budget bytes. Files past the budget keep their metadata.
+func budgetPatches(files []CommitFile, includePatch bool, budget int) []CommitFile {
+	out := make([]CommitFile, 0, len(files))
+	remaining := budget
+	for _, f := range files {
+		switch {
+		case !includePatch:
+			f.Patch = ""
+		case len(f.Patch) > remaining:
+			f.Patch = f.Patch[:remaining]
+			f.PatchTruncated = true
+			remaining = 0
+		default:
+			remaining -= len(f.Patch)
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+func commitsGet(apiKey, owner, repo, sha string, includePatch bool) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/commits/%s", owner, repo, sha)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting commit: ", u))
+
+	var commit CommitDetails
+	if _, err := githubGetJSON(apiKey, u, &commit); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to get commit: %s", err)),
+			}},
+		}
+	}
+
+	result := CommitDetailsResult{
+		Sha:       commit.Sha,
+		HTMLURL:   commit.HTMLURL,
+		Message:   commit.Commit.Message,
+		Author:    commit.Commit.Author,
+		Committer: commit.Commit.Committer,
+		Stats:     commit.Stats,
+		Files:     budgetPatches(commit.Files, includePatch, commitPatchBudget),
+	}
+
+	responseJSON, err := json.Marshal(result)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+type Comparison struct {
+	Status       string              `json:"status"`
+	AheadBy      int                 `json:"ahead_by"`
+	BehindBy     int                 `json:"behind_by"`
+	TotalCommits int                 `json:"total_commits"`
+	HTMLURL      string              `json:"html_url"`
+	Commits      []PullRequestCommit `json:"commits"`
+	Files        []CommitFile        `json:"files"`
+}
+
+type ComparedCommit struct {
+	Sha     string `json:"sha"`
+	Subject string `json:"subject"`
+}
+
+type ComparedFile struct {
+	Filename string `json:"filename"`
+	Status   string `json:"status"`
+}
+
+type ComparisonSummary struct {
+	Status       string           `json:"status"`
+	AheadBy      int              `json:"ahead_by"`
+	BehindBy     int              `json:"behind_by"`
+	TotalCommits int              `json:"total_commits"`
+	HTMLURL      string           `json:"html_url"`
+	Commits      []ComparedCommit `json:"commits"`
+	Files        []ComparedFile   `json:"files"`
+}
+
+func summarizeComparison(c Comparison) ComparisonSummary {
+	summary := ComparisonSummary{
+		Status:       c.Status,
+		AheadBy:      c.AheadBy,
+		BehindBy:     c.BehindBy,
+		TotalCommits: c.TotalCommits,
+		HTMLURL:      c.HTMLURL,
+		Commits:      make([]ComparedCommit, 0, len(c.Commits)),
+		Files:        make([]ComparedFile, 0, len(c.Files)),
+	}
+	for _, commit := range c.Commits {
+		summary.Commits = append(summary.Commits, ComparedCommit{Sha: commit.Sha, Subject: firstLine(commit.Commit.Message)})
+	}
+	for _, file := range c.Files {
+		summary.Files = append(summary.Files, ComparedFile{Filename: file.Filename, Status: file.Status})
+	}
+	return summary
+}
+
+func commitsCompare(apiKey, owner, repo, base, head string) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/compare/%s...%s", owner, repo, base, head)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Comparing refs: ", u))
+
+	var comparison Comparison
+	status, err := githubGetJSON(apiKey, u, &comparison)
+	if status == 404 {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to compare %s...%s: one of the refs does not exist in %s/%s, or the two refs have no common history", base, head, owner, repo)),
+			}},
+		}
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to compare refs: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(summarizeComparison(comparison))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCommitsListURL(t *testing.T) {
+	tests := []struct {
+		args map[string]interface{}
+		want string
+	}{
+		{
+			map[string]interface{}{},
+			"https://api.github.com/repos/octo/repo/commits?per_page=30&page=1",
+		},
+		{
+			map[string]interface{}{"branch": "feature/x", "path": "src/main.go", "per_page": 500.0, "page": 2.0},
+			"https://api.github.com/repos/octo/repo/commits?sha=feature%2Fx&path=src%2Fmain.go&per_page=100&page=2",
+		},
+		{
+			map[string]interface{}{"sha": "abc123", "branch": "ignored", "author": "octocat", "since": "2025-01-01T00:00:00Z", "until": "2025-02-01T00:00:00Z"},
+			"https://api.github.com/repos/octo/repo/commits?sha=abc123&author=octocat&since=2025-01-01T00%3A00%3A00Z&until=2025-02-01T00%3A00%3A00Z&per_page=30&page=1",
+		},
+	}
+	for _, tt := range tests {
+		if got := commitsListURL("octo", "repo", tt.args); got != tt.want {
+			t.Errorf("commitsListURL(%v)\n got %s\nwant %s", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestSummarizeCommits(t *testing.T) {
+	var commits []PullRequestCommit
+	body := `[
+	  {"sha": "a1", "html_url": "https://github.com/octo/repo/commit/a1", "commit": {"author": {"name": "Mona", "date": "2025-01-01T00:00:00Z"}, "message": "Fix bug\n\nLonger description"}, "author": {"login": "mona"}},
+	  {"sha": "b2", "html_url": "https://github.com/octo/repo/commit/b2", "commit": {"author": {"name": "Unlinked", "date": "2025-01-02T00:00:00Z"}, "message": "Add feature"}, "author": null}
+	]`
+	if err := json.Unmarshal([]byte(body), &commits); err != nil {
+		t.Fatal(err)
+	}
+
+	got := summarizeCommits(commits, true)
+	want := []CommitSummary{
+		{Sha: "a1", Author: "mona", Date: "2025-01-01T00:00:00Z", Message: "Fix bug", HTMLURL: "https://github.com/octo/repo/commit/a1"},
+		{Sha: "b2", Author: "Unlinked", Date: "2025-01-02T00:00:00Z", Message: "Add feature", HTMLURL: "https://github.com/octo/repo/commit/b2"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("commit %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if summarizeCommits(commits, false)[0].HTMLURL != "" {
+		t.Fatal("expected html_url to be omitted")
+	}
+}
+
+func TestBudgetPatches(t *testing.T) {
+	files := []CommitFile{
+		{Filename: "a.go", Patch: "aaaa"},
+		{Filename: "b.go", Patch: "bbbbbb"},
+		{Filename: "c.go", Patch: "cc"},
+	}
+
+	got := budgetPatches(files, true, 7)
+	if got[0].Patch != "aaaa" || got[0].PatchTruncated {
+		t.Fatalf("first patch should fit: %+v", got[0])
+	}
+	if got[1].Patch != "bbb" || !got[1].PatchTruncated {
+		t.Fatalf("second patch should be cut to the remaining budget: %+v", got[1])
+	}
+	if got[2].Patch != "" || !got[2].PatchTruncated || got[2].Filename != "c.go" {
+		t.Fatalf("third patch should be dropped but keep its metadata: %+v", got[2])
+	}
+	if files[1].Patch != "bbbbbb" {
+		t.Fatal("input files must not be modified")
+	}
+
+	for _, f := range budgetPatches(files, false, 100) {
+		if f.Patch != "" || f.PatchTruncated {
+			t.Fatalf("patches should be omitted when include_patch is false: %+v", f)
+		}
+	}
+}
+
+func TestSummarizeComparison(t *testing.T) {
+	var comparison Comparison
+	err := json.Unmarshal([]byte(`{
+		"status": "diverged",
+		"ahead_by": 2,
+		"behind_by": 5,
+		"total_commits": 2,
+		"html_url": "https://github.com/o/r/compare/main...feature",
+		"commits": [
+			{"sha": "a1", "commit": {"message": "Add feature\n\nLong body"}},
+			{"sha": "b2", "commit": {"message": "Fix typo"}}
+		],
+		"files": [
+			{"filename": "main.go", "status": "modified", "patch": "@@ -1 +1 @@"},
+			{"filename": "old.go", "status": "removed"}
+		]
+	}`), &comparison)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := summarizeComparison(comparison)
+	if got.Status != "diverged" || got.AheadBy != 2 || got.BehindBy != 5 || got.TotalCommits != 2 {
+		t.Fatalf("unexpected counts: %+v", got)
+	}
+	if len(got.Commits) != 2 || got.Commits[0] != (ComparedCommit{Sha: "a1", Subject: "Add feature"}) {
+		t.Fatalf("unexpected commits: %+v", got.Commits)
+	}
+	if len(got.Files) != 2 || got.Files[1] != (ComparedFile{Filename: "old.go", Status: "removed"}) {
+		t.Fatalf("unexpected files: %+v", got.Files)
+	}
+}
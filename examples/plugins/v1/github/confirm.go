@@ -0,0 +1,359 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+// Destructive tools ask the user through the client's elicitation form
+// before they run. Clients that can't show forms make the host decline
+// with content {"unsupported": true}; only then does the optional confirm
+// argument let the call go ahead.
+var confirmProp = prop("boolean", "Set to true once the user has agreed, for clients that cannot show confirmation forms. Ignored when the client can ask the user itself.")
+
+// errElicitationUnsupported is what elicitation returns when the client
+// can't show forms.
+var errElicitationUnsupported = errors.New("the client cannot show confirmation forms")
+
+// destructiveCall is what a destructive tool call is about to do, for the
+// confirmation prompt. Consequence tells the user whether it can be undone.
+// An empty Description means the call won't destroy anything, e.g. an
+// upload without overwrite.
+type destructiveCall struct {
+	Description string
+	Consequence string
+}
+
+const (
+	irreversible      = "This cannot be undone."
+	restorableFromGit = "The old contents stay in the branch history and can be restored from an earlier commit."
+)
+
+// destructiveTools are the tools that delete or overwrite something, with
+// what a call is about to do. Call asks the user to confirm them before
+// dispatching and Describe declares their confirm argument, so a new
+// destructive tool only needs an entry here. gh-delete-repo is not listed:
+// it makes the user type the repository name (see confirmTyped). getJSON
+// fetches from the GitHub API for calls that need more than the arguments.
+var destructiveTools = map[string]func(args map[string]interface{}, getJSON func(u string, out interface{}) (uint16, error)) destructiveCall{
+	DeleteFileTool.Name: func(args map[string]interface{}, _ func(string, interface{}) (uint16, error)) destructiveCall {
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		path, _ := args["path"].(string)
+		if branch, _ := args["branch"].(string); branch != "" {
+			return destructiveCall{fmt.Sprintf("delete %s from branch %s of %s/%s", path, branch, owner, repo), restorableFromGit}
+		}
+		return destructiveCall{fmt.Sprintf("delete %s from %s/%s", path, owner, repo), restorableFromGit}
+	},
+	DeleteBranchTool.Name: func(args map[string]interface{}, _ func(string, interface{}) (uint16, error)) destructiveCall {
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		branch, _ := args["branch"].(string)
+		return destructiveCall{fmt.Sprintf("delete branch %s of %s/%s", branch, owner, repo), irreversible}
+	},
+	DeleteLabelTool.Name: func(args map[string]interface{}, _ func(string, interface{}) (uint16, error)) destructiveCall {
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		name, _ := args["name"].(string)
+		return destructiveCall{fmt.Sprintf("delete label %q of %s/%s and remove it from every issue and pull request", name, owner, repo), irreversible}
+	},
+	UploadReleaseAssetTool.Name: func(args map[string]interface{}, _ func(string, interface{}) (uint16, error)) destructiveCall {
+		if overwrite, _ := args["overwrite"].(bool); !overwrite {
+			return destructiveCall{}
+		}
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		name, _ := args["name"].(string)
+		return destructiveCall{fmt.Sprintf("replace the release asset %s of %s/%s if it already exists", name, owner, repo), irreversible}
+	},
+	CopyFileTool.Name: func(args map[string]interface{}, _ func(string, interface{}) (uint16, error)) destructiveCall {
+		if overwrite, _ := args["overwrite"].(bool); !overwrite {
+			return destructiveCall{}
+		}
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		branch, _ := args["branch"].(string)
+		return destructiveCall{fmt.Sprintf("replace any files the copy lands on in branch %s of %s/%s", branch, owner, repo), restorableFromGit}
+	},
+	SetActionsSecretTool.Name: func(args map[string]interface{}, getJSON func(string, interface{}) (uint16, error)) destructiveCall {
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		name, _ := args["name"].(string)
+		return describeSecretReplacement(owner, repo, name, getJSON)
+	},
+	RemoveCollaboratorTool.Name: func(args map[string]interface{}, _ func(string, interface{}) (uint16, error)) destructiveCall {
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		username, _ := args["username"].(string)
+		return destructiveCall{fmt.Sprintf("remove %s's access to %s/%s", username, owner, repo), "They can be invited again with gh-add-collaborator."}
+	},
+	DeleteRepoInvitationTool.Name: func(args map[string]interface{}, _ func(string, interface{}) (uint16, error)) destructiveCall {
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		id, _ := args["invitation_id"].(float64)
+		return destructiveCall{fmt.Sprintf("withdraw invitation %d to %s/%s", int(id), owner, repo), "A new invitation can be sent with gh-add-collaborator."}
+	},
+	DeleteDeployKeyTool.Name: func(args map[string]interface{}, _ func(string, interface{}) (uint16, error)) destructiveCall {
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		id, _ := args["key_id"].(float64)
+		return destructiveCall{fmt.Sprintf("delete deploy key %d of %s/%s", int(id), owner, repo), irreversible}
+	},
+	RemoveTeamRepoTool.Name: func(args map[string]interface{}, _ func(string, interface{}) (uint16, error)) destructiveCall {
+		org, _ := args["org"].(string)
+		slug, _ := args["team_slug"].(string)
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return destructiveCall{fmt.Sprintf("remove team %s/%s's access to %s/%s", org, slug, owner, repo), "The access can be given back with gh-add-team-repo."}
+	},
+	DeleteGistCommentTool.Name: func(args map[string]interface{}, _ func(string, interface{}) (uint16, error)) destructiveCall {
+		gistId, _ := args["gist_id"].(string)
+		id, _ := args["comment_id"].(float64)
+		return destructiveCall{fmt.Sprintf("delete comment %d on gist %s", int64(id), gistId), irreversible}
+	},
+	DeleteGistTool.Name: func(args map[string]interface{}, getJSON func(string, interface{}) (uint16, error)) destructiveCall {
+		gistId, _ := args["gist_id"].(string)
+		return destructiveCall{describeGistDeletion(gistId, getJSON), irreversible}
+	},
+}
+
+// confirmCall asks the user to confirm the call when the tool is
+// destructive. It returns nil to proceed, or the result to hand back.
+func confirmCall(name string, args map[string]interface{}, getJSON func(string, interface{}) (uint16, error), elicit func(destructiveCall) (string, error)) *CallToolResult {
+	describe, ok := destructiveTools[name]
+	if !ok {
+		return nil
+	}
+	call := describe(args, getJSON)
+	if call.Description == "" {
+		return nil
+	}
+	return confirmDestructive(args, call, elicit)
+}
+
+// withConfirmArg declares the confirm argument on a destructive tool. The
+// schema is copied, so the shared tool declarations are left alone.
+func withConfirmArg(tool ToolDescription) ToolDescription {
+	s, ok := tool.InputSchema.(schema)
+	if !ok {
+		return tool
+	}
+	properties, ok := s["properties"].(props)
+	if !ok {
+		return tool
+	}
+	withConfirm := props{"confirm": confirmProp}
+	for name, p := range properties {
+		withConfirm[name] = p
+	}
+	copied := schema{}
+	for key, value := range s {
+		copied[key] = value
+	}
+	copied["properties"] = withConfirm
+	tool.InputSchema = copied
+	return tool
+}
+
+//go:wasmimport extism:host/user create_elicitation
+func _createElicitation(uint64) uint64
+
+type elicitationRequest struct {
+	Message         string                 `json:"message"`
+	RequestedSchema map[string]interface{} `json:"requestedSchema"`
+}
+
+type elicitationResult struct {
+	Action  string                 `json:"action"`
+	Content map[string]interface{} `json:"content,omitempty"`
+}
+
+// sendElicitation sends the request to the client, and fails with
+// errElicitationUnsupported when the client can't show it.
+func sendElicitation(request elicitationRequest) (elicitationResult, error) {
+	mem, err := pdk.AllocateJSON(request)
+	if err != nil {
+		return elicitationResult{}, err
+	}
+
+	var out elicitationResult
+	if err := pdk.JSONFrom(_createElicitation(mem.Offset()), &out); err != nil {
+		return elicitationResult{}, err
+	}
+	if unsupported, _ := out.Content["unsupported"].(bool); unsupported && out.Action == "decline" {
+		return elicitationResult{}, errElicitationUnsupported
+	}
+	return out, nil
+}
+
+// elicitConfirmation asks the user to confirm the call and returns the
+// elicitation action ("accept", "decline" or "cancel").
+func elicitConfirmation(call destructiveCall) (string, error) {
+	out, err := sendElicitation(elicitationRequest{
+		Message: fmt.Sprintf("Confirm: %s. %s", call.Description, call.Consequence),
+		RequestedSchema: schema{
+			"type": "object",
+			"properties": schema{
+				"confirm": schema{
+					"type":        "boolean",
+					"title":       "Proceed",
+					"description": call.Description,
+				},
+			},
+			"required": []string{"confirm"},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if out.Action == "accept" {
+		if confirmed, _ := out.Content["confirm"].(bool); !confirmed {
+			return "decline", nil
+		}
+	}
+	return out.Action, nil
+}
+
+// confirmDestructive decides whether the call may run. The user is always
+// asked; confirm: true only stands in for them when the client can't ask.
+// It returns nil to proceed, or the result to hand back to the caller.
+func confirmDestructive(args map[string]interface{}, call destructiveCall, elicit func(destructiveCall) (string, error)) *CallToolResult {
+	action, err := elicit(call)
+	if err == nil && action == "accept" {
+		return nil
+	}
+	description := call.Description
+	unsupported := errors.Is(err, errElicitationUnsupported)
+	if confirmed, _ := args["confirm"].(bool); confirmed && unsupported {
+		return nil
+	}
+
+	message := fmt.Sprintf("Refusing to %s: the user did not confirm it.", description)
+	switch {
+	case unsupported:
+		message = fmt.Sprintf("Refusing to %s: the client cannot ask the user to confirm it. Ask the user, and if they agree call the tool again with confirm: true.", description)
+	case err != nil:
+		message = fmt.Sprintf("Refusing to %s: it could not be confirmed (%s).", description, err)
+	case action == "cancel":
+		message = fmt.Sprintf("Refusing to %s: the user cancelled it.", description)
+	}
+	return &CallToolResult{
+		IsError: some(true),
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(message),
+		}},
+	}
+}
+
+// elicitTypedConfirmation asks the user to type expected to confirm the
+// described action, and returns the elicitation action with what was typed.
+func elicitTypedConfirmation(description, expected string) (string, string, error) {
+	out, err := sendElicitation(elicitationRequest{
+		Message: fmt.Sprintf("Confirm: %s. This cannot be undone. Type %s to proceed.", description, expected),
+		RequestedSchema: schema{
+			"type": "object",
+			"properties": schema{
+				"name": schema{
+					"type":        "string",
+					"title":       fmt.Sprintf("Type %s", expected),
+					"description": description,
+				},
+			},
+			"required": []string{"name"},
+		},
+	})
+	if err != nil {
+		return "", "", err
+	}
+	typed, _ := out.Content["name"].(string)
+	return out.Action, typed, nil
+}
+
+// confirmTyped decides whether an irreversible action may run. Unlike
+// confirmDestructive there is no confirm argument: the user must accept and
+// type expected exactly. It returns nil to proceed, or the result to hand
+// back to the caller.
+func confirmTyped(description, expected string, elicit func(string, string) (string, string, error)) *CallToolResult {
+	action, typed, err := elicit(description, expected)
+	if err == nil && action == "accept" && strings.TrimSpace(typed) == expected {
+		return nil
+	}
+
+	reason := "was declined"
+	switch {
+	case errors.Is(err, errElicitationUnsupported):
+		reason = "could not be asked for because the client can't show confirmation forms"
+	case err != nil:
+		reason = fmt.Sprintf("could not be asked for (%s)", err)
+	case action == "cancel":
+		reason = "was cancelled"
+	case action == "accept":
+		reason = fmt.Sprintf("was not confirmed: %q was typed instead of %s", typed, expected)
+	}
+	return &CallToolResult{
+		IsError: some(true),
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(fmt.Sprintf("Refusing to %s: confirmation %s.", description, reason)),
+		}},
+	}
+}
+
+// describeGistDeletion resolves a gist id into something a user can
+// recognise in a confirmation prompt.
+func describeGistDeletion(gistId string, getJSON func(string, interface{}) (uint16, error)) string {
+	var gist struct {
+		Description string                     `json:"description"`
+		Files       map[string]json.RawMessage `json:"files"`
+		Owner       struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	}
+	u := fmt.Sprintf("https://api.github.com/gists/%s", gistId)
+	if _, err := getJSON(u, &gist); err != nil {
+		return fmt.Sprintf("delete gist %s", gistId)
+	}
+
+	names := make([]string, 0, len(gist.Files))
+	for name := range gist.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	description := fmt.Sprintf("delete gist %s owned by %s", gistId, gist.Owner.Login)
+	if gist.Description != "" {
+		description += fmt.Sprintf(" (%q)", gist.Description)
+	}
+	if len(names) > 0 {
+		description += " containing " + strings.Join(names, ", ")
+	}
+	return description
+}
+
+// describeSecretReplacement asks only when the secret already exists, since
+// setting a new one destroys nothing. When the lookup fails for any other
+// reason the user is asked anyway.
+func describeSecretReplacement(owner, repo, name string, getJSON func(string, interface{}) (uint16, error)) destructiveCall {
+	var secret struct {
+		UpdatedAt string `json:"updated_at"`
+	}
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/secrets/%s", owner, repo, name)
+	status, err := getJSON(u, &secret)
+	if status == 404 {
+		return destructiveCall{}
+	}
+	description := fmt.Sprintf("replace the Actions secret %s of %s/%s if it already exists", name, owner, repo)
+	if err == nil {
+		description = fmt.Sprintf("replace the Actions secret %s of %s/%s", name, owner, repo)
+		if secret.UpdatedAt != "" {
+			description += fmt.Sprintf(" (last updated %s)", secret.UpdatedAt)
+		}
+	}
+	return destructiveCall{description, "Secrets can't be read back, so the old value is lost unless you have it elsewhere."}
+}
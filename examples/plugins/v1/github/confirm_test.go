@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestConfirmDestructive(t *testing.T) {
+	elicitWith := func(action string, err error) func(destructiveCall) (string, error) {
+		return func(destructiveCall) (string, error) { return action, err }
+	}
+	confirmed := map[string]interface{}{"confirm": true}
+	call := destructiveCall{"delete gist 1", irreversible}
+
+	if got := confirmDestructive(map[string]interface{}{}, call, elicitWith("accept", nil)); got != nil {
+		t.Fatalf("accepted elicitation should proceed, got %+v", got)
+	}
+	if got := confirmDestructive(confirmed, call, elicitWith("", errElicitationUnsupported)); got != nil {
+		t.Fatalf("confirm: true should proceed when the client can't elicit, got %+v", got)
+	}
+
+	cases := []struct {
+		name   string
+		args   map[string]interface{}
+		elicit func(destructiveCall) (string, error)
+		want   string
+	}{
+		{"decline", map[string]interface{}{}, elicitWith("decline", nil), "the user did not confirm it"},
+		{"decline despite confirm", confirmed, elicitWith("decline", nil), "the user did not confirm it"},
+		{"cancel despite confirm", confirmed, elicitWith("cancel", nil), "the user cancelled it"},
+		{"unsupported", map[string]interface{}{}, elicitWith("", errElicitationUnsupported), "call the tool again with confirm: true"},
+		{"confirm false", map[string]interface{}{"confirm": false}, elicitWith("", errElicitationUnsupported), "call the tool again with confirm: true"},
+		{"elicitation error", confirmed, elicitWith("", errors.New("no peer available")), "could not be confirmed (no peer available)"},
+	}
+	for _, tc := range cases {
+		got := confirmDestructive(tc.args, call, tc.elicit)
+		if got == nil || got.IsError == nil || !*got.IsError {
+			t.Fatalf("%s: expected an error result, got %+v", tc.name, got)
+		}
+		text := *got.Content[0].Text
+		if !strings.Contains(text, tc.want) || !strings.Contains(text, "Refusing to delete gist 1") {
+			t.Errorf("%s: unexpected message %q", tc.name, text)
+		}
+	}
+}
+
+func TestConfirmCall(t *testing.T) {
+	noFetch := func(u string, _ interface{}) (uint16, error) { return 0, fmt.Errorf("unexpected fetch of %s", u) }
+	asked, consequence := "", ""
+	elicit := func(call destructiveCall) (string, error) {
+		asked, consequence = call.Description, call.Consequence
+		return "decline", nil
+	}
+
+	args := map[string]interface{}{"owner": "acme", "repo": "api", "path": "go.mod", "message": "Drop go.mod"}
+	if got := confirmCall(DeleteFileTool.Name, args, noFetch, elicit); got == nil || asked != "delete go.mod from acme/api" {
+		t.Errorf("gh-delete-file not gated: asked %q, got %+v", asked, got)
+	}
+
+	asked = ""
+	upload := map[string]interface{}{"owner": "acme", "repo": "api", "name": "app.tar.gz", "content": "x"}
+	if got := confirmCall(UploadReleaseAssetTool.Name, upload, noFetch, elicit); got != nil || asked != "" {
+		t.Errorf("upload without overwrite asked %q", asked)
+	}
+	upload["overwrite"] = true
+	if got := confirmCall(UploadReleaseAssetTool.Name, upload, noFetch, elicit); got == nil || !strings.Contains(asked, "replace the release asset app.tar.gz") {
+		t.Errorf("overwriting upload not gated: asked %q", asked)
+	}
+
+	asked = ""
+	copyArgs := map[string]interface{}{"source_owner": "acme", "source_repo": "lib", "source_path": "docs", "owner": "acme", "repo": "api", "branch": "main", "message": "Copy docs"}
+	if got := confirmCall(CopyFileTool.Name, copyArgs, noFetch, elicit); got != nil || asked != "" {
+		t.Errorf("copy without overwrite asked %q", asked)
+	}
+	copyArgs["overwrite"] = true
+	if got := confirmCall(CopyFileTool.Name, copyArgs, noFetch, elicit); got == nil || !strings.Contains(asked, "branch main of acme/api") || consequence != restorableFromGit {
+		t.Errorf("overwriting copy not gated: asked %q (%q)", asked, consequence)
+	}
+
+	gist := func(u string, out interface{}) (uint16, error) {
+		if u != "https://api.github.com/gists/abc" {
+			return 0, fmt.Errorf("unexpected fetch of %s", u)
+		}
+		return 200, json.Unmarshal([]byte(`{"description":"notes","owner":{"login":"octocat"},"files":{"b.md":{},"a.md":{}}}`), out)
+	}
+	confirmCall(DeleteGistTool.Name, map[string]interface{}{"gist_id": "abc"}, gist, elicit)
+	if want := `delete gist abc owned by octocat ("notes") containing a.md, b.md`; asked != want || consequence != irreversible {
+		t.Errorf("gist deletion asked %q (%q), want %q", asked, consequence, want)
+	}
+
+	confirmCall(RemoveCollaboratorTool.Name, map[string]interface{}{"owner": "acme", "repo": "api", "username": "octocat"}, noFetch, elicit)
+	if consequence == irreversible || !strings.Contains(consequence, "invited again") {
+		t.Errorf("removing a collaborator claims %q", consequence)
+	}
+
+	asked = ""
+	if got := confirmCall(ListLabelsTool.Name, map[string]interface{}{}, noFetch, elicit); got != nil || asked != "" {
+		t.Errorf("non-destructive tool asked %q", asked)
+	}
+}
+
+func TestConfirmCallSecret(t *testing.T) {
+	asked := ""
+	elicit := func(call destructiveCall) (string, error) {
+		asked = call.Description
+		return "decline", nil
+	}
+	secretAt := func(status uint16, body string) func(string, interface{}) (uint16, error) {
+		return func(u string, out interface{}) (uint16, error) {
+			if u != "https://api.github.com/repos/acme/api/actions/secrets/TOKEN" {
+				return 0, fmt.Errorf("unexpected fetch of %s", u)
+			}
+			if status != 200 {
+				return status, fmt.Errorf("%d %s", status, body)
+			}
+			return status, json.Unmarshal([]byte(body), out)
+		}
+	}
+	args := map[string]interface{}{"owner": "acme", "repo": "api", "name": "TOKEN", "value": "s3cret"}
+
+	if got := confirmCall(SetActionsSecretTool.Name, args, secretAt(404, "Not Found"), elicit); got != nil || asked != "" {
+		t.Errorf("creating a new secret asked %q", asked)
+	}
+	if got := confirmCall(SetActionsSecretTool.Name, args, secretAt(200, `{"name":"TOKEN","updated_at":"2024-01-02T03:04:05Z"}`), elicit); got == nil {
+		t.Error("replacing an existing secret not gated")
+	}
+	if want := "replace the Actions secret TOKEN of acme/api (last updated 2024-01-02T03:04:05Z)"; asked != want {
+		t.Errorf("asked %q, want %q", asked, want)
+	}
+	asked = ""
+	if got := confirmCall(SetActionsSecretTool.Name, args, secretAt(403, "Resource not accessible"), elicit); got == nil || !strings.Contains(asked, "if it already exists") {
+		t.Errorf("failed lookup should still ask, asked %q", asked)
+	}
+}
+
+func TestDestructiveToolsDeclareConfirm(t *testing.T) {
+	described, _ := Describe()
+	declared := map[string]ToolDescription{}
+	for _, tool := range described.Tools {
+		declared[tool.Name] = tool
+	}
+	for name := range destructiveTools {
+		tool, ok := declared[name]
+		if !ok {
+			t.Errorf("%s is listed as destructive but not declared", name)
+			continue
+		}
+		if _, ok := tool.InputSchema.(schema)["properties"].(props)["confirm"]; !ok {
+			t.Errorf("%s doesn't declare confirm", name)
+		}
+	}
+	for _, name := range []string{CopyFileTool.Name, SetActionsSecretTool.Name} {
+		if _, ok := destructiveTools[name]; !ok {
+			t.Errorf("%s overwrites and should be listed as destructive", name)
+		}
+	}
+	if _, ok := ListLabelsTool.InputSchema.(schema)["properties"].(props)["confirm"]; ok {
+		t.Error("confirm added to a tool that isn't destructive")
+	}
+	if _, ok := DeleteFileTool.InputSchema.(schema)["properties"].(props)["confirm"]; ok {
+		t.Error("Describe changed the shared gh-delete-file declaration")
+	}
+}
+
+func TestConfirmTyped(t *testing.T) {
+	elicitWith := func(action, typed string, err error) func(string, string) (string, string, error) {
+		return func(string, string) (string, string, error) { return action, typed, err }
+	}
+
+	if got := confirmTyped("delete acme/api", "acme/api", elicitWith("accept", " acme/api ", nil)); got != nil {
+		t.Fatalf("typing the name should proceed, got %+v", got)
+	}
+
+	cases := []struct {
+		name   string
+		elicit func(string, string) (string, string, error)
+		want   string
+	}{
+		{"wrong name", elicitWith("accept", "acme/ap", nil), `"acme/ap" was typed instead of acme/api`},
+		{"decline", elicitWith("decline", "", nil), "was declined"},
+		{"cancel", elicitWith("cancel", "", nil), "was cancelled"},
+		{"unsupported", elicitWith("", "", errElicitationUnsupported), "client can't show confirmation forms"},
+		{"unavailable", elicitWith("", "", errors.New("no peer available")), "could not be asked for (no peer available)"},
+	}
+	for _, tc := range cases {
+		got := confirmTyped("delete acme/api", "acme/api", tc.elicit)
+		if got == nil || got.IsError == nil || !*got.IsError {
+			t.Fatalf("%s: expected an error result, got %+v", tc.name, got)
+		}
+		text := *got.Content[0].Text
+		if !strings.Contains(text, tc.want) || !strings.Contains(text, "Refusing to delete acme/api") {
+			t.Errorf("%s: unexpected message %q", tc.name, text)
+		}
+	}
+}
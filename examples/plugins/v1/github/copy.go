@@ -0,0 +1,419 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	CopyFileTool = ToolDescription{
+		Name:        "gh-copy-file",
+		Description: "Copy a file, or a directory with everything under it, from one repository and ref to a branch of another (or the same) repository in a single commit. Paths are kept as they are in the source unless dest_prefix is given. Existing files at the destination are only replaced when overwrite is set.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"source_owner": prop("string", "The owner of the repository to copy from"),
+				"source_repo":  prop("string", "The repository to copy from"),
+				"source_path":  prop("string", "The file or directory to copy"),
+				"source_ref":   prop("string", "Branch, tag or commit sha to copy from (defaults to the default branch)"),
+				"owner":        prop("string", "The owner of the repository to copy to"),
+				"repo":         prop("string", "The repository to copy to"),
+				"branch":       prop("string", "The branch to commit the copy to"),
+				"dest_prefix":  prop("string", "Directory to put the copied paths under, e.g. vendor/upstream turns docs/a.md into vendor/upstream/docs/a.md"),
+				"message":      prop("string", "The commit message"),
+				"overwrite":    prop("boolean", "Replace files that already exist at the destination (default false)"),
+				"max_files":    prop("integer", fmt.Sprintf("Refuse to copy a directory with more files than this (default %d, max %d)", copyDefaultMaxFiles, copyMaxFiles)),
+			},
+			"required": []string{"source_owner", "source_repo", "source_path", "owner", "repo", "branch", "message"},
+		},
+	}
+)
+
+const (
+	copyDefaultMaxFiles = 100
+	copyMaxFiles        = 1000
+)
+
+type copyRequest struct {
+	SourceOwner string
+	SourceRepo  string
+	SourcePath  string
+	SourceRef   string
+	Owner       string
+	Repo        string
+	Branch      string
+	DestPrefix  string
+	Message     string
+	Overwrite   bool
+	MaxFiles    int
+}
+
+func copyRequestFromArgs(args map[string]interface{}) (copyRequest, error) {
+	req := copyRequest{MaxFiles: copyDefaultMaxFiles}
+	req.SourceOwner, _ = args["source_owner"].(string)
+	req.SourceRepo, _ = args["source_repo"].(string)
+	req.SourcePath, _ = args["source_path"].(string)
+	req.SourceRef, _ = args["source_ref"].(string)
+	req.Owner, _ = args["owner"].(string)
+	req.Repo, _ = args["repo"].(string)
+	req.Branch, _ = args["branch"].(string)
+	req.DestPrefix, _ = args["dest_prefix"].(string)
+	req.Message, _ = args["message"].(string)
+	req.Overwrite, _ = args["overwrite"].(bool)
+	if value, ok := args["max_files"].(float64); ok {
+		req.MaxFiles = int(value)
+	}
+
+	req.SourcePath = normalizeTreePrefix(req.SourcePath)
+	req.DestPrefix = normalizeTreePrefix(req.DestPrefix)
+	missing := []string{}
+	for _, f := range []struct{ name, value string }{
+		{"source_owner", req.SourceOwner},
+		{"source_repo", req.SourceRepo},
+		{"source_path", req.SourcePath},
+		{"owner", req.Owner},
+		{"repo", req.Repo},
+		{"branch", req.Branch},
+		{"message", req.Message},
+	} {
+		if strings.TrimSpace(f.value) == "" {
+			missing = append(missing, f.name)
+		}
+	}
+	if len(missing) > 0 {
+		return req, fmt.Errorf("%s required", strings.Join(missing, ", "))
+	}
+	if req.MaxFiles < 1 || req.MaxFiles > copyMaxFiles {
+		return req, fmt.Errorf("max_files must be between 1 and %d", copyMaxFiles)
+	}
+	return req, nil
+}
+
+// sameRepo reports whether source and destination are one repository, in
+// which case blobs don't need to be uploaded again.
+func (r copyRequest) sameRepo() bool {
+	return strings.EqualFold(r.SourceOwner, r.Owner) && strings.EqualFold(r.SourceRepo, r.Repo)
+}
+
+func (r copyRequest) destPath(sourcePath string) string {
+	if r.DestPrefix == "" {
+		return sourcePath
+	}
+	return r.DestPrefix + "/" + sourcePath
+}
+
+type CopiedFile struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type CopyResult struct {
+	Commit  string       `json:"commit"`
+	Branch  string       `json:"branch"`
+	Copied  []CopiedFile `json:"copied"`
+	Skipped []string     `json:"skipped,omitempty"`
+}
+
+type copyEntry struct {
+	From string
+	To   string
+	Mode string
+	Sha  string
+}
+
+// copyPlan picks the files under the source directory from its tree.
+// Submodules can't be copied into another repository and are reported as
+// skipped; directories are implied by the file paths.
+func copyPlan(req copyRequest, tree GitTree) ([]copyEntry, []string, error) {
+	if tree.Truncated {
+		return nil, nil, fmt.Errorf("the source tree is too large for GitHub to list in one response; copy a smaller directory")
+	}
+	var plan []copyEntry
+	var skipped []string
+	for _, e := range tree.Tree {
+		if !strings.HasPrefix(e.Path, req.SourcePath+"/") {
+			continue
+		}
+		switch e.Type {
+		case "blob":
+			plan = append(plan, copyEntry{From: e.Path, To: req.destPath(e.Path), Mode: e.Mode, Sha: e.Sha})
+		case "commit":
+			skipped = append(skipped, e.Path)
+		}
+	}
+	if len(plan) == 0 {
+		return nil, nil, fmt.Errorf("no files under %s", req.SourcePath)
+	}
+	if len(plan) > req.MaxFiles {
+		return nil, nil, fmt.Errorf("%s has %d files, more than max_files %d", req.SourcePath, len(plan), req.MaxFiles)
+	}
+	return plan, skipped, nil
+}
+
+// copyConflicts lists the destination paths that already hold a file.
+func copyConflicts(plan []copyEntry, existing GitTree) []string {
+	files := map[string]bool{}
+	for _, e := range existing.Tree {
+		if e.Type == "blob" {
+			files[e.Path] = true
+		}
+	}
+	var conflicts []string
+	for _, e := range plan {
+		if files[e.To] {
+			conflicts = append(conflicts, e.To)
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+func conflictError(conflicts []string) error {
+	shown := conflicts
+	if len(shown) > 10 {
+		shown = shown[:10]
+	}
+	more := ""
+	if len(conflicts) > len(shown) {
+		more = fmt.Sprintf(" and %d more", len(conflicts)-len(shown))
+	}
+	verb := "exist"
+	if len(conflicts) == 1 {
+		verb = "exists"
+	}
+	return fmt.Errorf("%s%s already %s at the destination; set overwrite to replace them", strings.Join(shown, ", "), more, verb)
+}
+
+// rawBase64 strips the line breaks GitHub wraps base64 content with and
+// checks what is left decodes. The content is passed on still encoded, so
+// binary files are copied byte for byte.
+func rawBase64(content string) (string, error) {
+	content = strings.NewReplacer("\n", "", "\r", "").Replace(content)
+	if _, err := base64.StdEncoding.DecodeString(content); err != nil {
+		return "", fmt.Errorf("invalid base64 content: %w", err)
+	}
+	return content, nil
+}
+
+func copyFile(apiKey string, args map[string]interface{}) CallToolResult {
+	req, err := copyRequestFromArgs(args)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid copy: %s", err)),
+			}},
+		}
+	}
+
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/contents/%s", req.SourceOwner, req.SourceRepo, req.SourcePath)
+	if req.SourceRef != "" {
+		u += "?ref=" + url.QueryEscape(req.SourceRef)
+	}
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting copy source: ", u))
+	var source json.RawMessage
+	if _, err := githubGetJSON(apiKey, u, &source); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to get %s: %s", req.SourcePath, err)),
+			}},
+		}
+	}
+
+	var result CopyResult
+	if bytes.HasPrefix(bytes.TrimSpace(source), []byte("[")) {
+		result, err = copyDirectory(apiKey, req)
+	} else {
+		result, err = copySingleFile(apiKey, req, source)
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to copy %s: %s", req.SourcePath, err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(result)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+// copySingleFile copies one file with the contents API.
+func copySingleFile(apiKey string, req copyRequest, source json.RawMessage) (CopyResult, error) {
+	var file FileContent
+	if err := json.Unmarshal(source, &file); err != nil {
+		return CopyResult{}, err
+	}
+	if file.Type != "file" {
+		return CopyResult{}, fmt.Errorf("%s is a %s, not a file or directory", req.SourcePath, file.Type)
+	}
+
+	content := file.Content
+	if content == "" && file.Size > 0 {
+		// Files over 1 MB come without content; the blob API still has it
+		blob, err := gitBlob(apiKey, req.SourceOwner, req.SourceRepo, file.Sha)
+		if err != nil {
+			return CopyResult{}, err
+		}
+		content = blob
+	}
+	content, err := rawBase64(content)
+	if err != nil {
+		return CopyResult{}, err
+	}
+
+	dest := req.destPath(file.Path)
+	destURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/contents/%s", req.Owner, req.Repo, dest)
+	body := FileCreate{Content: content, Message: req.Message, Branch: req.Branch}
+
+	var existing FileContent
+	status, err := githubGetJSON(apiKey, destURL+"?ref="+url.QueryEscape(req.Branch), &existing)
+	switch {
+	case err == nil && !req.Overwrite:
+		return CopyResult{}, conflictError([]string{dest})
+	case err == nil && existing.Type != "file":
+		return CopyResult{}, fmt.Errorf("%s is a %s at the destination", dest, existing.Type)
+	case err == nil:
+		body.Sha = some(existing.Sha)
+	case status != 404:
+		return CopyResult{}, fmt.Errorf("checking %s at the destination: %s", dest, err)
+	}
+
+	want := uint16(201)
+	if body.Sha != nil {
+		want = 200
+	}
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Copying file to: ", destURL))
+	var written struct {
+		Commit struct {
+			Sha string `json:"sha"`
+		} `json:"commit"`
+	}
+	if err := githubSendJSON(apiKey, pdk.MethodPut, destURL, body, want, &written); err != nil {
+		return CopyResult{}, fmt.Errorf("writing %s: %s", dest, err)
+	}
+	return CopyResult{
+		Commit: written.Commit.Sha,
+		Branch: req.Branch,
+		Copied: []CopiedFile{{From: file.Path, To: dest}},
+	}, nil
+}
+
+func gitBlob(apiKey, owner, repo, sha string) (string, error) {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/blobs/%s", owner, repo, sha)
+	var blob struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	if _, err := githubGetJSON(apiKey, u, &blob); err != nil {
+		return "", fmt.Errorf("reading blob %s: %s", sha, err)
+	}
+	if blob.Encoding != "base64" {
+		return "", fmt.Errorf("blob %s has unexpected encoding %q", sha, blob.Encoding)
+	}
+	return blob.Content, nil
+}
+
+// copyDirectory copies a subtree with the git data API: the blobs are
+// uploaded to the destination (unless it is the same repository), then one
+// tree and one commit are created on top of the branch.
+func copyDirectory(apiKey string, req copyRequest) (CopyResult, error) {
+	sourceRef := req.SourceRef
+	if sourceRef == "" {
+		sourceRef = "HEAD"
+	}
+	var sourceTree GitTree
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/trees/%s?recursive=1", req.SourceOwner, req.SourceRepo, url.PathEscape(sourceRef))
+	if _, err := githubGetJSON(apiKey, u, &sourceTree); err != nil {
+		return CopyResult{}, fmt.Errorf("listing the source: %s", err)
+	}
+	plan, skipped, err := copyPlan(req, sourceTree)
+	if err != nil {
+		return CopyResult{}, err
+	}
+
+	head, err := branchGetSha(apiKey, req.Owner, req.Repo, req.Branch)
+	if err != nil {
+		return CopyResult{}, fmt.Errorf("branch %s: %s", req.Branch, err)
+	}
+	var destTree GitTree
+	u = fmt.Sprintf("https://api.github.com/repos/%s/%s/git/trees/%s?recursive=1", req.Owner, req.Repo, head)
+	if _, err := githubGetJSON(apiKey, u, &destTree); err != nil {
+		return CopyResult{}, fmt.Errorf("listing the destination: %s", err)
+	}
+	if !req.Overwrite {
+		if destTree.Truncated {
+			return CopyResult{}, fmt.Errorf("the destination tree is too large to check for existing files; set overwrite to copy anyway")
+		}
+		if conflicts := copyConflicts(plan, destTree); len(conflicts) > 0 {
+			return CopyResult{}, conflictError(conflicts)
+		}
+	}
+
+	tree := TreeSchema{BaseTree: destTree.Sha, Tree: []TreeEntry{}}
+	result := CopyResult{Branch: req.Branch, Copied: []CopiedFile{}, Skipped: skipped}
+	for _, e := range plan {
+		sha := e.Sha
+		if !req.sameRepo() {
+			content, err := gitBlob(apiKey, req.SourceOwner, req.SourceRepo, e.Sha)
+			if err == nil {
+				content, err = rawBase64(content)
+			}
+			if err != nil {
+				return CopyResult{}, err
+			}
+			var created struct {
+				Sha string `json:"sha"`
+			}
+			blobURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/blobs", req.Owner, req.Repo)
+			if err := githubSendJSON(apiKey, pdk.MethodPost, blobURL, map[string]string{"content": content, "encoding": "base64"}, 201, &created); err != nil {
+				return CopyResult{}, fmt.Errorf("uploading %s: %s", e.From, err)
+			}
+			sha = created.Sha
+		}
+		tree.Tree = append(tree.Tree, TreeEntry{Path: e.To, Mode: e.Mode, Type: "blob", Sha: sha})
+		result.Copied = append(result.Copied, CopiedFile{From: e.From, To: e.To})
+	}
+
+	var created TreeSchema
+	treeURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/trees", req.Owner, req.Repo)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Creating tree: ", treeURL))
+	if err := githubSendJSON(apiKey, pdk.MethodPost, treeURL, tree, 201, &created); err != nil {
+		return CopyResult{}, fmt.Errorf("creating tree: %s", err)
+	}
+	commit, err := createCommit(apiKey, req.Owner, req.Repo, req.Message, created.Sha, []string{head})
+	if err != nil {
+		return CopyResult{}, err
+	}
+	if ref := updateRef(apiKey, req.Owner, req.Repo, "heads/"+req.Branch, commit.Sha); ref.IsError != nil && *ref.IsError {
+		return CopyResult{}, fmt.Errorf("created commit %s but could not move %s to it: %s", commit.Sha, req.Branch, *ref.Content[0].Text)
+	}
+	result.Commit = commit.Sha
+	return result, nil
+}
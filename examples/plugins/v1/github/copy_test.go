@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCopyRequestFromArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		wantErr string
+	}{
+		{name: "minimal", args: `{"source_owner":"acme","source_repo":"api","source_path":"docs","owner":"acme","repo":"web","branch":"main","message":"Copy docs"}`},
+		{name: "missing fields", args: `{"source_owner":"acme","source_path":"/","owner":"acme"}`, wantErr: "source_repo, source_path, repo, branch, message required"},
+		{name: "max_files too high", args: `{"source_owner":"acme","source_repo":"api","source_path":"docs","owner":"acme","repo":"web","branch":"main","message":"m","max_files":5000}`, wantErr: "max_files must be between 1 and 1000"},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		req, err := copyRequestFromArgs(args)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error %v, want one containing %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || req.MaxFiles != copyDefaultMaxFiles || req.Overwrite {
+			t.Errorf("%s: %+v, %v", tt.name, req, err)
+		}
+	}
+}
+
+const copyTreeFixture = `{
+	"sha": "root",
+	"truncated": false,
+	"tree": [
+		{"path": "README.md", "mode": "100644", "type": "blob", "sha": "a1"},
+		{"path": "docs", "mode": "040000", "type": "tree", "sha": "b0"},
+		{"path": "docs/guide.md", "mode": "100644", "type": "blob", "sha": "b1"},
+		{"path": "docs/img", "mode": "040000", "type": "tree", "sha": "b2"},
+		{"path": "docs/img/logo.png", "mode": "100644", "type": "blob", "sha": "b3"},
+		{"path": "docs/build.sh", "mode": "100755", "type": "blob", "sha": "b4"},
+		{"path": "docs/theme", "mode": "160000", "type": "commit", "sha": "b5"},
+		{"path": "docsite/index.html", "mode": "100644", "type": "blob", "sha": "c1"}
+	]
+}`
+
+func TestCopyPlan(t *testing.T) {
+	var tree GitTree
+	if err := json.Unmarshal([]byte(copyTreeFixture), &tree); err != nil {
+		t.Fatal(err)
+	}
+
+	req := copyRequest{SourcePath: "docs", DestPrefix: "vendor/upstream", MaxFiles: 10}
+	plan, skipped, err := copyPlan(req, tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []copyEntry{
+		{From: "docs/guide.md", To: "vendor/upstream/docs/guide.md", Mode: "100644", Sha: "b1"},
+		{From: "docs/img/logo.png", To: "vendor/upstream/docs/img/logo.png", Mode: "100644", Sha: "b3"},
+		{From: "docs/build.sh", To: "vendor/upstream/docs/build.sh", Mode: "100755", Sha: "b4"},
+	}
+	if len(plan) != len(want) {
+		t.Fatalf("plan %+v, want %+v", plan, want)
+	}
+	for i := range want {
+		if plan[i] != want[i] {
+			t.Errorf("entry %d: %+v, want %+v", i, plan[i], want[i])
+		}
+	}
+	if len(skipped) != 1 || skipped[0] != "docs/theme" {
+		t.Errorf("skipped %v, want the submodule", skipped)
+	}
+
+	req.MaxFiles = 2
+	if _, _, err := copyPlan(req, tree); err == nil || !strings.Contains(err.Error(), "docs has 3 files, more than max_files 2") {
+		t.Errorf("cap: %v", err)
+	}
+	req.SourcePath = "doc"
+	if _, _, err := copyPlan(req, tree); err == nil || !strings.Contains(err.Error(), "no files under doc") {
+		t.Errorf("empty: %v", err)
+	}
+	tree.Truncated = true
+	if _, _, err := copyPlan(copyRequest{SourcePath: "docs", MaxFiles: 10}, tree); err == nil {
+		t.Error("truncated source tree accepted")
+	}
+}
+
+func TestCopyConflicts(t *testing.T) {
+	var existing GitTree
+	if err := json.Unmarshal([]byte(copyTreeFixture), &existing); err != nil {
+		t.Fatal(err)
+	}
+	plan := []copyEntry{
+		{From: "docs/guide.md", To: "docs/guide.md"},
+		{From: "docs/new.md", To: "docs/new.md"},
+		{From: "docs/img", To: "docs/img"},
+		{From: "README.md", To: "README.md"},
+	}
+	got := copyConflicts(plan, existing)
+	if strings.Join(got, ",") != "README.md,docs/guide.md" {
+		t.Errorf("conflicts %v", got)
+	}
+
+	many := make([]string, 12)
+	for i := range many {
+		many[i] = "f" + string(rune('a'+i))
+	}
+	if err := conflictError(many); !strings.Contains(err.Error(), "fj and 2 more already exist") {
+		t.Errorf("error %v", err)
+	}
+	if err := conflictError([]string{"docs/guide.md"}); !strings.Contains(err.Error(), "docs/guide.md already exists") {
+		t.Errorf("error %v", err)
+	}
+}
+
+func TestRawBase64KeepsBinaryIntact(t *testing.T) {
+	data := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff, 0xfe, '\r', '\n', 0x1a}
+	data = append(data, bytes.Repeat([]byte{0x00, 0x80, 0xff}, 40)...)
+	encoded := base64.StdEncoding.EncodeToString(data)
+	// GitHub wraps content at 60 characters
+	var wrapped strings.Builder
+	for i := 0; i < len(encoded); i += 60 {
+		end := i + 60
+		if end > len(encoded) {
+			end = len(encoded)
+		}
+		wrapped.WriteString(encoded[i:end] + "\n")
+	}
+
+	got, err := rawBase64(wrapped.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, _ := base64.StdEncoding.DecodeString(got)
+	if !bytes.Equal(decoded, data) {
+		t.Errorf("decoded %x, want %x", decoded, data)
+	}
+	if _, err := rawBase64("not base64!"); err == nil {
+		t.Error("invalid content accepted")
+	}
+}
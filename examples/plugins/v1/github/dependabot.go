@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ListDependabotAlertsTool = ToolDescription{
+		Name:        "gh-list-dependabot-alerts",
+		Description: "List Dependabot alerts of a repository: vulnerable package, affected versions, severity, advisory IDs and the version that fixes it",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":     prop("string", "The owner of the repository"),
+				"repo":      prop("string", "The repository name"),
+				"state":     prop("string", "Comma-separated states: auto_dismissed, dismissed, fixed, open"),
+				"severity":  prop("string", "Comma-separated severities: low, medium, high, critical"),
+				"ecosystem": prop("string", "Comma-separated ecosystems, e.g. npm,pip,gomod,maven,rubygems,nuget,composer,rust"),
+				"package":   prop("string", "Comma-separated package names"),
+				"per_page":  prop("integer", "Number of results per page (max 100)"),
+				"page":      prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	UpdateDependabotAlertTool = ToolDescription{
+		Name:        "gh-update-dependabot-alert",
+		Description: "Dismiss a Dependabot alert with a reason, or reopen a dismissed one",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":             prop("string", "The owner of the repository"),
+				"repo":              prop("string", "The repository name"),
+				"alert_number":      prop("integer", "The alert number"),
+				"state":             prop("string", "dismissed or open (default dismissed)"),
+				"dismissed_reason":  prop("string", "Required to dismiss: fix_started, inaccurate, no_bandwidth, not_used or tolerable_risk"),
+				"dismissed_comment": prop("string", "Why the alert was dismissed, up to 280 characters"),
+			},
+			"required": []string{"owner", "repo", "alert_number"},
+		},
+	}
+)
+
+const dependabotCommentMax = 280
+
+type DependabotAlert struct {
+	Number     int    `json:"number"`
+	State      string `json:"state"`
+	Dependency struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		ManifestPath string `json:"manifest_path"`
+	} `json:"dependency"`
+	SecurityAdvisory struct {
+		GHSAID   string  `json:"ghsa_id"`
+		CVEID    *string `json:"cve_id"`
+		Summary  string  `json:"summary"`
+		Severity string  `json:"severity"`
+	} `json:"security_advisory"`
+	SecurityVulnerability struct {
+		VulnerableVersionRange string `json:"vulnerable_version_range"`
+		FirstPatchedVersion    *struct {
+			Identifier string `json:"identifier"`
+		} `json:"first_patched_version"`
+	} `json:"security_vulnerability"`
+	DismissedReason *string `json:"dismissed_reason"`
+	HTMLURL         string  `json:"html_url"`
+}
+
+// DependabotAlertSummary is kept flat and short so dozens of alerts fit in
+// one response. The alert's page is at
+// https://github.com/{owner}/{repo}/security/dependabot/{number}.
+type DependabotAlertSummary struct {
+	Number     int    `json:"number"`
+	State      string `json:"state"`
+	Package    string `json:"package"`
+	Vulnerable string `json:"vulnerable"`
+	Fixed      string `json:"fixed,omitempty"`
+	Severity   string `json:"severity"`
+	ID         string `json:"id"`
+	Summary    string `json:"summary"`
+	Manifest   string `json:"manifest"`
+}
+
+func summarizeDependabotAlerts(alerts []DependabotAlert) []DependabotAlertSummary {
+	summaries := []DependabotAlertSummary{}
+	for _, a := range alerts {
+		summary := DependabotAlertSummary{
+			Number:     a.Number,
+			State:      a.State,
+			Package:    fmt.Sprintf("%s:%s", a.Dependency.Package.Ecosystem, a.Dependency.Package.Name),
+			Vulnerable: a.SecurityVulnerability.VulnerableVersionRange,
+			Severity:   a.SecurityAdvisory.Severity,
+			ID:         a.SecurityAdvisory.GHSAID,
+			Summary:    truncateText(a.SecurityAdvisory.Summary, 120),
+			Manifest:   a.Dependency.ManifestPath,
+		}
+		if a.SecurityVulnerability.FirstPatchedVersion != nil {
+			summary.Fixed = a.SecurityVulnerability.FirstPatchedVersion.Identifier
+		}
+		if a.SecurityAdvisory.CVEID != nil && *a.SecurityAdvisory.CVEID != "" {
+			summary.ID = fmt.Sprintf("%s/%s", *a.SecurityAdvisory.CVEID, a.SecurityAdvisory.GHSAID)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// marshalAlerts is json.Marshal without HTML escaping, which would turn
+// version ranges like "< 4.17.12" into "\u003c 4.17.12".
+func marshalAlerts(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+func dependabotAlertsURL(owner, repo string, args map[string]interface{}) string {
+	params := paginationParams(args)
+	for _, key := range []string{"state", "severity", "ecosystem", "package"} {
+		if value, _ := args[key].(string); value != "" {
+			params = append(params, fmt.Sprintf("%s=%s", key, url.QueryEscape(value)))
+		}
+	}
+	return fmt.Sprintf("https://api.github.com/repos/%s/%s/dependabot/alerts?%s", owner, repo, strings.Join(params, "&"))
+}
+
+// dependabotUpdateBody builds the PATCH body. GitHub requires a reason to
+// dismiss an alert.
+func dependabotUpdateBody(args map[string]interface{}) (map[string]interface{}, error) {
+	state, _ := args["state"].(string)
+	if state == "" {
+		state = "dismissed"
+	}
+	switch state {
+	case "open":
+		return map[string]interface{}{"state": state}, nil
+	case "dismissed":
+	default:
+		return nil, fmt.Errorf("state must be dismissed or open, got %q", state)
+	}
+
+	body := map[string]interface{}{"state": state}
+	reason, _ := args["dismissed_reason"].(string)
+	switch reason {
+	case "fix_started", "inaccurate", "no_bandwidth", "not_used", "tolerable_risk":
+		body["dismissed_reason"] = reason
+	case "":
+		return nil, fmt.Errorf("dismissed_reason is required to dismiss an alert")
+	default:
+		return nil, fmt.Errorf("dismissed_reason must be fix_started, inaccurate, no_bandwidth, not_used or tolerable_risk, got %q", reason)
+	}
+	if comment, _ := args["dismissed_comment"].(string); comment != "" {
+		if utf8.RuneCountInString(comment) > dependabotCommentMax {
+			return nil, fmt.Errorf("dismissed_comment must be at most %d characters", dependabotCommentMax)
+		}
+		body["dismissed_comment"] = comment
+	}
+	return body, nil
+}
+
+// dependabotError explains a 403, which GitHub also answers when Dependabot
+// alerts are turned off for the repository.
+func dependabotError(owner, repo string, status uint16, err error) string {
+	if status == 403 {
+		return fmt.Sprintf("Dependabot alerts are not available for %s/%s: they may be disabled in the repository's security settings, or the token lacks the security_events scope (or Dependabot alerts read for fine-grained tokens). GitHub said: %s", owner, repo, err)
+	}
+	return err.Error()
+}
+
+func dependabotList(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	u := dependabotAlertsURL(owner, repo, args)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing Dependabot alerts: ", u))
+
+	alerts := []DependabotAlert{}
+	if status, err := githubGetJSON(apiKey, u, &alerts); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list Dependabot alerts: %s", dependabotError(owner, repo, status, err))),
+			}},
+		}
+	}
+
+	responseJSON, err := marshalAlerts(summarizeDependabotAlerts(alerts))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func dependabotUpdate(apiKey, owner, repo string, number int, args map[string]interface{}) CallToolResult {
+	body, err := dependabotUpdateBody(args)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid update: %s", err)),
+			}},
+		}
+	}
+
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/dependabot/alerts/%d", owner, repo, number)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Updating Dependabot alert: ", u))
+	var alert DependabotAlert
+	if err := githubSendJSON(apiKey, pdk.MethodPatch, u, body, 200, &alert); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to update Dependabot alert: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := marshalAlerts(summarizeDependabotAlerts([]DependabotAlert{alert})[0])
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
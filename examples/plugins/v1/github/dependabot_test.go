@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSummarizeDependabotAlerts(t *testing.T) {
+	var alerts []DependabotAlert
+	if err := json.Unmarshal([]byte(`[
+		{"number":7,"state":"open",
+		 "dependency":{"package":{"ecosystem":"npm","name":"lodash"},"manifest_path":"package-lock.json"},
+		 "security_advisory":{"ghsa_id":"GHSA-jf85-cpcp-j695","cve_id":"CVE-2019-10744","summary":"Prototype Pollution in lodash","severity":"critical"},
+		 "security_vulnerability":{"vulnerable_version_range":"< 4.17.12","first_patched_version":{"identifier":"4.17.12"}}},
+		{"number":2,"state":"dismissed",
+		 "dependency":{"package":{"ecosystem":"pip","name":"pyyaml"},"manifest_path":"requirements.txt"},
+		 "security_advisory":{"ghsa_id":"GHSA-8q59-q68h-6hv4","cve_id":null,"summary":"Arbitrary code execution","severity":"high"},
+		 "security_vulnerability":{"vulnerable_version_range":"< 5.4","first_patched_version":null}}
+	]`), &alerts); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := marshalAlerts(summarizeDependabotAlerts(alerts))
+	want := `[{"number":7,"state":"open","package":"npm:lodash","vulnerable":"< 4.17.12","fixed":"4.17.12","severity":"critical","id":"CVE-2019-10744/GHSA-jf85-cpcp-j695","summary":"Prototype Pollution in lodash","manifest":"package-lock.json"},` +
+		`{"number":2,"state":"dismissed","package":"pip:pyyaml","vulnerable":"< 5.4","severity":"high","id":"GHSA-8q59-q68h-6hv4","summary":"Arbitrary code execution","manifest":"requirements.txt"}]`
+	if string(got) != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestDependabotAlertsURL(t *testing.T) {
+	got := dependabotAlertsURL("o", "r", map[string]interface{}{"state": "open", "severity": "high,critical", "package": "lodash"})
+	want := "https://api.github.com/repos/o/r/dependabot/alerts?per_page=30&page=1&state=open&severity=high%2Ccritical&package=lodash"
+	if got != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestDependabotUpdateBody(t *testing.T) {
+	body, err := dependabotUpdateBody(map[string]interface{}{"dismissed_reason": "not_used", "dismissed_comment": "dev only"})
+	if err != nil || fmt.Sprint(body) != "map[dismissed_comment:dev only dismissed_reason:not_used state:dismissed]" {
+		t.Errorf("dismiss: got %v, %v", body, err)
+	}
+	body, err = dependabotUpdateBody(map[string]interface{}{"state": "open", "dismissed_reason": "not_used"})
+	if err != nil || fmt.Sprint(body) != "map[state:open]" {
+		t.Errorf("reopen: got %v, %v", body, err)
+	}
+	for name, args := range map[string]map[string]interface{}{
+		"no reason":    {},
+		"bad reason":   {"dismissed_reason": "wontfix"},
+		"bad state":    {"state": "fixed"},
+		"long comment": {"dismissed_reason": "inaccurate", "dismissed_comment": strings.Repeat("x", 281)},
+	} {
+		if _, err := dependabotUpdateBody(args); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
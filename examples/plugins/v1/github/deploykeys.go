@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ListDeployKeysTool = ToolDescription{
+		Name:        "gh-list-deploy-keys",
+		Description: "List the deploy keys of a repository",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":    prop("string", "The owner of the repository"),
+				"repo":     prop("string", "The repository name"),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	AddDeployKeyTool = ToolDescription{
+		Name:        "gh-add-deploy-key",
+		Description: "Add an SSH deploy key to a repository",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":     prop("string", "The owner of the repository"),
+				"repo":      prop("string", "The repository name"),
+				"title":     prop("string", "A name for the key"),
+				"key":       prop("string", "The SSH public key, e.g. the contents of id_ed25519.pub"),
+				"read_only": prop("boolean", "Only allow reading the repository (default true)"),
+			},
+			"required": []string{"owner", "repo", "title", "key"},
+		},
+	}
+	DeleteDeployKeyTool = ToolDescription{
+		Name:        "gh-delete-deploy-key",
+		Description: "Remove a deploy key from a repository. Anything using the key loses access.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":  prop("string", "The owner of the repository"),
+				"repo":   prop("string", "The repository name"),
+				"key_id": prop("integer", "The deploy key id"),
+			},
+			"required": []string{"owner", "repo", "key_id"},
+		},
+	}
+	DeployKeyTools = []ToolDescription{
+		ListDeployKeysTool,
+		AddDeployKeyTool,
+		DeleteDeployKeyTool,
+	}
+)
+
+var sshKeyPrefixes = []string{"ssh-ed25519 ", "ssh-rsa ", "ecdsa-sha2-", "sk-ssh-ed25519@openssh.com ", "sk-ecdsa-sha2-"}
+
+type DeployKey struct {
+	ID        int    `json:"id"`
+	Title     string `json:"title"`
+	Key       string `json:"key"`
+	ReadOnly  bool   `json:"read_only"`
+	Verified  bool   `json:"verified"`
+	CreatedAt string `json:"created_at"`
+}
+
+// deployKeyBody checks the key looks like an SSH public key before GitHub
+// sees it. Keys default to read-only, as GitHub's do.
+func deployKeyBody(args map[string]interface{}) (map[string]interface{}, error) {
+	title, _ := args["title"].(string)
+	key, _ := args["key"].(string)
+	key = strings.TrimSpace(key)
+	if strings.TrimSpace(title) == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+	if strings.Contains(key, "PRIVATE KEY") {
+		return nil, fmt.Errorf("key is a private key; give the public key (the .pub file) instead")
+	}
+	valid := false
+	for _, prefix := range sshKeyPrefixes {
+		valid = valid || strings.HasPrefix(key, prefix)
+	}
+	if !valid || len(strings.Fields(key)) < 2 {
+		return nil, fmt.Errorf("key must be an SSH public key starting with ssh-ed25519, ssh-rsa or ecdsa-sha2-")
+	}
+	readOnly := true
+	if value, ok := args["read_only"].(bool); ok {
+		readOnly = value
+	}
+	return map[string]interface{}{"title": title, "key": key, "read_only": readOnly}, nil
+}
+
+// deployKeyInUse reports whether a 422 is GitHub refusing a key that is
+// already added to this or another repository.
+func deployKeyInUse(body []byte) bool {
+	return strings.Contains(strings.ToLower(githubErrorMessage(body)), "key is already in use")
+}
+
+func deployKeysList(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/keys?%s", owner, repo, strings.Join(paginationParams(args), "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing deploy keys: ", u))
+
+	keys := []DeployKey{}
+	if _, err := githubGetJSON(apiKey, u, &keys); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list deploy keys: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(keys)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func deployKeysAdd(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	body, err := deployKeyBody(args)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid deploy key: %s", err)),
+			}},
+		}
+	}
+
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/keys", owner, repo)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Adding deploy key: ", u))
+	status, response, err := githubSend(apiKey, pdk.MethodPost, u, body)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to add deploy key: %s", err)),
+			}},
+		}
+	}
+	if status == 422 && deployKeyInUse(response) {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some("This key is already in use, as a deploy key of a repository or by a user account. GitHub won't accept it twice; generate a new key pair instead of retrying, or use the existing key where it is."),
+			}},
+		}
+	}
+	if status != 201 {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to add deploy key: %s", githubStatusError(status, response))),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(response)),
+		}},
+	}
+}
+
+func deployKeysDelete(apiKey, owner, repo string, id int) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/keys/%d", owner, repo, id)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Deleting deploy key: ", u))
+	_, err := githubSendExpect(apiKey, pdk.MethodDelete, u, nil, 204)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to delete deploy key %d: %s", id, err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(fmt.Sprintf("Deleted deploy key %d of %s/%s", id, owner, repo)),
+		}},
+	}
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ListEnvironmentsTool = ToolDescription{
+		Name:        "gh-list-environments",
+		Description: "List a repository's deployment environments and their protection rules",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":    prop("string", "The owner of the repository"),
+				"repo":     prop("string", "The repository name"),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	ListDeploymentsTool = ToolDescription{
+		Name:        "gh-list-deployments",
+		Description: "List deployments of a repository, newest first, each with its latest status. With environment set, the first one whose state is success is what is currently deployed there.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":       prop("string", "The owner of the repository"),
+				"repo":        prop("string", "The repository name"),
+				"environment": prop("string", "Only deployments to this environment, e.g. production"),
+				"ref":         prop("string", "Only deployments of this branch, tag or SHA"),
+				"per_page":    prop("integer", "Number of results per page (max 100)"),
+				"page":        prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	ListDeploymentStatusesTool = ToolDescription{
+		Name:        "gh-list-deployment-statuses",
+		Description: "List the statuses of a deployment, newest first",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":         prop("string", "The owner of the repository"),
+				"repo":          prop("string", "The repository name"),
+				"deployment_id": prop("integer", "The deployment ID"),
+				"per_page":      prop("integer", "Number of results per page (max 100)"),
+				"page":          prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo", "deployment_id"},
+		},
+	}
+	DeploymentTools = []ToolDescription{
+		ListEnvironmentsTool,
+		ListDeploymentsTool,
+		ListDeploymentStatusesTool,
+	}
+)
+
+type Environment struct {
+	Name            string `json:"name"`
+	HTMLURL         string `json:"html_url"`
+	UpdatedAt       string `json:"updated_at"`
+	ProtectionRules []struct {
+		Type string `json:"type"`
+	} `json:"protection_rules"`
+	DeploymentBranchPolicy *struct {
+		ProtectedBranches    bool `json:"protected_branches"`
+		CustomBranchPolicies bool `json:"custom_branch_policies"`
+	} `json:"deployment_branch_policy"`
+}
+
+type EnvironmentSummary struct {
+	Name            string   `json:"name"`
+	ProtectionRules []string `json:"protection_rules"`
+	Branches        string   `json:"branches"`
+	UpdatedAt       string   `json:"updated_at"`
+	HTMLURL         string   `json:"html_url"`
+}
+
+type Deployment struct {
+	ID          int64  `json:"id"`
+	SHA         string `json:"sha"`
+	Ref         string `json:"ref"`
+	Task        string `json:"task"`
+	Environment string `json:"environment"`
+	Description string `json:"description"`
+	Creator     struct {
+		Login string `json:"login"`
+	} `json:"creator"`
+	CreatedAt string `json:"created_at"`
+}
+
+type DeploymentStatus struct {
+	State          string `json:"state"`
+	Description    string `json:"description"`
+	Environment    string `json:"environment"`
+	EnvironmentURL string `json:"environment_url"`
+	LogURL         string `json:"log_url"`
+	Creator        struct {
+		Login string `json:"login"`
+	} `json:"creator"`
+	CreatedAt string `json:"created_at"`
+}
+
+type DeploymentSummary struct {
+	ID          int64  `json:"id"`
+	Environment string `json:"environment"`
+	Ref         string `json:"ref"`
+	SHA         string `json:"sha"`
+	Creator     string `json:"creator"`
+	CreatedAt   string `json:"created_at"`
+	// State of the latest status; deployments without one are "pending"
+	State          string `json:"state"`
+	StateAt        string `json:"state_at,omitempty"`
+	EnvironmentURL string `json:"environment_url,omitempty"`
+	LogURL         string `json:"log_url,omitempty"`
+}
+
+func summarizeEnvironments(environments []Environment) []EnvironmentSummary {
+	summaries := []EnvironmentSummary{}
+	for _, e := range environments {
+		summary := EnvironmentSummary{
+			Name:            e.Name,
+			ProtectionRules: []string{},
+			Branches:        "all",
+			UpdatedAt:       e.UpdatedAt,
+			HTMLURL:         e.HTMLURL,
+		}
+		for _, rule := range e.ProtectionRules {
+			summary.ProtectionRules = append(summary.ProtectionRules, rule.Type)
+		}
+		if policy := e.DeploymentBranchPolicy; policy != nil {
+			switch {
+			case policy.ProtectedBranches:
+				summary.Branches = "protected"
+			case policy.CustomBranchPolicies:
+				summary.Branches = "custom"
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+func summarizeDeployment(d Deployment, latest *DeploymentStatus) DeploymentSummary {
+	summary := DeploymentSummary{
+		ID:          d.ID,
+		Environment: d.Environment,
+		Ref:         d.Ref,
+		SHA:         shortSha(d.SHA),
+		Creator:     d.Creator.Login,
+		CreatedAt:   d.CreatedAt,
+		State:       "pending",
+	}
+	if latest != nil {
+		summary.State = latest.State
+		summary.StateAt = latest.CreatedAt
+		summary.EnvironmentURL = latest.EnvironmentURL
+		summary.LogURL = latest.LogURL
+	}
+	return summary
+}
+
+func deploymentsURL(owner, repo string, args map[string]interface{}) string {
+	params := paginationParams(args)
+	for _, key := range []string{"environment", "ref"} {
+		if value, _ := args[key].(string); value != "" {
+			params = append(params, fmt.Sprintf("%s=%s", key, url.QueryEscape(value)))
+		}
+	}
+	return fmt.Sprintf("https://api.github.com/repos/%s/%s/deployments?%s", owner, repo, strings.Join(params, "&"))
+}
+
+func deploymentsListEnvironments(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/environments?%s", owner, repo, strings.Join(paginationParams(args), "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing environments: ", u))
+
+	var response struct {
+		TotalCount   int           `json:"total_count"`
+		Environments []Environment `json:"environments"`
+	}
+	if _, err := githubGetJSON(apiKey, u, &response); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list environments: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(summarizeEnvironments(response.Environments))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+// deploymentsList looks up the latest status of each deployment listed,
+// one request per deployment.
+func deploymentsList(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	u := deploymentsURL(owner, repo, args)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing deployments: ", u))
+
+	deployments := []Deployment{}
+	if _, err := githubGetJSON(apiKey, u, &deployments); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list deployments: %s", err)),
+			}},
+		}
+	}
+
+	summaries := []DeploymentSummary{}
+	for _, d := range deployments {
+		u := fmt.Sprintf("https://api.github.com/repos/%s/%s/deployments/%d/statuses?per_page=1", owner, repo, d.ID)
+		pdk.Log(pdk.LogDebug, fmt.Sprint("Getting latest deployment status: ", u))
+		var statuses []DeploymentStatus
+		if _, err := githubGetJSON(apiKey, u, &statuses); err != nil {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Failed to get the status of deployment %d: %s", d.ID, err)),
+				}},
+			}
+		}
+		var latest *DeploymentStatus
+		if len(statuses) > 0 {
+			latest = &statuses[0]
+		}
+		summaries = append(summaries, summarizeDeployment(d, latest))
+	}
+
+	responseJSON, err := json.Marshal(summaries)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func deploymentsListStatuses(apiKey, owner, repo string, id int64, args map[string]interface{}) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/deployments/%d/statuses?%s", owner, repo, id, strings.Join(paginationParams(args), "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing deployment statuses: ", u))
+
+	statuses := []DeploymentStatus{}
+	if _, err := githubGetJSON(apiKey, u, &statuses); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list deployment statuses: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(statuses)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
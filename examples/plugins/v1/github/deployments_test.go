@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSummarizeEnvironments(t *testing.T) {
+	var environments []Environment
+	if err := json.Unmarshal([]byte(`[
+		{"name":"production","html_url":"https://github.com/o/r/deployments/activity_log?environments_filter=production","updated_at":"2024-05-01T00:00:00Z",
+		 "protection_rules":[{"type":"required_reviewers"},{"type":"wait_timer"}],
+		 "deployment_branch_policy":{"protected_branches":true,"custom_branch_policies":false}},
+		{"name":"preview","html_url":"https://github.com/o/r/deployments/activity_log?environments_filter=preview","updated_at":"2024-04-01T00:00:00Z"}
+	]`), &environments); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(summarizeEnvironments(environments))
+	want := `[{"name":"production","protection_rules":["required_reviewers","wait_timer"],"branches":"protected","updated_at":"2024-05-01T00:00:00Z","html_url":"https://github.com/o/r/deployments/activity_log?environments_filter=production"},` +
+		`{"name":"preview","protection_rules":[],"branches":"all","updated_at":"2024-04-01T00:00:00Z","html_url":"https://github.com/o/r/deployments/activity_log?environments_filter=preview"}]`
+	if string(got) != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestSummarizeDeployment(t *testing.T) {
+	var d Deployment
+	if err := json.Unmarshal([]byte(`{"id":42,"sha":"a84d88e7554fc1fa21bcbc4efae3c782a70d2b9d","ref":"v1.2.0","environment":"production","creator":{"login":"octocat"},"created_at":"2024-05-01T10:00:00Z"}`), &d); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := json.Marshal(summarizeDeployment(d, nil))
+	want := `{"id":42,"environment":"production","ref":"v1.2.0","sha":"a84d88e","creator":"octocat","created_at":"2024-05-01T10:00:00Z","state":"pending"}`
+	if string(got) != want {
+		t.Errorf("no status:\n got %s\nwant %s", got, want)
+	}
+
+	latest := &DeploymentStatus{State: "success", CreatedAt: "2024-05-01T10:05:00Z", EnvironmentURL: "https://example.com"}
+	got, _ = json.Marshal(summarizeDeployment(d, latest))
+	want = `{"id":42,"environment":"production","ref":"v1.2.0","sha":"a84d88e","creator":"octocat","created_at":"2024-05-01T10:00:00Z","state":"success","state_at":"2024-05-01T10:05:00Z","environment_url":"https://example.com"}`
+	if string(got) != want {
+		t.Errorf("with status:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestDeploymentsURL(t *testing.T) {
+	got := deploymentsURL("o", "r", map[string]interface{}{"environment": "production", "ref": "main", "per_page": float64(1)})
+	if want := "https://api.github.com/repos/o/r/deployments?per_page=1&page=1&environment=production&ref=main"; got != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+}
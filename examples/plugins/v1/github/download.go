@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+const (
+	downloadDefaultMaxBytes = 1 << 20
+	downloadMaxMaxBytes     = 10 << 20
+)
+
+var DownloadFileTool = ToolDescription{
+	Name:        "gh-download-file",
+	Description: "Download a file from a GitHub repository as is. Text files are returned as text and binary files, such as images or archives, as a base64 blob with their media type.",
+	InputSchema: schema{
+		"type": "object",
+		"properties": props{
+			"owner":     prop("string", "The owner of the repository"),
+			"repo":      prop("string", "The repository name"),
+			"path":      prop("string", "The path of the file"),
+			"ref":       prop("string", "(optional) Branch, tag or commit to download the file from, defaults to the default branch"),
+			"max_bytes": prop("integer", fmt.Sprintf("Refuse files larger than this many bytes (default %d, at most %d)", downloadDefaultMaxBytes, downloadMaxMaxBytes)),
+		},
+		"required": []string{"owner", "repo", "path"},
+	},
+}
+
+func downloadMaxBytes(args map[string]interface{}) (int, error) {
+	value, ok := args["max_bytes"]
+	if !ok || value == nil {
+		return downloadDefaultMaxBytes, nil
+	}
+	size, ok := value.(float64)
+	if !ok || size != float64(int(size)) || size < 1 || size > downloadMaxMaxBytes {
+		return 0, fmt.Errorf("max_bytes must be a whole number of bytes from 1 to %d, got %v", downloadMaxMaxBytes, value)
+	}
+	return int(size), nil
+}
+
+// rawIsDirectory tells a directory listing apart from a file. The raw
+// media type only applies to files; directories still get the JSON listing.
+func rawIsDirectory(headers map[string]string, body []byte) bool {
+	contentType, _ := responseHeader(headers, "Content-Type")
+	return strings.HasPrefix(contentType, "application/json") && bytes.HasPrefix(bytes.TrimSpace(body), []byte("["))
+}
+
+// downloadResult returns the file's data unless it is over maxBytes.
+func downloadResult(uri, path string, data []byte, maxBytes int) CallToolResult {
+	if len(data) > maxBytes {
+		message := fmt.Sprintf("%s is %d bytes, more than max_bytes %d", path, len(data), maxBytes)
+		if len(data) <= downloadMaxMaxBytes {
+			message += "; raise max_bytes to download it"
+		}
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(message),
+			}},
+		}
+	}
+	return CallToolResult{
+		Content: []Content{sniffedContent(uri, path, data)},
+	}
+}
+
+func filesDownload(apiKey, owner, repo, path string, args map[string]interface{}) CallToolResult {
+	failed := func(message string) CallToolResult {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(message),
+			}},
+		}
+	}
+	maxBytes, err := downloadMaxBytes(args)
+	if err != nil {
+		return failed(err.Error())
+	}
+
+	u := fmt.Sprint("https://api.github.com/repos/", owner, "/", repo, "/contents/", strings.TrimPrefix(path, "/"))
+	ref, _ := args["ref"].(string)
+	if ref != "" {
+		u += "?ref=" + url.QueryEscape(ref)
+	}
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Downloading file: ", u))
+	resp := githubRequest(apiKey, pdk.MethodGet, u, "application/vnd.github.raw").Send()
+	switch {
+	case resp.Status() == 404:
+		if ref != "" {
+			return failed(fmt.Sprintf("%s not found in %s/%s at %s", path, owner, repo, ref))
+		}
+		return failed(fmt.Sprintf("%s not found in %s/%s", path, owner, repo))
+	case resp.Status() != 200:
+		return failed(fmt.Sprintf("Failed to download %s: %s", path, githubStatusError(resp.Status(), resp.Body())))
+	case rawIsDirectory(resp.Headers(), resp.Body()):
+		return failed(fmt.Sprintf("%s is a directory; list it with gh-get-file-contents and download its files one at a time", path))
+	}
+
+	return downloadResult(u, path, resp.Body(), maxBytes)
+}
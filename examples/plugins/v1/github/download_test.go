@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDownloadMaxBytes(t *testing.T) {
+	if got, err := downloadMaxBytes(map[string]interface{}{}); err != nil || got != downloadDefaultMaxBytes {
+		t.Errorf("default = %d, %v", got, err)
+	}
+	if got, err := downloadMaxBytes(map[string]interface{}{"max_bytes": float64(4096)}); err != nil || got != 4096 {
+		t.Errorf("4096 = %d, %v", got, err)
+	}
+	for _, bad := range []interface{}{float64(0), float64(1.5), float64(downloadMaxMaxBytes + 1), "big"} {
+		if _, err := downloadMaxBytes(map[string]interface{}{"max_bytes": bad}); err == nil {
+			t.Errorf("max_bytes %v should fail", bad)
+		}
+	}
+}
+
+func TestRawIsDirectory(t *testing.T) {
+	listing := []byte(`[{"type":"file","name":"main.go"}]`)
+	if !rawIsDirectory(map[string]string{"content-type": "application/json; charset=utf-8"}, listing) {
+		t.Error("JSON listing not detected")
+	}
+	if rawIsDirectory(map[string]string{"content-type": "application/vnd.github.raw"}, listing) {
+		t.Error("raw JSON file taken for a directory")
+	}
+}
+
+func TestDownloadResult(t *testing.T) {
+	text := downloadResult("u", "README.md", []byte("# Hello\n"), 100)
+	if text.IsError != nil || text.Content[0].Type != ContentTypeText || *text.Content[0].Text != "# Hello\n" {
+		t.Errorf("text file: %+v", text.Content[0])
+	}
+
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00")
+	blob, _ := json.Marshal(downloadResult("u", "logo.png", png, 100).Content[0])
+	if want := `{"resource":{"blob":"iVBORw0KGgoAAA==","mimeType":"image/png","uri":"u"},"type":"resource"}`; string(blob) != want {
+		t.Errorf("binary file:\n got %s\nwant %s", blob, want)
+	}
+
+	tooBig := downloadResult("u", "logo.png", png, 4)
+	if tooBig.IsError == nil || *tooBig.Content[0].Text != "logo.png is 10 bytes, more than max_bytes 4; raise max_bytes to download it" {
+		t.Errorf("too big: %+v", *tooBig.Content[0].Text)
+	}
+}
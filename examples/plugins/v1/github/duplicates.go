@@ -0,0 +1,423 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	FindDuplicateIssuesTool = ToolDescription{
+		Name:        "gh-find-duplicate-issues",
+		Description: "Find open issues that may be duplicates of a new issue. Give the title and body of the issue you are about to file, or the number of an existing issue. Several searches are run (title keywords, error messages from code blocks) and the results are ranked by word overlap, with a similarity score from 0 to 1. Set confirm_with_model to have the client's model pick the real duplicate, if any; this needs a client that supports sampling. A confirmed duplicate comes with a suggested comment to post with gh-add-issue-comment.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":              prop("string", "The owner of the repository"),
+				"repo":               prop("string", "The repository name"),
+				"title":              prop("string", "Title of the new issue"),
+				"body":               prop("string", "Body of the new issue"),
+				"issue":              prop("integer", "Number of an existing issue to check instead of title and body"),
+				"max_results":        prop("integer", "Number of candidates to return (default 5, max 20)"),
+				"confirm_with_model": prop("boolean", "Ask the client's model, through sampling, which candidate is a real duplicate. Fails if the client does not support sampling."),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+)
+
+const (
+	duplicateDefaultResults = 5
+	duplicateMaxResults     = 20
+	// Results fetched per derived query
+	duplicateSearchPerPage = 20
+)
+
+var duplicateStopWords = func() map[string]bool {
+	words := map[string]bool{}
+	for _, w := range strings.Fields(`a an and are as at be been but by can cannot could did do does doesn't
+		dont don't for from get gets got had has have how i if in into is isn't it it's its
+		me my no not of on or our should so some that the their then there these this to
+		too use used using was we were what when where which while why will with would you your
+		after again all also any bug issue problem error errors work works working`) {
+		words[w] = true
+	}
+	return words
+}()
+
+var (
+	duplicateTokenRe   = regexp.MustCompile(`[a-z0-9][a-z0-9_.']*[a-z0-9]|[a-z0-9]`)
+	duplicateFenceRe   = regexp.MustCompile("(?s)```[^\n]*\n(.*?)```")
+	duplicateErrorRe   = regexp.MustCompile(`(?i)\b(error|exception|panic|panicked|fatal|failed|failure|traceback|cannot|unable)\b`)
+	duplicateNoiseRe   = regexp.MustCompile(`(?i)0x[0-9a-f]+|\b[0-9a-f]{12,}\b|(?:[\w.:-]*[/\\])+[\w.-]+(?::\d+)*|\b\d+(?:\.\d+)*\b`)
+	duplicateSpacesRe  = regexp.MustCompile(`\s+`)
+	duplicateLeadingRe = regexp.MustCompile(`^[\s\[\]():|>#*-]+`)
+	duplicateWordRe    = regexp.MustCompile(`[\pL\pN]`)
+)
+
+// duplicateTokens lowercases text and splits it into the words that carry
+// meaning: stop words, numbers and single letters are dropped.
+func duplicateTokens(text string) []string {
+	var tokens []string
+	for _, t := range duplicateTokenRe.FindAllString(strings.ToLower(text), -1) {
+		t = strings.Trim(t, ".'")
+		if len(t) < 2 || duplicateStopWords[t] {
+			continue
+		}
+		if _, err := strconv.ParseFloat(t, 64); err == nil {
+			continue
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens
+}
+
+func tokenSet(tokens []string) map[string]bool {
+	set := make(map[string]bool, len(tokens))
+	for _, t := range tokens {
+		set[t] = true
+	}
+	return set
+}
+
+// errorSignatures pulls error lines out of the fenced code blocks of a body,
+// with paths, line numbers, addresses and other run-specific details removed
+// so they match the same error reported from another machine.
+func errorSignatures(body string) []string {
+	var signatures []string
+	seen := map[string]bool{}
+	for _, block := range duplicateFenceRe.FindAllStringSubmatch(body, -1) {
+		for _, line := range strings.Split(block[1], "\n") {
+			if !duplicateErrorRe.MatchString(line) {
+				continue
+			}
+			line = duplicateNoiseRe.ReplaceAllString(line, " ")
+			line = strings.NewReplacer(`"`, " ", "`", " ").Replace(line)
+			line = duplicateLeadingRe.ReplaceAllString(line, "")
+			var words []string
+			for _, w := range strings.Fields(duplicateSpacesRe.ReplaceAllString(line, " ")) {
+				// Punctuation left behind by the removed details
+				if duplicateWordRe.MatchString(w) {
+					words = append(words, w)
+				}
+			}
+			if len(words) > 8 {
+				words = words[:8]
+			}
+			if len(words) < 2 {
+				continue
+			}
+			signature := strings.Join(words, " ")
+			if !seen[signature] {
+				seen[signature] = true
+				signatures = append(signatures, signature)
+			}
+		}
+	}
+	return signatures
+}
+
+// duplicateQueries derives the searches run for a new issue: its title
+// keywords restricted to titles, fewer title keywords anywhere in an issue,
+// and each of the first error signatures as an exact phrase.
+func duplicateQueries(owner, repo, title, body string) []string {
+	scope := fmt.Sprintf("repo:%s/%s is:issue is:open", owner, repo)
+
+	var keywords []string
+	seen := map[string]bool{}
+	for _, t := range duplicateTokens(title) {
+		if !seen[t] {
+			seen[t] = true
+			keywords = append(keywords, t)
+		}
+	}
+
+	var queries []string
+	if len(keywords) > 0 {
+		titleKeywords := keywords
+		if len(titleKeywords) > 5 {
+			titleKeywords = titleKeywords[:5]
+		}
+		queries = append(queries, fmt.Sprintf("%s in:title %s", scope, strings.Join(titleKeywords, " ")))
+	}
+	if len(keywords) > 3 {
+		queries = append(queries, fmt.Sprintf("%s %s", scope, strings.Join(keywords[:3], " ")))
+	}
+	signatures := errorSignatures(body)
+	if len(signatures) > 2 {
+		signatures = signatures[:2]
+	}
+	for _, s := range signatures {
+		queries = append(queries, fmt.Sprintf("%s %q", scope, s))
+	}
+	return queries
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range a {
+		if b[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+type DuplicateCandidate struct {
+	Number      int      `json:"number"`
+	Title       string   `json:"title"`
+	URL         string   `json:"url"`
+	Score       float64  `json:"score"`
+	SharedTerms []string `json:"shared_terms"`
+	SameError   bool     `json:"same_error,omitempty"`
+	body        string
+}
+
+type searchedIssue struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+// scoreDuplicate rates how alike two issues are from 0 to 1: mostly title
+// overlap, then overlap of all their words, with a bonus for sharing an
+// error signature.
+func scoreDuplicate(title, body string, candidate searchedIssue) DuplicateCandidate {
+	titleTokens := tokenSet(duplicateTokens(title))
+	allTokens := tokenSet(duplicateTokens(title + "\n" + body))
+	candidateTitle := tokenSet(duplicateTokens(candidate.Title))
+	candidateAll := tokenSet(duplicateTokens(candidate.Title + "\n" + candidate.Body))
+
+	result := DuplicateCandidate{
+		Number:      candidate.Number,
+		Title:       candidate.Title,
+		URL:         candidate.HTMLURL,
+		SharedTerms: []string{},
+		body:        candidate.Body,
+	}
+	for t := range titleTokens {
+		if candidateAll[t] {
+			result.SharedTerms = append(result.SharedTerms, t)
+		}
+	}
+	sort.Strings(result.SharedTerms)
+
+	candidateSignatures := map[string]bool{}
+	for _, s := range errorSignatures(candidate.Body) {
+		candidateSignatures[s] = true
+	}
+	for _, s := range errorSignatures(body) {
+		if candidateSignatures[s] {
+			result.SameError = true
+		}
+	}
+
+	score := 0.6*jaccard(titleTokens, candidateTitle) + 0.4*jaccard(allTokens, candidateAll)
+	if result.SameError {
+		score = 0.5 + score/2
+	}
+	result.Score = math.Round(score*1000) / 1000
+	return result
+}
+
+// rankDuplicates scores every candidate once, drops the issue being checked,
+// and returns the best max, highest score first.
+func rankDuplicates(title, body string, exclude int, found []searchedIssue, max int) []DuplicateCandidate {
+	seen := map[int]bool{exclude: true}
+	ranked := []DuplicateCandidate{}
+	for _, issue := range found {
+		if seen[issue.Number] {
+			continue
+		}
+		seen[issue.Number] = true
+		if c := scoreDuplicate(title, body, issue); c.Score > 0 {
+			ranked = append(ranked, c)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Number < ranked[j].Number
+	})
+	if len(ranked) > max {
+		ranked = ranked[:max]
+	}
+	return ranked
+}
+
+//go:wasmimport extism:host/user create_message
+func _createMessage(uint64) uint64
+
+type samplingMessage struct {
+	Role    string `json:"role"`
+	Content struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+// askModel sends a single prompt to the client's model through sampling.
+// The host fails the whole call when the client does not support sampling,
+// so it is only used when the caller asks for it.
+func askModel(system, prompt string) (string, error) {
+	message := samplingMessage{Role: "user"}
+	message.Content.Type = "text"
+	message.Content.Text = prompt
+	mem, err := pdk.AllocateJSON(map[string]interface{}{
+		"messages":     []samplingMessage{message},
+		"systemPrompt": system,
+		"maxTokens":    20,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var out samplingMessage
+	if err := pdk.JSONFrom(_createMessage(mem.Offset()), &out); err != nil {
+		return "", err
+	}
+	return out.Content.Text, nil
+}
+
+func duplicatePrompt(title, body string, candidates []DuplicateCandidate) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "New issue:\nTitle: %s\n%s\n\nExisting issues:\n", title, truncateText(body, 2000))
+	for _, c := range candidates {
+		fmt.Fprintf(&b, "\n#%d: %s\n%s\n", c.Number, c.Title, truncateText(c.body, 1000))
+	}
+	b.WriteString("\nWhich existing issue reports the same problem as the new issue? Answer with its number, like #12, or with none.")
+	return b.String()
+}
+
+var duplicateAnswerRe = regexp.MustCompile(`#?(\d+)`)
+
+// parseDuplicateAnswer reads the model's answer, accepting only the number
+// of one of the candidates it was shown.
+func parseDuplicateAnswer(answer string, candidates []DuplicateCandidate) (int, bool) {
+	m := duplicateAnswerRe.FindStringSubmatch(answer)
+	if m == nil {
+		return 0, false
+	}
+	n, _ := strconv.Atoi(m[1])
+	for _, c := range candidates {
+		if c.Number == n {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+type DuplicateReport struct {
+	Queries          []string             `json:"queries"`
+	Candidates       []DuplicateCandidate `json:"candidates"`
+	ModelChoice      *int                 `json:"model_choice,omitempty"`
+	SuggestedComment string               `json:"suggested_comment,omitempty"`
+}
+
+func issueFindDuplicates(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	title, _ := args["title"].(string)
+	body, _ := args["body"].(string)
+	exclude := 0
+	if number, ok := args["issue"].(float64); ok {
+		exclude = int(number)
+		var issue ExportedIssue
+		u := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d", owner, repo, exclude)
+		if _, err := githubGetJSON(apiKey, u, &issue); err != nil {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Failed to fetch issue: %s", err)),
+				}},
+			}
+		}
+		title, body = issue.Title, issue.Body
+	}
+	max := duplicateDefaultResults
+	if value, ok := args["max_results"].(float64); ok {
+		max = int(value)
+	}
+	if max < 1 || max > duplicateMaxResults {
+		max = duplicateDefaultResults
+	}
+
+	queries := duplicateQueries(owner, repo, title, body)
+	if len(queries) == 0 {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some("Nothing to search for: give a title with some keywords, or an issue number"),
+			}},
+		}
+	}
+
+	var found []searchedIssue
+	for _, q := range queries {
+		u := fmt.Sprintf("https://api.github.com/search/issues?per_page=%d&q=%s", duplicateSearchPerPage, url.QueryEscape(q))
+		pdk.Log(pdk.LogDebug, fmt.Sprint("Searching issues: ", u))
+
+		var result struct {
+			Items []searchedIssue `json:"items"`
+		}
+		if _, err := githubGetJSON(apiKey, u, &result); err != nil {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Failed to search issues: %s", err)),
+				}},
+			}
+		}
+		found = append(found, result.Items...)
+	}
+
+	report := DuplicateReport{Queries: queries, Candidates: rankDuplicates(title, body, exclude, found, max)}
+
+	if confirm, _ := args["confirm_with_model"].(bool); confirm && len(report.Candidates) > 0 {
+		answer, err := askModel("You triage GitHub issues. Only call an issue a duplicate when it reports the same problem, not just a related one.", duplicatePrompt(title, body, report.Candidates))
+		if err != nil {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Failed to ask the model to confirm a duplicate: %s", err)),
+				}},
+			}
+		}
+		if n, ok := parseDuplicateAnswer(answer, report.Candidates); ok {
+			report.ModelChoice = &n
+			report.SuggestedComment = fmt.Sprintf("Duplicate of #%d", n)
+		}
+	}
+
+	responseJSON, err := json.Marshal(report)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
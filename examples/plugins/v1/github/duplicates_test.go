@@ -0,0 +1,119 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+const duplicateBody = "Starting the server with two plugins crashes:\n\n" +
+	"```\n" +
+	"thread 'main' panicked at src/plugins/loader.rs:118:9:\n" +
+	"Error: failed to instantiate module 0x7ffd5c2a: unknown import extism:host/user.create_message\n" +
+	"```\n"
+
+var duplicateFixtures = []searchedIssue{
+	{
+		Number:  101,
+		Title:   "Plugin loader fails with unknown import create_message",
+		Body:    "```\nError: failed to instantiate module 0x55aa01: unknown import extism:host/user.create_message\n```\nSeen on v0.1.4",
+		HTMLURL: "https://github.com/o/r/issues/101",
+	},
+	{
+		Number:  87,
+		Title:   "Server crashes when loading two plugins",
+		Body:    "Loading a second plugin brings the whole server down.",
+		HTMLURL: "https://github.com/o/r/issues/87",
+	},
+	{
+		Number:  55,
+		Title:   "Add a dark theme to the docs site",
+		Body:    "The docs are too bright.",
+		HTMLURL: "https://github.com/o/r/issues/55",
+	},
+	{
+		Number:  200,
+		Title:   "Server crashes loading plugins",
+		Body:    "this is the issue being checked",
+		HTMLURL: "https://github.com/o/r/issues/200",
+	},
+}
+
+func TestErrorSignatures(t *testing.T) {
+	got := errorSignatures(duplicateBody)
+	want := []string{
+		"thread 'main' panicked at",
+		"Error: failed to instantiate module unknown import",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+
+	if got := errorSignatures("Error: outside a code block"); got != nil {
+		t.Errorf("signatures outside code blocks: %q", got)
+	}
+}
+
+func TestDuplicateQueries(t *testing.T) {
+	got := duplicateQueries("o", "r", "Server crashes when loading two plugins with sampling", duplicateBody)
+	want := []string{
+		"repo:o/r is:issue is:open in:title server crashes loading two plugins",
+		"repo:o/r is:issue is:open server crashes loading",
+		`repo:o/r is:issue is:open "thread 'main' panicked at"`,
+		`repo:o/r is:issue is:open "Error: failed to instantiate module unknown import"`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+
+	if got := duplicateQueries("o", "r", "It doesn't work", ""); got != nil {
+		t.Errorf("stop words only: got %q", got)
+	}
+}
+
+func TestRankDuplicates(t *testing.T) {
+	title := "Server crashes when loading two plugins"
+	// Search results repeat across queries
+	found := append(append([]searchedIssue{}, duplicateFixtures...), duplicateFixtures[0])
+	ranked := rankDuplicates(title, duplicateBody, 200, found, 5)
+
+	var numbers []int
+	for _, c := range ranked {
+		numbers = append(numbers, c.Number)
+	}
+	if !reflect.DeepEqual(numbers, []int{87, 101}) {
+		t.Fatalf("ranked %v, want [87 101]", numbers)
+	}
+	if ranked[0].Score < 0.6 || ranked[0].Score >= 1 {
+		t.Errorf("same title with a different body scored %v, want between 0.6 and 1", ranked[0].Score)
+	}
+	if !ranked[1].SameError || ranked[1].Score < 0.5 {
+		t.Errorf("shared error signature: same_error %v score %v, want true and at least 0.5", ranked[1].SameError, ranked[1].Score)
+	}
+	if !reflect.DeepEqual(ranked[0].SharedTerms, []string{"crashes", "loading", "plugins", "server", "two"}) {
+		t.Errorf("shared terms %q", ranked[0].SharedTerms)
+	}
+
+	if capped := rankDuplicates(title, duplicateBody, 0, duplicateFixtures, 1); len(capped) != 1 || capped[0].Number != 87 {
+		t.Errorf("max_results 1 without exclusion: %+v", capped)
+	}
+}
+
+func TestParseDuplicateAnswer(t *testing.T) {
+	candidates := []DuplicateCandidate{{Number: 87}, {Number: 101}}
+	tests := []struct {
+		answer string
+		want   int
+		ok     bool
+	}{
+		{"#101", 101, true},
+		{"It is 87.", 87, true},
+		{"none", 0, false},
+		{"#5", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseDuplicateAnswer(tt.answer, candidates)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseDuplicateAnswer(%q) = %d, %v, want %d, %v", tt.answer, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	IssueEngagementTool = ToolDescription{
+		Name:        "gh-issue-engagement",
+		Description: "Summarize engagement on an issue thread: comment counts per author, reaction totals, first and last activity, and the three most-reacted comments. Useful for triaging noisy threads.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner": prop("string", "The owner of the repository"),
+				"repo":  prop("string", "The repository name"),
+				"issue": prop("integer", "The issue number"),
+			},
+			"required": []string{"owner", "repo", "issue"},
+		},
+	}
+)
+
+const (
+	// Maximum number of comment pages (100 comments each) fetched per call.
+	engagementRequestBudget = 10
+	// Comment bodies in the top comments list are cut to this many characters.
+	engagementBodyPreview = 200
+	engagementTopComments = 3
+)
+
+type ReactionRollup struct {
+	TotalCount int `json:"total_count"`
+	PlusOne    int `json:"+1"`
+	MinusOne   int `json:"-1"`
+	Laugh      int `json:"laugh"`
+	Hooray     int `json:"hooray"`
+	Confused   int `json:"confused"`
+	Heart      int `json:"heart"`
+	Rocket     int `json:"rocket"`
+	Eyes       int `json:"eyes"`
+}
+
+type IssueComment struct {
+	ID   int `json:"id"`
+	User *struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Body      string          `json:"body"`
+	HTMLURL   string          `json:"html_url"`
+	CreatedAt string          `json:"created_at"`
+	UpdatedAt string          `json:"updated_at"`
+	Reactions *ReactionRollup `json:"reactions"`
+}
+
+type EngagementAuthor struct {
+	Login     string `json:"login"`
+	Comments  int    `json:"comments"`
+	Reactions int    `json:"reactions_received"`
+}
+
+type EngagementComment struct {
+	Author    string `json:"author"`
+	Reactions int    `json:"reactions"`
+	CreatedAt string `json:"created_at"`
+	HTMLURL   string `json:"html_url"`
+	Body      string `json:"body"`
+}
+
+type IssueEngagement struct {
+	Issue          int                 `json:"issue"`
+	TotalComments  int                 `json:"total_comments"`
+	TotalReactions int                 `json:"total_reactions"`
+	FirstActivity  string              `json:"first_activity,omitempty"`
+	LastActivity   string              `json:"last_activity,omitempty"`
+	Leaderboard    []EngagementAuthor  `json:"leaderboard"`
+	TopComments    []EngagementComment `json:"top_comments"`
+	Truncated      bool                `json:"truncated"`
+}
+
+// commentAuthor returns the comment author's login. Comments from deleted
+// accounts come back with a null user and are attributed to GitHub's ghost user.
+func commentAuthor(c IssueComment) string {
+	if c.User == nil || c.User.Login == "" {
+		return "ghost"
+	}
+	return c.User.Login
+}
+
+func truncateText(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:max]) + "…"
+}
+
+func summarizeEngagement(issue int, comments []IssueComment, truncated bool) IssueEngagement {
+	summary := IssueEngagement{
+		Issue:         issue,
+		TotalComments: len(comments),
+		Leaderboard:   []EngagementAuthor{},
+		TopComments:   []EngagementComment{},
+		Truncated:     truncated,
+	}
+
+	authors := map[string]*EngagementAuthor{}
+	ranked := make([]EngagementComment, 0, len(comments))
+	for _, c := range comments {
+		login := commentAuthor(c)
+		reactions := 0
+		if c.Reactions != nil {
+			reactions = c.Reactions.TotalCount
+		}
+		summary.TotalReactions += reactions
+
+		a, ok := authors[login]
+		if !ok {
+			a = &EngagementAuthor{Login: login}
+			authors[login] = a
+		}
+		a.Comments++
+		a.Reactions += reactions
+
+		// Timestamps are RFC 3339 in UTC so they compare lexically
+		if summary.FirstActivity == "" || c.CreatedAt < summary.FirstActivity {
+			summary.FirstActivity = c.CreatedAt
+		}
+		last := c.CreatedAt
+		if c.UpdatedAt > last {
+			last = c.UpdatedAt
+		}
+		if last > summary.LastActivity {
+			summary.LastActivity = last
+		}
+
+		ranked = append(ranked, EngagementComment{
+			Author:    login,
+			Reactions: reactions,
+			CreatedAt: c.CreatedAt,
+			HTMLURL:   c.HTMLURL,
+			Body:      truncateText(c.Body, engagementBodyPreview),
+		})
+	}
+
+	for _, a := range authors {
+		summary.Leaderboard = append(summary.Leaderboard, *a)
+	}
+	sort.Slice(summary.Leaderboard, func(i, j int) bool {
+		a, b := summary.Leaderboard[i], summary.Leaderboard[j]
+		if a.Comments != b.Comments {
+			return a.Comments > b.Comments
+		}
+		if a.Reactions != b.Reactions {
+			return a.Reactions > b.Reactions
+		}
+		return a.Login < b.Login
+	})
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Reactions > ranked[j].Reactions
+	})
+	for _, c := range ranked {
+		if len(summary.TopComments) == engagementTopComments || c.Reactions == 0 {
+			break
+		}
+		summary.TopComments = append(summary.TopComments, c)
+	}
+
+	return summary
+}
+
+func issueEngagement(apiKey, owner, repo string, issue int) CallToolResult {
+	comments := []IssueComment{}
+	truncated := false
+
+	// Comment payloads carry a reactions rollup, so paging the comments is enough
+	for page := 1; ; page++ {
+		if page > engagementRequestBudget {
+			truncated = true
+			break
+		}
+		u := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d/comments?per_page=100&page=%d", owner, repo, issue, page)
+		pdk.Log(pdk.LogDebug, fmt.Sprint("Fetching issue comments: ", u))
+
+		var batch []IssueComment
+		if _, err := githubGetJSON(apiKey, u, &batch); err != nil {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Failed to fetch issue comments: %s", err)),
+				}},
+			}
+		}
+		comments = append(comments, batch...)
+		if len(batch) < 100 {
+			break
+		}
+	}
+
+	responseJSON, err := json.Marshal(summarizeEngagement(issue, comments, truncated))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const engagementFixture = `[
+  {"id": 1, "user": {"login": "alice"}, "body": "first", "created_at": "2025-01-01T10:00:00Z", "updated_at": "2025-01-01T10:00:00Z", "reactions": {"total_count": 2, "+1": 2}},
+  {"id": 2, "user": null, "body": "from a deleted account", "created_at": "2025-01-02T10:00:00Z", "updated_at": "2025-01-05T10:00:00Z", "reactions": {"total_count": 7, "heart": 7}},
+  {"id": 3, "user": {"login": "bob"}, "body": "third", "created_at": "2025-01-03T10:00:00Z", "updated_at": "2025-01-03T10:00:00Z"},
+  {"id": 4, "user": {"login": "alice"}, "body": "fourth", "created_at": "2025-01-04T10:00:00Z", "updated_at": "2025-01-04T10:00:00Z", "reactions": {"total_count": 1, "eyes": 1}},
+  {"id": 5, "user": {"login": "carol"}, "body": "fifth", "created_at": "2025-01-04T11:00:00Z", "updated_at": "2025-01-04T11:00:00Z", "reactions": {"total_count": 3, "rocket": 3}}
+]`
+
+func TestSummarizeEngagement(t *testing.T) {
+	var comments []IssueComment
+	if err := json.Unmarshal([]byte(engagementFixture), &comments); err != nil {
+		t.Fatal(err)
+	}
+
+	got := summarizeEngagement(42, comments, false)
+
+	if got.TotalComments != 5 || got.TotalReactions != 13 {
+		t.Fatalf("unexpected totals: %d comments, %d reactions", got.TotalComments, got.TotalReactions)
+	}
+	if got.FirstActivity != "2025-01-01T10:00:00Z" || got.LastActivity != "2025-01-05T10:00:00Z" {
+		t.Fatalf("unexpected activity range %s - %s", got.FirstActivity, got.LastActivity)
+	}
+
+	wantBoard := []string{"alice", "ghost", "carol", "bob"}
+	if len(got.Leaderboard) != len(wantBoard) {
+		t.Fatalf("unexpected leaderboard %+v", got.Leaderboard)
+	}
+	for i, login := range wantBoard {
+		if got.Leaderboard[i].Login != login {
+			t.Fatalf("leaderboard[%d] = %s, want %s (%+v)", i, got.Leaderboard[i].Login, login, got.Leaderboard)
+		}
+	}
+	if got.Leaderboard[0].Comments != 2 || got.Leaderboard[0].Reactions != 3 {
+		t.Fatalf("unexpected alice entry %+v", got.Leaderboard[0])
+	}
+
+	wantTop := []int{7, 3, 2}
+	if len(got.TopComments) != len(wantTop) {
+		t.Fatalf("unexpected top comments %+v", got.TopComments)
+	}
+	for i, reactions := range wantTop {
+		if got.TopComments[i].Reactions != reactions {
+			t.Fatalf("top_comments[%d] has %d reactions, want %d", i, got.TopComments[i].Reactions, reactions)
+		}
+	}
+	if got.TopComments[0].Author != "ghost" {
+		t.Fatalf("expected the deleted user's comment to be attributed to ghost, got %s", got.TopComments[0].Author)
+	}
+}
+
+func TestSummarizeEngagementEmpty(t *testing.T) {
+	got := summarizeEngagement(1, nil, false)
+	if got.TotalComments != 0 || len(got.Leaderboard) != 0 || len(got.TopComments) != 0 {
+		t.Fatalf("unexpected summary %+v", got)
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	if got := truncateText("short", 10); got != "short" {
+		t.Fatalf("got %q", got)
+	}
+	long := strings.Repeat("é", 12)
+	if got := truncateText(long, 10); got != strings.Repeat("é", 10)+"…" {
+		t.Fatalf("got %q", got)
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ListRepoEventsTool = ToolDescription{
+		Name:        "gh-list-repo-events",
+		Description: "List recent activity in a repository, newest first, each event summarized in one line. GitHub keeps events for 90 days.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":    prop("string", "The owner of the repository"),
+				"repo":     prop("string", "The repository name"),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	ListUserEventsTool = ToolDescription{
+		Name:        "gh-list-user-events",
+		Description: "List a user's recent activity, newest first, each event summarized in one line. Private events are included for the authenticated user.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"username": prop("string", "The user's login"),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"username"},
+		},
+	}
+)
+
+type Event struct {
+	Type  string `json:"type"`
+	Actor struct {
+		Login string `json:"login"`
+	} `json:"actor"`
+	Repo struct {
+		Name string `json:"name"`
+	} `json:"repo"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt string          `json:"created_at"`
+}
+
+type EventSummary struct {
+	Type      string `json:"type"`
+	Actor     string `json:"actor"`
+	Repo      string `json:"repo"`
+	Summary   string `json:"summary"`
+	CreatedAt string `json:"created_at"`
+}
+
+// eventPayload holds the payload fields of the event types summarized.
+// Each type fills in only its own.
+type eventPayload struct {
+	Action string `json:"action"`
+	// PushEvent
+	Ref     string `json:"ref"`
+	Size    int    `json:"size"`
+	Commits []struct {
+		Message string `json:"message"`
+	} `json:"commits"`
+	// CreateEvent and DeleteEvent
+	RefType string `json:"ref_type"`
+	// IssuesEvent and IssueCommentEvent
+	Issue *struct {
+		Number      int              `json:"number"`
+		Title       string           `json:"title"`
+		PullRequest *json.RawMessage `json:"pull_request"`
+	} `json:"issue"`
+	// PullRequest events
+	Number      int `json:"number"`
+	PullRequest *struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		Merged bool   `json:"merged"`
+	} `json:"pull_request"`
+	Review *struct {
+		State string `json:"state"`
+	} `json:"review"`
+	// CommitCommentEvent
+	Comment *struct {
+		CommitID string `json:"commit_id"`
+	} `json:"comment"`
+	Forkee *struct {
+		FullName string `json:"full_name"`
+	} `json:"forkee"`
+	Release *struct {
+		TagName string `json:"tag_name"`
+		Name    string `json:"name"`
+	} `json:"release"`
+	Member *struct {
+		Login string `json:"login"`
+	} `json:"member"`
+	// GollumEvent
+	Pages []struct {
+		Action string `json:"action"`
+		Title  string `json:"title"`
+	} `json:"pages"`
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
+
+// eventSummary describes what the event did in one line. Types without a
+// summary of their own are described by their name.
+func eventSummary(event Event) string {
+	var p eventPayload
+	_ = json.Unmarshal(event.Payload, &p)
+
+	switch event.Type {
+	case "PushEvent":
+		size := p.Size
+		if size == 0 {
+			size = len(p.Commits)
+		}
+		return fmt.Sprintf("pushed %s to %s", plural(size, "commit"), strings.TrimPrefix(p.Ref, "refs/heads/"))
+	case "CreateEvent":
+		if p.RefType == "repository" {
+			return "created the repository"
+		}
+		return fmt.Sprintf("created %s %s", p.RefType, p.Ref)
+	case "DeleteEvent":
+		return fmt.Sprintf("deleted %s %s", p.RefType, p.Ref)
+	case "IssuesEvent":
+		if p.Issue != nil {
+			return fmt.Sprintf("%s issue #%d: %s", p.Action, p.Issue.Number, p.Issue.Title)
+		}
+	case "IssueCommentEvent":
+		if p.Issue != nil {
+			kind := "issue"
+			if p.Issue.PullRequest != nil {
+				kind = "pull request"
+			}
+			return fmt.Sprintf("commented on %s #%d: %s", kind, p.Issue.Number, p.Issue.Title)
+		}
+	case "PullRequestEvent":
+		if p.PullRequest != nil {
+			action := p.Action
+			if action == "closed" && p.PullRequest.Merged {
+				action = "merged"
+			}
+			return fmt.Sprintf("%s pull request #%d: %s", action, p.Number, p.PullRequest.Title)
+		}
+	case "PullRequestReviewEvent":
+		if p.PullRequest != nil && p.Review != nil {
+			return fmt.Sprintf("reviewed pull request #%d: %s", p.PullRequest.Number, strings.ReplaceAll(strings.ToLower(p.Review.State), "_", " "))
+		}
+	case "PullRequestReviewCommentEvent":
+		if p.PullRequest != nil {
+			return fmt.Sprintf("commented on the diff of pull request #%d: %s", p.PullRequest.Number, p.PullRequest.Title)
+		}
+	case "CommitCommentEvent":
+		if p.Comment != nil {
+			return fmt.Sprintf("commented on commit %s", shortSha(p.Comment.CommitID))
+		}
+	case "ForkEvent":
+		if p.Forkee != nil {
+			return fmt.Sprintf("forked the repository to %s", p.Forkee.FullName)
+		}
+	case "WatchEvent":
+		return "starred the repository"
+	case "ReleaseEvent":
+		if p.Release != nil {
+			return fmt.Sprintf("%s release %s", p.Action, p.Release.TagName)
+		}
+	case "MemberEvent":
+		if p.Member != nil {
+			return fmt.Sprintf("%s collaborator %s", p.Action, p.Member.Login)
+		}
+	case "PublicEvent":
+		return "made the repository public"
+	case "GollumEvent":
+		titles := []string{}
+		for _, page := range p.Pages {
+			titles = append(titles, page.Title)
+		}
+		return fmt.Sprintf("updated %s of the wiki: %s", plural(len(p.Pages), "page"), strings.Join(titles, ", "))
+	}
+	return event.Type
+}
+
+func summarizeEvents(events []Event) []EventSummary {
+	summaries := []EventSummary{}
+	for _, e := range events {
+		summaries = append(summaries, EventSummary{
+			Type:      e.Type,
+			Actor:     e.Actor.Login,
+			Repo:      e.Repo.Name,
+			Summary:   eventSummary(e),
+			CreatedAt: e.CreatedAt,
+		})
+	}
+	return summaries
+}
+
+func eventsList(apiKey, u string) CallToolResult {
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing events: ", u))
+
+	events := []Event{}
+	if _, err := githubGetJSON(apiKey, u, &events); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list events: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(summarizeEvents(events))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func repoEventsURL(owner, repo string, args map[string]interface{}) string {
+	return fmt.Sprintf("https://api.github.com/repos/%s/%s/events?%s", owner, repo, strings.Join(paginationParams(args), "&"))
+}
+
+func userEventsURL(username string, args map[string]interface{}) string {
+	return fmt.Sprintf("https://api.github.com/users/%s/events?%s", url.PathEscape(username), strings.Join(paginationParams(args), "&"))
+}
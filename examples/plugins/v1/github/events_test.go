@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEventSummary(t *testing.T) {
+	for payload, want := range map[string]string{
+		`{"type":"PushEvent","payload":{"ref":"refs/heads/main","size":3}}`:                                                    "pushed 3 commits to main",
+		`{"type":"PushEvent","payload":{"ref":"refs/heads/fix","commits":[{"message":"x"}]}}`:                                  "pushed 1 commit to fix",
+		`{"type":"CreateEvent","payload":{"ref_type":"tag","ref":"v1.0.0"}}`:                                                   "created tag v1.0.0",
+		`{"type":"CreateEvent","payload":{"ref_type":"repository","ref":null}}`:                                                "created the repository",
+		`{"type":"DeleteEvent","payload":{"ref_type":"branch","ref":"old"}}`:                                                   "deleted branch old",
+		`{"type":"IssuesEvent","payload":{"action":"opened","issue":{"number":12,"title":"Crash"}}}`:                           "opened issue #12: Crash",
+		`{"type":"IssueCommentEvent","payload":{"action":"created","issue":{"number":7,"title":"Fix","pull_request":{}}}}`:     "commented on pull request #7: Fix",
+		`{"type":"PullRequestEvent","payload":{"action":"closed","number":9,"pull_request":{"title":"Retry","merged":true}}}`:  "merged pull request #9: Retry",
+		`{"type":"PullRequestEvent","payload":{"action":"opened","number":10,"pull_request":{"title":"Docs","merged":false}}}`: "opened pull request #10: Docs",
+		`{"type":"PullRequestReviewEvent","payload":{"review":{"state":"changes_requested"},"pull_request":{"number":9}}}`:     "reviewed pull request #9: changes requested",
+		`{"type":"PullRequestReviewCommentEvent","payload":{"pull_request":{"number":9,"title":"Retry"}}}`:                     "commented on the diff of pull request #9: Retry",
+		`{"type":"CommitCommentEvent","payload":{"comment":{"commit_id":"a84d88e7554fc1fa21bcbc4efae3c782a70d2b9d"}}}`:         "commented on commit a84d88e",
+		`{"type":"ForkEvent","payload":{"forkee":{"full_name":"bob/r"}}}`:                                                      "forked the repository to bob/r",
+		`{"type":"WatchEvent","payload":{"action":"started"}}`:                                                                 "starred the repository",
+		`{"type":"ReleaseEvent","payload":{"action":"published","release":{"tag_name":"v2.0.0"}}}`:                             "published release v2.0.0",
+		`{"type":"MemberEvent","payload":{"action":"added","member":{"login":"carol"}}}`:                                       "added collaborator carol",
+		`{"type":"PublicEvent","payload":{}}`:                                                                                  "made the repository public",
+		`{"type":"GollumEvent","payload":{"pages":[{"action":"edited","title":"Home"},{"action":"created","title":"FAQ"}]}}`:   "updated 2 pages of the wiki: Home, FAQ",
+		`{"type":"SponsorshipEvent","payload":{}}`:                                                                             "SponsorshipEvent",
+		`{"type":"IssuesEvent","payload":{"action":"opened"}}`:                                                                 "IssuesEvent",
+	} {
+		var event Event
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			t.Fatal(err)
+		}
+		if got := eventSummary(event); got != want {
+			t.Errorf("%s:\n got %q\nwant %q", payload, got, want)
+		}
+	}
+}
+
+func TestSummarizeEvents(t *testing.T) {
+	var events []Event
+	if err := json.Unmarshal([]byte(`[{"type":"WatchEvent","actor":{"login":"alice"},"repo":{"name":"o/r"},"payload":{"action":"started"},"created_at":"2024-05-01T10:00:00Z"}]`), &events); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(summarizeEvents(events))
+	if want := `[{"type":"WatchEvent","actor":"alice","repo":"o/r","summary":"starred the repository","created_at":"2024-05-01T10:00:00Z"}]`; string(got) != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ExportIssueTool = ToolDescription{
+		Name:        "gh-export-issue",
+		Description: "Export an issue thread as a single markdown document: front matter with the issue metadata and linked pull requests, the issue body, then every comment with its author and timestamp. Very long threads are cut at a size limit and say how many comments were left out.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner": prop("string", "The owner of the repository"),
+				"repo":  prop("string", "The repository name"),
+				"issue": prop("integer", "The issue number"),
+			},
+			"required": []string{"owner", "repo", "issue"},
+		},
+	}
+)
+
+const (
+	// Maximum number of pages (100 items each) fetched per call, for comments
+	// and timeline events separately.
+	exportRequestBudget = 10
+	// Comments stop being added once the document reaches this many bytes.
+	exportSizeLimit = 200000
+)
+
+type ExportedIssue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	Body   string `json:"body"`
+	User   *struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	HTMLURL   string  `json:"html_url"`
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt string  `json:"updated_at"`
+	ClosedAt  *string `json:"closed_at"`
+	Labels    []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Assignees []struct {
+		Login string `json:"login"`
+	} `json:"assignees"`
+}
+
+type LinkedPullRequest struct {
+	Repository string `json:"repository"`
+	Number     int    `json:"number"`
+	Title      string `json:"title"`
+	State      string `json:"state"`
+	HTMLURL    string `json:"html_url"`
+}
+
+// linkedPullRequests picks the pull requests that mention the issue out of
+// its timeline, once each, ordered by repository and number.
+func linkedPullRequests(events []TimelineEvent) []LinkedPullRequest {
+	seen := map[string]bool{}
+	linked := []LinkedPullRequest{}
+	for _, e := range events {
+		if e.Event != "cross-referenced" || e.Source == nil || e.Source.Issue == nil || e.Source.Issue.PullRequest == nil {
+			continue
+		}
+		issue := e.Source.Issue
+		state := issue.State
+		if issue.PullRequest.MergedAt != nil {
+			state = "merged"
+		}
+		pr := LinkedPullRequest{
+			Repository: issue.Repository.FullName,
+			Number:     issue.Number,
+			Title:      issue.Title,
+			State:      state,
+			HTMLURL:    issue.HTMLURL,
+		}
+		key := fmt.Sprint(pr.Repository, "#", pr.Number)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		linked = append(linked, pr)
+	}
+	sort.Slice(linked, func(i, j int) bool {
+		if linked[i].Repository != linked[j].Repository {
+			return linked[i].Repository < linked[j].Repository
+		}
+		return linked[i].Number < linked[j].Number
+	})
+	return linked
+}
+
+// yamlString quotes a front matter value. JSON strings are valid YAML.
+func yamlString(s string) string {
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
+
+// renderIssueMarkdown builds the exported document. The output depends only
+// on its arguments so the same thread always renders the same way.
+func renderIssueMarkdown(repository string, issue ExportedIssue, comments []IssueComment, linked []LinkedPullRequest, limit int) string {
+	var b strings.Builder
+
+	author := "ghost"
+	if issue.User != nil && issue.User.Login != "" {
+		author = issue.User.Login
+	}
+
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "repository: %s\n", yamlString(repository))
+	fmt.Fprintf(&b, "number: %d\n", issue.Number)
+	fmt.Fprintf(&b, "title: %s\n", yamlString(issue.Title))
+	fmt.Fprintf(&b, "state: %s\n", yamlString(issue.State))
+	fmt.Fprintf(&b, "author: %s\n", yamlString(author))
+	fmt.Fprintf(&b, "url: %s\n", yamlString(issue.HTMLURL))
+	fmt.Fprintf(&b, "created_at: %s\n", yamlString(issue.CreatedAt))
+	fmt.Fprintf(&b, "updated_at: %s\n", yamlString(issue.UpdatedAt))
+	if issue.ClosedAt != nil {
+		fmt.Fprintf(&b, "closed_at: %s\n", yamlString(*issue.ClosedAt))
+	}
+	labels := make([]string, 0, len(issue.Labels))
+	for _, l := range issue.Labels {
+		labels = append(labels, yamlString(l.Name))
+	}
+	fmt.Fprintf(&b, "labels: [%s]\n", strings.Join(labels, ", "))
+	assignees := make([]string, 0, len(issue.Assignees))
+	for _, a := range issue.Assignees {
+		assignees = append(assignees, yamlString(a.Login))
+	}
+	fmt.Fprintf(&b, "assignees: [%s]\n", strings.Join(assignees, ", "))
+	fmt.Fprintf(&b, "comments: %d\n", len(comments))
+	if len(linked) == 0 {
+		b.WriteString("linked_pull_requests: []\n")
+	} else {
+		b.WriteString("linked_pull_requests:\n")
+		for _, pr := range linked {
+			fmt.Fprintf(&b, "  - {repository: %s, number: %d, state: %s, url: %s}\n", yamlString(pr.Repository), pr.Number, yamlString(pr.State), yamlString(pr.HTMLURL))
+		}
+	}
+	b.WriteString("---\n\n")
+
+	fmt.Fprintf(&b, "# %s (#%d)\n\n", issue.Title, issue.Number)
+	fmt.Fprintf(&b, "_Opened by @%s on %s_\n\n", author, issue.CreatedAt)
+	if body := strings.TrimSpace(issue.Body); body != "" {
+		b.WriteString(body)
+		b.WriteString("\n\n")
+	}
+
+	if len(linked) > 0 {
+		b.WriteString("## Linked pull requests\n\n")
+		for _, pr := range linked {
+			fmt.Fprintf(&b, "- [%s#%d](%s) %s (%s)\n", pr.Repository, pr.Number, pr.HTMLURL, pr.Title, pr.State)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(comments) > 0 {
+		b.WriteString("## Comments\n")
+	}
+	for i, c := range comments {
+		var entry strings.Builder
+		fmt.Fprintf(&entry, "\n### @%s on %s\n\n", commentAuthor(c), c.CreatedAt)
+		if body := strings.TrimSpace(c.Body); body != "" {
+			entry.WriteString(body)
+			entry.WriteString("\n")
+		}
+		if b.Len()+entry.Len() > limit {
+			fmt.Fprintf(&b, "\n_%d more comments not included: the export reached its size limit. See %s for the full thread._\n", len(comments)-i, issue.HTMLURL)
+			break
+		}
+		b.WriteString(entry.String())
+	}
+
+	return b.String()
+}
+
+// exportFetchPages pages through a list endpoint, stopping at the request
+// budget. It reports whether more pages were left.
+func exportFetchPages[T any](apiKey, u string) ([]T, bool, error) {
+	items := []T{}
+	for page := 1; page <= exportRequestBudget; page++ {
+		pageURL := fmt.Sprintf("%s?per_page=100&page=%d", u, page)
+		pdk.Log(pdk.LogDebug, fmt.Sprint("Fetching: ", pageURL))
+
+		var batch []T
+		if _, err := githubGetJSON(apiKey, pageURL, &batch); err != nil {
+			return nil, false, err
+		}
+		items = append(items, batch...)
+		if len(batch) < 100 {
+			return items, false, nil
+		}
+	}
+	return items, true, nil
+}
+
+func issueExport(apiKey, owner, repo string, number int) CallToolResult {
+	base := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d", owner, repo, number)
+
+	var issue ExportedIssue
+	if _, err := githubGetJSON(apiKey, base, &issue); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to fetch issue: %s", err)),
+			}},
+		}
+	}
+
+	comments, truncated, err := exportFetchPages[IssueComment](apiKey, base+"/comments")
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to fetch issue comments: %s", err)),
+			}},
+		}
+	}
+
+	events, _, err := exportFetchPages[TimelineEvent](apiKey, base+"/timeline")
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to fetch issue timeline: %s", err)),
+			}},
+		}
+	}
+
+	document := renderIssueMarkdown(owner+"/"+repo, issue, comments, linkedPullRequests(events), exportSizeLimit)
+	if truncated {
+		document += fmt.Sprintf("\n_Only the first %d comments were fetched. See %s for the full thread._\n", len(comments), issue.HTMLURL)
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(document),
+		}},
+	}
+}
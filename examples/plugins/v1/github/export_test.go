@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const exportIssueFixture = `{
+	"number": 42,
+	"title": "Crash on \"empty\" config",
+	"state": "closed",
+	"body": "Steps:\n\n![screenshot](https://example.com/a.png)\n",
+	"user": {"login": "alice"},
+	"html_url": "https://github.com/o/r/issues/42",
+	"created_at": "2024-01-02T03:04:05Z",
+	"updated_at": "2024-01-05T00:00:00Z",
+	"closed_at": "2024-01-05T00:00:00Z",
+	"labels": [{"name": "bug"}, {"name": "p1"}],
+	"assignees": [{"login": "bob"}]
+}`
+
+const exportCommentsFixture = `[
+	{"user": {"login": "bob"}, "body": "Can reproduce.", "created_at": "2024-01-03T00:00:00Z"},
+	{"user": null, "body": "  +1  ", "created_at": "2024-01-04T00:00:00Z"}
+]`
+
+const exportTimelineFixture = `[
+	{"event": "labeled"},
+	{"event": "cross-referenced", "source": {"issue": {"number": 7, "title": "Unrelated issue", "repository": {"full_name": "o/r"}}}},
+	{"event": "cross-referenced", "source": {"issue": {"number": 50, "title": "Fix crash", "state": "closed", "html_url": "https://github.com/o/r/pull/50", "pull_request": {"merged_at": "2024-01-05T00:00:00Z"}, "repository": {"full_name": "o/r"}}}},
+	{"event": "cross-referenced", "source": {"issue": {"number": 3, "title": "Backport", "state": "open", "html_url": "https://github.com/o/a/pull/3", "pull_request": {}, "repository": {"full_name": "o/a"}}}},
+	{"event": "cross-referenced", "source": {"issue": {"number": 50, "title": "Fix crash", "state": "closed", "html_url": "https://github.com/o/r/pull/50", "pull_request": {"merged_at": "2024-01-05T00:00:00Z"}, "repository": {"full_name": "o/r"}}}}
+]`
+
+const exportSnapshot = `---
+repository: "o/r"
+number: 42
+title: "Crash on \"empty\" config"
+state: "closed"
+author: "alice"
+url: "https://github.com/o/r/issues/42"
+created_at: "2024-01-02T03:04:05Z"
+updated_at: "2024-01-05T00:00:00Z"
+closed_at: "2024-01-05T00:00:00Z"
+labels: ["bug", "p1"]
+assignees: ["bob"]
+comments: 2
+linked_pull_requests:
+  - {repository: "o/a", number: 3, state: "open", url: "https://github.com/o/a/pull/3"}
+  - {repository: "o/r", number: 50, state: "merged", url: "https://github.com/o/r/pull/50"}
+---
+
+# Crash on "empty" config (#42)
+
+_Opened by @alice on 2024-01-02T03:04:05Z_
+
+Steps:
+
+![screenshot](https://example.com/a.png)
+
+## Linked pull requests
+
+- [o/a#3](https://github.com/o/a/pull/3) Backport (open)
+- [o/r#50](https://github.com/o/r/pull/50) Fix crash (merged)
+
+## Comments
+
+### @bob on 2024-01-03T00:00:00Z
+
+Can reproduce.
+
+### @ghost on 2024-01-04T00:00:00Z
+
++1
+`
+
+func exportFixtures(t *testing.T) (ExportedIssue, []IssueComment, []LinkedPullRequest) {
+	t.Helper()
+	var issue ExportedIssue
+	var comments []IssueComment
+	var events []TimelineEvent
+	for fixture, out := range map[string]interface{}{exportIssueFixture: &issue, exportCommentsFixture: &comments, exportTimelineFixture: &events} {
+		if err := json.Unmarshal([]byte(fixture), out); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return issue, comments, linkedPullRequests(events)
+}
+
+func TestRenderIssueMarkdown(t *testing.T) {
+	issue, comments, linked := exportFixtures(t)
+
+	got := renderIssueMarkdown("o/r", issue, comments, linked, exportSizeLimit)
+	if got != exportSnapshot {
+		t.Fatalf("unexpected export:\n%s", got)
+	}
+	if again := renderIssueMarkdown("o/r", issue, comments, linked, exportSizeLimit); again != got {
+		t.Fatal("rendering is not deterministic")
+	}
+}
+
+func TestRenderIssueMarkdownSizeLimit(t *testing.T) {
+	issue, comments, linked := exportFixtures(t)
+	limit := strings.Index(exportSnapshot, "### @ghost")
+
+	got := renderIssueMarkdown("o/r", issue, comments, linked, limit)
+	if !strings.Contains(got, "Can reproduce.") || strings.Contains(got, "@ghost") {
+		t.Fatalf("expected only the first comment to fit:\n%s", got)
+	}
+	if !strings.Contains(got, "_1 more comments not included") {
+		t.Fatalf("expected a note about the omitted comment:\n%s", got)
+	}
+}
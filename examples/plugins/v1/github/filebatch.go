@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	getFilesMaxPaths      = 20
+	getFilesDefaultBudget = 100000
+)
+
+var GetFilesTool = ToolDescription{
+	Name:        "gh-get-files",
+	Description: "Get the contents of several files in a GitHub repository at once, one content block per file. Missing files are skipped and reported rather than failing the call.",
+	InputSchema: schema{
+		"type": "object",
+		"properties": props{
+			"owner":     prop("string", "The owner of the repository"),
+			"repo":      prop("string", "The repository name"),
+			"ref":       prop("string", "(optional) Branch, tag or commit to read the files at, defaults to the default branch"),
+			"paths":     arrprop("array", fmt.Sprintf("The paths of the files, at most %d", getFilesMaxPaths), "string"),
+			"max_bytes": prop("integer", fmt.Sprintf("Total size budget for all the files' content; files past it are cut off or skipped (default %d)", getFilesDefaultBudget)),
+		},
+		"required": []string{"owner", "repo", "paths"},
+	},
+}
+
+// getFilesPaths reads the paths, dropping leading slashes and duplicates.
+func getFilesPaths(args map[string]interface{}) ([]string, error) {
+	list, _ := args["paths"].([]interface{})
+	paths := []string{}
+	seen := map[string]bool{}
+	for _, p := range list {
+		path, ok := p.(string)
+		if !ok {
+			return nil, fmt.Errorf("paths must be strings, got %#v", p)
+		}
+		path = strings.TrimPrefix(strings.TrimSpace(path), "/")
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		paths = append(paths, path)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("paths must list at least one file")
+	}
+	if len(paths) > getFilesMaxPaths {
+		return nil, fmt.Errorf("paths lists %d files; ask for at most %d at a time", len(paths), getFilesMaxPaths)
+	}
+	return paths, nil
+}
+
+// cutText cuts s to at most n bytes without splitting a character.
+func cutText(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// getFiles fetches each path and labels its content with the path. Files
+// that can't be returned are listed in a final block instead of failing
+// the call, which only fails when none of the files could be returned.
+// Once budget bytes of content have been returned, the file that crosses
+// it is cut off and the rest are skipped without being fetched.
+func getFiles(paths []string, budget int, fetch func(path string) (UnionContent, error)) CallToolResult {
+	blocks := []Content{}
+	skipped, truncated := []string{}, []string{}
+	used := 0
+	for _, path := range paths {
+		if used >= budget {
+			skipped = append(skipped, fmt.Sprintf("%s: size budget of %d bytes used up", path, budget))
+			continue
+		}
+		uc, err := fetch(path)
+		var ce *contentsError
+		if errors.As(err, &ce) {
+			if ce.Status == 404 {
+				skipped = append(skipped, fmt.Sprintf("%s: not found", path))
+			} else {
+				skipped = append(skipped, fmt.Sprintf("%s: %s", path, githubStatusError(ce.Status, ce.Body)))
+			}
+			continue
+		}
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %s", path, err))
+			continue
+		}
+		if uc.isArray {
+			skipped = append(skipped, fmt.Sprintf("%s: is a directory", path))
+			continue
+		}
+		if uc.FileContent.Type != "file" {
+			skipped = append(skipped, fmt.Sprintf("%s: is a %s, not a file", path, uc.FileContent.Type))
+			continue
+		}
+		// The contents API leaves files over a megabyte out
+		if uc.FileContent.Encoding == "none" {
+			skipped = append(skipped, fmt.Sprintf("%s: %d bytes, too large for the contents API", path, uc.FileContent.Size))
+			continue
+		}
+		text := uc.FileContent.Content
+		if _, isText := sniffContent(path, []byte(text)); !isText {
+			skipped = append(skipped, fmt.Sprintf("%s: binary file of %d bytes", path, len(text)))
+			continue
+		}
+
+		if cut := cutText(text, budget-used); len(cut) < len(text) {
+			truncated = append(truncated, fmt.Sprintf("%s: showing the first %d of %d bytes", path, len(cut), len(text)))
+			text = cut + fmt.Sprintf("\n[truncated: the size budget of %d bytes ran out]", budget)
+			used = budget
+		} else {
+			used += len(text)
+		}
+		blocks = append(blocks, Content{
+			Type: ContentTypeText,
+			Text: some(fmt.Sprintf("==> %s <==\n%s", path, text)),
+		})
+	}
+
+	var notes []string
+	if len(skipped) > 0 {
+		notes = append(notes, "Skipped:\n- "+strings.Join(skipped, "\n- "))
+	}
+	if len(truncated) > 0 {
+		notes = append(notes, "Truncated:\n- "+strings.Join(truncated, "\n- "))
+	}
+	if len(notes) > 0 {
+		blocks = append(blocks, Content{
+			Type: ContentTypeText,
+			Text: some(strings.Join(notes, "\n\n")),
+		})
+	}
+
+	result := CallToolResult{Content: blocks}
+	if len(skipped) == len(paths) {
+		result.IsError = some(true)
+	}
+	return result
+}
+
+func filesGetMany(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	paths, err := getFilesPaths(args)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(err.Error()),
+			}},
+		}
+	}
+	budget := getFilesDefaultBudget
+	if maxBytes, ok := args["max_bytes"].(float64); ok && maxBytes > 0 {
+		budget = int(maxBytes)
+	}
+	var ref *string
+	if r, _ := args["ref"].(string); r != "" {
+		ref = &r
+	}
+
+	return getFiles(paths, budget, func(path string) (UnionContent, error) {
+		return filesGetContentsInternal(apiKey, owner, repo, path, ref)
+	})
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetFilesPaths(t *testing.T) {
+	paths, err := getFilesPaths(map[string]interface{}{"paths": []interface{}{"/go.mod", "main.go", " go.mod ", ""}})
+	if err != nil || strings.Join(paths, ",") != "go.mod,main.go" {
+		t.Errorf("got %q, %v", paths, err)
+	}
+	if _, err := getFilesPaths(map[string]interface{}{"paths": []interface{}{}}); err == nil {
+		t.Error("empty paths should fail")
+	}
+	many := []interface{}{}
+	for i := 0; i <= getFilesMaxPaths; i++ {
+		many = append(many, strings.Repeat("a", i+1))
+	}
+	if _, err := getFilesPaths(map[string]interface{}{"paths": many}); err == nil || !strings.Contains(err.Error(), "at most 20") {
+		t.Errorf("too many paths: %v", err)
+	}
+}
+
+func TestGetFiles(t *testing.T) {
+	file := func(content string) UnionContent {
+		return UnionContent{FileContent: FileContent{Type: "file", Encoding: "utf-8", Size: len(content), Content: content}}
+	}
+	repo := map[string]UnionContent{
+		"go.mod":     file("module example\n"),
+		"main.go":    file("package main\n\nfunc main() {}\n"),
+		"logo.png":   file("\x89PNG\r\n\x1a\n\x00\x00"),
+		"cmd":        {isArray: true},
+		"big.json":   {FileContent: FileContent{Type: "file", Encoding: "none", Size: 2 << 20}},
+		"Dockerfile": file("FROM scratch\n"),
+	}
+	fetched := []string{}
+	fetch := func(path string) (UnionContent, error) {
+		fetched = append(fetched, path)
+		if uc, ok := repo[path]; ok {
+			return uc, nil
+		}
+		return UnionContent{}, &contentsError{Status: 404, Body: []byte(`{"message":"Not Found"}`)}
+	}
+
+	result := getFiles([]string{"go.mod", "missing.txt", "logo.png", "cmd", "big.json", "main.go", "Dockerfile"}, 25, fetch)
+	if result.IsError != nil {
+		t.Errorf("partial success reported as an error")
+	}
+	if len(result.Content) != 3 {
+		t.Fatalf("got %d blocks", len(result.Content))
+	}
+	if got := *result.Content[0].Text; got != "==> go.mod <==\nmodule example\n" {
+		t.Errorf("first block = %q", got)
+	}
+	if got := *result.Content[1].Text; got != "==> main.go <==\npackage ma\n[truncated: the size budget of 25 bytes ran out]" {
+		t.Errorf("second block = %q", got)
+	}
+	want := "Skipped:\n" +
+		"- missing.txt: not found\n" +
+		"- logo.png: binary file of 10 bytes\n" +
+		"- cmd: is a directory\n" +
+		"- big.json: 2097152 bytes, too large for the contents API\n" +
+		"- Dockerfile: size budget of 25 bytes used up\n\n" +
+		"Truncated:\n" +
+		"- main.go: showing the first 10 of 29 bytes"
+	if got := *result.Content[2].Text; got != want {
+		t.Errorf("notes =\n%s\nwant\n%s", got, want)
+	}
+	if strings.Contains(strings.Join(fetched, ","), "Dockerfile") {
+		t.Error("fetched a file after the budget ran out")
+	}
+
+	result = getFiles([]string{"missing.txt"}, 100, fetch)
+	if result.IsError == nil || !*result.IsError {
+		t.Error("no files returned should be an error")
+	}
+}
+
+func TestCutText(t *testing.T) {
+	if got := cutText("héllo", 2); got != "h" {
+		t.Errorf("cut inside a character: %q", got)
+	}
+	if got := cutText("héllo", 10); got != "héllo" {
+		t.Errorf("short text changed: %q", got)
+	}
+}
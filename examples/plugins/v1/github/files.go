@@ -41,6 +41,22 @@ var (
 			"required": []string{"owner", "repo", "path", "content", "message", "branch"},
 		},
 	}
+	DeleteFileTool = ToolDescription{
+		Name:        "gh-delete-file",
+		Description: "Delete a file from a GitHub repository in a new commit. Directories can't be deleted this way.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":   prop("string", "The owner of the repository"),
+				"repo":    prop("string", "The repository name"),
+				"path":    prop("string", "The path of the file"),
+				"message": prop("string", "The commit message"),
+				"branch":  prop("string", "(optional) The branch name, defaults to the repository's default branch"),
+				"sha":     prop("string", "(optional) The blob sha of the file being deleted, looked up when omitted"),
+			},
+			"required": []string{"owner", "repo", "path", "message"},
+		},
+	}
 	PushFilesTool = ToolDescription{
 		Name:        "gh-push-files",
 		Description: "Push files to a GitHub repository",
@@ -67,8 +83,14 @@ var (
 	}
 	FileTools = []ToolDescription{
 		GetFileContentsTool,
+		GetFilesTool,
+		DownloadFileTool,
 		CreateOrUpdateFileTool,
+		DeleteFileTool,
 		PushFilesTool,
+		GetTreeTool,
+		CopyFileTool,
+		GetReadmeTool,
 	}
 )
 
@@ -109,41 +131,109 @@ func filesCreateOrUpdate(apiKey string, owner string, repo string, path string,
 	}
 
 	url := fmt.Sprint("https://api.github.com/repos/", owner, "/", repo, "/contents/", path)
-	req := pdk.NewHTTPRequest(pdk.MethodPut, url)
-	req.SetHeader("Authorization", fmt.Sprint("token ", apiKey))
-	req.SetHeader("Accept", "application/vnd.github.v3+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
-
-	res, err := json.Marshal(file)
+	// 201 when the file was created, 200 when an existing one was updated
+	body, err := githubSendExpect(apiKey, pdk.MethodPut, url, file, 201, 200)
 	if err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprint("Failed to marshal file: ", err)),
+				Text: some(fmt.Sprint("Failed to create or update file: ", err)),
 			}},
 		}, nil
 	}
 
-	req.SetBody([]byte(res))
-	resp := req.Send()
-	if resp.Status() != 201 {
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(body)),
+		}},
+	}, nil
+
+}
+
+type FileDelete struct {
+	Message string  `json:"message"`
+	Branch  *string `json:"branch,omitempty"`
+	Sha     string  `json:"sha"`
+}
+
+func fileDeleteFromArgs(args map[string]interface{}) FileDelete {
+	file := FileDelete{}
+	if message, ok := args["message"].(string); ok {
+		file.Message = message
+	}
+	if branch, ok := args["branch"].(string); ok && branch != "" {
+		file.Branch = some(branch)
+	}
+	if sha, ok := args["sha"].(string); ok {
+		file.Sha = sha
+	}
+	return file
+}
+
+// fileDeleteSha returns the sha to delete path with, preferring the one the
+// caller gave. The contents API answers an opaque 422 for directories, so
+// they are caught here first.
+func fileDeleteSha(uc UnionContent, path, sha string) (string, error) {
+	if uc.isArray {
+		return "", fmt.Errorf("%s is a directory with %d entries; only files can be deleted, so delete them one at a time", path, len(uc.DirectoryContents))
+	}
+	if uc.FileContent.Type != "" && uc.FileContent.Type != "file" && uc.FileContent.Type != "symlink" {
+		return "", fmt.Errorf("%s is a %s, not a file", path, uc.FileContent.Type)
+	}
+	if sha != "" {
+		return sha, nil
+	}
+	return uc.FileContent.Sha, nil
+}
+
+func filesDelete(apiKey string, owner string, repo string, path string, file FileDelete) CallToolResult {
+	failed := func(message string) CallToolResult {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprint("Failed to create or update file: ", resp.Status(), " ", string(resp.Body()))),
+				Text: some(message),
 			}},
-		}, nil
+		}
+	}
+
+	uc, err := filesGetContentsInternal(apiKey, owner, repo, path, file.Branch)
+	if err != nil {
+		return failed(err.Error())
+	}
+	if file.Sha, err = fileDeleteSha(uc, path, file.Sha); err != nil {
+		return failed(err.Error())
+	}
+
+	u := fmt.Sprint("https://api.github.com/repos/", owner, "/", repo, "/contents/", path)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Deleting file: ", u))
+	var response struct {
+		Commit struct {
+			Sha     string `json:"sha"`
+			HTMLURL string `json:"html_url"`
+		} `json:"commit"`
+	}
+	if err := githubSendJSON(apiKey, pdk.MethodDelete, u, file, 200, &response); err != nil {
+		return failed(fmt.Sprint("Failed to delete file: ", err))
+	}
+
+	responseJSON, err := json.Marshal(map[string]string{
+		"path":     path,
+		"commit":   response.Commit.Sha,
+		"html_url": response.Commit.HTMLURL,
+	})
+	if err != nil {
+		return failed(fmt.Sprint("Failed to marshal response: ", err))
 	}
 
 	return CallToolResult{
 		Content: []Content{{
 			Type: ContentTypeText,
-			Text: some(string(resp.Body())),
+			Text: some(string(responseJSON)),
 		}},
-	}, nil
-
+	}
 }
 
 type UnionContent struct {
@@ -205,6 +295,18 @@ func filesGetContents(apiKey string, owner string, repo string, path string, bra
 	}
 }
 
+// contentsError is a contents API request that didn't answer 200, kept
+// typed so callers can tell a missing path from other failures.
+type contentsError struct {
+	Status uint16
+	Body   []byte
+	URL    string
+}
+
+func (e *contentsError) Error() string {
+	return fmt.Sprintf("Failed to get file contents: %d %s (%s)", e.Status, string(e.Body), e.URL)
+}
+
 func filesGetContentsInternal(apiKey string, owner string, repo string, path string, branch *string) (UnionContent, error) {
 	u := fmt.Sprint("https://api.github.com/repos/", owner, "/", repo, "/contents/", path)
 
@@ -214,28 +316,37 @@ func filesGetContentsInternal(apiKey string, owner string, repo string, path str
 	}
 	u = fmt.Sprint(u, "?", params.Encode())
 
-	req := pdk.NewHTTPRequest(pdk.MethodGet, u)
-	req.SetHeader("Authorization", fmt.Sprint("token ", apiKey))
-	req.SetHeader("Accept", "application/vnd.github.v3+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
-
-	resp := req.Send()
-	if resp.Status() != 200 {
-		return UnionContent{}, fmt.Errorf("Failed to get file contents: %d %s (%s)", resp.Status(), string(resp.Body()), u)
+	status, body, err := githubSend(apiKey, pdk.MethodGet, u, nil)
+	if err != nil {
+		return UnionContent{}, err
+	}
+	if status != 200 {
+		return UnionContent{}, &contentsError{Status: status, Body: body, URL: u}
 	}
+	return parseContents(body)
+}
 
+// parseContents reads a contents API answer, a file or a directory listing.
+// A file's base64 content is replaced with the decoded text; files too large
+// for the API keep encoding "none" and no content.
+func parseContents(body []byte) (UnionContent, error) {
 	// attempt to parse this as a file
 	uc := UnionContent{}
 	fc := &uc.FileContent
-	if err := json.Unmarshal(resp.Body(), fc); err == nil {
-		base64.StdEncoding.DecodeString(fc.Content)
-		// replace it with the decoded content
-		fc.Content = string(fc.Content)
+	if err := json.Unmarshal(body, fc); err == nil {
+		if fc.Encoding == "base64" {
+			text, err := decodeContent(fc.Path, fc.Encoding, fc.Content)
+			if err != nil {
+				return UnionContent{}, err
+			}
+			// replace it with the decoded content
+			fc.Content, fc.Encoding = text, "utf-8"
+		}
 		return uc, nil
 	} else {
 		// if it's not a file, try to parse it as a directory
 		d := []DirectoryContent{}
-		if err := json.Unmarshal(resp.Body(), &d); err != nil {
+		if err := json.Unmarshal(body, &d); err != nil {
 			return UnionContent{}, fmt.Errorf("Failed to unmarshal directory contents: %w", err)
 		}
 		uc.DirectoryContents = d
@@ -266,25 +377,17 @@ func filePushFromArgs(args map[string]interface{}) []FileOperation {
 
 func filesPush(apiKey, owner, repo, branch, message string, files []FileOperation) CallToolResult {
 	url := fmt.Sprint("https://api.github.com/repos/", owner, "/", repo, "/git/refs/heads/", branch)
-	req := pdk.NewHTTPRequest(pdk.MethodGet, url)
-	req.SetHeader("Authorization", fmt.Sprint("token ", apiKey))
-	req.SetHeader("Accept", "application/vnd.github.v3+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
-
-	resp := req.Send()
-	if resp.Status() != 200 {
+	ref := RefSchema{}
+	if _, err := githubGetJSON(apiKey, url, &ref); err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprint("Failed to get branch: ", resp.Status())),
+				Text: some(fmt.Sprint("Failed to get branch: ", err)),
 			}},
 		}
 	}
 
-	ref := RefSchema{}
-	json.Unmarshal(resp.Body(), &ref)
-
 	commitSha := ref.Object.Sha
 	if tree, err := createTree(apiKey, owner, repo, files, commitSha); err != nil {
 		return CallToolResult{
@@ -336,27 +439,24 @@ func createTree(apiKey, owner, repo string, files []FileOperation, baseTree stri
 	}
 
 	url := fmt.Sprint("https://api.github.com/repos/", owner, "/", repo, "/git/trees")
-	req := pdk.NewHTTPRequest(pdk.MethodPost, url)
-	req.SetHeader("Authorization", fmt.Sprint("token ", apiKey))
-	req.SetHeader("Accept", "application/vnd.github.v3+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
-	req.SetHeader("Content-Type", "application/json")
-
-	res, err := json.Marshal(tree)
-
+	body, err := githubSendExpect(apiKey, pdk.MethodPost, url, tree, 201)
 	if err != nil {
-		return TreeSchema{}, fmt.Errorf("Failed to marshal tree: %w", err)
-	}
-	req.SetBody(res)
-
-	resp := req.Send()
-	if resp.Status() != 201 {
-		return TreeSchema{}, fmt.Errorf("Failed to create tree: %d %s", resp.Status(), string(resp.Body()))
+		return TreeSchema{}, fmt.Errorf("Failed to create tree: %w", err)
 	}
+	return parseCreatedTree(body)
+}
 
+// parseCreatedTree reads the new tree from GitHub's answer. The commit is
+// built on its sha, so a tree without one is an error.
+func parseCreatedTree(body []byte) (TreeSchema, error) {
 	ts := TreeSchema{}
-	err = json.Unmarshal(resp.Body(), &res)
-	return ts, err
+	if err := json.Unmarshal(body, &ts); err != nil {
+		return TreeSchema{}, fmt.Errorf("Failed to parse tree: %w", err)
+	}
+	if ts.Sha == "" {
+		return TreeSchema{}, fmt.Errorf("Failed to parse tree: no sha in %s", string(body))
+	}
+	return ts, nil
 }
 
 type Author struct {
@@ -390,43 +490,24 @@ func createCommit(apiKey, owner, repo, message, tree string, parents []string) (
 	}
 
 	url := fmt.Sprint("https://api.github.com/repos/", owner, "/", repo, "/git/commits")
-	req := pdk.NewHTTPRequest(pdk.MethodPost, url)
-	req.SetHeader("Authorization", fmt.Sprint("token ", apiKey))
-	req.SetHeader("Accept", "application/vnd.github.v3+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
-	req.SetHeader("Content-Type", "application/json")
-
-	res, _ := json.Marshal(commit)
-	req.SetBody(res)
-
-	resp := req.Send()
-	if resp.Status() != 201 {
-		return Commit{}, fmt.Errorf("Failed to create commit: %d %s", resp.Status(), string(resp.Body()))
-	}
-
 	cs := Commit{}
-	json.Unmarshal(resp.Body(), &cs)
+	if err := githubSendJSON(apiKey, pdk.MethodPost, url, commit, 201, &cs); err != nil {
+		return Commit{}, fmt.Errorf("Failed to create commit: %w", err)
+	}
 	return cs, nil
 }
 
 func updateRef(apiKey, owner, repo, ref, sha string) CallToolResult {
 	url := fmt.Sprint("https://api.github.com/repos/", owner, "/", repo, "/git/refs/", ref)
-	req := pdk.NewHTTPRequest(pdk.MethodPatch, url)
-	req.SetHeader("Authorization", fmt.Sprint("token ", apiKey))
-	req.SetHeader("Accept", "application/vnd.github.v3+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
-	req.SetHeader("Content-Type", "application/json")
-
-	res, _ := json.Marshal(map[string]any{"sha": sha, "force": true})
-	req.SetBody(res)
-
-	resp := req.Send()
-	if resp.Status() != 200 {
+	// The new commit is always parented on the current head, so a fast-forward
+	// is enough; forcing would silently drop commits pushed in the meantime.
+	body, err := githubSendExpect(apiKey, pdk.MethodPatch, url, map[string]any{"sha": sha, "force": false}, 200)
+	if err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprint("Failed to update ref: ", resp.Status(), " ", string(resp.Body()))),
+				Text: some(fmt.Sprint("Failed to update ref: ", err)),
 			}},
 		}
 	}
@@ -434,7 +515,7 @@ func updateRef(apiKey, owner, repo, ref, sha string) CallToolResult {
 	return CallToolResult{
 		Content: []Content{{
 			Type: ContentTypeText,
-			Text: some(string(resp.Body())),
+			Text: some(string(body)),
 		}},
 	}
 }
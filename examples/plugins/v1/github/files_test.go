@@ -0,0 +1,51 @@
+package main
+
+import "testing"
+
+func TestFileDeleteSha(t *testing.T) {
+	file := UnionContent{FileContent: FileContent{Type: "file", Path: "go.mod", Sha: "3d21ec53"}}
+	if sha, err := fileDeleteSha(file, "go.mod", ""); err != nil || sha != "3d21ec53" {
+		t.Errorf("looked up sha = %q, %v", sha, err)
+	}
+	if sha, err := fileDeleteSha(file, "go.mod", "95b966ae"); err != nil || sha != "95b966ae" {
+		t.Errorf("given sha = %q, %v", sha, err)
+	}
+
+	dir := UnionContent{isArray: true, DirectoryContents: []DirectoryContent{{Name: "a.go"}, {Name: "b.go"}}}
+	_, err := fileDeleteSha(dir, "pkg", "")
+	if want := "pkg is a directory with 2 entries; only files can be deleted, so delete them one at a time"; err == nil || err.Error() != want {
+		t.Errorf("directory: %v", err)
+	}
+
+	submodule := UnionContent{FileContent: FileContent{Type: "submodule", Sha: "fa1c3b2e"}}
+	if _, err := fileDeleteSha(submodule, "vendor/lib", ""); err == nil || err.Error() != "vendor/lib is a submodule, not a file" {
+		t.Errorf("submodule: %v", err)
+	}
+}
+
+func TestParseCreatedTree(t *testing.T) {
+	tree, err := parseCreatedTree([]byte(`{"sha":"cd8274d1","url":"https://api.github.com/repos/acme/api/git/trees/cd8274d1","tree":[]}`))
+	if err != nil || tree.Sha != "cd8274d1" {
+		t.Errorf("tree = %+v, %v", tree, err)
+	}
+	if _, err := parseCreatedTree([]byte(`{"tree":[]}`)); err == nil {
+		t.Error("a tree without a sha was accepted")
+	}
+}
+
+func TestParseContents(t *testing.T) {
+	file, err := parseContents([]byte(`{"type":"file","path":"go.mod","encoding":"base64","content":"bW9kdWxlIGdp\ndGh1Ygo=\n"}`))
+	if err != nil || file.isArray || file.FileContent.Content != "module github\n" || file.FileContent.Encoding != "utf-8" {
+		t.Errorf("file = %+v, %v", file, err)
+	}
+
+	large, err := parseContents([]byte(`{"type":"file","path":"big.json","encoding":"none","content":"","size":2097152}`))
+	if err != nil || large.FileContent.Encoding != "none" || large.FileContent.Content != "" {
+		t.Errorf("large file = %+v, %v", large, err)
+	}
+
+	dir, err := parseContents([]byte(`[{"type":"file","name":"a.go"},{"type":"dir","name":"pkg"}]`))
+	if err != nil || !dir.isArray || len(dir.DirectoryContents) != 2 {
+		t.Errorf("directory = %+v, %v", dir, err)
+	}
+}
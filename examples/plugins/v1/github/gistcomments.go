@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ListGistCommentsTool = ToolDescription{
+		Name:        "gh-list-gist-comments",
+		Description: "List the comments on a gist, oldest first",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"gist_id":  prop("string", "The unique identifier of the gist."),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"gist_id"},
+		},
+	}
+	AddGistCommentTool = ToolDescription{
+		Name:        "gh-add-gist-comment",
+		Description: "Comment on a gist",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"gist_id": prop("string", "The unique identifier of the gist."),
+				"body":    prop("string", "The comment text (Markdown)"),
+			},
+			"required": []string{"gist_id", "body"},
+		},
+	}
+	DeleteGistCommentTool = ToolDescription{
+		Name:        "gh-delete-gist-comment",
+		Description: "Delete a comment on a gist. Asks the user to confirm first.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"gist_id":    prop("string", "The unique identifier of the gist."),
+				"comment_id": prop("integer", "The comment id from gh-list-gist-comments"),
+			},
+			"required": []string{"gist_id", "comment_id"},
+		},
+	}
+)
+
+type GistComment struct {
+	ID   int64 `json:"id"`
+	User *struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	CreatedAt string `json:"created_at"`
+	Body      string `json:"body"`
+}
+
+type GistCommentSummary struct {
+	ID        int64  `json:"id"`
+	Author    string `json:"author"`
+	CreatedAt string `json:"created_at"`
+	Body      string `json:"body"`
+}
+
+// summarizeGistComments names the author of each comment; deleted users
+// show up as ghost, as on github.com.
+func summarizeGistComments(comments []GistComment) []GistCommentSummary {
+	summaries := []GistCommentSummary{}
+	for _, c := range comments {
+		author := "ghost"
+		if c.User != nil {
+			author = c.User.Login
+		}
+		summaries = append(summaries, GistCommentSummary{ID: c.ID, Author: author, CreatedAt: c.CreatedAt, Body: c.Body})
+	}
+	return summaries
+}
+
+func gistCommentsList(apiKey, gistId string, args map[string]interface{}) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/gists/%s/comments?%s", gistId, strings.Join(paginationParams(args), "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing gist comments: ", u))
+
+	comments := []GistComment{}
+	if _, err := githubGetJSON(apiKey, u, &comments); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list gist comments: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(summarizeGistComments(comments))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func gistCommentsAdd(apiKey, gistId, body string) CallToolResult {
+	if strings.TrimSpace(body) == "" {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some("The comment body must not be empty"),
+			}},
+		}
+	}
+
+	u := fmt.Sprintf("https://api.github.com/gists/%s/comments", gistId)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Adding gist comment: ", u))
+	var comment GistComment
+	if err := githubSendJSON(apiKey, pdk.MethodPost, u, map[string]string{"body": body}, 201, &comment); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to add gist comment: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(summarizeGistComments([]GistComment{comment})[0])
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func gistCommentsDelete(apiKey, gistId string, commentId int64) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/gists/%s/comments/%d", gistId, commentId)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Deleting gist comment: ", u))
+	_, err := githubSendExpect(apiKey, pdk.MethodDelete, u, nil, 204)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to delete gist comment: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(fmt.Sprintf("Deleted comment %d on gist %s", commentId, gistId)),
+		}},
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSummarizeGistComments(t *testing.T) {
+	var comments []GistComment
+	if err := json.Unmarshal([]byte(`[
+		{"id":1,"user":{"login":"octocat"},"created_at":"2024-05-01T10:00:00Z","body":"Nice"},
+		{"id":2,"user":null,"created_at":"2024-05-02T10:00:00Z","body":"Thanks"}
+	]`), &comments); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(summarizeGistComments(comments))
+	want := `[{"id":1,"author":"octocat","created_at":"2024-05-01T10:00:00Z","body":"Nice"},{"id":2,"author":"ghost","created_at":"2024-05-02T10:00:00Z","body":"Thanks"}]`
+	if string(got) != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+}
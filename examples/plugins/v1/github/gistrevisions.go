@@ -0,0 +1,223 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ListGistCommitsTool = ToolDescription{
+		Name:        "gh-list-gist-commits",
+		Description: "List the revisions of a gist, newest first, with the lines each one added and deleted",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"gist_id":  prop("string", "The unique identifier of the gist."),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"gist_id"},
+		},
+	}
+	GetGistRevisionTool = ToolDescription{
+		Name:        "gh-get-gist-revision",
+		Description: "Get the files of a gist as they were at a revision from gh-list-gist-commits",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"gist_id":  prop("string", "The unique identifier of the gist."),
+				"sha":      prop("string", "The revision's version sha"),
+				"filename": prop("string", "Only return this file"),
+			},
+			"required": []string{"gist_id", "sha"},
+		},
+	}
+)
+
+type GistCommit struct {
+	Version string `json:"version"`
+	User    *struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	CommittedAt  string `json:"committed_at"`
+	ChangeStatus struct {
+		Total     int `json:"total"`
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+	} `json:"change_status"`
+}
+
+type GistCommitSummary struct {
+	Version      string `json:"version"`
+	Author       string `json:"author"`
+	CommittedAt  string `json:"committed_at"`
+	ChangeStatus struct {
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+	} `json:"change_status"`
+}
+
+type GistFile struct {
+	Filename  string  `json:"filename"`
+	Language  *string `json:"language"`
+	Size      int     `json:"size"`
+	RawURL    string  `json:"raw_url"`
+	Truncated bool    `json:"truncated"`
+	Content   string  `json:"content"`
+}
+
+type GistRevisionFile struct {
+	Filename  string  `json:"filename"`
+	Language  *string `json:"language"`
+	Size      int     `json:"size"`
+	Content   string  `json:"content"`
+	Truncated bool    `json:"truncated,omitempty"`
+	RawURL    string  `json:"raw_url,omitempty"`
+	Note      string  `json:"note,omitempty"`
+}
+
+func summarizeGistCommits(commits []GistCommit) []GistCommitSummary {
+	summaries := []GistCommitSummary{}
+	for _, c := range commits {
+		summary := GistCommitSummary{Version: c.Version, Author: "ghost", CommittedAt: c.CommittedAt}
+		if c.User != nil {
+			summary.Author = c.User.Login
+		}
+		summary.ChangeStatus.Additions = c.ChangeStatus.Additions
+		summary.ChangeStatus.Deletions = c.ChangeStatus.Deletions
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// gistRevisionFiles lists the files in name order, or only the named one.
+// The API cuts file content off at about a megabyte and marks it
+// truncated, so those files are fetched again in full from their raw_url.
+// When that fails the partial content is kept along with the raw_url.
+func gistRevisionFiles(files map[string]GistFile, filename string, fetchRaw func(string) ([]byte, error)) ([]GistRevisionFile, error) {
+	names := []string{}
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if filename != "" {
+		if _, ok := files[filename]; !ok {
+			return nil, fmt.Errorf("the revision has no file %q; its files are %s", filename, strings.Join(names, ", "))
+		}
+		names = []string{filename}
+	}
+
+	result := []GistRevisionFile{}
+	for _, name := range names {
+		f := files[name]
+		file := GistRevisionFile{Filename: name, Language: f.Language, Size: f.Size, Content: f.Content}
+		if f.Truncated {
+			if raw, err := fetchRaw(f.RawURL); err == nil {
+				file.Content = string(raw)
+			} else {
+				file.Truncated = true
+				file.RawURL = f.RawURL
+				file.Note = fmt.Sprintf("Content is cut off at %d of %d bytes, and the full file could not be fetched: %s", len(f.Content), f.Size, err)
+			}
+		}
+		result = append(result, file)
+	}
+	return result, nil
+}
+
+// gistFetchRaw downloads a file from gist.githubusercontent.com. Raw URLs
+// embed the revision, so they need no token.
+func gistFetchRaw(u string) ([]byte, error) {
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Fetching raw gist file: ", u))
+	resp := pdk.NewHTTPRequest(pdk.MethodGet, u).Send()
+	switch resp.Status() {
+	case 200:
+		return resp.Body(), nil
+	case 0:
+		return nil, fmt.Errorf("no response; add gist.githubusercontent.com to allowed_hosts")
+	}
+	return nil, fmt.Errorf("%d %s", resp.Status(), string(resp.Body()))
+}
+
+func gistCommitsList(apiKey, gistId string, args map[string]interface{}) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/gists/%s/commits?%s", gistId, strings.Join(paginationParams(args), "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing gist commits: ", u))
+
+	commits := []GistCommit{}
+	if _, err := githubGetJSON(apiKey, u, &commits); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list gist commits: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(summarizeGistCommits(commits))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func gistGetRevision(apiKey, gistId, sha, filename string) CallToolResult {
+	failed := func(message string) CallToolResult {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(message),
+			}},
+		}
+	}
+
+	u := fmt.Sprintf("https://api.github.com/gists/%s/%s", gistId, sha)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting gist revision: ", u))
+	var revision struct {
+		ID          string              `json:"id"`
+		Description *string             `json:"description"`
+		Files       map[string]GistFile `json:"files"`
+	}
+	if _, err := githubGetJSON(apiKey, u, &revision); err != nil {
+		return failed(fmt.Sprintf("Failed to get revision %s of gist %s: %s", sha, gistId, err))
+	}
+
+	files, err := gistRevisionFiles(revision.Files, filename, gistFetchRaw)
+	if err != nil {
+		return failed(err.Error())
+	}
+
+	responseJSON, err := json.Marshal(map[string]interface{}{
+		"id":          revision.ID,
+		"version":     sha,
+		"description": revision.Description,
+		"files":       files,
+	})
+	if err != nil {
+		return failed(fmt.Sprintf("Failed to marshal response: %s", err))
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
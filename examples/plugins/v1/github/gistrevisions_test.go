@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestSummarizeGistCommits(t *testing.T) {
+	var commits []GistCommit
+	if err := json.Unmarshal([]byte(`[
+		{"version":"57a7f021","user":{"login":"octocat"},"committed_at":"2024-05-02T10:00:00Z","change_status":{"total":5,"additions":3,"deletions":2}},
+		{"version":"1a2b3c4d","user":null,"committed_at":"2024-05-01T10:00:00Z","change_status":{"total":1,"additions":1,"deletions":0}}
+	]`), &commits); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(summarizeGistCommits(commits))
+	want := `[{"version":"57a7f021","author":"octocat","committed_at":"2024-05-02T10:00:00Z","change_status":{"additions":3,"deletions":2}},` +
+		`{"version":"1a2b3c4d","author":"ghost","committed_at":"2024-05-01T10:00:00Z","change_status":{"additions":1,"deletions":0}}]`
+	if string(got) != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestGistRevisionFiles(t *testing.T) {
+	files := map[string]GistFile{
+		"b.txt": {Filename: "b.txt", Size: 2, Content: "hi"},
+		"a.log": {Filename: "a.log", Size: 10, RawURL: "https://gist.githubusercontent.com/raw/a.log", Truncated: true, Content: "0123"},
+	}
+	fetched := func(u string) ([]byte, error) {
+		if u != "https://gist.githubusercontent.com/raw/a.log" {
+			t.Errorf("fetched %s", u)
+		}
+		return []byte("0123456789"), nil
+	}
+
+	got, err := gistRevisionFiles(files, "", fetched)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Filename != "a.log" || got[1].Filename != "b.txt" {
+		t.Fatalf("got %+v", got)
+	}
+	if got[0].Content != "0123456789" || got[0].Truncated || got[0].RawURL != "" {
+		t.Errorf("truncated file not refetched: %+v", got[0])
+	}
+
+	unreachable := func(string) ([]byte, error) { return nil, fmt.Errorf("no response") }
+	got, err = gistRevisionFiles(files, "a.log", unreachable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || !got[0].Truncated || got[0].Content != "0123" || got[0].RawURL == "" {
+		t.Errorf("got %+v", got)
+	}
+	if want := "Content is cut off at 4 of 10 bytes, and the full file could not be fetched: no response"; got[0].Note != want {
+		t.Errorf("note = %q", got[0].Note)
+	}
+
+	if _, err := gistRevisionFiles(files, "c.md", fetched); err == nil || err.Error() != `the revision has no file "c.md"; its files are a.log, b.txt` {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -3,6 +3,10 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/extism/go-pdk"
 )
@@ -46,12 +50,12 @@ var (
 	}
 	UpdateGistTool = ToolDescription{
 		Name:        "gh-update-gist",
-		Description: "Lists pull requests in a specified repository. Supports different response formats via accept parameter.",
+		Description: "Update the files of a gist, and its description when one is given",
 		InputSchema: schema{
 			"type": "object",
 			"properties": props{
 				"gist_id":     prop("string", "The unique identifier of the gist."),
-				"description": prop("string", "Description of the gist"),
+				"description": prop("string", "New description of the gist; left unchanged when omitted"),
 				"files": SchemaProperty{
 					Type:        "object",
 					Description: "Files contained in the gist.",
@@ -72,7 +76,7 @@ var (
 	}
 	DeleteGistTool = ToolDescription{
 		Name:        "gh-delete-gist",
-		Description: "Delete a specified gist.",
+		Description: "Delete a specified gist. Asks the user to confirm first.",
 		InputSchema: schema{
 			"type": "object",
 			"properties": props{
@@ -81,6 +85,42 @@ var (
 			"required": []string{"gist_id"},
 		},
 	}
+	ListGistsTool = ToolDescription{
+		Name:        "gh-list-gists",
+		Description: "List a user's public gists, or all of the authenticated user's gists when no username is given",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"username": prop("string", "The user's login (default: the authenticated user)"),
+				"since":    prop("string", "Only gists updated after this time (ISO 8601 timestamp YYYY-MM-DDTHH:MM:SSZ)"),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+		},
+	}
+	ForkGistTool = ToolDescription{
+		Name:        "gh-fork-gist",
+		Description: "Fork a gist into the authenticated user's account and return the new gist's id",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"gist_id": prop("string", "The unique identifier of the gist to fork."),
+			},
+			"required": []string{"gist_id"},
+		},
+	}
+	ListStarredGistsTool = ToolDescription{
+		Name:        "gh-list-starred-gists",
+		Description: "List the gists the authenticated user has starred",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"since":    prop("string", "Only gists updated after this time (ISO 8601 timestamp YYYY-MM-DDTHH:MM:SSZ)"),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+		},
+	}
 )
 
 var GistTools = []ToolDescription{
@@ -88,38 +128,117 @@ var GistTools = []ToolDescription{
 	GetGistTool,
 	UpdateGistTool,
 	DeleteGistTool,
+	ListGistsTool,
+	ListStarredGistsTool,
+	ForkGistTool,
+	StarGistTool,
+	UnstarGistTool,
+	IsGistStarredTool,
+	ListGistCommitsTool,
+	GetGistRevisionTool,
+	ListGistCommentsTool,
+	AddGistCommentTool,
+	DeleteGistCommentTool,
 }
 
-func gistCreate(apiKey, description string, files map[string]any) CallToolResult {
-	url := "https://api.github.com/gists"
-	req := pdk.NewHTTPRequest(pdk.MethodPost, url)
-	req.SetHeader("Authorization", fmt.Sprintf("token %s", apiKey))
-	req.SetHeader("Content-Type", "application/json")
-	req.SetHeader("Accept", "application/vnd.github+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
+type Gist struct {
+	ID          string                     `json:"id"`
+	Description *string                    `json:"description"`
+	Public      bool                       `json:"public"`
+	Files       map[string]json.RawMessage `json:"files"`
+	Owner       *struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+	UpdatedAt string `json:"updated_at"`
+	HTMLURL   string `json:"html_url"`
+}
 
-	data := map[string]any{
-		"description": description,
-		"files":       files,
+type GistSummary struct {
+	ID          string   `json:"id"`
+	Description *string  `json:"description"`
+	Files       []string `json:"files"`
+	Public      bool     `json:"public"`
+	Owner       string   `json:"owner,omitempty"`
+	UpdatedAt   string   `json:"updated_at"`
+	HTMLURL     string   `json:"html_url"`
+}
+
+func summarizeGists(gists []Gist) []GistSummary {
+	summaries := []GistSummary{}
+	for _, g := range gists {
+		summary := GistSummary{
+			ID:          g.ID,
+			Description: g.Description,
+			Files:       []string{},
+			Public:      g.Public,
+			UpdatedAt:   g.UpdatedAt,
+			HTMLURL:     g.HTMLURL,
+		}
+		for name := range g.Files {
+			summary.Files = append(summary.Files, name)
+		}
+		sort.Strings(summary.Files)
+		if g.Owner != nil {
+			summary.Owner = g.Owner.Login
+		}
+		summaries = append(summaries, summary)
 	}
-	res, err := json.Marshal(data)
+	return summaries
+}
+
+// userGistsURL lists the user's public gists, or every gist of the
+// authenticated user when username is empty.
+func userGistsURL(username string) string {
+	if username == "" {
+		return "https://api.github.com/gists"
+	}
+	return fmt.Sprintf("https://api.github.com/users/%s/gists", url.PathEscape(username))
+}
+
+// gistsListURL adds since and pagination to base, one of the gist listing
+// endpoints.
+func gistsListURL(base string, args map[string]interface{}) (string, error) {
+	params := paginationParams(args)
+	if since, _ := args["since"].(string); since != "" {
+		if _, err := time.Parse(time.RFC3339, since); err != nil {
+			return "", fmt.Errorf("since must be an ISO 8601 timestamp like 2024-06-30T17:00:00Z, got %q", since)
+		}
+		params = append(params, "since="+since)
+	}
+	return fmt.Sprintf("%s?%s", base, strings.Join(params, "&")), nil
+}
+
+func gistsList(apiKey, base string, args map[string]interface{}) CallToolResult {
+	u, err := gistsListURL(base, args)
 	if err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprintf("Failed to marshal gist data: %s", err)),
+				Text: some(fmt.Sprintf("Invalid gist listing: %s", err)),
 			}},
 		}
 	}
-	req.SetBody(res)
-	resp := req.Send()
-	if resp.Status() != 201 {
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing gists: ", u))
+
+	gists := []Gist{}
+	if _, err := githubGetJSON(apiKey, u, &gists); err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprintf("Failed to create gist: %d %s", resp.Status(), string(resp.Body()))),
+				Text: some(fmt.Sprintf("Failed to list gists: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(summarizeGists(gists))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
 			}},
 		}
 	}
@@ -127,41 +246,126 @@ func gistCreate(apiKey, description string, files map[string]any) CallToolResult
 	return CallToolResult{
 		Content: []Content{{
 			Type: ContentTypeText,
-			Text: some(string(resp.Body())),
+			Text: some(string(responseJSON)),
 		}},
 	}
 }
 
-func gistUpdate(apiKey, gistId, description string, files map[string]any) CallToolResult {
-	url := fmt.Sprintf("https://api.github.com/gists/%s", gistId)
-	req := pdk.NewHTTPRequest(pdk.MethodPatch, url)
-	req.SetHeader("Authorization", fmt.Sprintf("token %s", apiKey))
-	req.SetHeader("Content-Type", "application/json")
-	req.SetHeader("Accept", "application/vnd.github+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
+// gistStatusError describes a gist request that didn't answer with the
+// want status. GitHub answers 404 for gists the token can't see as well as
+// for missing ones.
+func gistStatusError(action, gistId string, want, status uint16, body []byte) error {
+	switch status {
+	case want:
+		return nil
+	case 404:
+		return fmt.Errorf("Failed to %s gist %s: not found, or not visible to this token", action, gistId)
+	}
+	return fmt.Errorf("Failed to %s gist %s: %w", action, gistId, githubStatusError(status, body))
+}
+
+type ForkedGist struct {
+	ID         string `json:"id"`
+	HTMLURL    string `json:"html_url"`
+	ForkedFrom string `json:"forked_from"`
+}
+
+// forkedGist picks the new gist's id out of the answer to a fork.
+func forkedGist(gistId string, body []byte) (ForkedGist, error) {
+	var fork Gist
+	if err := json.Unmarshal(body, &fork); err != nil {
+		return ForkedGist{}, err
+	}
+	if fork.ID == "" {
+		return ForkedGist{}, fmt.Errorf("the response has no gist id")
+	}
+	return ForkedGist{ID: fork.ID, HTMLURL: fork.HTMLURL, ForkedFrom: gistId}, nil
+}
+
+func gistFork(apiKey, gistId string) CallToolResult {
+	failed := func(message string) CallToolResult {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(message),
+			}},
+		}
+	}
 
+	u := fmt.Sprintf("https://api.github.com/gists/%s/forks", gistId)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Forking gist: ", u))
+	body, err := githubSendExpect(apiKey, pdk.MethodPost, u, map[string]interface{}{}, 201)
+	var fork ForkedGist
+	if err == nil {
+		fork, err = forkedGist(gistId, body)
+	}
+	if err != nil {
+		return failed(fmt.Sprintf("Failed to fork gist %s: %s", gistId, err))
+	}
+
+	responseJSON, err := json.Marshal(fork)
+	if err != nil {
+		return failed(fmt.Sprintf("Failed to marshal response: %s", err))
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func gistCreate(apiKey, description string, files map[string]any) CallToolResult {
+	url := "https://api.github.com/gists"
 	data := map[string]any{
 		"description": description,
 		"files":       files,
 	}
-	res, err := json.Marshal(data)
+	body, err := githubSendExpect(apiKey, pdk.MethodPost, url, data, 201)
 	if err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprintf("Failed to marshal gist data: %s", err)),
+				Text: some(fmt.Sprintf("Failed to create gist: %s", err)),
 			}},
 		}
 	}
-	req.SetBody(res)
-	resp := req.Send()
-	if resp.Status() != 201 {
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(body)),
+		}},
+	}
+}
+
+// gistUpdateBody builds the PATCH body. description is nil when the caller
+// didn't give one, so the gist keeps its current description.
+func gistUpdateBody(description *string, files map[string]any) map[string]any {
+	data := map[string]any{
+		"files": files,
+	}
+	if description != nil {
+		data["description"] = *description
+	}
+	return data
+}
+
+func gistUpdate(apiKey, gistId string, description *string, files map[string]any) CallToolResult {
+	url := fmt.Sprintf("https://api.github.com/gists/%s", gistId)
+	status, body, err := githubSend(apiKey, pdk.MethodPatch, url, gistUpdateBody(description, files))
+	if err == nil {
+		err = gistStatusError("update", gistId, 200, status, body)
+	}
+	if err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprintf("Failed to create gist: %d %s", resp.Status(), string(resp.Body()))),
+				Text: some(err.Error()),
 			}},
 		}
 	}
@@ -169,26 +373,23 @@ func gistUpdate(apiKey, gistId, description string, files map[string]any) CallTo
 	return CallToolResult{
 		Content: []Content{{
 			Type: ContentTypeText,
-			Text: some(string(resp.Body())),
+			Text: some(string(body)),
 		}},
 	}
 }
 
 func gistGet(apiKey, gistId string) CallToolResult {
 	url := fmt.Sprintf("https://api.github.com/gists/%s", gistId)
-	req := pdk.NewHTTPRequest(pdk.MethodGet, url)
-	req.SetHeader("Authorization", fmt.Sprintf("token %s", apiKey))
-	req.SetHeader("Content-Type", "application/json")
-	req.SetHeader("Accept", "application/vnd.github+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
-
-	resp := req.Send()
-	if resp.Status() != 201 {
+	status, body, err := githubSend(apiKey, pdk.MethodGet, url, nil)
+	if err == nil {
+		err = gistStatusError("get", gistId, 200, status, body)
+	}
+	if err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprintf("Failed to create branch: %d %s", resp.Status(), string(resp.Body()))),
+				Text: some(err.Error()),
 			}},
 		}
 	}
@@ -196,26 +397,23 @@ func gistGet(apiKey, gistId string) CallToolResult {
 	return CallToolResult{
 		Content: []Content{{
 			Type: ContentTypeText,
-			Text: some(string(resp.Body())),
+			Text: some(string(body)),
 		}},
 	}
 }
 
 func gistDelete(apiKey, gistId string) CallToolResult {
 	url := fmt.Sprintf("https://api.github.com/gists/%s", gistId)
-	req := pdk.NewHTTPRequest(pdk.MethodDelete, url)
-	req.SetHeader("Authorization", fmt.Sprintf("token %s", apiKey))
-	req.SetHeader("Content-Type", "application/json")
-	req.SetHeader("Accept", "application/vnd.github+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
-
-	resp := req.Send()
-	if resp.Status() != 201 {
+	status, body, err := githubSend(apiKey, pdk.MethodDelete, url, nil)
+	if err == nil {
+		err = gistStatusError("delete", gistId, 204, status, body)
+	}
+	if err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprintf("Failed to create branch: %d %s", resp.Status(), string(resp.Body()))),
+				Text: some(err.Error()),
 			}},
 		}
 	}
@@ -223,7 +421,7 @@ func gistDelete(apiKey, gistId string) CallToolResult {
 	return CallToolResult{
 		Content: []Content{{
 			Type: ContentTypeText,
-			Text: some(string(resp.Body())),
+			Text: some(fmt.Sprintf("Deleted gist %s", gistId)),
 		}},
 	}
 }
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSummarizeGists(t *testing.T) {
+	var gists []Gist
+	if err := json.Unmarshal([]byte(`[
+		{"id":"aa5a315d","description":"Deploy notes","public":false,"owner":{"login":"octocat"},
+		 "files":{"notes.md":{"size":120},"deploy.sh":{"size":40}},
+		 "updated_at":"2024-05-01T10:00:00Z","html_url":"https://gist.github.com/aa5a315d"},
+		{"id":"bb6b","description":null,"public":true,"files":{},"updated_at":"2024-04-01T10:00:00Z","html_url":"https://gist.github.com/bb6b"}
+	]`), &gists); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(summarizeGists(gists))
+	want := `[{"id":"aa5a315d","description":"Deploy notes","files":["deploy.sh","notes.md"],"public":false,"owner":"octocat","updated_at":"2024-05-01T10:00:00Z","html_url":"https://gist.github.com/aa5a315d"},` +
+		`{"id":"bb6b","description":null,"files":[],"public":true,"updated_at":"2024-04-01T10:00:00Z","html_url":"https://gist.github.com/bb6b"}]`
+	if string(got) != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestGistsListURL(t *testing.T) {
+	got, err := gistsListURL("https://api.github.com/gists/starred", map[string]interface{}{"since": "2024-05-01T00:00:00Z"})
+	if want := "https://api.github.com/gists/starred?per_page=30&page=1&since=2024-05-01T00:00:00Z"; err != nil || got != want {
+		t.Errorf("got %s, %v", got, err)
+	}
+	if _, err := gistsListURL("https://api.github.com/gists", map[string]interface{}{"since": "last week"}); err == nil {
+		t.Error("expected an error for an invalid since")
+	}
+}
+
+func TestGistStatusError(t *testing.T) {
+	if err := gistStatusError("get", "aa5a315d", 200, 200, []byte(`{"id":"aa5a315d"}`)); err != nil {
+		t.Errorf("get 200: %v", err)
+	}
+	if err := gistStatusError("delete", "aa5a315d", 204, 204, nil); err != nil {
+		t.Errorf("delete 204: %v", err)
+	}
+	for _, action := range []string{"get", "delete"} {
+		err := gistStatusError(action, "aa5a315d", 200, 404, []byte(`{"message":"Not Found"}`))
+		if want := "Failed to " + action + " gist aa5a315d: not found, or not visible to this token"; err == nil || err.Error() != want {
+			t.Errorf("%s 404: %v", action, err)
+		}
+	}
+	err := gistStatusError("delete", "aa5a315d", 204, 403, []byte(`{"message":"Must have admin rights"}`))
+	if want := "Failed to delete gist aa5a315d: 403 Must have admin rights"; err == nil || err.Error() != want {
+		t.Errorf("delete 403: %v", err)
+	}
+	if err := gistStatusError("get", "aa5a315d", 200, 201, nil); err == nil {
+		t.Error("get 201 should fail")
+	}
+}
+
+func TestGistUpdateBody(t *testing.T) {
+	files := map[string]any{"notes.md": map[string]any{"content": "hi"}}
+	got, _ := json.Marshal(gistUpdateBody(nil, files))
+	if want := `{"files":{"notes.md":{"content":"hi"}}}`; string(got) != want {
+		t.Errorf("without description: got %s, want %s", got, want)
+	}
+	description := ""
+	got, _ = json.Marshal(gistUpdateBody(&description, files))
+	if want := `{"description":"","files":{"notes.md":{"content":"hi"}}}`; string(got) != want {
+		t.Errorf("with description: got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	StarGistTool = ToolDescription{
+		Name:        "gh-star-gist",
+		Description: "Star a gist as the authenticated user",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"gist_id": prop("string", "The unique identifier of the gist."),
+			},
+			"required": []string{"gist_id"},
+		},
+	}
+	UnstarGistTool = ToolDescription{
+		Name:        "gh-unstar-gist",
+		Description: "Remove the authenticated user's star from a gist",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"gist_id": prop("string", "The unique identifier of the gist."),
+			},
+			"required": []string{"gist_id"},
+		},
+	}
+	IsGistStarredTool = ToolDescription{
+		Name:        "gh-is-gist-starred",
+		Description: "Check whether the authenticated user has starred a gist",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"gist_id": prop("string", "The unique identifier of the gist."),
+			},
+			"required": []string{"gist_id"},
+		},
+	}
+)
+
+// gistsSetStar stars the gist, or unstars it when star is false.
+func gistsSetStar(apiKey, gistId string, star bool) CallToolResult {
+	method := pdk.MethodPut
+	if !star {
+		method = pdk.MethodDelete
+	}
+	u := fmt.Sprintf("https://api.github.com/gists/%s/star", gistId)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Setting gist star: ", u))
+	status, body, err := githubSend(apiKey, method, u, nil)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to star gist %s: %s", gistId, err)),
+			}},
+		}
+	}
+	return gistStarResult(gistId, star, status, body)
+}
+
+// gistStarResult turns the answer to starring or unstarring a gist into a
+// result. Both answer 204 with no body, so the text is made up here.
+func gistStarResult(gistId string, star bool, status uint16, body []byte) CallToolResult {
+	action, done := "star", "Starred"
+	if !star {
+		action, done = "unstar", "Unstarred"
+	}
+	if status != 204 {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to %s gist %s: %s", action, gistId, githubStatusError(status, body))),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(fmt.Sprintf("%s gist %s", done, gistId)),
+		}},
+	}
+}
+
+func gistsIsStarred(apiKey, gistId string) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/gists/%s/star", gistId)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Checking gist star: ", u))
+	status, body, err := githubSend(apiKey, pdk.MethodGet, u, nil)
+	var starred bool
+	if err == nil {
+		starred, err = presenceResult(status, body)
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to check whether gist %s is starred: %s", gistId, err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(map[string]interface{}{
+		"gist_id": gistId,
+		"starred": starred,
+	})
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
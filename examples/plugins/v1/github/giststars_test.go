@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGistStarResult(t *testing.T) {
+	tests := []struct {
+		star    bool
+		status  uint16
+		body    string
+		text    string
+		isError bool
+	}{
+		{star: true, status: 204, text: "Starred gist aa5a315d"},
+		{star: false, status: 204, text: "Unstarred gist aa5a315d"},
+		{star: true, status: 404, body: `{"message":"Not Found"}`, text: "Failed to star gist aa5a315d: 404 Not Found", isError: true},
+		{star: false, status: 403, body: `{"message":"Forbidden"}`, text: "Failed to unstar gist aa5a315d: 403 Forbidden", isError: true},
+	}
+	for _, tt := range tests {
+		result := gistStarResult("aa5a315d", tt.star, tt.status, []byte(tt.body))
+		if got := *result.Content[0].Text; got != tt.text {
+			t.Errorf("star=%v status=%d: got %q, want %q", tt.star, tt.status, got, tt.text)
+		}
+		if (result.IsError != nil && *result.IsError) != tt.isError {
+			t.Errorf("star=%v status=%d: IsError = %v", tt.star, tt.status, result.IsError)
+		}
+	}
+}
+
+func TestForkedGist(t *testing.T) {
+	fork, err := forkedGist("aa5a315d", []byte(`{"id":"bd1f4c2e","html_url":"https://gist.github.com/bd1f4c2e","owner":{"login":"octocat"},"files":{"notes.md":{}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(fork)
+	if want := `{"id":"bd1f4c2e","html_url":"https://gist.github.com/bd1f4c2e","forked_from":"aa5a315d"}`; string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	if _, err := forkedGist("aa5a315d", []byte(`{}`)); err == nil {
+		t.Error("a response without an id should fail")
+	}
+}
@@ -2,4 +2,9 @@ module github
 
 go 1.24
 
-require github.com/extism/go-pdk v1.1.3
+require (
+	github.com/extism/go-pdk v1.1.3
+	golang.org/x/crypto v0.40.0
+)
+
+require golang.org/x/sys v0.34.0 // indirect
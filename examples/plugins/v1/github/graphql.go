@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+type GraphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type GraphQLError struct {
+	Type    string `json:"type,omitempty"`
+	Message string `json:"message"`
+}
+
+type GraphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []GraphQLError  `json:"errors"`
+}
+
+// graphqlQuery runs a query or mutation against the GitHub GraphQL API and
+// unmarshals the data field into out.
+func graphqlQuery(apiKey, query string, variables map[string]interface{}, out interface{}) error {
+	url := "https://api.github.com/graphql"
+	status, body, err := githubSend(apiKey, pdk.MethodPost, url, GraphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return fmt.Errorf("Failed to marshal GraphQL request: %w", err)
+	}
+	if status != 200 {
+		return fmt.Errorf("GraphQL request failed: %s", githubStatusError(status, body))
+	}
+
+	return parseGraphQLResponse(body, out)
+}
+
+// parseGraphQLResponse decodes a GraphQL response body. GitHub reports most
+// failures with a 200 status and an errors array, so those are turned into an
+// error carrying every message verbatim.
+func parseGraphQLResponse(body []byte, out interface{}) error {
+	var gr GraphQLResponse
+	if err := json.Unmarshal(body, &gr); err != nil {
+		return fmt.Errorf("Failed to parse GraphQL response: %w", err)
+	}
+
+	if len(gr.Errors) > 0 {
+		messages := make([]string, 0, len(gr.Errors))
+		for _, e := range gr.Errors {
+			messages = append(messages, e.Message)
+		}
+		return errors.New(strings.Join(messages, "; "))
+	}
+
+	if out == nil || len(gr.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(gr.Data, out); err != nil {
+		return fmt.Errorf("Failed to parse GraphQL data: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+func TestParseGraphQLResponse(t *testing.T) {
+	var out struct {
+		Viewer struct {
+			Login string `json:"login"`
+		} `json:"viewer"`
+	}
+	if err := parseGraphQLResponse([]byte(`{"data":{"viewer":{"login":"octocat"}}}`), &out); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if out.Viewer.Login != "octocat" {
+		t.Fatalf("expected octocat, got %q", out.Viewer.Login)
+	}
+}
+
+func TestParseGraphQLResponseErrors(t *testing.T) {
+	body := []byte(`{"data":null,"errors":[{"type":"UNPROCESSABLE","message":"Pull request is not in draft"},{"message":"second"}]}`)
+	err := parseGraphQLResponse(body, nil)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if err.Error() != "Pull request is not in draft; second" {
+		t.Fatalf("unexpected error message: %q", err)
+	}
+}
+
+func TestParseGraphQLResponseInvalid(t *testing.T) {
+	if err := parseGraphQLResponse([]byte(`not json`), nil); err == nil {
+		t.Fatal("expected an error")
+	}
+}
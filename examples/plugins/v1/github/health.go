@@ -0,0 +1,368 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	RepoHealthTool = ToolDescription{
+		Name:        "gh-repo-health",
+		Description: "Summarize the health of a GitHub repository as a red/yellow/green scorecard covering recent activity, releases, issue and pull request throughput over the last 30 days, CI status on the default branch and community files (LICENSE, SECURITY, CONTRIBUTING). Dimensions whose data can't be fetched are reported as unknown.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner": prop("string", "The owner of the repository"),
+				"repo":  prop("string", "The repository name"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+)
+
+const (
+	HealthGreen   = "green"
+	HealthYellow  = "yellow"
+	HealthRed     = "red"
+	HealthUnknown = "unknown"
+)
+
+// Scoring thresholds for gh-repo-health.
+const (
+	// A push within this many days is green, within healthActivityYellowDays yellow, older red.
+	healthActivityGreenDays  = 30
+	healthActivityYellowDays = 90
+
+	// A release within this many days is green, within healthReleaseYellowDays yellow, older red.
+	// Repositories that never published a release are yellow.
+	healthReleaseGreenDays  = 90
+	healthReleaseYellowDays = 365
+
+	// Throughput is closed/opened over healthWindowDays. At or above
+	// healthThroughputGreen is green, at or above healthThroughputYellow yellow,
+	// below that red. A window with nothing opened is green.
+	healthWindowDays       = 30
+	healthThroughputGreen  = 0.75
+	healthThroughputYellow = 0.4
+
+	// Number of community files (out of LICENSE, SECURITY, CONTRIBUTING) needed
+	// for green and yellow respectively.
+	healthCommunityGreen  = 3
+	healthCommunityYellow = 2
+)
+
+type HealthDimension struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+type HealthScorecard struct {
+	Repository string            `json:"repository"`
+	Overall    string            `json:"overall"`
+	Summary    string            `json:"summary"`
+	Dimensions []HealthDimension `json:"dimensions"`
+}
+
+func scoreAge(days, greenDays, yellowDays int) string {
+	switch {
+	case days <= greenDays:
+		return HealthGreen
+	case days <= yellowDays:
+		return HealthYellow
+	default:
+		return HealthRed
+	}
+}
+
+func scoreThroughput(opened, closed int) string {
+	if opened == 0 {
+		return HealthGreen
+	}
+	ratio := float64(closed) / float64(opened)
+	switch {
+	case ratio >= healthThroughputGreen:
+		return HealthGreen
+	case ratio >= healthThroughputYellow:
+		return HealthYellow
+	default:
+		return HealthRed
+	}
+}
+
+// scoreChecks maps check run conclusions to a status: any failure is red,
+// anything still running or neutral is yellow and all successes are green.
+func scoreChecks(conclusions []string) string {
+	if len(conclusions) == 0 {
+		return HealthUnknown
+	}
+	status := HealthGreen
+	for _, c := range conclusions {
+		switch c {
+		case "success", "skipped":
+		case "failure", "timed_out", "cancelled", "action_required", "startup_failure", "error":
+			return HealthRed
+		default:
+			status = HealthYellow
+		}
+	}
+	return status
+}
+
+func scoreCommunity(present int) string {
+	switch {
+	case present >= healthCommunityGreen:
+		return HealthGreen
+	case present >= healthCommunityYellow:
+		return HealthYellow
+	default:
+		return HealthRed
+	}
+}
+
+// overallHealth is the worst known status. It is unknown only when no dimension could be scored.
+func overallHealth(dimensions []HealthDimension) string {
+	overall := HealthUnknown
+	for _, d := range dimensions {
+		switch d.Status {
+		case HealthRed:
+			return HealthRed
+		case HealthYellow:
+			overall = HealthYellow
+		case HealthGreen:
+			if overall == HealthUnknown {
+				overall = HealthGreen
+			}
+		}
+	}
+	return overall
+}
+
+func healthSummary(repository string, dimensions []HealthDimension) string {
+	counts := map[string]int{}
+	attention := []string{}
+	for _, d := range dimensions {
+		counts[d.Status]++
+		if d.Status == HealthRed || d.Status == HealthYellow {
+			attention = append(attention, d.Name)
+		}
+	}
+
+	var verdict string
+	switch overallHealth(dimensions) {
+	case HealthGreen:
+		verdict = "looks healthy"
+	case HealthYellow:
+		verdict = "is mostly healthy"
+	case HealthRed:
+		verdict = "needs attention"
+	default:
+		verdict = "could not be assessed"
+	}
+
+	summary := fmt.Sprintf("%s %s (%d green, %d yellow, %d red, %d unknown)",
+		repository, verdict, counts[HealthGreen], counts[HealthYellow], counts[HealthRed], counts[HealthUnknown])
+	if len(attention) > 0 {
+		summary += "; review " + strings.Join(attention, ", ")
+	}
+	return summary + "."
+}
+
+func daysSince(timestamp string, now time.Time) (int, error) {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return 0, err
+	}
+	return int(now.Sub(t).Hours() / 24), nil
+}
+
+func searchIssueCount(apiKey, query string) (int, error) {
+	u := fmt.Sprint("https://api.github.com/search/issues?per_page=1&q=", url.QueryEscape(query))
+	var result struct {
+		TotalCount int `json:"total_count"`
+	}
+	if _, err := githubGetJSON(apiKey, u, &result); err != nil {
+		return 0, err
+	}
+	return result.TotalCount, nil
+}
+
+func healthThroughput(apiKey, fullName, kind string, now time.Time) HealthDimension {
+	name := "issues"
+	closedQualifier := "closed"
+	if kind == "pr" {
+		name = "pull_requests"
+		closedQualifier = "merged"
+	}
+	since := now.AddDate(0, 0, -healthWindowDays).Format("2006-01-02")
+
+	opened, err := searchIssueCount(apiKey, fmt.Sprintf("repo:%s is:%s created:>=%s", fullName, kind, since))
+	if err != nil {
+		return HealthDimension{Name: name, Status: HealthUnknown, Detail: fmt.Sprint("search failed: ", err)}
+	}
+	closed, err := searchIssueCount(apiKey, fmt.Sprintf("repo:%s is:%s %s:>=%s", fullName, kind, closedQualifier, since))
+	if err != nil {
+		return HealthDimension{Name: name, Status: HealthUnknown, Detail: fmt.Sprint("search failed: ", err)}
+	}
+	return HealthDimension{
+		Name:   name,
+		Status: scoreThroughput(opened, closed),
+		Detail: fmt.Sprintf("%d opened, %d %s in the last %d days", opened, closed, closedQualifier, healthWindowDays),
+	}
+}
+
+func healthCommunityFiles(apiKey, owner, repo string) HealthDimension {
+	wanted := []string{"LICENSE", "SECURITY", "CONTRIBUTING"}
+	found := map[string]bool{}
+
+	// Community files may live at the root or under .github
+	anyListed := false
+	for _, dir := range []string{"", ".github"} {
+		uc, err := filesGetContentsInternal(apiKey, owner, repo, dir, nil)
+		if err != nil || !uc.isArray {
+			continue
+		}
+		anyListed = true
+		for _, entry := range uc.DirectoryContents {
+			upper := strings.ToUpper(entry.Name)
+			for _, w := range wanted {
+				if strings.HasPrefix(upper, w) || (w == "LICENSE" && strings.HasPrefix(upper, "LICENCE")) {
+					found[w] = true
+				}
+			}
+		}
+	}
+	if !anyListed {
+		return HealthDimension{Name: "community_files", Status: HealthUnknown, Detail: "could not list repository contents"}
+	}
+
+	present, missing := []string{}, []string{}
+	for _, w := range wanted {
+		if found[w] {
+			present = append(present, w)
+		} else {
+			missing = append(missing, w)
+		}
+	}
+	detail := "all community files present"
+	if len(missing) > 0 {
+		detail = "missing " + strings.Join(missing, ", ")
+	}
+	return HealthDimension{Name: "community_files", Status: scoreCommunity(len(present)), Detail: detail}
+}
+
+func healthCI(apiKey, owner, repo, branch string) HealthDimension {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/commits/%s/check-runs?per_page=100", owner, repo, url.PathEscape(branch))
+	var runs struct {
+		CheckRuns []struct {
+			Status     string  `json:"status"`
+			Conclusion *string `json:"conclusion"`
+		} `json:"check_runs"`
+	}
+	if _, err := githubGetJSON(apiKey, u, &runs); err != nil {
+		return HealthDimension{Name: "ci", Status: HealthUnknown, Detail: fmt.Sprint("failed to fetch check runs: ", err)}
+	}
+
+	conclusions := make([]string, 0, len(runs.CheckRuns))
+	for _, r := range runs.CheckRuns {
+		if r.Conclusion == nil {
+			conclusions = append(conclusions, r.Status)
+		} else {
+			conclusions = append(conclusions, *r.Conclusion)
+		}
+	}
+	if len(conclusions) == 0 {
+		return HealthDimension{Name: "ci", Status: HealthUnknown, Detail: fmt.Sprintf("no check runs on %s", branch)}
+	}
+	return HealthDimension{
+		Name:   "ci",
+		Status: scoreChecks(conclusions),
+		Detail: fmt.Sprintf("%d check runs on %s", len(conclusions), branch),
+	}
+}
+
+func healthRelease(apiKey, owner, repo string, now time.Time) HealthDimension {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/latest", owner, repo)
+	var release struct {
+		TagName     string `json:"tag_name"`
+		PublishedAt string `json:"published_at"`
+	}
+	status, err := githubGetJSON(apiKey, u, &release)
+	if status == 404 {
+		return HealthDimension{Name: "release", Status: HealthYellow, Detail: "no published releases"}
+	}
+	if err != nil {
+		return HealthDimension{Name: "release", Status: HealthUnknown, Detail: fmt.Sprint("failed to fetch latest release: ", err)}
+	}
+	days, err := daysSince(release.PublishedAt, now)
+	if err != nil {
+		return HealthDimension{Name: "release", Status: HealthUnknown, Detail: fmt.Sprint("invalid release date: ", err)}
+	}
+	return HealthDimension{
+		Name:   "release",
+		Status: scoreAge(days, healthReleaseGreenDays, healthReleaseYellowDays),
+		Detail: fmt.Sprintf("latest release %s published %d days ago", release.TagName, days),
+	}
+}
+
+func reposHealth(apiKey, owner, repo string) CallToolResult {
+	now := time.Now().UTC()
+	fullName := fmt.Sprintf("%s/%s", owner, repo)
+	dimensions := []HealthDimension{}
+
+	var details RepositoryDetails
+	_, err := githubGetJSON(apiKey, fmt.Sprintf("https://api.github.com/repos/%s/%s", owner, repo), &details)
+	if err != nil {
+		dimensions = append(dimensions, HealthDimension{Name: "activity", Status: HealthUnknown, Detail: fmt.Sprint("failed to fetch repository details: ", err)})
+	} else if days, err := daysSince(details.PushedAt, now); err != nil {
+		dimensions = append(dimensions, HealthDimension{Name: "activity", Status: HealthUnknown, Detail: fmt.Sprint("invalid push date: ", err)})
+	} else {
+		dimensions = append(dimensions, HealthDimension{
+			Name:   "activity",
+			Status: scoreAge(days, healthActivityGreenDays, healthActivityYellowDays),
+			Detail: fmt.Sprintf("last push %d days ago", days),
+		})
+	}
+
+	dimensions = append(dimensions,
+		healthRelease(apiKey, owner, repo, now),
+		healthThroughput(apiKey, fullName, "issue", now),
+		healthThroughput(apiKey, fullName, "pr", now),
+	)
+
+	if details.DefaultBranch != "" {
+		dimensions = append(dimensions, healthCI(apiKey, owner, repo, details.DefaultBranch))
+	} else {
+		dimensions = append(dimensions, HealthDimension{Name: "ci", Status: HealthUnknown, Detail: "default branch unknown"})
+	}
+
+	dimensions = append(dimensions, healthCommunityFiles(apiKey, owner, repo))
+
+	scorecard := HealthScorecard{
+		Repository: fullName,
+		Overall:    overallHealth(dimensions),
+		Summary:    healthSummary(fullName, dimensions),
+		Dimensions: dimensions,
+	}
+
+	responseJSON, err := json.Marshal(scorecard)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScoreAge(t *testing.T) {
+	tests := []struct {
+		days int
+		want string
+	}{
+		{0, HealthGreen},
+		{healthActivityGreenDays, HealthGreen},
+		{healthActivityGreenDays + 1, HealthYellow},
+		{healthActivityYellowDays, HealthYellow},
+		{healthActivityYellowDays + 1, HealthRed},
+	}
+	for _, tt := range tests {
+		if got := scoreAge(tt.days, healthActivityGreenDays, healthActivityYellowDays); got != tt.want {
+			t.Errorf("scoreAge(%d) = %s, want %s", tt.days, got, tt.want)
+		}
+	}
+}
+
+func TestScoreThroughput(t *testing.T) {
+	tests := []struct {
+		opened, closed int
+		want           string
+	}{
+		{0, 0, HealthGreen},
+		{0, 5, HealthGreen},
+		{4, 3, HealthGreen},
+		{4, 2, HealthYellow},
+		{10, 4, HealthYellow},
+		{10, 3, HealthRed},
+	}
+	for _, tt := range tests {
+		if got := scoreThroughput(tt.opened, tt.closed); got != tt.want {
+			t.Errorf("scoreThroughput(%d, %d) = %s, want %s", tt.opened, tt.closed, got, tt.want)
+		}
+	}
+}
+
+func TestScoreChecks(t *testing.T) {
+	tests := []struct {
+		conclusions []string
+		want        string
+	}{
+		{nil, HealthUnknown},
+		{[]string{"success", "skipped"}, HealthGreen},
+		{[]string{"success", "in_progress"}, HealthYellow},
+		{[]string{"success", "neutral"}, HealthYellow},
+		{[]string{"in_progress", "failure"}, HealthRed},
+		{[]string{"timed_out"}, HealthRed},
+	}
+	for _, tt := range tests {
+		if got := scoreChecks(tt.conclusions); got != tt.want {
+			t.Errorf("scoreChecks(%v) = %s, want %s", tt.conclusions, got, tt.want)
+		}
+	}
+}
+
+func TestScoreCommunity(t *testing.T) {
+	want := []string{HealthRed, HealthRed, HealthYellow, HealthGreen}
+	for present, w := range want {
+		if got := scoreCommunity(present); got != w {
+			t.Errorf("scoreCommunity(%d) = %s, want %s", present, got, w)
+		}
+	}
+}
+
+func TestOverallHealth(t *testing.T) {
+	dims := func(statuses ...string) []HealthDimension {
+		d := []HealthDimension{}
+		for _, s := range statuses {
+			d = append(d, HealthDimension{Name: s, Status: s})
+		}
+		return d
+	}
+	tests := []struct {
+		dimensions []HealthDimension
+		want       string
+	}{
+		{dims(HealthUnknown, HealthUnknown), HealthUnknown},
+		{dims(HealthGreen, HealthUnknown), HealthGreen},
+		{dims(HealthGreen, HealthYellow, HealthUnknown), HealthYellow},
+		{dims(HealthYellow, HealthRed, HealthGreen), HealthRed},
+	}
+	for _, tt := range tests {
+		if got := overallHealth(tt.dimensions); got != tt.want {
+			t.Errorf("overallHealth(%v) = %s, want %s", tt.dimensions, got, tt.want)
+		}
+	}
+}
+
+func TestHealthSummary(t *testing.T) {
+	dimensions := []HealthDimension{
+		{Name: "activity", Status: HealthGreen},
+		{Name: "release", Status: HealthYellow},
+		{Name: "ci", Status: HealthUnknown},
+	}
+	got := healthSummary("octo/repo", dimensions)
+	want := "octo/repo is mostly healthy (1 green, 1 yellow, 0 red, 1 unknown); review release."
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	got = healthSummary("octo/repo", []HealthDimension{{Name: "activity", Status: HealthGreen}})
+	if !strings.HasPrefix(got, "octo/repo looks healthy") || strings.Contains(got, "review") {
+		t.Fatalf("unexpected summary %q", got)
+	}
+}
+
+func TestDaysSince(t *testing.T) {
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+	days, err := daysSince("2025-03-01T12:00:00Z", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if days != 30 {
+		t.Fatalf("expected 30 days, got %d", days)
+	}
+	if _, err := daysSince("not a date", now); err == nil {
+		t.Fatal("expected an error for an invalid timestamp")
+	}
+}
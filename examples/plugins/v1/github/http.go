@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+// githubMediaType is what the API is asked for unless an endpoint needs
+// something else, such as application/vnd.github.raw.
+const githubMediaType = "application/vnd.github+json"
+
+// githubRequest starts a request to the GitHub API with the headers every
+// call needs.
+func githubRequest(apiKey string, method pdk.HTTPMethod, u, mediaType string) *pdk.HTTPRequest {
+	req := pdk.NewHTTPRequest(method, u)
+	req.SetHeader("Authorization", fmt.Sprint("token ", apiKey))
+	req.SetHeader("Accept", mediaType)
+	req.SetHeader("User-Agent", "github-mcpx-servlet")
+	return req
+}
+
+// githubSend sends a JSON body, or none when body is nil, and returns the
+// status and response body for the caller to interpret.
+func githubSend(apiKey string, method pdk.HTTPMethod, u string, body interface{}) (uint16, []byte, error) {
+	req := githubRequest(apiKey, method, u, githubMediaType)
+	if body != nil {
+		res, err := json.Marshal(body)
+		if err != nil {
+			return 0, nil, err
+		}
+		req.SetHeader("Content-Type", "application/json")
+		req.SetBody(res)
+	}
+	resp := req.Send()
+	return resp.Status(), resp.Body(), nil
+}
+
+// githubSendExpect is githubSend for callers that accept only the given
+// statuses. Any other status is an error carrying GitHub's message.
+func githubSendExpect(apiKey string, method pdk.HTTPMethod, u string, body interface{}, want ...uint16) ([]byte, error) {
+	status, response, err := githubSend(apiKey, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range want {
+		if status == w {
+			return response, nil
+		}
+	}
+	return response, githubStatusError(status, response)
+}
+
+// githubSendJSON sends body as JSON and decodes the response into out when
+// the status is the expected one.
+func githubSendJSON(apiKey string, method pdk.HTTPMethod, u string, body interface{}, want uint16, out interface{}) error {
+	response, err := githubSendExpect(apiKey, method, u, body, want)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(response, out)
+}
+
+func githubGetJSON(apiKey, u string, out interface{}) (uint16, error) {
+	return githubGetMediaJSON(apiKey, u, githubMediaType, out)
+}
+
+// githubGetMediaJSON is githubGetJSON for endpoints with an alternative
+// media type, such as application/vnd.github.star+json.
+func githubGetMediaJSON(apiKey, u, mediaType string, out interface{}) (uint16, error) {
+	resp := githubRequest(apiKey, pdk.MethodGet, u, mediaType).Send()
+	if resp.Status() != 200 {
+		return resp.Status(), githubStatusError(resp.Status(), resp.Body())
+	}
+	return resp.Status(), json.Unmarshal(resp.Body(), out)
+}
+
+// githubDownload fetches a file the API serves through a redirect to a
+// signed URL, such as a release asset or a logs archive. The signed URL must
+// be fetched without the GitHub token.
+func githubDownload(apiKey, u string) ([]byte, error) {
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Downloading: ", u))
+	resp := githubRequest(apiKey, pdk.MethodGet, u, "application/octet-stream").Send()
+
+	if location := redirectLocation(resp.Status(), resp.Headers()); location != "" {
+		pdk.Log(pdk.LogDebug, fmt.Sprint("Following download redirect: ", location))
+		req := pdk.NewHTTPRequest(pdk.MethodGet, location)
+		req.SetHeader("Accept", "application/octet-stream")
+		req.SetHeader("User-Agent", "github-mcpx-servlet")
+		resp = req.Send()
+	}
+	if resp.Status() != 200 {
+		return nil, githubStatusError(resp.Status(), resp.Body())
+	}
+	return resp.Body(), nil
+}
+
+// githubStatusError reports an unexpected status with GitHub's message.
+func githubStatusError(status uint16, body []byte) error {
+	return fmt.Errorf("%d %s", status, githubErrorMessage(body))
+}
+
+// githubErrorMessage pulls the message out of a GitHub error body, falling
+// back to the body itself.
+func githubErrorMessage(body []byte) string {
+	var apiErr struct {
+		Message string `json:"message"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &apiErr); err != nil || apiErr.Message == "" {
+		return string(body)
+	}
+	messages := []string{apiErr.Message}
+	for _, e := range apiErr.Errors {
+		if e.Message != "" {
+			messages = append(messages, e.Message)
+		}
+	}
+	return strings.Join(messages, ": ")
+}
+
+// responseHeader looks a header up ignoring case, since hosts may pass
+// names through lower-cased.
+func responseHeader(headers map[string]string, name string) (string, bool) {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
@@ -1,8 +1,8 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/extism/go-pdk"
@@ -11,28 +11,48 @@ import (
 var (
 	ListIssuesTool = ToolDescription{
 		Name:        "gh-list-issues",
-		Description: "List issues from a GitHub repository",
+		Description: "List issues and pull requests of a GitHub repository",
 		InputSchema: schema{
 			"type": "object",
 			"properties": props{
 				"owner":     prop("string", "The owner of the repository"),
 				"repo":      prop("string", "The repository name"),
-				"filter":    prop("string", "Filter by assigned, created, mentioned, subscribed, repos, all"),
 				"state":     prop("string", "The state of the issues (open, closed, all)"),
 				"labels":    prop("string", "A list of comma separated label names (e.g. bug,ui,@high)"),
+				"assignee":  prop("string", "Only issues assigned to this user; none for unassigned, * for any"),
+				"creator":   prop("string", "Only issues opened by this user"),
+				"mentioned": prop("string", "Only issues mentioning this user"),
+				"milestone": prop("string", "Only issues in this milestone number; none for no milestone, * for any"),
 				"sort":      prop("string", "Sort field (created, updated, comments)"),
 				"direction": prop("string", "Sort direction (asc or desc)"),
 				"since":     prop("string", "ISO 8601 timestamp (YYYY-MM-DDTHH:MM:SSZ)"),
-				"collab":    prop("boolean", "Filter by issues that are collaborated on"),
-				"orgs":      prop("boolean", "Filter by organization issues"),
-				"owned":     prop("boolean", "Filter by owned issues"),
-				"pulls":     prop("boolean", "Include pull requests in results"),
 				"per_page":  prop("integer", "Number of results per page (max 100)"),
 				"page":      prop("integer", "Page number for pagination"),
 			},
 			"required": []string{"owner", "repo"},
 		},
 	}
+	ListMyIssuesTool = ToolDescription{
+		Name:        "gh-list-my-issues",
+		Description: "List issues across all repositories visible to the authenticated user, e.g. everything assigned to them",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"filter":    prop("string", "Filter by assigned, created, mentioned, subscribed, repos, all (default assigned)"),
+				"state":     prop("string", "The state of the issues (open, closed, all)"),
+				"labels":    prop("string", "A list of comma separated label names (e.g. bug,ui,@high)"),
+				"sort":      prop("string", "Sort field (created, updated, comments)"),
+				"direction": prop("string", "Sort direction (asc or desc)"),
+				"since":     prop("string", "ISO 8601 timestamp (YYYY-MM-DDTHH:MM:SSZ)"),
+				"collab":    prop("boolean", "Include issues in repositories you collaborate on"),
+				"orgs":      prop("boolean", "Include issues in your organizations' repositories"),
+				"owned":     prop("boolean", "Include issues in repositories you own"),
+				"pulls":     prop("boolean", "Include pull requests in results"),
+				"per_page":  prop("integer", "Number of results per page (max 100)"),
+				"page":      prop("integer", "Page number for pagination"),
+			},
+		},
+	}
 	CreateIssueTool = ToolDescription{
 		Name:        "gh-create-issue",
 		Description: "Create an issue on a GitHub repository",
@@ -45,7 +65,12 @@ var (
 				"body":      prop("string", "The body of the issue"),
 				"state":     prop("string", "The state of the issue"),
 				"assignees": arrprop("array", "The assignees of the issue", "string"),
-				"milestone": prop("integer", "The milestone of the issue"),
+				"milestone": SchemaProperty{
+					Type:        []string{"integer", "string"},
+					Description: "The milestone of the issue: its number as an integer (e.g. 3) or its title as a string (e.g. \"v1.0\"). A string is always taken as a title, so \"2024\" is the milestone titled 2024.",
+				},
+				"milestone_title": prop("string", "Deprecated: give the title as milestone instead. Ignored when milestone is given."),
+				"labels":          arrprop("array", "The labels of the issue", "string"),
 			},
 			"required": []string{"owner", "repo", "title", "body"},
 		},
@@ -83,104 +108,120 @@ var (
 		InputSchema: schema{
 			"type": "object",
 			"properties": props{
-				"owner":     prop("string", "The owner of the repository"),
-				"repo":      prop("string", "The repository name"),
-				"issue":     prop("integer", "The issue number"),
-				"title":     prop("string", "The title of the issue"),
-				"body":      prop("string", "The body of the issue"),
-				"state":     prop("string", "The state of the issue"),
-				"assignees": arrprop("array", "The assignees of the issue", "string"),
-				"milestone": prop("integer", "The milestone of the issue"),
+				"owner":        prop("string", "The owner of the repository"),
+				"repo":         prop("string", "The repository name"),
+				"issue":        prop("integer", "The issue number"),
+				"title":        prop("string", "The title of the issue"),
+				"body":         prop("string", "The body of the issue"),
+				"state":        prop("string", "open or closed"),
+				"state_reason": prop("string", "Why the state changed: completed or not_planned when closing, reopened when reopening. Implies the state if it isn't given."),
+				"assignees":    arrprop("array", "The assignees of the issue; an empty list removes them all", "string"),
+				"milestone": SchemaProperty{
+					Type:        []string{"integer", "string"},
+					Description: "The milestone of the issue: its number as an integer (e.g. 3) or its title as a string (e.g. \"v1.0\"). A string is always taken as a title, so \"2024\" is the milestone titled 2024.",
+				},
+				"milestone_title": prop("string", "Deprecated: give the title as milestone instead. Ignored when milestone is given."),
+				"labels":          arrprop("array", "The labels of the issue; an empty list removes them all", "string"),
+				"clear":           arrprop("array", "Fields to empty: assignees, labels, milestone or body", "string"),
+			},
+			"required": []string{"owner", "repo", "issue"},
+		},
+	}
+	CloseIssueTool = ToolDescription{
+		Name:        "gh-close-issue",
+		Description: "Close an issue as completed or not planned, optionally posting a closing comment first",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":        prop("string", "The owner of the repository"),
+				"repo":         prop("string", "The repository name"),
+				"issue":        prop("integer", "The issue number"),
+				"state_reason": prop("string", "completed or not_planned (default completed)"),
+				"comment":      prop("string", "A comment to post before closing, e.g. why it won't be fixed"),
 			},
 			"required": []string{"owner", "repo", "issue"},
 		},
 	}
 	IssueTools = []ToolDescription{
 		ListIssuesTool,
+		ListMyIssuesTool,
 		CreateIssueTool,
 		GetIssueTool,
 		UpdateIssueTool,
+		CloseIssueTool,
 		AddIssueCommentTool,
+		IssueEngagementTool,
+		ExportIssueTool,
+		FindDuplicateIssuesTool,
+		AddIssueAssigneesTool,
+		RemoveIssueAssigneesTool,
+		LockIssueTool,
+		UnlockIssueTool,
+		IssueTimelineTool,
+		TransferIssueTool,
 	}
 )
 
 type Issue struct {
-	Title     string   `json:"title,omitempty"`
-	Body      string   `json:"body,omitempty"`
-	Assignees []string `json:"assignees,omitempty"`
-	Milestone int      `json:"milestone,omitempty"`
-	Labels    []string `json:"labels,omitempty"`
+	Title       string   `json:"title,omitempty"`
+	Body        string   `json:"body,omitempty"`
+	Assignees   []string `json:"assignees,omitempty"`
+	Milestone   int      `json:"milestone,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	State       string   `json:"state,omitempty"`
+	StateReason string   `json:"state_reason,omitempty"`
+	// Resolved to Milestone by issueResolveMilestone before sending
+	MilestoneTitle string `json:"-"`
 }
 
-func issueList(apiKey string, owner, repo string, args map[string]interface{}) (CallToolResult, error) {
-	baseURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues", owner, repo)
-	params := make([]string, 0)
-
-	// String parameters
-	stringParams := map[string]string{
-		"filter":    "assigned", // Default value
-		"state":     "open",     // Default value
-		"labels":    "",
-		"sort":      "created", // Default value
-		"direction": "desc",    // Default value
-		"since":     "",
-	}
-
-	for key := range stringParams {
+// issueListParams adds the string parameters in order, falling back to the
+// defaults, then the boolean ones that were given, then pagination.
+func issueListParams(args map[string]interface{}, keys []string, defaults map[string]string, boolKeys []string) []string {
+	params := []string{}
+	for _, key := range keys {
 		if value, ok := args[key].(string); ok && value != "" {
-			params = append(params, fmt.Sprintf("%s=%s", key, value))
-		} else if stringParams[key] != "" {
-			// Add default value if one exists
-			params = append(params, fmt.Sprintf("%s=%s", key, stringParams[key]))
+			params = append(params, fmt.Sprintf("%s=%s", key, url.QueryEscape(value)))
+		} else if defaults[key] != "" {
+			params = append(params, fmt.Sprintf("%s=%s", key, defaults[key]))
 		}
 	}
-
-	// Boolean parameters
-	boolParams := []string{"collab", "orgs", "owned", "pulls"}
-	for _, param := range boolParams {
-		if value, ok := args[param].(bool); ok {
-			params = append(params, fmt.Sprintf("%s=%t", param, value))
+	for _, key := range boolKeys {
+		if value, ok := args[key].(bool); ok {
+			params = append(params, fmt.Sprintf("%s=%t", key, value))
 		}
 	}
+	return append(params, paginationParams(args)...)
+}
 
-	// Pagination parameters
-	perPage := 30 // Default value
-	if value, ok := args["per_page"].(float64); ok {
-		if value > 100 {
-			perPage = 100 // Max value
-		} else if value > 0 {
-			perPage = int(value)
-		}
-	}
-	params = append(params, fmt.Sprintf("per_page=%d", perPage))
-
-	page := 1 // Default value
-	if value, ok := args["page"].(float64); ok && value > 0 {
-		page = int(value)
-	}
-	params = append(params, fmt.Sprintf("page=%d", page))
-
-	// Build final URL
-	url := baseURL
-	if len(params) > 0 {
-		url = fmt.Sprintf("%s?%s", baseURL, strings.Join(params, "&"))
-	}
+// issueListURL lists a repository's issues. filter, collab, orgs, owned
+// and pulls only exist on the user endpoint (see myIssuesURL).
+func issueListURL(owner, repo string, args map[string]interface{}) string {
+	params := issueListParams(args,
+		[]string{"state", "labels", "assignee", "creator", "mentioned", "milestone", "sort", "direction", "since"},
+		map[string]string{"state": "open", "sort": "created", "direction": "desc"},
+		nil)
+	return fmt.Sprintf("https://api.github.com/repos/%s/%s/issues?%s", owner, repo, strings.Join(params, "&"))
+}
 
-	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing issues: ", url))
+// myIssuesURL lists issues across the authenticated user's repositories.
+func myIssuesURL(args map[string]interface{}) string {
+	params := issueListParams(args,
+		[]string{"filter", "state", "labels", "sort", "direction", "since"},
+		map[string]string{"filter": "assigned", "state": "open", "sort": "created", "direction": "desc"},
+		[]string{"collab", "orgs", "owned", "pulls"})
+	return fmt.Sprintf("https://api.github.com/issues?%s", strings.Join(params, "&"))
+}
 
-	// Make request
-	req := pdk.NewHTTPRequest(pdk.MethodGet, url)
-	req.SetHeader("Authorization", fmt.Sprint("token ", apiKey))
-	req.SetHeader("Accept", "application/vnd.github+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
+func issueList(apiKey string, u string) (CallToolResult, error) {
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing issues: ", u))
 
-	resp := req.Send()
-	if resp.Status() != 200 {
+	body, err := githubSendExpect(apiKey, pdk.MethodGet, u, nil, 200)
+	if err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprintf("Failed to list issues: %d %s", resp.Status(), string(resp.Body()))),
+				Text: some(fmt.Sprintf("Failed to list issues: %s", err)),
 			}},
 		}, nil
 	}
@@ -188,7 +229,7 @@ func issueList(apiKey string, owner, repo string, args map[string]interface{}) (
 	return CallToolResult{
 		Content: []Content{{
 			Type: ContentTypeText,
-			Text: some(string(resp.Body())),
+			Text: some(string(body)),
 		}},
 	}, nil
 }
@@ -203,32 +244,69 @@ func issueFromArgs(args map[string]interface{}) Issue {
 	}
 	if assignees, ok := args["assignees"].([]interface{}); ok {
 		for _, a := range assignees {
-			data.Assignees = append(data.Assignees, a.(string))
+			if assignee, ok := a.(string); ok {
+				data.Assignees = append(data.Assignees, assignee)
+			}
 		}
 	}
-	if milestone, ok := args["milestone"].(float64); ok {
+	switch milestone := args["milestone"].(type) {
+	case float64:
 		data.Milestone = int(milestone)
+	case string:
+		// A string is always a title, even one that looks like a number
+		// such as "2024"; numbers arrive as numbers.
+		data.MilestoneTitle = strings.TrimSpace(milestone)
+	}
+	// milestone_title predates titles in milestone and is kept for callers
+	// that still send it
+	if title, ok := args["milestone_title"].(string); ok && args["milestone"] == nil {
+		data.MilestoneTitle = strings.TrimSpace(title)
+	}
+	if state, ok := args["state"].(string); ok {
+		data.State = state
+	}
+	if reason, ok := args["state_reason"].(string); ok {
+		data.StateReason = reason
 	}
 	if labels, ok := args["labels"].([]interface{}); ok {
 		for _, l := range labels {
-			data.Labels = append(data.Labels, l.(string))
+			if label, ok := l.(string); ok {
+				data.Labels = append(data.Labels, label)
+			}
 		}
 	}
 	return data
 }
 
+// issueCheckState validates state and state_reason, and fills in the state
+// a reason implies.
+func issueCheckState(data *Issue) error {
+	if data.State != "" && data.State != "open" && data.State != "closed" {
+		return fmt.Errorf("state must be open or closed, got %q", data.State)
+	}
+	switch data.StateReason {
+	case "":
+	case "completed", "not_planned":
+		if data.State == "open" {
+			return fmt.Errorf("state_reason %s only applies when closing", data.StateReason)
+		}
+		data.State = "closed"
+	case "reopened":
+		if data.State == "closed" {
+			return fmt.Errorf("state_reason reopened only applies when reopening")
+		}
+		data.State = "open"
+	default:
+		return fmt.Errorf("state_reason must be completed, not_planned or reopened, got %q", data.StateReason)
+	}
+	return nil
+}
+
 func issueCreate(apiKey string, owner, repo string, data Issue) (CallToolResult, error) {
 	url := fmt.Sprint("https://api.github.com/repos/", owner, "/", repo, "/issues")
 	pdk.Log(pdk.LogDebug, fmt.Sprint("Adding comment: ", url))
 
-	req := pdk.NewHTTPRequest(pdk.MethodPost, url)
-	req.SetHeader("Authorization", fmt.Sprint("token ", apiKey))
-	req.SetHeader("Accept", "application/vnd.github.v3+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
-	req.SetHeader("Content-Type", "application/json")
-
-	res, err := json.Marshal(data)
-
+	body, err := githubSendExpect(apiKey, pdk.MethodPost, url, data, 201)
 	if err != nil {
 		return CallToolResult{
 			IsError: some(true),
@@ -239,23 +317,10 @@ func issueCreate(apiKey string, owner, repo string, data Issue) (CallToolResult,
 		}, nil
 	}
 
-	req.SetBody([]byte(res))
-	resp := req.Send()
-
-	if resp.Status() != 201 {
-		return CallToolResult{
-			IsError: some(true),
-			Content: []Content{{
-				Type: ContentTypeText,
-				Text: some(fmt.Sprint("Failed to create issue: ", resp.Status(), " ", string(resp.Body()))),
-			}},
-		}, nil
-	}
-
 	return CallToolResult{
 		Content: []Content{{
 			Type: ContentTypeText,
-			Text: some(string(resp.Body())),
+			Text: some(string(body)),
 		}},
 	}, nil
 }
@@ -264,17 +329,13 @@ func issueGet(apiKey string, owner, repo string, issue int) (CallToolResult, err
 	url := fmt.Sprint("https://api.github.com/repos/", owner, "/", repo, "/issues/", issue)
 	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting issue: ", url))
 
-	req := pdk.NewHTTPRequest(pdk.MethodGet, url)
-	req.SetHeader("Authorization", fmt.Sprint("token ", apiKey))
-	req.SetHeader("Accept", "application/vnd.github.v3+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
-	resp := req.Send()
-	if resp.Status() != 200 {
+	body, err := githubSendExpect(apiKey, pdk.MethodGet, url, nil, 200)
+	if err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprint("Failed to get issue: ", resp.Status())),
+				Text: some(fmt.Sprint("Failed to get issue: ", err)),
 			}},
 		}, nil
 	}
@@ -282,22 +343,72 @@ func issueGet(apiKey string, owner, repo string, issue int) (CallToolResult, err
 	return CallToolResult{
 		Content: []Content{{
 			Type: ContentTypeText,
-			Text: some(string(resp.Body())),
+			Text: some(string(body)),
 		}},
 	}, nil
 }
 
-func issueUpdate(apiKey string, owner, repo string, issue int, data Issue) (CallToolResult, error) {
+// issueClearable maps the fields the clear argument accepts to the value
+// that empties them.
+var issueClearable = map[string]interface{}{
+	"assignees": []string{},
+	"labels":    []string{},
+	"milestone": nil,
+	"body":      nil,
+}
+
+// issueUpdateBody builds the PATCH payload. Unlike marshaling Issue, it
+// keeps empty values the caller gave explicitly, so "assignees": [] removes
+// every assignee, and fields named in clear are emptied. Fields the caller
+// didn't mention are left out and stay as they are.
+func issueUpdateBody(args map[string]interface{}, data Issue) (map[string]interface{}, error) {
+	body := map[string]interface{}{}
+	if data.Title != "" {
+		body["title"] = data.Title
+	}
+	if _, ok := args["body"].(string); ok {
+		body["body"] = data.Body
+	}
+	if data.State != "" {
+		body["state"] = data.State
+	}
+	if data.StateReason != "" {
+		body["state_reason"] = data.StateReason
+	}
+	if _, ok := args["assignees"].([]interface{}); ok {
+		body["assignees"] = append([]string{}, data.Assignees...)
+	}
+	if _, ok := args["labels"].([]interface{}); ok {
+		body["labels"] = append([]string{}, data.Labels...)
+	}
+	if data.Milestone != 0 {
+		body["milestone"] = data.Milestone
+	}
+
+	clear, _ := args["clear"].([]interface{})
+	for _, c := range clear {
+		field, _ := c.(string)
+		empty, ok := issueClearable[field]
+		if !ok {
+			return nil, fmt.Errorf("clear can name assignees, labels, milestone or body, got %#v", c)
+		}
+		if _, set := body[field]; set {
+			return nil, fmt.Errorf("%s is both given and cleared", field)
+		}
+		body[field] = empty
+	}
+
+	if len(body) == 0 {
+		return nil, fmt.Errorf("nothing to update")
+	}
+	return body, nil
+}
+
+func issueUpdate(apiKey string, owner, repo string, issue int, data map[string]interface{}) (CallToolResult, error) {
 	url := fmt.Sprint("https://api.github.com/repos/", owner, "/", repo, "/issues/", issue)
 	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting issue: ", url))
 
-	req := pdk.NewHTTPRequest(pdk.MethodPatch, url)
-	req.SetHeader("Authorization", fmt.Sprint("token ", apiKey))
-	req.SetHeader("Accept", "application/vnd.github.v3+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
-	req.SetHeader("Content-Type", "application/json")
-
-	res, err := json.Marshal(data)
+	body, err := githubSendExpect(apiKey, pdk.MethodPatch, url, data, 200)
 	if err != nil {
 		return CallToolResult{
 			IsError: some(true),
@@ -308,22 +419,10 @@ func issueUpdate(apiKey string, owner, repo string, issue int, data Issue) (Call
 		}, nil
 	}
 
-	req.SetBody([]byte(res))
-	resp := req.Send()
-	if resp.Status() != 200 {
-		return CallToolResult{
-			IsError: some(true),
-			Content: []Content{{
-				Type: ContentTypeText,
-				Text: some(fmt.Sprint("Failed to update issue: ", resp.Status())),
-			}},
-		}, nil
-	}
-
 	return CallToolResult{
 		Content: []Content{{
 			Type: ContentTypeText,
-			Text: some(string(resp.Body())),
+			Text: some(string(body)),
 		}},
 	}, nil
 }
@@ -332,43 +431,47 @@ func issueAddComment(apiKey string, owner, repo string, issue int, comment strin
 	url := fmt.Sprint("https://api.github.com/repos/", owner, "/", repo, "/issues/", issue, "/comments")
 	pdk.Log(pdk.LogDebug, fmt.Sprint("Adding comment: ", url))
 
-	req := pdk.NewHTTPRequest(pdk.MethodPost, url)
-	req.SetHeader("Authorization", fmt.Sprint("token ", apiKey))
-	req.SetHeader("Accept", "application/vnd.github.v3+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
-	req.SetHeader("Content-Type", "application/json")
-
-	res, err := json.Marshal(map[string]string{
-		"body": comment,
-	})
-
+	body, err := githubSendExpect(apiKey, pdk.MethodPost, url, map[string]string{"body": comment}, 201)
 	if err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprint("Failed to create issue: ", err)),
+				Text: some(fmt.Sprint("Failed to add comment: ", err)),
 			}},
 		}, nil
 	}
 
-	req.SetBody([]byte(res))
-	resp := req.Send()
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(body)),
+		}},
+	}, nil
+}
 
-	if resp.Status() != 201 {
+// issueClose posts the closing comment, if any, then closes the issue, so
+// the comment shows above the close event.
+func issueClose(apiKey string, owner, repo string, issue int, reason, comment string) (CallToolResult, error) {
+	if reason == "" {
+		reason = "completed"
+	}
+	state := Issue{State: "closed", StateReason: reason}
+	if err := issueCheckState(&state); err != nil || reason == "reopened" {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprint("Failed to add comment: ", resp.Status())),
+				Text: some(fmt.Sprintf("state_reason must be completed or not_planned, got %q", reason)),
 			}},
 		}, nil
 	}
 
-	return CallToolResult{
-		Content: []Content{{
-			Type: ContentTypeText,
-			Text: some(string(resp.Body())),
-		}},
-	}, nil
+	if strings.TrimSpace(comment) != "" {
+		result, err := issueAddComment(apiKey, owner, repo, issue, comment)
+		if err != nil || (result.IsError != nil && *result.IsError) {
+			return result, err
+		}
+	}
+	return issueUpdate(apiKey, owner, repo, issue, map[string]interface{}{"state": "closed", "state_reason": reason})
 }
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestIssueUpdateStateBody(t *testing.T) {
+	tests := []struct {
+		args    string
+		want    string
+		wantErr string
+	}{
+		{args: `{"state_reason":"completed"}`, want: `{"state":"closed","state_reason":"completed"}`},
+		{args: `{"state":"closed","state_reason":"not_planned"}`, want: `{"state":"closed","state_reason":"not_planned"}`},
+		{args: `{"state_reason":"reopened"}`, want: `{"state":"open","state_reason":"reopened"}`},
+		{args: `{"state":"closed"}`, want: `{"state":"closed"}`},
+		{args: `{"title":"Crash"}`, want: `{"title":"Crash"}`},
+		{args: `{"state":"open","state_reason":"not_planned"}`, wantErr: "only applies when closing"},
+		{args: `{"state":"closed","state_reason":"reopened"}`, wantErr: "only applies when reopening"},
+		{args: `{"state_reason":"wontfix"}`, wantErr: "state_reason must be completed, not_planned or reopened"},
+		{args: `{"state":"merged"}`, wantErr: "state must be open or closed"},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		data := issueFromArgs(args)
+		err := issueCheckState(&data)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error %v, want one containing %q", tt.args, err, tt.wantErr)
+			}
+			continue
+		}
+		out, _ := json.Marshal(data)
+		if err != nil || string(out) != tt.want {
+			t.Errorf("%s: got %s, %v, want %s", tt.args, out, err, tt.want)
+		}
+	}
+}
+
+func TestIssueFromArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      string
+		body      string
+		milestone string
+	}{
+		{
+			name: "labels and milestone number",
+			args: `{"title":"Crash","labels":["bug","ui"],"milestone":3}`,
+			body: `{"title":"Crash","milestone":3,"labels":["bug","ui"]}`,
+		},
+		{
+			name:      "a numeric-looking string is a title",
+			args:      `{"title":"Crash","milestone":"2024"}`,
+			body:      `{"title":"Crash"}`,
+			milestone: "2024",
+		},
+		{
+			name:      "milestone title is looked up later, not sent",
+			args:      `{"title":"Crash","milestone":" v1.0 "}`,
+			body:      `{"title":"Crash"}`,
+			milestone: "v1.0",
+		},
+		{
+			name:      "deprecated milestone_title still works",
+			args:      `{"title":"Crash","milestone_title":" v1.0 "}`,
+			body:      `{"title":"Crash"}`,
+			milestone: "v1.0",
+		},
+		{
+			name: "milestone wins over milestone_title",
+			args: `{"title":"Crash","milestone":3,"milestone_title":"v1.0"}`,
+			body: `{"title":"Crash","milestone":3}`,
+		},
+		{
+			name: "non-string labels are ignored",
+			args: `{"body":"Steps","labels":["bug",7]}`,
+			body: `{"body":"Steps","labels":["bug"]}`,
+		},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		data := issueFromArgs(args)
+		out, _ := json.Marshal(data)
+		if string(out) != tt.body || data.MilestoneTitle != tt.milestone {
+			t.Errorf("%s: got %s with title %q, want %s with title %q", tt.name, out, data.MilestoneTitle, tt.body, tt.milestone)
+		}
+	}
+}
+
+func TestIssueToolsDeclareLabels(t *testing.T) {
+	for _, tool := range []ToolDescription{CreateIssueTool, UpdateIssueTool} {
+		properties := tool.InputSchema.(schema)["properties"].(props)
+		if labels, ok := properties["labels"]; !ok || labels.Type != "array" {
+			t.Errorf("%s doesn't declare labels as an array: %+v", tool.Name, labels)
+		}
+		if _, numeric := numericProperties(tool)["milestone"]; numeric {
+			t.Errorf("%s declares milestone as a number, so titles would be rejected", tool.Name)
+		}
+		milestone, _ := json.Marshal(properties["milestone"].Type)
+		if string(milestone) != `["integer","string"]` {
+			t.Errorf("%s declares milestone as %s", tool.Name, milestone)
+		}
+	}
+}
+
+func TestIssueUpdateBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "untouched fields are left out",
+			args: `{"issue":7,"title":"Crash on start"}`,
+			want: `{"title":"Crash on start"}`,
+		},
+		{
+			name: "explicit empty lists are sent",
+			args: `{"issue":7,"assignees":[],"labels":[]}`,
+			want: `{"assignees":[],"labels":[]}`,
+		},
+		{
+			name: "clear empties the named fields",
+			args: `{"issue":7,"clear":["milestone","assignees","body"]}`,
+			want: `{"assignees":[],"body":null,"milestone":null}`,
+		},
+		{
+			name: "set and clear different fields",
+			args: `{"issue":7,"labels":["bug"],"milestone":3,"clear":["assignees"]}`,
+			want: `{"assignees":[],"labels":["bug"],"milestone":3}`,
+		},
+		{
+			name: "empty body is sent when given",
+			args: `{"issue":7,"body":""}`,
+			want: `{"body":""}`,
+		},
+		{name: "unknown field", args: `{"issue":7,"clear":["title"]}`, wantErr: "clear can name"},
+		{name: "set and cleared", args: `{"issue":7,"labels":["bug"],"clear":["labels"]}`, wantErr: "labels is both given and cleared"},
+		{name: "nothing", args: `{"issue":7}`, wantErr: "nothing to update"},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		body, err := issueUpdateBody(args, issueFromArgs(args))
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error %v, want one containing %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		out, _ := json.Marshal(body)
+		if err != nil || string(out) != tt.want {
+			t.Errorf("%s: got %s, %v, want %s", tt.name, out, err, tt.want)
+		}
+	}
+}
+
+func TestIssueListURLs(t *testing.T) {
+	tests := []struct {
+		name string
+		url  func(map[string]interface{}) string
+		args string
+		want string
+	}{
+		{
+			name: "repository defaults",
+			url:  func(args map[string]interface{}) string { return issueListURL("acme", "api", args) },
+			args: `{"owner":"acme","repo":"api"}`,
+			want: "https://api.github.com/repos/acme/api/issues?state=open&sort=created&direction=desc&per_page=30&page=1",
+		},
+		{
+			name: "repository filters, user-only parameters dropped",
+			url:  func(args map[string]interface{}) string { return issueListURL("acme", "api", args) },
+			args: `{"state":"all","labels":"bug,good first issue","assignee":"octocat","creator":"hubot","mentioned":"mona","milestone":"none","since":"2024-01-01T00:00:00Z","filter":"created","pulls":true,"per_page":50,"page":2}`,
+			want: "https://api.github.com/repos/acme/api/issues?state=all&labels=bug%2Cgood+first+issue&assignee=octocat&creator=hubot&mentioned=mona&milestone=none&sort=created&direction=desc&since=2024-01-01T00%3A00%3A00Z&per_page=50&page=2",
+		},
+		{
+			name: "user defaults",
+			url:  myIssuesURL,
+			args: `{}`,
+			want: "https://api.github.com/issues?filter=assigned&state=open&sort=created&direction=desc&per_page=30&page=1",
+		},
+		{
+			name: "user filters",
+			url:  myIssuesURL,
+			args: `{"filter":"mentioned","sort":"updated","collab":true,"orgs":false,"pulls":true,"assignee":"ignored"}`,
+			want: "https://api.github.com/issues?filter=mentioned&state=open&sort=updated&direction=desc&collab=true&orgs=false&pulls=true&per_page=30&page=1",
+		},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		if got := tt.url(args); got != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.name, got, tt.want)
+		}
+	}
+}
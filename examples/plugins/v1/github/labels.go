@@ -0,0 +1,394 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ListLabelsTool = ToolDescription{
+		Name:        "gh-list-labels",
+		Description: "List the labels defined in a repository, with their color and description. Use it to pick existing labels before labeling an issue.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":    prop("string", "The owner of the repository"),
+				"repo":     prop("string", "The repository name"),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	CreateLabelTool = ToolDescription{
+		Name:        "gh-create-label",
+		Description: "Create a label in a repository",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":       prop("string", "The owner of the repository"),
+				"repo":        prop("string", "The repository name"),
+				"name":        prop("string", "The label name"),
+				"color":       prop("string", "Six hex digits without '#', e.g. d73a4a (GitHub picks one if omitted)"),
+				"description": prop("string", "A short description of the label (max 100 characters)"),
+			},
+			"required": []string{"owner", "repo", "name"},
+		},
+	}
+	UpdateLabelTool = ToolDescription{
+		Name:        "gh-update-label",
+		Description: "Rename a label or change its color or description. Issues keep the label when it is renamed.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":       prop("string", "The owner of the repository"),
+				"repo":        prop("string", "The repository name"),
+				"name":        prop("string", "The current label name"),
+				"new_name":    prop("string", "The new label name"),
+				"color":       prop("string", "Six hex digits without '#', e.g. d73a4a"),
+				"description": prop("string", "The new description; an empty string clears it"),
+			},
+			"required": []string{"owner", "repo", "name"},
+		},
+	}
+	DeleteLabelTool = ToolDescription{
+		Name:        "gh-delete-label",
+		Description: "Delete a label from a repository. It is removed from every issue and pull request that has it. Asks the user to confirm first.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner": prop("string", "The owner of the repository"),
+				"repo":  prop("string", "The repository name"),
+				"name":  prop("string", "The label to delete"),
+			},
+			"required": []string{"owner", "repo", "name"},
+		},
+	}
+	AddIssueLabelsTool = ToolDescription{
+		Name:        "gh-add-issue-labels",
+		Description: "Add labels to an issue or pull request, keeping the ones it already has. Returns the issue's full label set.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":  prop("string", "The owner of the repository"),
+				"repo":   prop("string", "The repository name"),
+				"issue":  prop("integer", "The issue or pull request number"),
+				"labels": arrprop("array", "Label names to add", "string"),
+			},
+			"required": []string{"owner", "repo", "issue", "labels"},
+		},
+	}
+	RemoveIssueLabelTool = ToolDescription{
+		Name:        "gh-remove-issue-label",
+		Description: "Remove one label from an issue or pull request. Returns the issue's remaining labels.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner": prop("string", "The owner of the repository"),
+				"repo":  prop("string", "The repository name"),
+				"issue": prop("integer", "The issue or pull request number"),
+				"label": prop("string", "The label name to remove"),
+			},
+			"required": []string{"owner", "repo", "issue", "label"},
+		},
+	}
+	LabelTools = []ToolDescription{
+		ListLabelsTool,
+		CreateLabelTool,
+		UpdateLabelTool,
+		DeleteLabelTool,
+		AddIssueLabelsTool,
+		RemoveIssueLabelTool,
+	}
+)
+
+var labelColorRe = regexp.MustCompile(`^[0-9a-fA-F]{6}$`)
+
+type Label struct {
+	Name        string  `json:"name"`
+	Color       string  `json:"color"`
+	Description *string `json:"description"`
+	Default     bool    `json:"default"`
+}
+
+func labelColor(color string) (string, error) {
+	if !labelColorRe.MatchString(color) {
+		return "", fmt.Errorf("color must be six hex digits without '#', e.g. d73a4a, got %q", color)
+	}
+	return strings.ToLower(color), nil
+}
+
+// labelCreateBody builds the POST /labels body.
+func labelCreateBody(args map[string]interface{}) (map[string]string, error) {
+	body := map[string]string{}
+	name, _ := args["name"].(string)
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	body["name"] = name
+	if color, _ := args["color"].(string); color != "" {
+		color, err := labelColor(color)
+		if err != nil {
+			return nil, err
+		}
+		body["color"] = color
+	}
+	if description, _ := args["description"].(string); description != "" {
+		body["description"] = description
+	}
+	return body, nil
+}
+
+// labelUpdateBody builds the PATCH /labels/{name} body. Only the given
+// fields are sent, but an empty description is sent to clear it.
+func labelUpdateBody(args map[string]interface{}) (map[string]string, error) {
+	body := map[string]string{}
+	if newName, _ := args["new_name"].(string); newName != "" {
+		body["new_name"] = newName
+	}
+	if color, _ := args["color"].(string); color != "" {
+		color, err := labelColor(color)
+		if err != nil {
+			return nil, err
+		}
+		body["color"] = color
+	}
+	if description, ok := args["description"].(string); ok {
+		body["description"] = description
+	}
+	if len(body) == 0 {
+		return nil, fmt.Errorf("give at least one of new_name, color or description")
+	}
+	return body, nil
+}
+
+func labelURL(owner, repo, name string) string {
+	return fmt.Sprintf("https://api.github.com/repos/%s/%s/labels/%s", owner, repo, url.PathEscape(name))
+}
+
+func labelsList(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	u := fmt.Sprint("https://api.github.com/repos/", owner, "/", repo, "/labels?", strings.Join(paginationParams(args), "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing labels: ", u))
+
+	labels := []Label{}
+	if _, err := githubGetJSON(apiKey, u, &labels); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list labels: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(labels)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+// labelsWrite creates the label, or updates it when name is set.
+func labelsWrite(apiKey, owner, repo, name string, args map[string]interface{}) CallToolResult {
+	method, u, want, action := pdk.MethodPost, fmt.Sprintf("https://api.github.com/repos/%s/%s/labels", owner, repo), uint16(201), "create"
+	body, err := labelCreateBody(args)
+	if name != "" {
+		method, u, want, action = pdk.MethodPatch, labelURL(owner, repo, name), 200, "update"
+		body, err = labelUpdateBody(args)
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid label: %s", err)),
+			}},
+		}
+	}
+
+	pdk.Log(pdk.LogDebug, fmt.Sprintf("Label %s: %s", action, u))
+	var label Label
+	if err := githubSendJSON(apiKey, method, u, body, want, &label); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to %s label: %s", action, err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(label)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func labelsDelete(apiKey, owner, repo, name string) CallToolResult {
+	u := labelURL(owner, repo, name)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Deleting label: ", u))
+	_, err := githubSendExpect(apiKey, pdk.MethodDelete, u, nil, 204)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to delete label: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(fmt.Sprintf("Deleted label %q from %s/%s", name, owner, repo)),
+		}},
+	}
+}
+
+// issueLabelsFromArgs reads the labels to add, dropping blanks and
+// case-insensitive duplicates.
+func issueLabelsFromArgs(args map[string]interface{}) ([]string, error) {
+	var raw []string
+	switch labels := args["labels"].(type) {
+	case nil:
+	case []interface{}:
+		for _, l := range labels {
+			name, ok := l.(string)
+			if !ok {
+				return nil, fmt.Errorf("labels must be strings, got %#v", l)
+			}
+			raw = append(raw, name)
+		}
+	default:
+		return nil, fmt.Errorf("labels must be an array of label names, got %#v", labels)
+	}
+
+	labels := []string{}
+	seen := map[string]bool{}
+	for _, name := range raw {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		labels = append(labels, name)
+	}
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("labels must list at least one label")
+	}
+	return labels, nil
+}
+
+func issueLabelsURL(owner, repo string, number int) string {
+	return fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d/labels", owner, repo, number)
+}
+
+func issueLabelsResult(labels []Label) CallToolResult {
+	responseJSON, err := json.Marshal(labels)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func issueAddLabels(apiKey, owner, repo string, number int, args map[string]interface{}) CallToolResult {
+	labels, err := issueLabelsFromArgs(args)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid labels: %s", err)),
+			}},
+		}
+	}
+
+	u := issueLabelsURL(owner, repo, number)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Adding issue labels: ", u))
+	result := []Label{}
+	if err := githubSendJSON(apiKey, pdk.MethodPost, u, map[string][]string{"labels": labels}, 200, &result); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to add labels to #%d: %s", number, err)),
+			}},
+		}
+	}
+	return issueLabelsResult(result)
+}
+
+func issueRemoveLabel(apiKey, owner, repo string, number int, label string) CallToolResult {
+	u := issueLabelsURL(owner, repo, number) + "/" + url.PathEscape(label)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Removing issue label: ", u))
+	status, body, err := githubSend(apiKey, pdk.MethodDelete, u, nil)
+	if err == nil && status != 200 {
+		err = githubStatusError(status, body)
+		if status == 404 {
+			err = fmt.Errorf("the issue doesn't exist or doesn't have that label")
+		}
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to remove label %q from #%d: %s", label, number, err)),
+			}},
+		}
+	}
+
+	result := []Label{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Removed label %q from #%d but could not read the remaining labels: %s", label, number, err)),
+			}},
+		}
+	}
+	return issueLabelsResult(result)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLabelCreateBody(t *testing.T) {
+	tests := []struct {
+		args    string
+		want    string
+		wantErr string
+	}{
+		{args: `{"name":"bug"}`, want: `{"name":"bug"}`},
+		{args: `{"name":"needs triage","color":"D73A4A","description":"Not looked at yet"}`, want: `{"color":"d73a4a","description":"Not looked at yet","name":"needs triage"}`},
+		{args: `{"name":" "}`, wantErr: "name is required"},
+		{args: `{"name":"bug","color":"#d73a4a"}`, wantErr: "without '#'"},
+		{args: `{"name":"bug","color":"red"}`, wantErr: "six hex digits"},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		body, err := labelCreateBody(args)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error %v, want one containing %q", tt.args, err, tt.wantErr)
+			}
+			continue
+		}
+		out, _ := json.Marshal(body)
+		if err != nil || string(out) != tt.want {
+			t.Errorf("%s: got %s, %v, want %s", tt.args, out, err, tt.want)
+		}
+	}
+}
+
+func TestLabelUpdateBody(t *testing.T) {
+	tests := []struct {
+		args    string
+		want    string
+		wantErr string
+	}{
+		{args: `{"name":"bug","new_name":"defect"}`, want: `{"new_name":"defect"}`},
+		{args: `{"name":"bug","color":"00ff00"}`, want: `{"color":"00ff00"}`},
+		{args: `{"name":"bug","description":""}`, want: `{"description":""}`},
+		{args: `{"name":"bug"}`, wantErr: "give at least one of"},
+		{args: `{"name":"bug","color":"0f0"}`, wantErr: "six hex digits"},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		body, err := labelUpdateBody(args)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error %v, want one containing %q", tt.args, err, tt.wantErr)
+			}
+			continue
+		}
+		out, _ := json.Marshal(body)
+		if err != nil || string(out) != tt.want {
+			t.Errorf("%s: got %s, %v, want %s", tt.args, out, err, tt.want)
+		}
+	}
+}
+
+func TestLabelURLEscapesName(t *testing.T) {
+	got := labelURL("acme", "api", "good first issue/ui")
+	want := "https://api.github.com/repos/acme/api/labels/good%20first%20issue%2Fui"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestIssueLabelsFromArgs(t *testing.T) {
+	tests := []struct {
+		args    string
+		want    string
+		wantErr string
+	}{
+		{args: `{"labels":["bug","needs triage"]}`, want: "bug|needs triage"},
+		{args: `{"labels":["bug"," Bug ","ui/ux",""]}`, want: "bug|ui/ux"},
+		{args: `{"labels":"bug, good first issue"}`, wantErr: "labels must be an array"},
+		{args: `{"labels":[]}`, wantErr: "at least one label"},
+		{args: `{}`, wantErr: "at least one label"},
+		{args: `{"labels":[1]}`, wantErr: "labels must be strings"},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		labels, err := issueLabelsFromArgs(args)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error %v, want one containing %q", tt.args, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || strings.Join(labels, "|") != tt.want {
+			t.Errorf("%s: got %q, %v, want %q", tt.args, labels, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var RepoLanguagesTool = ToolDescription{
+	Name:        "gh-repo-languages",
+	Description: "Break a repository's code down by language, as a share of its bytes",
+	InputSchema: schema{
+		"type": "object",
+		"properties": props{
+			"owner": prop("string", "The owner of the repository"),
+			"repo":  prop("string", "The repository name"),
+		},
+		"required": []string{"owner", "repo"},
+	},
+}
+
+type LanguageShare struct {
+	Language string  `json:"-"`
+	Bytes    int     `json:"bytes"`
+	Percent  float64 `json:"percent"`
+}
+
+// languageShares turns GitHub's byte counts into percentages rounded to
+// one decimal, largest first. Rounded percentages may not add up to 100.
+func languageShares(bytes map[string]int) []LanguageShare {
+	total := 0
+	for _, b := range bytes {
+		total += b
+	}
+	shares := make([]LanguageShare, 0, len(bytes))
+	for language, b := range bytes {
+		share := LanguageShare{Language: language, Bytes: b}
+		if total > 0 {
+			share.Percent = math.Round(float64(b)*1000/float64(total)) / 10
+		}
+		shares = append(shares, share)
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Bytes != shares[j].Bytes {
+			return shares[i].Bytes > shares[j].Bytes
+		}
+		return shares[i].Language < shares[j].Language
+	})
+	return shares
+}
+
+func languagesTable(shares []LanguageShare) string {
+	width := len("Language")
+	for _, s := range shares {
+		if len(s.Language) > width {
+			width = len(s.Language)
+		}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-*s  %12s  %7s\n", width, "Language", "Bytes", "Percent")
+	for _, s := range shares {
+		fmt.Fprintf(&b, "%-*s  %12d  %6.1f%%\n", width, s.Language, s.Bytes, s.Percent)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func reposLanguages(apiKey, owner, repo string) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/languages", owner, repo)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting languages: ", u))
+
+	bytes := map[string]int{}
+	if _, err := githubGetJSON(apiKey, u, &bytes); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to get languages: %s", err)),
+			}},
+		}
+	}
+	if len(bytes) == 0 {
+		return CallToolResult{
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("GitHub detected no languages in %s/%s", owner, repo)),
+			}},
+		}
+	}
+
+	shares := languageShares(bytes)
+	structured := map[string]interface{}{}
+	for _, s := range shares {
+		structured[s.Language] = s
+	}
+	// Round-trip so the structured content holds plain JSON values
+	responseJSON, err := json.Marshal(structured)
+	if err == nil {
+		structured = map[string]interface{}{}
+		err = json.Unmarshal(responseJSON, &structured)
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(languagesTable(shares)),
+		}},
+		StructuredContent: structured,
+	}
+}
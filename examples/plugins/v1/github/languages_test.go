@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLanguageShares(t *testing.T) {
+	shares := languageShares(map[string]int{"Go": 6120, "Rust": 3000, "Shell": 440, "Makefile": 440, "Dockerfile": 0})
+	got, _ := json.Marshal(shares)
+	want := `[{"bytes":6120,"percent":61.2},{"bytes":3000,"percent":30},{"bytes":440,"percent":4.4},{"bytes":440,"percent":4.4},{"bytes":0,"percent":0}]`
+	if string(got) != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+	order := ""
+	for _, s := range shares {
+		order += s.Language + " "
+	}
+	if order != "Go Rust Makefile Shell Dockerfile " {
+		t.Errorf("order: %s", order)
+	}
+
+	// 1/3 each rounds down, so the total is 99.9
+	for _, s := range languageShares(map[string]int{"A": 1, "B": 1, "C": 1}) {
+		if s.Percent != 33.3 {
+			t.Errorf("%s: got %v", s.Language, s.Percent)
+		}
+	}
+	if got := languageShares(map[string]int{}); len(got) != 0 {
+		t.Errorf("no languages: got %v", got)
+	}
+}
+
+func TestLanguagesTable(t *testing.T) {
+	got := languagesTable([]LanguageShare{{Language: "Go", Bytes: 6120, Percent: 61.2}, {Language: "JavaScript", Bytes: 25, Percent: 0.3}})
+	want := "Language           Bytes  Percent\n" +
+		"Go                  6120    61.2%\n" +
+		"JavaScript            25     0.3%"
+	if got != want {
+		t.Errorf("\n got %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/extism/go-pdk"
+)
+
+var GetLicenseTool = ToolDescription{
+	Name:        "gh-get-license",
+	Description: "Get the license GitHub detected for a repository: its SPDX id, name and optionally the full text",
+	InputSchema: schema{
+		"type": "object",
+		"properties": props{
+			"owner":        prop("string", "The owner of the repository"),
+			"repo":         prop("string", "The repository name"),
+			"include_text": prop("boolean", "Include the full license text (default false)"),
+		},
+		"required": []string{"owner", "repo"},
+	},
+}
+
+type RepoLicense struct {
+	Path     string `json:"path"`
+	HTMLURL  string `json:"html_url"`
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+	License  struct {
+		Key    string `json:"key"`
+		Name   string `json:"name"`
+		SPDXID string `json:"spdx_id"`
+	} `json:"license"`
+}
+
+type LicenseSummary struct {
+	SPDXID  string `json:"spdx_id"`
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	HTMLURL string `json:"html_url"`
+	Text    string `json:"text,omitempty"`
+}
+
+// licenseSummary picks the fields worth returning. GitHub reports a
+// license file it can't identify with the SPDX id NOASSERTION.
+func licenseSummary(license RepoLicense, includeText bool) (LicenseSummary, error) {
+	summary := LicenseSummary{
+		SPDXID:  license.License.SPDXID,
+		Name:    license.License.Name,
+		Path:    license.Path,
+		HTMLURL: license.HTMLURL,
+	}
+	if includeText {
+		text, err := decodeContent(license.Path, license.Encoding, license.Content)
+		if err != nil {
+			return summary, err
+		}
+		summary.Text = text
+	}
+	return summary, nil
+}
+
+func reposGetLicense(apiKey, owner, repo string, includeText bool) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/license", owner, repo)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting license: ", u))
+
+	var license RepoLicense
+	status, err := githubGetJSON(apiKey, u, &license)
+	if status == 404 {
+		return CallToolResult{
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("No license detected in %s/%s", owner, repo)),
+			}},
+		}
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to get license: %s", err)),
+			}},
+		}
+	}
+
+	summary, err := licenseSummary(license, includeText)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to read license: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(summary)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLicenseSummary(t *testing.T) {
+	var license RepoLicense
+	if err := json.Unmarshal([]byte(`{
+		"path": "LICENSE",
+		"html_url": "https://github.com/o/r/blob/main/LICENSE",
+		"content": "QXBhY2hl\nIExpY2Vuc2U=\n",
+		"encoding": "base64",
+		"license": {"key": "apache-2.0", "name": "Apache License 2.0", "spdx_id": "Apache-2.0"}
+	}`), &license); err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := licenseSummary(license, false)
+	got, _ := json.Marshal(summary)
+	want := `{"spdx_id":"Apache-2.0","name":"Apache License 2.0","path":"LICENSE","html_url":"https://github.com/o/r/blob/main/LICENSE"}`
+	if err != nil || string(got) != want {
+		t.Errorf("without text:\n got %s, %v\nwant %s", got, err, want)
+	}
+
+	summary, err = licenseSummary(license, true)
+	if err != nil || summary.Text != "Apache License" {
+		t.Errorf("with text: got %q, %v", summary.Text, err)
+	}
+
+	license.Content = "%%%"
+	if _, err := licenseSummary(license, false); err != nil {
+		t.Errorf("text not requested, so it shouldn't be decoded: %v", err)
+	}
+	if _, err := licenseSummary(license, true); err == nil {
+		t.Error("expected a decode error")
+	}
+}
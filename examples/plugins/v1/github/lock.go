@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	LockIssueTool = ToolDescription{
+		Name:        "gh-lock-issue",
+		Description: "Lock the conversation of an issue or pull request so only collaborators can comment",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":       prop("string", "The owner of the repository"),
+				"repo":        prop("string", "The repository name"),
+				"issue":       prop("integer", "The issue or pull request number"),
+				"lock_reason": prop("string", "Why the conversation is locked: off-topic, too heated, resolved or spam (optional)"),
+			},
+			"required": []string{"owner", "repo", "issue"},
+		},
+	}
+	UnlockIssueTool = ToolDescription{
+		Name:        "gh-unlock-issue",
+		Description: "Unlock the conversation of an issue or pull request",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner": prop("string", "The owner of the repository"),
+				"repo":  prop("string", "The repository name"),
+				"issue": prop("integer", "The issue or pull request number"),
+			},
+			"required": []string{"owner", "repo", "issue"},
+		},
+	}
+)
+
+var lockReasons = []string{"off-topic", "too heated", "resolved", "spam"}
+
+// lockReason validates lock_reason, accepting too_heated and too-heated
+// for "too heated".
+func lockReason(args map[string]interface{}) (string, error) {
+	reason, _ := args["lock_reason"].(string)
+	reason = strings.ToLower(strings.TrimSpace(reason))
+	if reason == "" {
+		return "", nil
+	}
+	if reason == "too_heated" || reason == "too-heated" {
+		reason = "too heated"
+	}
+	for _, r := range lockReasons {
+		if reason == r {
+			return reason, nil
+		}
+	}
+	return "", fmt.Errorf("lock_reason must be one of %s, got %q", strings.Join(lockReasons, ", "), args["lock_reason"])
+}
+
+// issueSetLocked locks or unlocks the conversation. GitHub answers 204
+// without a body, so the result describes what was done.
+func issueSetLocked(apiKey, owner, repo string, number int, args map[string]interface{}, locked bool) CallToolResult {
+	reason, err := lockReason(args)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid lock: %s", err)),
+			}},
+		}
+	}
+
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d/lock", owner, repo, number)
+	method, action := pdk.MethodDelete, "unlock"
+	if locked {
+		method, action = pdk.MethodPut, "lock"
+	}
+	pdk.Log(pdk.LogDebug, fmt.Sprintf("Issue %s: %s", action, u))
+	var body interface{}
+	if locked && reason != "" {
+		body = map[string]string{"lock_reason": reason}
+	}
+	_, err = githubSendExpect(apiKey, method, u, body, 204)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to %s #%d: %s", action, number, err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(lockMessage(owner, repo, number, locked, reason)),
+		}},
+	}
+}
+
+func lockMessage(owner, repo string, number int, locked bool, reason string) string {
+	if !locked {
+		return fmt.Sprintf("Unlocked the conversation on %s/%s#%d", owner, repo, number)
+	}
+	if reason == "" {
+		return fmt.Sprintf("Locked the conversation on %s/%s#%d", owner, repo, number)
+	}
+	return fmt.Sprintf("Locked the conversation on %s/%s#%d as %s", owner, repo, number, reason)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLockReason(t *testing.T) {
+	tests := []struct {
+		reason  interface{}
+		want    string
+		wantErr bool
+	}{
+		{reason: nil, want: ""},
+		{reason: "spam", want: "spam"},
+		{reason: " Off-Topic ", want: "off-topic"},
+		{reason: "too heated", want: "too heated"},
+		{reason: "too_heated", want: "too heated"},
+		{reason: "rude", wantErr: true},
+	}
+	for _, tt := range tests {
+		args := map[string]interface{}{}
+		if tt.reason != nil {
+			args["lock_reason"] = tt.reason
+		}
+		got, err := lockReason(args)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%v: got %q, %v, want %q", tt.reason, got, err, tt.want)
+		}
+		if err != nil && !strings.Contains(err.Error(), "off-topic, too heated, resolved, spam") {
+			t.Errorf("error doesn't list the reasons: %v", err)
+		}
+	}
+}
+
+func TestLockMessage(t *testing.T) {
+	tests := []struct {
+		locked bool
+		reason string
+		want   string
+	}{
+		{true, "", "Locked the conversation on acme/api#7"},
+		{true, "too heated", "Locked the conversation on acme/api#7 as too heated"},
+		{false, "", "Unlocked the conversation on acme/api#7"},
+	}
+	for _, tt := range tests {
+		if got := lockMessage("acme", "api", 7, tt.locked, tt.reason); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
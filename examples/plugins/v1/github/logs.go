@@ -0,0 +1,298 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var GetRunLogsTool = ToolDescription{
+	Name:        "gh-get-run-logs",
+	Description: "Read the logs of a GitHub Actions workflow run: the full log of one job, or by default the last lines of every failed job",
+	InputSchema: schema{
+		"type": "object",
+		"properties": props{
+			"owner":      prop("string", "The owner of the repository"),
+			"repo":       prop("string", "The repository name"),
+			"run_id":     prop("integer", "The workflow run id"),
+			"job":        prop("string", "Return the full log of the job with this name"),
+			"tail_lines": prop("integer", "Lines kept from the end of each failed job's log (default 50)"),
+			"max_bytes":  prop("integer", "Budget of uncompressed log bytes to read; logs that don't fit are skipped and listed (default 1048576, at most 10485760)"),
+		},
+		"required": []string{"owner", "repo", "run_id"},
+	},
+}
+
+const (
+	logsDefaultTailLines = 50
+	logsDefaultMaxBytes  = 1 << 20
+	logsMaxMaxBytes      = 10 << 20
+)
+
+type WorkflowStep struct {
+	Name        string  `json:"name"`
+	Number      int     `json:"number"`
+	Status      string  `json:"status"`
+	Conclusion  *string `json:"conclusion"`
+	StartedAt   *string `json:"started_at"`
+	CompletedAt *string `json:"completed_at"`
+}
+
+type WorkflowJob struct {
+	ID          int            `json:"id"`
+	Name        string         `json:"name"`
+	Status      string         `json:"status"`
+	Conclusion  *string        `json:"conclusion"`
+	StartedAt   *string        `json:"started_at"`
+	CompletedAt *string        `json:"completed_at"`
+	HTMLURL     string         `json:"html_url"`
+	Steps       []WorkflowStep `json:"steps"`
+}
+
+type JobLog struct {
+	Job string `json:"job"`
+	// Lines left out from the start of the log, when only its tail is kept
+	OmittedLines int    `json:"omitted_lines,omitempty"`
+	Text         string `json:"text"`
+}
+
+type SkippedLog struct {
+	File string `json:"file"`
+	Size uint64 `json:"size"`
+}
+
+type RunLogs struct {
+	RunID   int          `json:"run_id"`
+	Logs    []JobLog     `json:"logs"`
+	Skipped []SkippedLog `json:"skipped,omitempty"`
+}
+
+type runLogsRequest struct {
+	RunID     int
+	Job       string
+	TailLines int
+	MaxBytes  uint64
+}
+
+func runLogsRequestFromArgs(args map[string]interface{}) (runLogsRequest, error) {
+	req := runLogsRequest{TailLines: logsDefaultTailLines, MaxBytes: logsDefaultMaxBytes}
+	id, _ := args["run_id"].(float64)
+	if id < 1 {
+		return req, fmt.Errorf("run_id is required")
+	}
+	req.RunID = int(id)
+	req.Job, _ = args["job"].(string)
+	if value, ok := args["tail_lines"].(float64); ok {
+		if value < 1 {
+			return req, fmt.Errorf("tail_lines must be at least 1, got %v", value)
+		}
+		req.TailLines = int(value)
+	}
+	if value, ok := args["max_bytes"].(float64); ok {
+		if value < 1 || value > logsMaxMaxBytes {
+			return req, fmt.Errorf("max_bytes must be from 1 to %d, got %v", logsMaxMaxBytes, value)
+		}
+		req.MaxBytes = uint64(value)
+	}
+	return req, nil
+}
+
+// jobLogFiles maps job names to their log in the run's logs archive. Each
+// job has a top-level file named after it with a numeric prefix, e.g.
+// "0_build.txt", next to a directory of per-step logs.
+func jobLogFiles(files []*zip.File) map[string]*zip.File {
+	jobs := map[string]*zip.File{}
+	for _, f := range files {
+		if strings.Contains(f.Name, "/") || path.Ext(f.Name) != ".txt" {
+			continue
+		}
+		prefix, name, ok := strings.Cut(strings.TrimSuffix(f.Name, ".txt"), "_")
+		if _, err := strconv.Atoi(prefix); !ok || err != nil {
+			continue
+		}
+		jobs[name] = f
+	}
+	return jobs
+}
+
+// logFileName is how GitHub names a job in the logs archive: characters
+// that aren't allowed in file names are dropped.
+func logFileName(job string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) {
+			return -1
+		}
+		return r
+	}, job)
+}
+
+func tailLines(text string, n int) (string, int) {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) <= n {
+		return strings.Join(lines, "\n"), 0
+	}
+	return strings.Join(lines[len(lines)-n:], "\n"), len(lines) - n
+}
+
+// extractRunLogs reads the logs of the requested job, or the tails of the
+// failed jobs, from the logs archive. Logs are only decompressed while they
+// fit in the budget; the others are reported as skipped.
+func extractRunLogs(archive []byte, req runLogsRequest, failedJobs []string) (RunLogs, error) {
+	result := RunLogs{RunID: req.RunID, Logs: []JobLog{}}
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return result, fmt.Errorf("reading logs archive: %w", err)
+	}
+	files := jobLogFiles(reader.File)
+
+	jobs := failedJobs
+	if req.Job != "" {
+		jobs = []string{req.Job}
+	}
+	budget := req.MaxBytes
+	for _, job := range jobs {
+		f, ok := files[logFileName(job)]
+		if !ok {
+			if req.Job == "" {
+				continue
+			}
+			names := make([]string, 0, len(files))
+			for name := range files {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return result, fmt.Errorf("no log for job %q; the run has logs for %s", req.Job, strings.Join(names, ", "))
+		}
+		if f.UncompressedSize64 > budget {
+			result.Skipped = append(result.Skipped, SkippedLog{File: f.Name, Size: f.UncompressedSize64})
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return result, fmt.Errorf("opening %s: %w", f.Name, err)
+		}
+		data, err := io.ReadAll(io.LimitReader(rc, int64(budget)))
+		rc.Close()
+		if err != nil {
+			return result, fmt.Errorf("reading %s: %w", f.Name, err)
+		}
+		budget -= uint64(len(data))
+
+		log := JobLog{Job: job, Text: string(data)}
+		if req.Job == "" {
+			log.Text, log.OmittedLines = tailLines(log.Text, req.TailLines)
+		}
+		result.Logs = append(result.Logs, log)
+	}
+	return result, nil
+}
+
+// failedConclusion reports whether a job or step conclusion is a failure.
+func failedConclusion(conclusion *string) bool {
+	return conclusion != nil && (*conclusion == "failure" || *conclusion == "timed_out")
+}
+
+func failedJobNames(jobs []WorkflowJob) []string {
+	names := []string{}
+	for _, j := range jobs {
+		if failedConclusion(j.Conclusion) {
+			names = append(names, j.Name)
+		}
+	}
+	return names
+}
+
+func runJobs(apiKey, owner, repo string, runID int) ([]WorkflowJob, error) {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/runs/%d/jobs?filter=latest&per_page=100", owner, repo, runID)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing run jobs: ", u))
+	var page struct {
+		Jobs []WorkflowJob `json:"jobs"`
+	}
+	if _, err := githubGetJSON(apiKey, u, &page); err != nil {
+		return nil, err
+	}
+	return page.Jobs, nil
+}
+
+func runsGetLogs(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	req, err := runLogsRequestFromArgs(args)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid logs request: %s", err)),
+			}},
+		}
+	}
+
+	var failed []string
+	if req.Job == "" {
+		jobs, err := runJobs(apiKey, owner, repo, req.RunID)
+		if err != nil {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Failed to list the run's jobs: %s", err)),
+				}},
+			}
+		}
+		if failed = failedJobNames(jobs); len(failed) == 0 {
+			return CallToolResult{
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Run %d has no failed jobs; pass job to read a job's full log", req.RunID)),
+				}},
+			}
+		}
+	}
+
+	archive, err := githubDownload(apiKey, fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/runs/%d/logs", owner, repo, req.RunID))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to download logs of run %d: %s", req.RunID, err)),
+			}},
+		}
+	}
+
+	logs, err := extractRunLogs(archive, req, failed)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(err.Error()),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(logs)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
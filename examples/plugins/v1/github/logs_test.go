@@ -0,0 +1,127 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func logsArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func numberedLines(n int) string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func TestExtractRunLogs(t *testing.T) {
+	archive := logsArchive(t, map[string]string{
+		"0_build.txt":              numberedLines(10),
+		"1_test (ubuntu).txt":      numberedLines(3),
+		"2_deploy prod.txt":        strings.Repeat("x", 200),
+		"build/1_Set up job.txt":   "step log",
+		"4_lintstyle.txt":          "clean",
+		"test (ubuntu)/2_Test.txt": "step log",
+	})
+
+	t.Run("tails of failed jobs", func(t *testing.T) {
+		req := runLogsRequest{RunID: 7, TailLines: 2, MaxBytes: 100}
+		got, err := extractRunLogs(archive, req, []string{"build", "test (ubuntu)", "deploy prod", "gone"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got.Logs) != 2 {
+			t.Fatalf("expected 2 logs, got %+v", got.Logs)
+		}
+		if got.Logs[0].Job != "build" || got.Logs[0].Text != "line 9\nline 10" || got.Logs[0].OmittedLines != 8 {
+			t.Errorf("build: %+v", got.Logs[0])
+		}
+		if got.Logs[1].Text != "line 2\nline 3" || got.Logs[1].OmittedLines != 1 {
+			t.Errorf("test: %+v", got.Logs[1])
+		}
+		if len(got.Skipped) != 1 || got.Skipped[0].File != "2_deploy prod.txt" || got.Skipped[0].Size != 200 {
+			t.Errorf("skipped: %+v", got.Skipped)
+		}
+	})
+
+	t.Run("full log of one job", func(t *testing.T) {
+		req := runLogsRequest{RunID: 7, Job: "build", TailLines: 2, MaxBytes: 1000}
+		got, err := extractRunLogs(archive, req, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got.Logs) != 1 || got.Logs[0].Text != numberedLines(10) || got.Logs[0].OmittedLines != 0 {
+			t.Errorf("got %+v", got.Logs)
+		}
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		req := runLogsRequest{RunID: 7, Job: "docs", MaxBytes: 1000}
+		_, err := extractRunLogs(archive, req, nil)
+		if err == nil || !strings.Contains(err.Error(), "build, deploy prod, lintstyle, test (ubuntu)") {
+			t.Errorf("got %v", err)
+		}
+	})
+
+	t.Run("not a zip", func(t *testing.T) {
+		if _, err := extractRunLogs([]byte("nope"), runLogsRequest{MaxBytes: 1}, nil); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestFailedJobNames(t *testing.T) {
+	failure, timedOut, success := "failure", "timed_out", "success"
+	got := failedJobNames([]WorkflowJob{
+		{Name: "build", Conclusion: &success},
+		{Name: "test", Conclusion: &failure},
+		{Name: "e2e", Conclusion: &timedOut},
+		{Name: "deploy"},
+	})
+	if strings.Join(got, ",") != "test,e2e" {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestRunLogsRequestFromArgs(t *testing.T) {
+	req, err := runLogsRequestFromArgs(map[string]interface{}{"run_id": float64(9)})
+	if err != nil || req.RunID != 9 || req.TailLines != logsDefaultTailLines || req.MaxBytes != logsDefaultMaxBytes {
+		t.Errorf("defaults: %+v, %v", req, err)
+	}
+	for _, args := range []map[string]interface{}{
+		{},
+		{"run_id": float64(9), "tail_lines": float64(0)},
+		{"run_id": float64(9), "max_bytes": float64(logsMaxMaxBytes + 1)},
+	} {
+		if _, err := runLogsRequestFromArgs(args); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
+
+func TestLogFileName(t *testing.T) {
+	if got := logFileName(`lint/style: "go"`); got != "lintstyle go" {
+		t.Errorf("got %q", got)
+	}
+}
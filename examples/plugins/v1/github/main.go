@@ -4,6 +4,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/extism/go-pdk"
 )
@@ -26,11 +27,30 @@ func Call(input CallToolRequest) (CallToolResult, error) {
 	}
 	args := input.Params.Arguments.(map[string]interface{})
 	pdk.Log(pdk.LogDebug, fmt.Sprint("Args: ", args))
+	if tool, ok := findTool(input.Params.Name); ok {
+		if err := normalizeNumericArgs(tool, args); err != nil {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Invalid arguments for %s: %s", input.Params.Name, err)),
+				}},
+			}, nil
+		}
+	}
+	getJSON := func(u string, out interface{}) (uint16, error) {
+		return githubGetJSON(apiKey, u, out)
+	}
+	if refused := confirmCall(input.Params.Name, args, getJSON, elicitConfirmation); refused != nil {
+		return *refused, nil
+	}
 	switch input.Params.Name {
 	case ListIssuesTool.Name:
 		owner, _ := args["owner"].(string)
 		repo, _ := args["repo"].(string)
-		return issueList(apiKey, owner, repo, args)
+		return issueList(apiKey, issueListURL(owner, repo, args))
+	case ListMyIssuesTool.Name:
+		return issueList(apiKey, myIssuesURL(args))
 	case GetIssueTool.Name:
 		owner, _ := args["owner"].(string)
 		repo, _ := args["repo"].(string)
@@ -46,13 +66,61 @@ func Call(input CallToolRequest) (CallToolResult, error) {
 		owner, _ := args["owner"].(string)
 		repo, _ := args["repo"].(string)
 		data := issueFromArgs(args)
+		if failed := issueResolveMilestone(apiKey, owner, repo, &data); failed != nil {
+			return *failed, nil
+		}
 		return issueCreate(apiKey, owner, repo, data)
 	case UpdateIssueTool.Name:
 		owner, _ := args["owner"].(string)
 		repo, _ := args["repo"].(string)
 		issue, _ := args["issue"].(float64)
 		data := issueFromArgs(args)
-		return issueUpdate(apiKey, owner, repo, int(issue), data)
+		if err := issueCheckState(&data); err != nil {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Invalid issue update: %s", err)),
+				}},
+			}, nil
+		}
+		if failed := issueResolveMilestone(apiKey, owner, repo, &data); failed != nil {
+			return *failed, nil
+		}
+		body, err := issueUpdateBody(args, data)
+		if err != nil {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Invalid issue update: %s", err)),
+				}},
+			}, nil
+		}
+		return issueUpdate(apiKey, owner, repo, int(issue), body)
+	case CloseIssueTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		issue, _ := args["issue"].(float64)
+		reason, _ := args["state_reason"].(string)
+		comment, _ := args["comment"].(string)
+		return issueClose(apiKey, owner, repo, int(issue), reason, comment)
+	case IssueEngagementTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		issue, _ := args["issue"].(float64)
+		return issueEngagement(apiKey, owner, repo, int(issue)), nil
+
+	case ExportIssueTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		issue, _ := args["issue"].(float64)
+		return issueExport(apiKey, owner, repo, int(issue)), nil
+
+	case FindDuplicateIssuesTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return issueFindDuplicates(apiKey, owner, repo, args), nil
 
 	case GetFileContentsTool.Name:
 		owner, _ := args["owner"].(string)
@@ -61,6 +129,19 @@ func Call(input CallToolRequest) (CallToolResult, error) {
 		branch, _ := args["branch"].(string)
 		res := filesGetContents(apiKey, owner, repo, path, &branch)
 		return res, nil
+	case GetFilesTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return filesGetMany(apiKey, owner, repo, args), nil
+	case DownloadFileTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		path, _ := args["path"].(string)
+		return filesDownload(apiKey, owner, repo, path, args), nil
+	case GetReadmeTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return reposGetReadme(apiKey, owner, repo, args), nil
 	case CreateOrUpdateFileTool.Name:
 		owner, _ := args["owner"].(string)
 		repo, _ := args["repo"].(string)
@@ -68,6 +149,13 @@ func Call(input CallToolRequest) (CallToolResult, error) {
 		file := fileCreateFromArgs(args)
 		return filesCreateOrUpdate(apiKey, owner, repo, path, file)
 
+	case DeleteFileTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		path, _ := args["path"].(string)
+		file := fileDeleteFromArgs(args)
+		return filesDelete(apiKey, owner, repo, path, file), nil
+
 	case CreateBranchTool.Name:
 		owner, _ := args["owner"].(string)
 		repo, _ := args["repo"].(string)
@@ -77,6 +165,46 @@ func Call(input CallToolRequest) (CallToolResult, error) {
 			maybeBranch = &branch
 		}
 		return branchCreate(apiKey, owner, repo, from, maybeBranch), nil
+	case DeleteBranchTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		branch, _ := args["branch"].(string)
+		return branchDelete(apiKey, owner, repo, branch), nil
+	case RenameBranchTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		branch, _ := args["branch"].(string)
+		newName, _ := args["new_name"].(string)
+		return branchRename(apiKey, owner, repo, branch, newName), nil
+	case GetBranchProtectionTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		branch, _ := args["branch"].(string)
+		return branchGetProtection(apiKey, owner, repo, branch), nil
+	case UpdateBranchProtectionTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		branch, _ := args["branch"].(string)
+		return branchUpdateProtection(apiKey, owner, repo, branch, args), nil
+	case ReviewPullRequestChunkedTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		pullNumber, _ := args["pull_number"].(float64)
+		chunkID, _ := args["chunk_id"].(string)
+		budget := reviewDefaultChunkBytes
+		if value, ok := args["chunk_bytes"].(float64); ok {
+			if value < reviewMinChunkBytes || value > reviewMaxChunkBytes {
+				return CallToolResult{
+					IsError: some(true),
+					Content: []Content{{
+						Type: ContentTypeText,
+						Text: some(fmt.Sprintf("chunk_bytes must be between %d and %d, got %v", reviewMinChunkBytes, reviewMaxChunkBytes, value)),
+					}},
+				}, nil
+			}
+			budget = int(value)
+		}
+		return pullRequestReviewChunked(apiKey, owner, repo, int(pullNumber), chunkID, budget), nil
 
 	case ListPullRequestsTool.Name:
 		owner, _ := args["owner"].(string)
@@ -89,6 +217,47 @@ func Call(input CallToolRequest) (CallToolResult, error) {
 		pr := branchPullRequestSchemaFromArgs(args)
 		return branchCreatePullRequest(apiKey, owner, repo, pr), nil
 
+	case ListPullRequestCommitsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		pullNumber, _ := args["pull_number"].(float64)
+		return pullRequestListCommits(apiKey, owner, repo, int(pullNumber), args)
+
+	case SetPullRequestDraftTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		pullNumber, _ := args["pull_number"].(float64)
+		draft, _ := args["draft"].(bool)
+		return pullRequestSetDraft(apiKey, owner, repo, int(pullNumber), draft), nil
+
+	case ClosePullRequestTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		pullNumber, _ := args["pull_number"].(float64)
+		return pullRequestSetState(apiKey, owner, repo, int(pullNumber), "closed"), nil
+
+	case ReopenPullRequestTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		pullNumber, _ := args["pull_number"].(float64)
+		return pullRequestSetState(apiKey, owner, repo, int(pullNumber), "open"), nil
+
+	case EnableAutoMergeTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		pullNumber, _ := args["pull_number"].(float64)
+		mergeMethod, _ := args["merge_method"].(string)
+		disable, _ := args["disable"].(bool)
+		return pullRequestSetAutoMerge(apiKey, owner, repo, int(pullNumber), mergeMethod, disable), nil
+
+	case GetTreeTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return treeGet(apiKey, owner, repo, args), nil
+
+	case CopyFileTool.Name:
+		return copyFile(apiKey, args), nil
+
 	case PushFilesTool.Name:
 		owner, _ := args["owner"].(string)
 		repo, _ := args["repo"].(string)
@@ -98,8 +267,22 @@ func Call(input CallToolRequest) (CallToolResult, error) {
 		return filesPush(apiKey, owner, repo, branch, message, files), nil
 
 	case ListReposTool.Name:
-		owner, _ := args["owner"].(string)
-		return reposList(apiKey, owner, args)
+		username, _ := args["username"].(string)
+		return reposList(apiKey, reposListURL(username, args))
+
+	case ListOrgReposTool.Name:
+		org, _ := args["org"].(string)
+		u, err := orgReposURL(org, args)
+		if err != nil {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(err.Error()),
+				}},
+			}, nil
+		}
+		return reposList(apiKey, u)
 
 	case GetRepositoryCollaboratorsTool.Name:
 		owner, _ := args["owner"].(string)
@@ -116,6 +299,503 @@ func Call(input CallToolRequest) (CallToolResult, error) {
 		repo, _ := args["repo"].(string)
 		return reposGetDetails(apiKey, owner, repo)
 
+	case RepoHealthTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return reposHealth(apiKey, owner, repo), nil
+
+	case ListCommitsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return commitsList(apiKey, owner, repo, args)
+
+	case GetCommitTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		sha, _ := args["sha"].(string)
+		includePatch := true
+		if value, ok := args["include_patch"].(bool); ok {
+			includePatch = value
+		}
+		return commitsGet(apiKey, owner, repo, sha, includePatch), nil
+
+	case ListTagsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return tagsList(apiKey, owner, repo, args), nil
+
+	case CreateTagTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		tag, _ := args["tag"].(string)
+		sha, _ := args["sha"].(string)
+		return tagsCreate(apiKey, owner, repo, tag, sha, args), nil
+
+	case SearchIssuesTool.Name:
+		return searchIssues(apiKey, args), nil
+
+	case SearchUsersTool.Name:
+		return searchUsers(apiKey, args), nil
+
+	case SearchCommitsTool.Name:
+		return searchCommits(apiKey, args), nil
+
+	case ListLabelsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return labelsList(apiKey, owner, repo, args), nil
+
+	case CreateLabelTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return labelsWrite(apiKey, owner, repo, "", args), nil
+
+	case UpdateLabelTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		name, _ := args["name"].(string)
+		return labelsWrite(apiKey, owner, repo, name, args), nil
+
+	case DeleteLabelTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		name, _ := args["name"].(string)
+		return labelsDelete(apiKey, owner, repo, name), nil
+
+	case AddIssueLabelsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		issue, _ := args["issue"].(float64)
+		return issueAddLabels(apiKey, owner, repo, int(issue), args), nil
+
+	case RemoveIssueLabelTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		issue, _ := args["issue"].(float64)
+		label, _ := args["label"].(string)
+		return issueRemoveLabel(apiKey, owner, repo, int(issue), label), nil
+
+	case ListMilestonesTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return milestonesList(apiKey, owner, repo, args), nil
+
+	case CreateMilestoneTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return milestonesWrite(apiKey, owner, repo, 0, args), nil
+
+	case UpdateMilestoneTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		number, _ := args["milestone_number"].(float64)
+		return milestonesWrite(apiKey, owner, repo, int(number), args), nil
+
+	case CloseMilestoneTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		number, _ := args["milestone_number"].(float64)
+		return milestonesWrite(apiKey, owner, repo, int(number), map[string]interface{}{"state": "closed"}), nil
+
+	case AddIssueAssigneesTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		issue, _ := args["issue"].(float64)
+		return issueChangeAssignees(apiKey, owner, repo, int(issue), args, false), nil
+
+	case RemoveIssueAssigneesTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		issue, _ := args["issue"].(float64)
+		return issueChangeAssignees(apiKey, owner, repo, int(issue), args, true), nil
+
+	case LockIssueTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		issue, _ := args["issue"].(float64)
+		return issueSetLocked(apiKey, owner, repo, int(issue), args, true), nil
+
+	case UnlockIssueTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		issue, _ := args["issue"].(float64)
+		return issueSetLocked(apiKey, owner, repo, int(issue), args, false), nil
+
+	case AddReactionTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		subjectType, _ := args["subject_type"].(string)
+		subjectID, _ := args["subject_id"].(float64)
+		content, _ := args["content"].(string)
+		return reactionsAdd(apiKey, owner, repo, subjectType, int(subjectID), content), nil
+
+	case ListReactionsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		subjectType, _ := args["subject_type"].(string)
+		subjectID, _ := args["subject_id"].(float64)
+		content, _ := args["content"].(string)
+		return reactionsList(apiKey, owner, repo, subjectType, int(subjectID), content), nil
+
+	case IssueTimelineTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		issue, _ := args["issue"].(float64)
+		return issueTimeline(apiKey, owner, repo, int(issue), args), nil
+
+	case TransferIssueTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		issue, _ := args["issue"].(float64)
+		return issueTransfer(apiKey, owner, repo, int(issue), args), nil
+
+	case ListReleasesTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return releasesList(apiKey, owner, repo, args), nil
+
+	case CreateReleaseTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return releasesCreate(apiKey, owner, repo, args), nil
+
+	case GetLatestReleaseTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return releaseGet(apiKey, owner, repo, ""), nil
+
+	case GetReleaseByTagTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		tag, _ := args["tag"].(string)
+		if tag == "" {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some("tag is required"),
+				}},
+			}, nil
+		}
+		return releaseGet(apiKey, owner, repo, tag), nil
+
+	case UploadReleaseAssetTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return releaseUploadAsset(apiKey, owner, repo, args), nil
+
+	case DownloadReleaseAssetTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return releaseDownloadAsset(apiKey, owner, repo, args), nil
+
+	case GenerateReleaseNotesTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return releasesGenerateNotes(apiKey, owner, repo, args), nil
+
+	case ListWorkflowsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return workflowsList(apiKey, owner, repo, args), nil
+
+	case GetWorkflowTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return workflowsGet(apiKey, owner, repo, args), nil
+
+	case DispatchWorkflowTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return workflowsDispatch(apiKey, owner, repo, args), nil
+
+	case ListWorkflowRunsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return runsList(apiKey, owner, repo, args), nil
+
+	case GetRunLogsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return runsGetLogs(apiKey, owner, repo, args), nil
+
+	case ListRunJobsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		runID, _ := args["run_id"].(float64)
+		return runsListJobs(apiKey, owner, repo, int(runID), args), nil
+
+	case ListArtifactsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return artifactsList(apiKey, owner, repo, args), nil
+
+	case DownloadArtifactTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		artifactID, _ := args["artifact_id"].(float64)
+		return artifactsDownload(apiKey, owner, repo, int(artifactID), args), nil
+
+	case CreateRepoTool.Name:
+		return reposCreate(apiKey, args), nil
+
+	case DeleteRepoTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return reposDelete(apiKey, owner, repo), nil
+
+	case ForkRepoTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return reposFork(apiKey, owner, repo, args), nil
+
+	case GetRepoTopicsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return reposGetTopics(apiKey, owner, repo), nil
+
+	case SetRepoTopicsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return reposSetTopics(apiKey, owner, repo, args), nil
+
+	case StarRepoTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return reposSetStarred(apiKey, owner, repo, true), nil
+
+	case UnstarRepoTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return reposSetStarred(apiKey, owner, repo, false), nil
+
+	case ListStarredTool.Name:
+		return reposListStarred(apiKey, args), nil
+
+	case ListStargazersTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return reposListStargazers(apiKey, owner, repo, args), nil
+
+	case ListWatchersTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return reposListWatchers(apiKey, owner, repo, args), nil
+
+	case AddCollaboratorTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		username, _ := args["username"].(string)
+		return collaboratorsAdd(apiKey, owner, repo, username, args), nil
+
+	case RemoveCollaboratorTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		username, _ := args["username"].(string)
+		return collaboratorsRemove(apiKey, owner, repo, username), nil
+
+	case ListRepoInvitationsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return invitationsList(apiKey, owner, repo, args), nil
+
+	case DeleteRepoInvitationTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		invitationID, _ := args["invitation_id"].(float64)
+		return invitationsRespond(apiKey, owner, repo, int(invitationID)), nil
+
+	case AcceptInvitationTool.Name:
+		invitationID, _ := args["invitation_id"].(float64)
+		return invitationsRespond(apiKey, "", "", int(invitationID)), nil
+
+	case RepoTrafficTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return reposTraffic(apiKey, owner, repo, args), nil
+
+	case RepoLanguagesTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return reposLanguages(apiKey, owner, repo), nil
+
+	case ListRepoEventsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return eventsList(apiKey, repoEventsURL(owner, repo, args)), nil
+
+	case RepoStatsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return reposStats(apiKey, owner, repo), nil
+
+	case GetLicenseTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		includeText, _ := args["include_text"].(bool)
+		return reposGetLicense(apiKey, owner, repo, includeText), nil
+
+	case ListDeployKeysTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return deployKeysList(apiKey, owner, repo, args), nil
+
+	case AddDeployKeyTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return deployKeysAdd(apiKey, owner, repo, args), nil
+
+	case DeleteDeployKeyTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		keyID, _ := args["key_id"].(float64)
+		return deployKeysDelete(apiKey, owner, repo, int(keyID)), nil
+
+	case CompareRefsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		base, _ := args["base"].(string)
+		head, _ := args["head"].(string)
+		return commitsCompare(apiKey, owner, repo, base, head), nil
+
+	case ListCheckRunsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		ref, _ := args["ref"].(string)
+		return checksListRuns(apiKey, owner, repo, ref, args)
+	case ListCheckSuitesTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		ref, _ := args["ref"].(string)
+		return checksListSuites(apiKey, owner, repo, ref, args), nil
+	case CreateCommitStatusTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		sha, _ := args["sha"].(string)
+		return statusesCreate(apiKey, owner, repo, sha, args), nil
+	case GetCombinedStatusTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		ref, _ := args["ref"].(string)
+		return statusesGetCombined(apiKey, owner, repo, ref), nil
+
+	case ListCodeScanningAlertsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return codeScanningList(apiKey, owner, repo, args), nil
+	case GetCodeScanningAlertTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		number, _ := args["alert_number"].(float64)
+		return codeScanningGet(apiKey, owner, repo, int(number)), nil
+
+	case ListDependabotAlertsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return dependabotList(apiKey, owner, repo, args), nil
+	case UpdateDependabotAlertTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		number, _ := args["alert_number"].(float64)
+		return dependabotUpdate(apiKey, owner, repo, int(number), args), nil
+
+	case ListActionsSecretsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return actionsListSecrets(apiKey, owner, repo, args), nil
+	case SetActionsSecretTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		name, _ := args["name"].(string)
+		value, _ := args["value"].(string)
+		return actionsSetSecret(apiKey, owner, repo, name, value), nil
+	case ListActionsVariablesTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return actionsListVariables(apiKey, owner, repo, args), nil
+	case SetActionsVariableTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		name, _ := args["name"].(string)
+		value, _ := args["value"].(string)
+		return actionsSetVariable(apiKey, owner, repo, name, value), nil
+
+	case ListEnvironmentsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return deploymentsListEnvironments(apiKey, owner, repo, args), nil
+	case ListDeploymentsTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return deploymentsList(apiKey, owner, repo, args), nil
+	case ListDeploymentStatusesTool.Name:
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		id, _ := args["deployment_id"].(float64)
+		return deploymentsListStatuses(apiKey, owner, repo, int64(id), args), nil
+
+	case WhoamiTool.Name:
+		return usersWhoami(apiKey), nil
+	case GetUserTool.Name:
+		username, _ := args["username"].(string)
+		return usersGet(apiKey, strings.TrimPrefix(username, "@")), nil
+	case ListFollowersTool.Name:
+		username, _ := args["username"].(string)
+		return usersListFollows(apiKey, strings.TrimPrefix(username, "@"), "followers", args), nil
+	case ListFollowingTool.Name:
+		username, _ := args["username"].(string)
+		return usersListFollows(apiKey, strings.TrimPrefix(username, "@"), "following", args), nil
+	case ListUserEventsTool.Name:
+		username, _ := args["username"].(string)
+		return eventsList(apiKey, userEventsURL(strings.TrimPrefix(username, "@"), args)), nil
+	case CheckFollowingTool.Name:
+		username, _ := args["username"].(string)
+		target, _ := args["target"].(string)
+		return usersCheckFollowing(apiKey, strings.TrimPrefix(username, "@"), strings.TrimPrefix(target, "@")), nil
+
+	case ListUserOrgsTool.Name:
+		username, _ := args["username"].(string)
+		return orgsListForUser(apiKey, strings.TrimPrefix(username, "@"), args), nil
+	case ListOrgMembersTool.Name:
+		org, _ := args["org"].(string)
+		return orgsListMembers(apiKey, org, args), nil
+	case GetOrgMembershipTool.Name:
+		org, _ := args["org"].(string)
+		username, _ := args["username"].(string)
+		return orgsGetMembership(apiKey, org, strings.TrimPrefix(username, "@")), nil
+	case ListOrgTeamsTool.Name:
+		org, _ := args["org"].(string)
+		return orgsListTeams(apiKey, org, args), nil
+
+	case ListTeamMembersTool.Name:
+		org, _ := args["org"].(string)
+		slug, _ := args["team_slug"].(string)
+		return teamsListMembers(apiKey, org, slug, args), nil
+	case ListTeamReposTool.Name:
+		org, _ := args["org"].(string)
+		slug, _ := args["team_slug"].(string)
+		return teamsListRepos(apiKey, org, slug, args), nil
+	case AddTeamRepoTool.Name:
+		org, _ := args["org"].(string)
+		slug, _ := args["team_slug"].(string)
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return teamsSetRepo(apiKey, org, slug, owner, repo, false, args), nil
+	case RemoveTeamRepoTool.Name:
+		org, _ := args["org"].(string)
+		slug, _ := args["team_slug"].(string)
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		return teamsSetRepo(apiKey, org, slug, owner, repo, true, args), nil
+
+	case ListNotificationsTool.Name:
+		return notificationsList(apiKey, args), nil
+	case MarkNotificationReadTool.Name:
+		threadID, _ := args["thread_id"].(float64)
+		return notificationsMarkRead(apiKey, int64(threadID)), nil
+	case MarkAllReadTool.Name:
+		return notificationsMarkAllRead(apiKey, args), nil
+
 	case CreateGistTool.Name:
 		description, _ := args["description"].(string)
 		files, _ := args["files"].(map[string]any)
@@ -127,10 +807,60 @@ func Call(input CallToolRequest) (CallToolResult, error) {
 
 	case UpdateGistTool.Name:
 		gistId, _ := args["gist_id"].(string)
-		description, _ := args["description"].(string)
+		var description *string
+		if d, ok := args["description"].(string); ok {
+			description = &d
+		}
 		files, _ := args["files"].(map[string]any)
 		return gistUpdate(apiKey, gistId, description, files), nil
 
+	case ListGistsTool.Name:
+		username, _ := args["username"].(string)
+		return gistsList(apiKey, userGistsURL(strings.TrimPrefix(username, "@")), args), nil
+
+	case ListStarredGistsTool.Name:
+		return gistsList(apiKey, "https://api.github.com/gists/starred", args), nil
+
+	case ForkGistTool.Name:
+		gistId, _ := args["gist_id"].(string)
+		return gistFork(apiKey, gistId), nil
+
+	case StarGistTool.Name:
+		gistId, _ := args["gist_id"].(string)
+		return gistsSetStar(apiKey, gistId, true), nil
+
+	case UnstarGistTool.Name:
+		gistId, _ := args["gist_id"].(string)
+		return gistsSetStar(apiKey, gistId, false), nil
+
+	case IsGistStarredTool.Name:
+		gistId, _ := args["gist_id"].(string)
+		return gistsIsStarred(apiKey, gistId), nil
+
+	case ListGistCommitsTool.Name:
+		gistId, _ := args["gist_id"].(string)
+		return gistCommitsList(apiKey, gistId, args), nil
+
+	case GetGistRevisionTool.Name:
+		gistId, _ := args["gist_id"].(string)
+		sha, _ := args["sha"].(string)
+		filename, _ := args["filename"].(string)
+		return gistGetRevision(apiKey, gistId, sha, filename), nil
+
+	case ListGistCommentsTool.Name:
+		gistId, _ := args["gist_id"].(string)
+		return gistCommentsList(apiKey, gistId, args), nil
+
+	case AddGistCommentTool.Name:
+		gistId, _ := args["gist_id"].(string)
+		body, _ := args["body"].(string)
+		return gistCommentsAdd(apiKey, gistId, body), nil
+
+	case DeleteGistCommentTool.Name:
+		gistId, _ := args["gist_id"].(string)
+		commentId, _ := args["comment_id"].(float64)
+		return gistCommentsDelete(apiKey, gistId, int64(commentId)), nil
+
 	case DeleteGistTool.Name:
 		gistId, _ := args["gist_id"].(string)
 		return gistDelete(apiKey, gistId), nil
@@ -154,12 +884,32 @@ func Describe() (ListToolsResult, error) {
 		BranchTools,
 		RepoTools,
 		GistTools,
+		CheckTools,
+		CommitTools,
+		TagTools,
+		SearchTools,
+		LabelTools,
+		MilestoneTools,
+		ReactionTools,
+		ReleaseTools,
+		ActionsTools,
+		DeployKeyTools,
+		SecurityTools,
+		DeploymentTools,
+		UserTools,
+		OrgTools,
+		NotificationTools,
 	}
 
 	tools := []ToolDescription{}
 
 	for _, toolset := range toolsets {
-		tools = append(tools, toolset...)
+		for _, tool := range toolset {
+			if _, ok := destructiveTools[tool.Name]; ok {
+				tool = withConfirmArg(tool)
+			}
+			tools = append(tools, tool)
+		}
 	}
 
 	// Ensure each tool's InputSchema has a required field
@@ -184,11 +934,40 @@ func some[T any](t T) *T {
 	return &t
 }
 
+// paginationParams builds the per_page and page query parameters shared by the
+// list tools. per_page defaults to 30 and is capped at 100, page defaults to 1.
+func paginationParams(args map[string]interface{}) []string {
+	perPage := 30 // Default value
+	if value, ok := args["per_page"].(float64); ok {
+		if value > 100 {
+			perPage = 100 // Max value
+		} else if value > 0 {
+			perPage = int(value)
+		}
+	}
+
+	page := 1 // Default value
+	if value, ok := args["page"].(float64); ok && value > 0 {
+		page = int(value)
+	}
+
+	return []string{fmt.Sprintf("per_page=%d", perPage), fmt.Sprintf("page=%d", page)}
+}
+
+// firstLine returns the subject line of a commit message.
+func firstLine(message string) string {
+	if i := strings.IndexByte(message, '\n'); i >= 0 {
+		return strings.TrimRight(message[:i], "\r")
+	}
+	return message
+}
+
 type SchemaProperty struct {
-	Type                 string  `json:"type"`
-	Description          string  `json:"description,omitempty"`
-	AdditionalProperties *schema `json:"additionalProperties,omitempty"`
-	Items                *schema `json:"items,omitempty"`
+	// A type name, or a []string of them for arguments that take either
+	Type                 interface{} `json:"type"`
+	Description          string      `json:"description,omitempty"`
+	AdditionalProperties *schema     `json:"additionalProperties,omitempty"`
+	Items                *schema     `json:"items,omitempty"`
 }
 
 func prop(tpe, description string) SchemaProperty {
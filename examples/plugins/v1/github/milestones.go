@@ -0,0 +1,322 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ListMilestonesTool = ToolDescription{
+		Name:        "gh-list-milestones",
+		Description: "List the milestones of a repository with their number, state, due date and issue counts",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":     prop("string", "The owner of the repository"),
+				"repo":      prop("string", "The repository name"),
+				"state":     prop("string", "open, closed or all (default open)"),
+				"sort":      prop("string", "due_on or completeness (default due_on)"),
+				"direction": prop("string", "asc or desc (default asc)"),
+				"per_page":  prop("integer", "Number of results per page (max 100)"),
+				"page":      prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	CreateMilestoneTool = ToolDescription{
+		Name:        "gh-create-milestone",
+		Description: "Create a milestone in a repository",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":       prop("string", "The owner of the repository"),
+				"repo":        prop("string", "The repository name"),
+				"title":       prop("string", "The milestone title"),
+				"description": prop("string", "The milestone description"),
+				"due_on":      prop("string", "Due date as an ISO-8601 date or timestamp, e.g. 2024-06-30 or 2024-06-30T17:00:00Z"),
+				"state":       prop("string", "open or closed (default open)"),
+			},
+			"required": []string{"owner", "repo", "title"},
+		},
+	}
+	UpdateMilestoneTool = ToolDescription{
+		Name:        "gh-update-milestone",
+		Description: "Change a milestone's title, description, due date or state",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":            prop("string", "The owner of the repository"),
+				"repo":             prop("string", "The repository name"),
+				"milestone_number": prop("integer", "The milestone number"),
+				"title":            prop("string", "The new title"),
+				"description":      prop("string", "The new description"),
+				"due_on":           prop("string", "The new due date as an ISO-8601 date or timestamp; an empty string removes it"),
+				"state":            prop("string", "open or closed"),
+			},
+			"required": []string{"owner", "repo", "milestone_number"},
+		},
+	}
+	CloseMilestoneTool = ToolDescription{
+		Name:        "gh-close-milestone",
+		Description: "Close a milestone. Its issues are left as they are.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":            prop("string", "The owner of the repository"),
+				"repo":             prop("string", "The repository name"),
+				"milestone_number": prop("integer", "The milestone number"),
+			},
+			"required": []string{"owner", "repo", "milestone_number"},
+		},
+	}
+	MilestoneTools = []ToolDescription{
+		ListMilestonesTool,
+		CreateMilestoneTool,
+		UpdateMilestoneTool,
+		CloseMilestoneTool,
+	}
+)
+
+type Milestone struct {
+	Number       int     `json:"number"`
+	Title        string  `json:"title"`
+	Description  *string `json:"description"`
+	State        string  `json:"state"`
+	DueOn        *string `json:"due_on"`
+	OpenIssues   int     `json:"open_issues"`
+	ClosedIssues int     `json:"closed_issues"`
+	HTMLURL      string  `json:"html_url"`
+}
+
+// milestoneDueOn accepts an ISO-8601 date or RFC 3339 timestamp and
+// returns the timestamp GitHub expects. GitHub only keeps the date.
+func milestoneDueOn(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC().Format(time.RFC3339), nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t.Format(time.RFC3339), nil
+	}
+	return "", fmt.Errorf("due_on must be an ISO-8601 date like 2024-06-30 or a timestamp like 2024-06-30T17:00:00Z, got %q", value)
+}
+
+// milestoneBody builds the POST or PATCH body from the given arguments.
+// On update an empty due_on is sent as null to remove the due date.
+func milestoneBody(args map[string]interface{}, update bool) (map[string]interface{}, error) {
+	body := map[string]interface{}{}
+	title, _ := args["title"].(string)
+	if strings.TrimSpace(title) != "" {
+		body["title"] = title
+	} else if !update {
+		return nil, fmt.Errorf("title is required")
+	}
+	if description, ok := args["description"].(string); ok {
+		body["description"] = description
+	}
+	if dueOn, ok := args["due_on"].(string); ok {
+		if update && strings.TrimSpace(dueOn) == "" {
+			body["due_on"] = nil
+		} else {
+			value, err := milestoneDueOn(dueOn)
+			if err != nil {
+				return nil, err
+			}
+			body["due_on"] = value
+		}
+	}
+	if state, _ := args["state"].(string); state != "" {
+		if state != "open" && state != "closed" {
+			return nil, fmt.Errorf("state must be open or closed, got %q", state)
+		}
+		body["state"] = state
+	}
+	if update && len(body) == 0 {
+		return nil, fmt.Errorf("give at least one of title, description, due_on or state")
+	}
+	return body, nil
+}
+
+// milestoneByTitle finds the milestone with the title, ignoring case.
+func milestoneByTitle(milestones []Milestone, title string) (Milestone, error) {
+	titles := []string{}
+	for _, m := range milestones {
+		if strings.EqualFold(strings.TrimSpace(m.Title), strings.TrimSpace(title)) {
+			return m, nil
+		}
+		titles = append(titles, fmt.Sprintf("%q", m.Title))
+	}
+	if len(titles) == 0 {
+		return Milestone{}, fmt.Errorf("no milestone titled %q: the repository has no milestones", title)
+	}
+	return Milestone{}, fmt.Errorf("no milestone titled %q; existing milestones are %s", title, strings.Join(titles, ", "))
+}
+
+// issueResolveMilestone looks up the number of the milestone the issue
+// names by title, since callers rarely know milestone numbers.
+func issueResolveMilestone(apiKey, owner, repo string, data *Issue) *CallToolResult {
+	if data.MilestoneTitle == "" {
+		return nil
+	}
+	failed := func(message string) *CallToolResult {
+		return &CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(message),
+			}},
+		}
+	}
+
+	milestones := []Milestone{}
+	for page := 1; page <= 10; page++ {
+		u := fmt.Sprintf("https://api.github.com/repos/%s/%s/milestones?state=all&per_page=100&page=%d", owner, repo, page)
+		pdk.Log(pdk.LogDebug, fmt.Sprint("Looking up milestone: ", u))
+		var batch []Milestone
+		if _, err := githubGetJSON(apiKey, u, &batch); err != nil {
+			return failed(fmt.Sprintf("Failed to list milestones: %s", err))
+		}
+		milestones = append(milestones, batch...)
+		if len(batch) < 100 {
+			break
+		}
+	}
+
+	milestone, err := milestoneByTitle(milestones, data.MilestoneTitle)
+	if err != nil {
+		return failed(err.Error())
+	}
+	data.Milestone = milestone.Number
+	return nil
+}
+
+// milestonesListQuery builds the list query, checking state, sort and
+// direction against the values GitHub accepts.
+func milestonesListQuery(args map[string]interface{}) (string, error) {
+	params := url.Values{}
+	for _, p := range paginationParams(args) {
+		key, value, _ := strings.Cut(p, "=")
+		params.Set(key, value)
+	}
+	allowed := []struct {
+		key    string
+		values []string
+	}{
+		{"state", []string{"open", "closed", "all"}},
+		{"sort", []string{"due_on", "completeness"}},
+		{"direction", []string{"asc", "desc"}},
+	}
+	for _, a := range allowed {
+		value, _ := args[a.key].(string)
+		if value == "" {
+			continue
+		}
+		valid := false
+		for _, v := range a.values {
+			valid = valid || value == v
+		}
+		if !valid {
+			return "", fmt.Errorf("%s must be one of %s, got %q", a.key, strings.Join(a.values, ", "), value)
+		}
+		params.Set(a.key, value)
+	}
+	return params.Encode(), nil
+}
+
+func milestonesList(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	query, err := milestonesListQuery(args)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid milestone filter: %s", err)),
+			}},
+		}
+	}
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/milestones?%s", owner, repo, query)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing milestones: ", u))
+
+	milestones := []Milestone{}
+	if _, err := githubGetJSON(apiKey, u, &milestones); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list milestones: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(milestones)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+// milestonesWrite creates a milestone, or updates milestone number when it
+// isn't 0.
+func milestonesWrite(apiKey, owner, repo string, number int, args map[string]interface{}) CallToolResult {
+	method, u, want, action := pdk.MethodPost, fmt.Sprintf("https://api.github.com/repos/%s/%s/milestones", owner, repo), uint16(201), "create"
+	if number != 0 {
+		method, u, want, action = pdk.MethodPatch, fmt.Sprintf("%s/%d", u, number), 200, "update"
+	}
+	body, err := milestoneBody(args, number != 0)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid milestone: %s", err)),
+			}},
+		}
+	}
+
+	pdk.Log(pdk.LogDebug, fmt.Sprintf("Milestone %s: %s", action, u))
+	var milestone Milestone
+	if err := githubSendJSON(apiKey, method, u, body, want, &milestone); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to %s milestone: %s", action, err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(milestone)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMilestoneDueOn(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "2024-06-30", want: "2024-06-30T00:00:00Z"},
+		{value: "2024-06-30T17:00:00Z", want: "2024-06-30T17:00:00Z"},
+		{value: "2024-06-30T17:00:00+02:00", want: "2024-06-30T15:00:00Z"},
+		{value: "30/06/2024", wantErr: true},
+		{value: "2024-02-30", wantErr: true},
+		{value: "next friday", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := milestoneDueOn(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%q: got %q, %v, want %q", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestMilestoneBody(t *testing.T) {
+	tests := []struct {
+		args    string
+		update  bool
+		want    string
+		wantErr string
+	}{
+		{args: `{"title":"v1.0","due_on":"2024-06-30"}`, want: `{"due_on":"2024-06-30T00:00:00Z","title":"v1.0"}`},
+		{args: `{"title":"v1.0","description":"First release","state":"closed"}`, want: `{"description":"First release","state":"closed","title":"v1.0"}`},
+		{args: `{"description":"x"}`, wantErr: "title is required"},
+		{args: `{"title":"v1.0","state":"done"}`, wantErr: "state must be open or closed"},
+		{args: `{"title":"v1.0","due_on":"June"}`, wantErr: "due_on must be an ISO-8601 date"},
+		{args: `{"due_on":""}`, update: true, want: `{"due_on":null}`},
+		{args: `{"state":"closed"}`, update: true, want: `{"state":"closed"}`},
+		{args: `{}`, update: true, wantErr: "give at least one of"},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		body, err := milestoneBody(args, tt.update)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error %v, want one containing %q", tt.args, err, tt.wantErr)
+			}
+			continue
+		}
+		out, _ := json.Marshal(body)
+		if err != nil || string(out) != tt.want {
+			t.Errorf("%s: got %s, %v, want %s", tt.args, out, err, tt.want)
+		}
+	}
+}
+
+func TestMilestoneByTitle(t *testing.T) {
+	milestones := []Milestone{{Number: 3, Title: "v1.0"}, {Number: 7, Title: "Q3 Planning"}}
+
+	if m, err := milestoneByTitle(milestones, " q3 planning"); err != nil || m.Number != 7 {
+		t.Errorf("got %+v, %v", m, err)
+	}
+	if _, err := milestoneByTitle(milestones, "v2.0"); err == nil || !strings.Contains(err.Error(), `existing milestones are "v1.0", "Q3 Planning"`) {
+		t.Errorf("error %v", err)
+	}
+	if _, err := milestoneByTitle(nil, "v1.0"); err == nil || !strings.Contains(err.Error(), "has no milestones") {
+		t.Errorf("error %v", err)
+	}
+}
+
+func TestMilestonesListQuery(t *testing.T) {
+	tests := []struct {
+		args    string
+		want    string
+		wantErr string
+	}{
+		{args: `{}`, want: "page=1&per_page=30"},
+		{args: `{"state":"all","sort":"completeness","direction":"desc","per_page":5}`, want: "direction=desc&page=1&per_page=5&sort=completeness&state=all"},
+		{args: `{"state":"open&sort=x"}`, wantErr: "state must be one of open, closed, all"},
+		{args: `{"sort":"title"}`, wantErr: "sort must be one of due_on, completeness"},
+		{args: `{"direction":"up"}`, wantErr: "direction must be one of asc, desc"},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		got, err := milestonesListQuery(args)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error %v, want one containing %q", tt.args, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %q, %v, want %q", tt.args, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ListNotificationsTool = ToolDescription{
+		Name:        "gh-list-notifications",
+		Description: "List the authenticated user's notifications, newest first, with the thread id needed to mark them read",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"all":           prop("boolean", "Include notifications already marked read (default false)"),
+				"participating": prop("boolean", "Only notifications where the user is directly participating or mentioned (default false)"),
+				"since":         prop("string", "Only notifications updated after this time (ISO 8601 timestamp YYYY-MM-DDTHH:MM:SSZ)"),
+				"owner":         prop("string", "With repo, only notifications from this repository"),
+				"repo":          prop("string", "With owner, only notifications from this repository"),
+				"per_page":      prop("integer", "Number of results per page (max 50)"),
+				"page":          prop("integer", "Page number for pagination"),
+			},
+		},
+	}
+	MarkNotificationReadTool = ToolDescription{
+		Name:        "gh-mark-notification-read",
+		Description: "Mark a notification thread as read",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"thread_id": prop("integer", "The thread id from gh-list-notifications"),
+			},
+			"required": []string{"thread_id"},
+		},
+	}
+	MarkAllReadTool = ToolDescription{
+		Name:        "gh-mark-all-read",
+		Description: "Mark all notifications, or those of one repository, as read",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"last_read_at": prop("string", "Only mark notifications updated before this time (ISO 8601 timestamp YYYY-MM-DDTHH:MM:SSZ, default now)"),
+				"owner":        prop("string", "With repo, only mark notifications from this repository"),
+				"repo":         prop("string", "With owner, only mark notifications from this repository"),
+			},
+		},
+	}
+	NotificationTools = []ToolDescription{
+		ListNotificationsTool,
+		MarkNotificationReadTool,
+		MarkAllReadTool,
+	}
+)
+
+type Notification struct {
+	ID      string `json:"id"`
+	Reason  string `json:"reason"`
+	Unread  bool   `json:"unread"`
+	Subject struct {
+		Title string `json:"title"`
+		Type  string `json:"type"`
+		URL   string `json:"url"`
+	} `json:"subject"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type NotificationSummary struct {
+	ThreadID   string `json:"thread_id"`
+	Reason     string `json:"reason"`
+	Unread     bool   `json:"unread"`
+	Title      string `json:"title"`
+	Type       string `json:"type"`
+	Repository string `json:"repository"`
+	// Issue or pull request number, when the subject has one
+	Number    string `json:"number,omitempty"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+func summarizeNotifications(notifications []Notification) []NotificationSummary {
+	summaries := []NotificationSummary{}
+	for _, n := range notifications {
+		summary := NotificationSummary{
+			ThreadID:   n.ID,
+			Reason:     n.Reason,
+			Unread:     n.Unread,
+			Title:      n.Subject.Title,
+			Type:       n.Subject.Type,
+			Repository: n.Repository.FullName,
+			UpdatedAt:  n.UpdatedAt,
+		}
+		if n.Subject.Type == "Issue" || n.Subject.Type == "PullRequest" {
+			summary.Number = n.Subject.URL[strings.LastIndex(n.Subject.URL, "/")+1:]
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// notificationsBase is the user's notifications, or one repository's when
+// both owner and repo are given.
+func notificationsBase(args map[string]interface{}) (string, error) {
+	owner, _ := args["owner"].(string)
+	repo, _ := args["repo"].(string)
+	switch {
+	case owner != "" && repo != "":
+		return fmt.Sprintf("https://api.github.com/repos/%s/%s/notifications", owner, repo), nil
+	case owner != "" || repo != "":
+		return "", fmt.Errorf("give both owner and repo, or neither")
+	}
+	return "https://api.github.com/notifications", nil
+}
+
+func notificationsURL(args map[string]interface{}) (string, error) {
+	base, err := notificationsBase(args)
+	if err != nil {
+		return "", err
+	}
+	params := paginationParams(args)
+	for _, key := range []string{"all", "participating"} {
+		if value, _ := args[key].(bool); value {
+			params = append(params, key+"=true")
+		}
+	}
+	if since, _ := args["since"].(string); since != "" {
+		if _, err := time.Parse(time.RFC3339, since); err != nil {
+			return "", fmt.Errorf("since must be an ISO 8601 timestamp like 2024-06-30T17:00:00Z, got %q", since)
+		}
+		params = append(params, "since="+since)
+	}
+	return fmt.Sprintf("%s?%s", base, strings.Join(params, "&")), nil
+}
+
+// markAllReadMessage turns the status into a confirmation. GitHub answers
+// 205 when done, or 202 when there are too many to mark read at once and
+// it finishes in the background.
+func markAllReadMessage(status uint16, body []byte, scope, lastReadAt string) (string, error) {
+	switch status {
+	case 205:
+		return fmt.Sprintf("Marked %s notifications updated before %s as read", scope, lastReadAt), nil
+	case 202:
+		return fmt.Sprintf("GitHub is marking %s notifications updated before %s as read in the background; they may show as unread for a few minutes", scope, lastReadAt), nil
+	}
+	return "", githubStatusError(status, body)
+}
+
+func notificationsList(apiKey string, args map[string]interface{}) CallToolResult {
+	u, err := notificationsURL(args)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid notification listing: %s", err)),
+			}},
+		}
+	}
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing notifications: ", u))
+
+	notifications := []Notification{}
+	if _, err := githubGetJSON(apiKey, u, &notifications); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list notifications: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(summarizeNotifications(notifications))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func notificationsMarkRead(apiKey string, threadID int64) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/notifications/threads/%d", threadID)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Marking notification read: ", u))
+
+	_, err := githubSendExpect(apiKey, pdk.MethodPatch, u, nil, 205)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to mark thread %d as read: %s", threadID, err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(fmt.Sprintf("Marked notification thread %d as read", threadID)),
+		}},
+	}
+}
+
+func notificationsMarkAllRead(apiKey string, args map[string]interface{}) CallToolResult {
+	failed := func(message string) CallToolResult {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(message),
+			}},
+		}
+	}
+	u, err := notificationsBase(args)
+	if err != nil {
+		return failed(fmt.Sprintf("Invalid request: %s", err))
+	}
+	lastReadAt, _ := args["last_read_at"].(string)
+	if lastReadAt == "" {
+		lastReadAt = time.Now().UTC().Format(time.RFC3339)
+	} else if _, err := time.Parse(time.RFC3339, lastReadAt); err != nil {
+		return failed(fmt.Sprintf("last_read_at must be an ISO 8601 timestamp like 2024-06-30T17:00:00Z, got %q", lastReadAt))
+	}
+	scope := "all"
+	if owner, _ := args["owner"].(string); owner != "" {
+		repo, _ := args["repo"].(string)
+		scope = fmt.Sprintf("%s/%s", owner, repo)
+	}
+
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Marking notifications read: ", u))
+	status, body, err := githubSend(apiKey, pdk.MethodPut, u, map[string]interface{}{"last_read_at": lastReadAt, "read": true})
+	if err != nil {
+		return failed(fmt.Sprintf("Failed to mark notifications as read: %s", err))
+	}
+	message, err := markAllReadMessage(status, body, scope, lastReadAt)
+	if err != nil {
+		return failed(fmt.Sprintf("Failed to mark notifications as read: %s", err))
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(message),
+		}},
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSummarizeNotifications(t *testing.T) {
+	var notifications []Notification
+	if err := json.Unmarshal([]byte(`[
+		{"id":"1","reason":"review_requested","unread":true,"updated_at":"2024-05-01T10:00:00Z",
+		 "subject":{"title":"Add retries","type":"PullRequest","url":"https://api.github.com/repos/o/r/pulls/42"},
+		 "repository":{"full_name":"o/r"}},
+		{"id":"2","reason":"ci_activity","unread":false,"updated_at":"2024-05-01T09:00:00Z",
+		 "subject":{"title":"CI workflow run failed","type":"CheckSuite","url":null},
+		 "repository":{"full_name":"o/r"}}
+	]`), &notifications); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(summarizeNotifications(notifications))
+	want := `[{"thread_id":"1","reason":"review_requested","unread":true,"title":"Add retries","type":"PullRequest","repository":"o/r","number":"42","updated_at":"2024-05-01T10:00:00Z"},` +
+		`{"thread_id":"2","reason":"ci_activity","unread":false,"title":"CI workflow run failed","type":"CheckSuite","repository":"o/r","updated_at":"2024-05-01T09:00:00Z"}]`
+	if string(got) != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestNotificationsURL(t *testing.T) {
+	got, err := notificationsURL(map[string]interface{}{"all": true, "since": "2024-05-01T00:00:00Z"})
+	if want := "https://api.github.com/notifications?per_page=30&page=1&all=true&since=2024-05-01T00:00:00Z"; err != nil || got != want {
+		t.Errorf("got %s, %v", got, err)
+	}
+	got, err = notificationsURL(map[string]interface{}{"owner": "o", "repo": "r", "participating": true, "all": false})
+	if want := "https://api.github.com/repos/o/r/notifications?per_page=30&page=1&participating=true"; err != nil || got != want {
+		t.Errorf("got %s, %v", got, err)
+	}
+	for _, args := range []map[string]interface{}{{"owner": "o"}, {"since": "yesterday"}} {
+		if _, err := notificationsURL(args); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
+
+func TestMarkAllReadMessage(t *testing.T) {
+	if got, err := markAllReadMessage(205, nil, "all", "2024-05-01T00:00:00Z"); err != nil || !strings.HasPrefix(got, "Marked all notifications") {
+		t.Errorf("205: got %q, %v", got, err)
+	}
+	if got, err := markAllReadMessage(202, []byte(`{"message":"Unread notifications couldn't be marked in a single request."}`), "o/r", "2024-05-01T00:00:00Z"); err != nil || !strings.Contains(got, "in the background") {
+		t.Errorf("202: got %q, %v", got, err)
+	}
+	if _, err := markAllReadMessage(403, []byte(`{"message":"Forbidden"}`), "all", ""); err == nil || err.Error() != "403 Forbidden" {
+		t.Errorf("403: got %v", err)
+	}
+}
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ListOrgMembersTool = ToolDescription{
+		Name:        "gh-list-org-members",
+		Description: "List the members of an organization with their role (admin or member)",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"org":      prop("string", "The organization name"),
+				"role":     prop("string", "all, admin or member (default all)"),
+				"filter":   prop("string", "all, or 2fa_disabled for members without two-factor authentication; needs an organization owner's token (default all)"),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"org"},
+		},
+	}
+	GetOrgMembershipTool = ToolDescription{
+		Name:        "gh-get-org-membership",
+		Description: "Get a user's membership of an organization: whether it is active or pending, and their role",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"org":      prop("string", "The organization name"),
+				"username": prop("string", "The user's login"),
+			},
+			"required": []string{"org", "username"},
+		},
+	}
+	ListOrgTeamsTool = ToolDescription{
+		Name:        "gh-list-org-teams",
+		Description: "List the teams of an organization that the token can see",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"org":      prop("string", "The organization name"),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"org"},
+		},
+	}
+	ListUserOrgsTool = ToolDescription{
+		Name:        "gh-list-user-orgs",
+		Description: "List the organizations a user belongs to. Without a username, lists the authenticated user's organizations, including private memberships.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"username": prop("string", "The user's login (default: the authenticated user)"),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+		},
+	}
+	OrgTools = []ToolDescription{
+		ListUserOrgsTool,
+		ListOrgMembersTool,
+		GetOrgMembershipTool,
+		ListOrgTeamsTool,
+		ListTeamMembersTool,
+		ListTeamReposTool,
+		AddTeamRepoTool,
+		RemoveTeamRepoTool,
+	}
+)
+
+// Pages of admins fetched to label members when listing every role
+const orgAdminPages = 10
+
+type Organization struct {
+	Login       string  `json:"login"`
+	Description *string `json:"description"`
+	AvatarURL   string  `json:"avatar_url"`
+}
+
+type OrgMember struct {
+	Login string `json:"login"`
+	Role  string `json:"role"`
+}
+
+type OrgMembers struct {
+	Org               string      `json:"org"`
+	Role              string      `json:"role"`
+	TwoFactorDisabled bool        `json:"two_factor_disabled_only"`
+	Members           []OrgMember `json:"members"`
+}
+
+type OrgMembership struct {
+	State string `json:"state"`
+	Role  string `json:"role"`
+	User  struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+type Team struct {
+	Slug        string  `json:"slug"`
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	Privacy     string  `json:"privacy"`
+	Permission  string  `json:"permission"`
+	Parent      *struct {
+		Slug string `json:"slug"`
+	} `json:"parent"`
+}
+
+type TeamSummary struct {
+	Slug        string  `json:"slug"`
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	Privacy     string  `json:"privacy"`
+	Parent      string  `json:"parent,omitempty"`
+}
+
+// userOrgsURL lists the user's public memberships, or every membership of
+// the authenticated user when username is empty.
+func userOrgsURL(username string, args map[string]interface{}) string {
+	base := "https://api.github.com/user/orgs"
+	if username != "" {
+		base = fmt.Sprintf("https://api.github.com/users/%s/orgs", url.PathEscape(username))
+	}
+	return fmt.Sprintf("%s?%s", base, strings.Join(paginationParams(args), "&"))
+}
+
+// orgMembersParams checks role and filter, returning them with defaults.
+func orgMembersParams(args map[string]interface{}) (string, string, error) {
+	role, _ := args["role"].(string)
+	if role == "" {
+		role = "all"
+	}
+	if role != "all" && role != "admin" && role != "member" {
+		return "", "", fmt.Errorf("role must be all, admin or member, got %q", role)
+	}
+	filter, _ := args["filter"].(string)
+	if filter == "" {
+		filter = "all"
+	}
+	if filter != "all" && filter != "2fa_disabled" {
+		return "", "", fmt.Errorf("filter must be all or 2fa_disabled, got %q", filter)
+	}
+	return role, filter, nil
+}
+
+// orgMemberRoles labels each login with its role. The members API doesn't
+// return roles, so when listing all roles, admins holds the logins listed
+// with role=admin.
+func orgMemberRoles(logins []string, role string, admins map[string]bool) []OrgMember {
+	members := []OrgMember{}
+	for _, login := range logins {
+		memberRole := role
+		if role == "all" {
+			memberRole = "member"
+			if admins[login] {
+				memberRole = "admin"
+			}
+		}
+		members = append(members, OrgMember{Login: login, Role: memberRole})
+	}
+	return members
+}
+
+func summarizeTeams(teams []Team) []TeamSummary {
+	summaries := []TeamSummary{}
+	for _, t := range teams {
+		summary := TeamSummary{Slug: t.Slug, Name: t.Name, Description: t.Description, Privacy: t.Privacy}
+		if t.Parent != nil {
+			summary.Parent = t.Parent.Slug
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+func orgMemberLogins(apiKey, u string) ([]string, error) {
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing organization members: ", u))
+	var users []struct {
+		Login string `json:"login"`
+	}
+	if _, err := githubGetJSON(apiKey, u, &users); err != nil {
+		return nil, err
+	}
+	logins := []string{}
+	for _, user := range users {
+		logins = append(logins, user.Login)
+	}
+	return logins, nil
+}
+
+func orgsListForUser(apiKey, username string, args map[string]interface{}) CallToolResult {
+	u := userOrgsURL(username, args)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing organizations: ", u))
+
+	orgs := []Organization{}
+	if _, err := githubGetJSON(apiKey, u, &orgs); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list organizations: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(orgs)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func orgsListMembers(apiKey, org string, args map[string]interface{}) CallToolResult {
+	role, filter, err := orgMembersParams(args)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid member listing: %s", err)),
+			}},
+		}
+	}
+
+	base := fmt.Sprintf("https://api.github.com/orgs/%s/members", org)
+	params := append(paginationParams(args), "role="+role, "filter="+filter)
+	logins, err := orgMemberLogins(apiKey, fmt.Sprintf("%s?%s", base, strings.Join(params, "&")))
+	admins := map[string]bool{}
+	for page := 1; err == nil && role == "all" && page <= orgAdminPages; page++ {
+		var batch []string
+		batch, err = orgMemberLogins(apiKey, fmt.Sprintf("%s?role=admin&filter=%s&per_page=100&page=%d", base, filter, page))
+		for _, login := range batch {
+			admins[login] = true
+		}
+		if len(batch) < 100 {
+			break
+		}
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list members of %s: %s", org, err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(OrgMembers{
+		Org:               org,
+		Role:              role,
+		TwoFactorDisabled: filter == "2fa_disabled",
+		Members:           orgMemberRoles(logins, role, admins),
+	})
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func orgsGetMembership(apiKey, org, username string) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/orgs/%s/memberships/%s", org, username)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting organization membership: ", u))
+
+	var membership OrgMembership
+	status, err := githubGetJSON(apiKey, u, &membership)
+	if status == 404 {
+		return CallToolResult{
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("%s is not a member of %s, or the membership isn't visible to this token", username, org)),
+			}},
+		}
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to get membership: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(map[string]string{
+		"org":   org,
+		"login": membership.User.Login,
+		"state": membership.State,
+		"role":  membership.Role,
+	})
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func orgsListTeams(apiKey, org string, args map[string]interface{}) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/orgs/%s/teams?%s", org, strings.Join(paginationParams(args), "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing teams: ", u))
+
+	teams := []Team{}
+	if _, err := githubGetJSON(apiKey, u, &teams); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list teams: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(summarizeTeams(teams))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUserOrgsURL(t *testing.T) {
+	if got := userOrgsURL("", map[string]interface{}{}); got != "https://api.github.com/user/orgs?per_page=30&page=1" {
+		t.Errorf("authenticated user: got %s", got)
+	}
+	if got := userOrgsURL("octocat", map[string]interface{}{"per_page": float64(100), "page": float64(2)}); got != "https://api.github.com/users/octocat/orgs?per_page=100&page=2" {
+		t.Errorf("username: got %s", got)
+	}
+}
+
+func TestOrgMembersParams(t *testing.T) {
+	role, filter, err := orgMembersParams(map[string]interface{}{})
+	if err != nil || role != "all" || filter != "all" {
+		t.Errorf("defaults: got %q %q %v", role, filter, err)
+	}
+	role, filter, err = orgMembersParams(map[string]interface{}{"role": "admin", "filter": "2fa_disabled"})
+	if err != nil || role != "admin" || filter != "2fa_disabled" {
+		t.Errorf("got %q %q %v", role, filter, err)
+	}
+	for _, args := range []map[string]interface{}{{"role": "owner"}, {"filter": "2fa_insecure"}} {
+		if _, _, err := orgMembersParams(args); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
+
+func TestOrgMemberRoles(t *testing.T) {
+	logins := []string{"alice", "bob"}
+	got, _ := json.Marshal(orgMemberRoles(logins, "all", map[string]bool{"bob": true}))
+	if want := `[{"login":"alice","role":"member"},{"login":"bob","role":"admin"}]`; string(got) != want {
+		t.Errorf("all:\n got %s\nwant %s", got, want)
+	}
+	got, _ = json.Marshal(orgMemberRoles(logins, "admin", nil))
+	if want := `[{"login":"alice","role":"admin"},{"login":"bob","role":"admin"}]`; string(got) != want {
+		t.Errorf("admin:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestSummarizeTeams(t *testing.T) {
+	var teams []Team
+	if err := json.Unmarshal([]byte(`[
+		{"slug":"platform","name":"Platform","description":"Infra","privacy":"closed","permission":"pull","parent":null},
+		{"slug":"sre","name":"SRE","description":null,"privacy":"secret","permission":"pull","parent":{"slug":"platform"}}
+	]`), &teams); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(summarizeTeams(teams))
+	want := `[{"slug":"platform","name":"Platform","description":"Infra","privacy":"closed"},{"slug":"sre","name":"SRE","description":null,"privacy":"secret","parent":"platform"}]`
+	if string(got) != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+}
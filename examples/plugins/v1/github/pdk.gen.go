@@ -90,17 +90,22 @@ type CallToolResult struct {
 	//
 	// If not set, this is assumed to be false (the call was successful).
 	IsError *bool `json:"isError,omitempty"`
+	// Machine-readable result, alongside the content meant for the model.
+	StructuredContent map[string]interface{} `json:"structuredContent,omitempty"`
 }
 
 // A content response.
 // For text content set type to ContentType.Text and set the `text` property
 // For image content set type to ContentType.Image and set the `data` and `mimeType` properties
+// For binary resource content set type to ContentType.Resource and set the `resource` property
 type Content struct {
 	Annotations *TextAnnotation `json:"annotations,omitempty"`
 	// The base64-encoded image data.
 	Data *string `json:"data,omitempty"`
 	// The MIME type of the image. Different providers may support different image types.
 	MimeType *string `json:"mimeType,omitempty"`
+	// The embedded binary resource.
+	Resource *BlobResourceContents `json:"resource,omitempty"`
 	// The text content of the message.
 	Text *string     `json:"text,omitempty"`
 	Type ContentType `json:"type"`
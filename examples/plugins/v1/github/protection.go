@@ -0,0 +1,284 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	GetBranchProtectionTool = ToolDescription{
+		Name:        "gh-get-branch-protection",
+		Description: "Get the protection rules of a branch: required status checks, required pull request reviews, enforce_admins, allow_force_pushes and allow_deletions. The output has the same shape gh-update-branch-protection accepts.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":  prop("string", "The owner of the repository"),
+				"repo":   prop("string", "The repository name"),
+				"branch": prop("string", "The branch name"),
+			},
+			"required": []string{"owner", "repo", "branch"},
+		},
+	}
+	UpdateBranchProtectionTool = ToolDescription{
+		Name:        "gh-update-branch-protection",
+		Description: "Replace the protection rules of a branch. This overwrites the whole configuration: a section that is left out is turned off, and push restrictions are removed. Read the current rules with gh-get-branch-protection first and send them back with your changes.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": schema{
+				"owner":  prop("string", "The owner of the repository"),
+				"repo":   prop("string", "The repository name"),
+				"branch": prop("string", "The branch name"),
+				"required_status_checks": schema{
+					"type":        "object",
+					"description": "Status checks that must pass before merging. Leave out to turn off.",
+					"properties": schema{
+						"strict":   prop("boolean", "Require branches to be up to date with the base before merging"),
+						"contexts": schema{"type": "array", "items": schema{"type": "string"}, "description": "Names of the required checks"},
+					},
+				},
+				"required_pull_request_reviews": schema{
+					"type":        "object",
+					"description": "Pull request review requirements. Leave out to turn off.",
+					"properties": schema{
+						"required_approving_review_count": prop("integer", "Number of approvals required, 0 to 6"),
+						"dismiss_stale_reviews":           prop("boolean", "Dismiss approvals when new commits are pushed"),
+					},
+				},
+				"enforce_admins":     prop("boolean", "Apply the rules to administrators too"),
+				"allow_force_pushes": prop("boolean", "Allow force pushes to the branch"),
+				"allow_deletions":    prop("boolean", "Allow the branch to be deleted"),
+			},
+			"required": []string{"owner", "repo", "branch"},
+		},
+	}
+)
+
+type RequiredStatusChecks struct {
+	Strict   bool     `json:"strict"`
+	Contexts []string `json:"contexts"`
+}
+
+type RequiredPullRequestReviews struct {
+	DismissStaleReviews          bool `json:"dismiss_stale_reviews"`
+	RequiredApprovingReviewCount int  `json:"required_approving_review_count"`
+}
+
+// BranchProtectionUpdate is the body of PUT .../protection. GitHub requires
+// required_status_checks, enforce_admins, required_pull_request_reviews and
+// restrictions to be present, with null meaning "off", so those fields have
+// no omitempty.
+type BranchProtectionUpdate struct {
+	RequiredStatusChecks       *RequiredStatusChecks       `json:"required_status_checks"`
+	EnforceAdmins              *bool                       `json:"enforce_admins"`
+	RequiredPullRequestReviews *RequiredPullRequestReviews `json:"required_pull_request_reviews"`
+	Restrictions               *struct{}                   `json:"restrictions"`
+	AllowForcePushes           *bool                       `json:"allow_force_pushes,omitempty"`
+	AllowDeletions             *bool                       `json:"allow_deletions,omitempty"`
+}
+
+type BranchProtectionSummary struct {
+	Branch                     string                      `json:"branch"`
+	Protected                  bool                        `json:"protected"`
+	RequiredStatusChecks       *RequiredStatusChecks       `json:"required_status_checks"`
+	RequiredPullRequestReviews *RequiredPullRequestReviews `json:"required_pull_request_reviews"`
+	EnforceAdmins              bool                        `json:"enforce_admins"`
+	AllowForcePushes           bool                        `json:"allow_force_pushes"`
+	AllowDeletions             bool                        `json:"allow_deletions"`
+}
+
+type branchProtectionToggle struct {
+	Enabled bool `json:"enabled"`
+}
+
+type BranchProtection struct {
+	RequiredStatusChecks       *RequiredStatusChecks       `json:"required_status_checks"`
+	RequiredPullRequestReviews *RequiredPullRequestReviews `json:"required_pull_request_reviews"`
+	EnforceAdmins              *branchProtectionToggle     `json:"enforce_admins"`
+	AllowForcePushes           *branchProtectionToggle     `json:"allow_force_pushes"`
+	AllowDeletions             *branchProtectionToggle     `json:"allow_deletions"`
+}
+
+func (t *branchProtectionToggle) enabled() bool {
+	return t != nil && t.Enabled
+}
+
+func summarizeBranchProtection(branch string, p BranchProtection) BranchProtectionSummary {
+	summary := BranchProtectionSummary{
+		Branch:                     branch,
+		Protected:                  true,
+		RequiredStatusChecks:       p.RequiredStatusChecks,
+		RequiredPullRequestReviews: p.RequiredPullRequestReviews,
+		EnforceAdmins:              p.EnforceAdmins.enabled(),
+		AllowForcePushes:           p.AllowForcePushes.enabled(),
+		AllowDeletions:             p.AllowDeletions.enabled(),
+	}
+	if summary.RequiredStatusChecks != nil && summary.RequiredStatusChecks.Contexts == nil {
+		summary.RequiredStatusChecks.Contexts = []string{}
+	}
+	return summary
+}
+
+func optionalBool(args map[string]interface{}, key string) (*bool, error) {
+	value, ok := args[key]
+	if !ok || value == nil {
+		return nil, nil
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a boolean, got %v", key, value)
+	}
+	return &b, nil
+}
+
+// branchProtectionFromArgs builds the PUT body from the tool arguments.
+// Sections that are missing or null are sent as explicit nulls.
+func branchProtectionFromArgs(args map[string]interface{}) (BranchProtectionUpdate, error) {
+	var update BranchProtectionUpdate
+
+	if value, ok := args["required_status_checks"]; ok && value != nil {
+		checks, ok := value.(map[string]interface{})
+		if !ok {
+			return update, fmt.Errorf("required_status_checks must be an object or null")
+		}
+		update.RequiredStatusChecks = &RequiredStatusChecks{Contexts: []string{}}
+		if strict, err := optionalBool(checks, "strict"); err != nil {
+			return update, fmt.Errorf("required_status_checks.%s", err)
+		} else if strict != nil {
+			update.RequiredStatusChecks.Strict = *strict
+		}
+		if contexts, ok := checks["contexts"]; ok && contexts != nil {
+			list, ok := contexts.([]interface{})
+			if !ok {
+				return update, fmt.Errorf("required_status_checks.contexts must be an array of strings")
+			}
+			for i, item := range list {
+				name, ok := item.(string)
+				if !ok || name == "" {
+					return update, fmt.Errorf("required_status_checks.contexts[%d] must be a non-empty string", i)
+				}
+				update.RequiredStatusChecks.Contexts = append(update.RequiredStatusChecks.Contexts, name)
+			}
+		}
+	}
+
+	if value, ok := args["required_pull_request_reviews"]; ok && value != nil {
+		reviews, ok := value.(map[string]interface{})
+		if !ok {
+			return update, fmt.Errorf("required_pull_request_reviews must be an object or null")
+		}
+		update.RequiredPullRequestReviews = &RequiredPullRequestReviews{RequiredApprovingReviewCount: 1}
+		if count, ok := reviews["required_approving_review_count"]; ok {
+			n, ok := numberArg(count)
+			if !ok || n != float64(int(n)) || n < 0 || n > 6 {
+				return update, fmt.Errorf("required_pull_request_reviews.required_approving_review_count must be a whole number from 0 to 6, got %v", count)
+			}
+			update.RequiredPullRequestReviews.RequiredApprovingReviewCount = int(n)
+		}
+		if dismiss, err := optionalBool(reviews, "dismiss_stale_reviews"); err != nil {
+			return update, fmt.Errorf("required_pull_request_reviews.%s", err)
+		} else if dismiss != nil {
+			update.RequiredPullRequestReviews.DismissStaleReviews = *dismiss
+		}
+	}
+
+	var err error
+	if update.EnforceAdmins, err = optionalBool(args, "enforce_admins"); err != nil {
+		return update, err
+	}
+	if update.AllowForcePushes, err = optionalBool(args, "allow_force_pushes"); err != nil {
+		return update, err
+	}
+	if update.AllowDeletions, err = optionalBool(args, "allow_deletions"); err != nil {
+		return update, err
+	}
+	return update, nil
+}
+
+func branchProtectionResult(branch string, body []byte) CallToolResult {
+	var protection BranchProtection
+	if err := json.Unmarshal(body, &protection); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to parse branch protection: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, _ := json.Marshal(summarizeBranchProtection(branch, protection))
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+// branchNotProtected tells an unprotected branch apart from the other
+// 404s, such as an unknown branch or repository, by GitHub's message.
+func branchNotProtected(status uint16, body []byte) bool {
+	var apiErr struct {
+		Message string `json:"message"`
+	}
+	return status == 404 && json.Unmarshal(body, &apiErr) == nil && apiErr.Message == "Branch not protected"
+}
+
+func branchGetProtection(apiKey, owner, repo, branch string) CallToolResult {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/branches/%s/protection", owner, repo, branch)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting branch protection: ", url))
+	status, body, err := githubSend(apiKey, pdk.MethodGet, url, nil)
+	if err == nil && branchNotProtected(status, body) {
+		responseJSON, _ := json.Marshal(BranchProtectionSummary{Branch: branch})
+		return CallToolResult{
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(string(responseJSON)),
+			}},
+		}
+	}
+	if err == nil && status != 200 {
+		err = githubStatusError(status, body)
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to get branch protection: %s", err)),
+			}},
+		}
+	}
+
+	return branchProtectionResult(branch, body)
+}
+
+func branchUpdateProtection(apiKey, owner, repo, branch string, args map[string]interface{}) CallToolResult {
+	update, err := branchProtectionFromArgs(args)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid branch protection: %s", err)),
+			}},
+		}
+	}
+
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/branches/%s/protection", owner, repo, branch)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Updating branch protection: ", url))
+	body, err := githubSendExpect(apiKey, pdk.MethodPut, url, update, 200)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to update branch protection: %s", err)),
+			}},
+		}
+	}
+
+	return branchProtectionResult(branch, body)
+}
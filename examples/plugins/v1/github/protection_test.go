@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBranchProtectionBody(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want string
+	}{
+		{
+			name: "everything omitted sends explicit nulls",
+			args: `{"owner":"o","repo":"r","branch":"main"}`,
+			want: `{"required_status_checks":null,"enforce_admins":null,"required_pull_request_reviews":null,"restrictions":null}`,
+		},
+		{
+			name: "explicit nulls stay null",
+			args: `{"required_status_checks":null,"required_pull_request_reviews":null,"enforce_admins":null}`,
+			want: `{"required_status_checks":null,"enforce_admins":null,"required_pull_request_reviews":null,"restrictions":null}`,
+		},
+		{
+			name: "full configuration",
+			args: `{"required_status_checks":{"strict":true,"contexts":["ci/build","ci/test"]},"required_pull_request_reviews":{"required_approving_review_count":2,"dismiss_stale_reviews":true},"enforce_admins":true,"allow_force_pushes":false,"allow_deletions":false}`,
+			want: `{"required_status_checks":{"strict":true,"contexts":["ci/build","ci/test"]},"enforce_admins":true,"required_pull_request_reviews":{"dismiss_stale_reviews":true,"required_approving_review_count":2},"restrictions":null,"allow_force_pushes":false,"allow_deletions":false}`,
+		},
+		{
+			name: "empty sections get defaults and an empty contexts array",
+			args: `{"required_status_checks":{},"required_pull_request_reviews":{}}`,
+			want: `{"required_status_checks":{"strict":false,"contexts":[]},"enforce_admins":null,"required_pull_request_reviews":{"dismiss_stale_reviews":false,"required_approving_review_count":1},"restrictions":null}`,
+		},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		update, err := branchProtectionFromArgs(args)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		body, _ := json.Marshal(update)
+		if string(body) != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.name, body, tt.want)
+		}
+	}
+}
+
+func TestBranchProtectionBodyErrors(t *testing.T) {
+	tests := []struct {
+		args string
+		want string
+	}{
+		{`{"required_status_checks":true}`, "required_status_checks must be an object or null"},
+		{`{"required_status_checks":{"contexts":"ci"}}`, "required_status_checks.contexts must be an array of strings"},
+		{`{"required_status_checks":{"contexts":["ci",""]}}`, "required_status_checks.contexts[1] must be a non-empty string"},
+		{`{"required_status_checks":{"strict":"yes"}}`, "required_status_checks.strict must be a boolean"},
+		{`{"required_pull_request_reviews":{"required_approving_review_count":7}}`, "must be a whole number from 0 to 6, got 7"},
+		{`{"enforce_admins":"true"}`, "enforce_admins must be a boolean"},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := branchProtectionFromArgs(args); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got %v, want %q", tt.args, err, tt.want)
+		}
+	}
+}
+
+func TestSummarizeBranchProtection(t *testing.T) {
+	var protection BranchProtection
+	err := json.Unmarshal([]byte(`{
+		"url": "https://api.github.com/repos/o/r/branches/main/protection",
+		"required_status_checks": {"strict": true, "contexts": null, "checks": []},
+		"enforce_admins": {"enabled": true},
+		"allow_force_pushes": {"enabled": false}
+	}`), &protection)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := json.Marshal(summarizeBranchProtection("main", protection))
+	want := `{"branch":"main","protected":true,"required_status_checks":{"strict":true,"contexts":[]},"required_pull_request_reviews":null,"enforce_admins":true,"allow_force_pushes":false,"allow_deletions":false}`
+	if string(got) != want {
+		t.Fatalf("\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestBranchNotProtected(t *testing.T) {
+	if !branchNotProtected(404, []byte(`{"message":"Branch not protected","documentation_url":"https://docs.github.com"}`)) {
+		t.Error("unprotected branch not recognized")
+	}
+	if branchNotProtected(404, []byte(`{"message":"Branch not found"}`)) {
+		t.Error("unknown branch reported as unprotected")
+	}
+	if branchNotProtected(404, []byte(`{"message":"Not Found"}`)) {
+		t.Error("unknown repository reported as unprotected")
+	}
+	if branchNotProtected(200, []byte(`{"message":"Branch not protected"}`)) {
+		t.Error("200 reported as unprotected")
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	AddReactionTool = ToolDescription{
+		Name:        "gh-add-reaction",
+		Description: "React to an issue, pull request, issue comment or pull request review comment. Adding a reaction you already made is not an error.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":        prop("string", "The owner of the repository"),
+				"repo":         prop("string", "The repository name"),
+				"subject_type": prop("string", "issue (also for pull requests), issue_comment or pull_request_review_comment"),
+				"subject_id":   prop("integer", "The issue or pull request number, or the comment id"),
+				"content":      prop("string", "+1, -1, laugh, confused, heart, hooray, rocket or eyes"),
+			},
+			"required": []string{"owner", "repo", "subject_type", "subject_id", "content"},
+		},
+	}
+	ListReactionsTool = ToolDescription{
+		Name:        "gh-list-reactions",
+		Description: "Count the reactions on an issue, pull request, issue comment or pull request review comment, with the first users who reacted for each",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":        prop("string", "The owner of the repository"),
+				"repo":         prop("string", "The repository name"),
+				"subject_type": prop("string", "issue (also for pull requests), issue_comment or pull_request_review_comment"),
+				"subject_id":   prop("integer", "The issue or pull request number, or the comment id"),
+				"content":      prop("string", "Only count this reaction (optional)"),
+			},
+			"required": []string{"owner", "repo", "subject_type", "subject_id"},
+		},
+	}
+	ReactionTools = []ToolDescription{
+		AddReactionTool,
+		ListReactionsTool,
+	}
+)
+
+const (
+	// Users listed per reaction; Count still covers all of them
+	reactionUsersCap = 20
+	// Pages of 100 reactions read when counting
+	reactionMaxPages = 10
+)
+
+var reactionContents = []string{"+1", "-1", "laugh", "confused", "heart", "hooray", "rocket", "eyes"}
+
+func reactionContent(content string) (string, error) {
+	for _, c := range reactionContents {
+		if content == c {
+			return content, nil
+		}
+	}
+	return "", fmt.Errorf("content must be one of %s, got %q", strings.Join(reactionContents, ", "), content)
+}
+
+// reactionsURL maps the subject to its reactions endpoint.
+func reactionsURL(owner, repo, subjectType string, id int) (string, error) {
+	base := fmt.Sprintf("https://api.github.com/repos/%s/%s", owner, repo)
+	switch subjectType {
+	case "issue", "pull_request":
+		return fmt.Sprintf("%s/issues/%d/reactions", base, id), nil
+	case "issue_comment":
+		return fmt.Sprintf("%s/issues/comments/%d/reactions", base, id), nil
+	case "pull_request_review_comment":
+		return fmt.Sprintf("%s/pulls/comments/%d/reactions", base, id), nil
+	default:
+		return "", fmt.Errorf("subject_type must be issue, issue_comment or pull_request_review_comment, got %q", subjectType)
+	}
+}
+
+type Reaction struct {
+	ID      int    `json:"id"`
+	Content string `json:"content"`
+	User    struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+type ReactionCount struct {
+	Content string   `json:"content"`
+	Count   int      `json:"count"`
+	Users   []string `json:"users"`
+}
+
+type ReactionSummary struct {
+	Total     int             `json:"total"`
+	Reactions []ReactionCount `json:"reactions"`
+	// Set when there were more reactions than were read
+	Incomplete bool `json:"incomplete,omitempty"`
+}
+
+// summarizeReactions counts reactions per content, in the order GitHub
+// shows them, listing up to reactionUsersCap users for each.
+func summarizeReactions(reactions []Reaction) ReactionSummary {
+	byContent := map[string]*ReactionCount{}
+	for _, r := range reactions {
+		count, ok := byContent[r.Content]
+		if !ok {
+			count = &ReactionCount{Content: r.Content, Users: []string{}}
+			byContent[r.Content] = count
+		}
+		count.Count++
+		if len(count.Users) < reactionUsersCap {
+			count.Users = append(count.Users, r.User.Login)
+		}
+	}
+
+	summary := ReactionSummary{Total: len(reactions), Reactions: []ReactionCount{}}
+	for _, c := range reactionContents {
+		if count, ok := byContent[c]; ok {
+			summary.Reactions = append(summary.Reactions, *count)
+		}
+	}
+	return summary
+}
+
+func reactionsAdd(apiKey, owner, repo, subjectType string, id int, content string) CallToolResult {
+	u, err := reactionsURL(owner, repo, subjectType, id)
+	if err == nil {
+		content, err = reactionContent(content)
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid reaction: %s", err)),
+			}},
+		}
+	}
+
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Adding reaction: ", u))
+	status, body, err := githubSend(apiKey, pdk.MethodPost, u, map[string]string{"content": content})
+	// 200 means the reaction already existed
+	if err == nil && status != 201 && status != 200 {
+		err = githubStatusError(status, body)
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to add reaction: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(fmt.Sprintf("Reacted %s to %s %d in %s/%s", content, subjectType, id, owner, repo)),
+		}},
+	}
+}
+
+func reactionsList(apiKey, owner, repo, subjectType string, id int, content string) CallToolResult {
+	u, err := reactionsURL(owner, repo, subjectType, id)
+	if err == nil && content != "" {
+		content, err = reactionContent(content)
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid reaction: %s", err)),
+			}},
+		}
+	}
+
+	u += "?per_page=100"
+	if content != "" {
+		u += "&content=" + content
+	}
+	reactions := []Reaction{}
+	incomplete := false
+	for page := 1; page <= reactionMaxPages; page++ {
+		pageURL := fmt.Sprintf("%s&page=%d", u, page)
+		pdk.Log(pdk.LogDebug, fmt.Sprint("Listing reactions: ", pageURL))
+		var batch []Reaction
+		if _, err := githubGetJSON(apiKey, pageURL, &batch); err != nil {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Failed to list reactions: %s", err)),
+				}},
+			}
+		}
+		reactions = append(reactions, batch...)
+		if len(batch) < 100 {
+			break
+		}
+		incomplete = page == reactionMaxPages
+	}
+
+	summary := summarizeReactions(reactions)
+	summary.Incomplete = incomplete
+	responseJSON, err := json.Marshal(summary)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
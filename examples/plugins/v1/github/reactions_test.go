@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestReactionsURL(t *testing.T) {
+	tests := []struct {
+		subjectType string
+		want        string
+	}{
+		{"issue", "https://api.github.com/repos/acme/api/issues/12/reactions"},
+		{"pull_request", "https://api.github.com/repos/acme/api/issues/12/reactions"},
+		{"issue_comment", "https://api.github.com/repos/acme/api/issues/comments/12/reactions"},
+		{"pull_request_review_comment", "https://api.github.com/repos/acme/api/pulls/comments/12/reactions"},
+	}
+	for _, tt := range tests {
+		got, err := reactionsURL("acme", "api", tt.subjectType, 12)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %q, %v, want %q", tt.subjectType, got, err, tt.want)
+		}
+	}
+	if _, err := reactionsURL("acme", "api", "commit", 12); err == nil {
+		t.Error("unknown subject type accepted")
+	}
+}
+
+func TestReactionContent(t *testing.T) {
+	for _, c := range []string{"+1", "-1", "laugh", "confused", "heart", "hooray", "rocket", "eyes"} {
+		if _, err := reactionContent(c); err != nil {
+			t.Errorf("%s: %v", c, err)
+		}
+	}
+	for _, c := range []string{"thumbsup", "", "HEART"} {
+		if _, err := reactionContent(c); err == nil || !strings.Contains(err.Error(), "content must be one of") {
+			t.Errorf("%q: error %v", c, err)
+		}
+	}
+}
+
+func TestSummarizeReactions(t *testing.T) {
+	var reactions []Reaction
+	for i := 0; i < 25; i++ {
+		reactions = append(reactions, Reaction{Content: "+1"})
+		reactions[len(reactions)-1].User.Login = fmt.Sprintf("user%d", i)
+	}
+	extra := `[{"content":"eyes","user":{"login":"octocat"}},{"content":"heart","user":{"login":"hubot"}},{"content":"eyes","user":{"login":"hubot"}}]`
+	var more []Reaction
+	if err := json.Unmarshal([]byte(extra), &more); err != nil {
+		t.Fatal(err)
+	}
+	reactions = append(more, reactions...)
+
+	summary := summarizeReactions(reactions)
+	if summary.Total != 28 || len(summary.Reactions) != 3 {
+		t.Fatalf("summary %+v", summary)
+	}
+	order := []string{}
+	for _, r := range summary.Reactions {
+		order = append(order, fmt.Sprintf("%s=%d", r.Content, r.Count))
+	}
+	if strings.Join(order, " ") != "+1=25 heart=1 eyes=2" {
+		t.Errorf("counts %v", order)
+	}
+	if users := summary.Reactions[0].Users; len(users) != reactionUsersCap || users[0] != "user0" {
+		t.Errorf("+1 users %v", users)
+	}
+	if users := summary.Reactions[2].Users; strings.Join(users, ",") != "octocat,hubot" {
+		t.Errorf("eyes users %v", users)
+	}
+}
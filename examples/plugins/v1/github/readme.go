@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var GetReadmeTool = ToolDescription{
+	Name:        "gh-get-readme",
+	Description: "Get a repository's README, whatever its file name, as markdown source, rendered HTML or the raw file",
+	InputSchema: schema{
+		"type": "object",
+		"properties": props{
+			"owner":  prop("string", "The owner of the repository"),
+			"repo":   prop("string", "The repository name"),
+			"format": prop("string", "markdown (the decoded source, default), html (rendered by GitHub) or raw"),
+			"ref":    prop("string", "Branch, tag or commit to read the README from (default: the default branch)"),
+		},
+		"required": []string{"owner", "repo"},
+	},
+}
+
+type Readme struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+}
+
+// readmeMediaType returns the Accept header for the format. markdown asks
+// for the JSON description, whose content is base64 encoded.
+func readmeMediaType(format string) (string, error) {
+	switch format {
+	case "", "markdown":
+		return "application/vnd.github+json", nil
+	case "html":
+		return "application/vnd.github.html", nil
+	case "raw":
+		return "application/vnd.github.raw", nil
+	}
+	return "", fmt.Errorf("format must be markdown, html or raw, got %q", format)
+}
+
+func readmeURL(owner, repo, ref string) string {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/readme", owner, repo)
+	if ref != "" {
+		u = fmt.Sprint(u, "?", url.Values{"ref": {ref}}.Encode())
+	}
+	return u
+}
+
+// readmeText decodes the content of the JSON description.
+func readmeText(body []byte) (string, error) {
+	var readme Readme
+	if err := json.Unmarshal(body, &readme); err != nil {
+		return "", fmt.Errorf("unexpected response: %w", err)
+	}
+	return decodeContent(readme.Path, readme.Encoding, readme.Content)
+}
+
+// decodeContent decodes the content of a file returned by the contents API.
+func decodeContent(path, encoding, content string) (string, error) {
+	if encoding != "base64" {
+		return "", fmt.Errorf("%s has unsupported encoding %q", path, encoding)
+	}
+	// GitHub wraps the base64 at 60 characters
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(content, "\n", ""))
+	if err != nil {
+		return "", fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return string(decoded), nil
+}
+
+func readmeNotFound(owner, repo, ref string) string {
+	if ref != "" {
+		return fmt.Sprintf("%s/%s has no README at %s, or the ref doesn't exist", owner, repo, ref)
+	}
+	return fmt.Sprintf("%s/%s has no README", owner, repo)
+}
+
+func reposGetReadme(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	format, _ := args["format"].(string)
+	ref, _ := args["ref"].(string)
+	failed := func(message string) CallToolResult {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(message),
+			}},
+		}
+	}
+
+	mediaType, err := readmeMediaType(format)
+	if err != nil {
+		return failed(fmt.Sprintf("Invalid README request: %s", err))
+	}
+
+	u := readmeURL(owner, repo, ref)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting README: ", u))
+	resp := githubRequest(apiKey, pdk.MethodGet, u, mediaType).Send()
+
+	switch resp.Status() {
+	case 200:
+	case 404:
+		return failed(readmeNotFound(owner, repo, ref))
+	default:
+		return failed(fmt.Sprintf("Failed to get README: %s", githubStatusError(resp.Status(), resp.Body())))
+	}
+
+	text := string(resp.Body())
+	if format == "" || format == "markdown" {
+		if text, err = readmeText(resp.Body()); err != nil {
+			return failed(fmt.Sprintf("Failed to read README: %s", err))
+		}
+	}
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(text),
+		}},
+	}
+}
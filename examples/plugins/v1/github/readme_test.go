@@ -0,0 +1,41 @@
+package main
+
+import "testing"
+
+func TestReadmeMediaType(t *testing.T) {
+	for format, want := range map[string]string{
+		"":         "application/vnd.github+json",
+		"markdown": "application/vnd.github+json",
+		"html":     "application/vnd.github.html",
+		"raw":      "application/vnd.github.raw",
+	} {
+		if got, err := readmeMediaType(format); err != nil || got != want {
+			t.Errorf("%q: got %q, %v", format, got, err)
+		}
+	}
+	if _, err := readmeMediaType("rst"); err == nil {
+		t.Error("rst: expected an error")
+	}
+}
+
+func TestReadmeURL(t *testing.T) {
+	if got := readmeURL("o", "r", ""); got != "https://api.github.com/repos/o/r/readme" {
+		t.Errorf("got %s", got)
+	}
+	if got := readmeURL("o", "r", "release/1.0"); got != "https://api.github.com/repos/o/r/readme?ref=release%2F1.0" {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestReadmeText(t *testing.T) {
+	got, err := readmeText([]byte(`{"name":"README.rst","path":"README.rst","encoding":"base64","content":"SGVsbG8s\nIHdvcmxk\n"}`))
+	if err != nil || got != "Hello, world" {
+		t.Errorf("got %q, %v", got, err)
+	}
+	if _, err := readmeText([]byte(`{"path":"README.md","encoding":"base64","content":"not base64!"}`)); err == nil {
+		t.Error("expected a decode error")
+	}
+	if _, err := readmeText([]byte(`{"path":"README.md","encoding":"none","content":""}`)); err == nil {
+		t.Error("expected an encoding error")
+	}
+}
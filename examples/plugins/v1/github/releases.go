@@ -0,0 +1,417 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ListReleasesTool = ToolDescription{
+		Name:        "gh-list-releases",
+		Description: "List the releases of a repository, newest first, with their tag, name, draft and prerelease flags, publish date and number of assets",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":        prop("string", "The owner of the repository"),
+				"repo":         prop("string", "The repository name"),
+				"include_body": prop("boolean", "Include the release notes, truncated (default false)"),
+				"per_page":     prop("integer", "Number of results per page (max 100)"),
+				"page":         prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	CreateReleaseTool = ToolDescription{
+		Name:        "gh-create-release",
+		Description: "Create a release, creating its tag from target_commitish when the tag doesn't exist yet. Returns the release id, html_url and the upload_url needed to upload assets.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":                  prop("string", "The owner of the repository"),
+				"repo":                   prop("string", "The repository name"),
+				"tag_name":               prop("string", "The tag to release"),
+				"target_commitish":       prop("string", "Branch or commit SHA the tag is created from when it doesn't exist (default: the default branch)"),
+				"name":                   prop("string", "The release title"),
+				"body":                   prop("string", "The release notes in markdown"),
+				"draft":                  prop("boolean", "Create an unpublished draft (default false)"),
+				"prerelease":             prop("boolean", "Mark the release as a prerelease (default false)"),
+				"make_latest":            prop("string", "true, false or legacy: whether this becomes the latest release (default true)"),
+				"generate_release_notes": prop("boolean", "Have GitHub write the notes from merged pull requests; a given body is put above them (default false)"),
+			},
+			"required": []string{"owner", "repo", "tag_name"},
+		},
+	}
+	GetLatestReleaseTool = ToolDescription{
+		Name:        "gh-get-latest-release",
+		Description: "Get the latest published release of a repository with its notes and downloadable assets",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner": prop("string", "The owner of the repository"),
+				"repo":  prop("string", "The repository name"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	GetReleaseByTagTool = ToolDescription{
+		Name:        "gh-get-release-by-tag",
+		Description: "Get the release of a tag with its notes and downloadable assets",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner": prop("string", "The owner of the repository"),
+				"repo":  prop("string", "The repository name"),
+				"tag":   prop("string", "The tag name, e.g. v1.2.0"),
+			},
+			"required": []string{"owner", "repo", "tag"},
+		},
+	}
+	GenerateReleaseNotesTool = ToolDescription{
+		Name:        "gh-generate-release-notes",
+		Description: "Draft release notes for a tag from the pull requests merged since the previous release, without creating the release. Pass the result to gh-create-release as name and body.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":             prop("string", "The owner of the repository"),
+				"repo":              prop("string", "The repository name"),
+				"tag_name":          prop("string", "The tag of the release, existing or not"),
+				"previous_tag_name": prop("string", "The tag to start from (default: the latest release before this one)"),
+				"target_commitish":  prop("string", "Branch or commit SHA the tag would be created from when it doesn't exist"),
+			},
+			"required": []string{"owner", "repo", "tag_name"},
+		},
+	}
+	ReleaseTools = []ToolDescription{
+		ListReleasesTool,
+		CreateReleaseTool,
+		GetLatestReleaseTool,
+		GetReleaseByTagTool,
+		UploadReleaseAssetTool,
+		DownloadReleaseAssetTool,
+		GenerateReleaseNotesTool,
+	}
+)
+
+// Characters of release notes kept by include_body
+const releaseBodyMax = 2000
+
+type ReleaseAsset struct {
+	ID                 int    `json:"id"`
+	Name               string `json:"name"`
+	ContentType        string `json:"content_type"`
+	Size               int    `json:"size"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+type Release struct {
+	ID          int            `json:"id"`
+	TagName     string         `json:"tag_name"`
+	Name        *string        `json:"name"`
+	Body        *string        `json:"body"`
+	Draft       bool           `json:"draft"`
+	Prerelease  bool           `json:"prerelease"`
+	PublishedAt *string        `json:"published_at"`
+	HTMLURL     string         `json:"html_url"`
+	UploadURL   string         `json:"upload_url"`
+	Assets      []ReleaseAsset `json:"assets"`
+}
+
+type ReleaseSummary struct {
+	TagName     string  `json:"tag_name"`
+	Name        *string `json:"name"`
+	Draft       bool    `json:"draft"`
+	Prerelease  bool    `json:"prerelease"`
+	PublishedAt *string `json:"published_at"`
+	Assets      int     `json:"assets"`
+	Body        string  `json:"body,omitempty"`
+}
+
+func summarizeReleases(releases []Release, includeBody bool) []ReleaseSummary {
+	summaries := make([]ReleaseSummary, 0, len(releases))
+	for _, r := range releases {
+		summary := ReleaseSummary{
+			TagName:     r.TagName,
+			Name:        r.Name,
+			Draft:       r.Draft,
+			Prerelease:  r.Prerelease,
+			PublishedAt: r.PublishedAt,
+			Assets:      len(r.Assets),
+		}
+		if includeBody && r.Body != nil {
+			summary.Body = truncateText(*r.Body, releaseBodyMax)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// releaseCreateBody builds the POST /releases body. An empty body is left
+// out so generated notes aren't replaced by nothing.
+func releaseCreateBody(args map[string]interface{}) (map[string]interface{}, error) {
+	body := map[string]interface{}{}
+	tag, _ := args["tag_name"].(string)
+	if strings.TrimSpace(tag) == "" {
+		return nil, fmt.Errorf("tag_name is required")
+	}
+	body["tag_name"] = tag
+	for _, key := range []string{"target_commitish", "name", "body"} {
+		if value, _ := args[key].(string); value != "" {
+			body[key] = value
+		}
+	}
+	for _, key := range []string{"draft", "prerelease", "generate_release_notes"} {
+		if value, ok := args[key].(bool); ok {
+			body[key] = value
+		}
+	}
+	switch value := args["make_latest"].(type) {
+	case nil:
+	case bool:
+		body["make_latest"] = fmt.Sprint(value)
+	case string:
+		if value != "true" && value != "false" && value != "legacy" {
+			return nil, fmt.Errorf("make_latest must be true, false or legacy, got %q", value)
+		}
+		body["make_latest"] = value
+	default:
+		return nil, fmt.Errorf("make_latest must be true, false or legacy, got %v", value)
+	}
+	return body, nil
+}
+
+type ReleaseDetailsAsset struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Size        int    `json:"size"`
+	DownloadURL string `json:"download_url"`
+}
+
+type ReleaseDetails struct {
+	ID          int                   `json:"id"`
+	TagName     string                `json:"tag_name"`
+	Name        *string               `json:"name"`
+	Body        *string               `json:"body"`
+	PublishedAt *string               `json:"published_at"`
+	HTMLURL     string                `json:"html_url"`
+	Assets      []ReleaseDetailsAsset `json:"assets"`
+}
+
+func releaseDetails(r Release) ReleaseDetails {
+	details := ReleaseDetails{
+		ID:          r.ID,
+		TagName:     r.TagName,
+		Name:        r.Name,
+		Body:        r.Body,
+		PublishedAt: r.PublishedAt,
+		HTMLURL:     r.HTMLURL,
+		Assets:      []ReleaseDetailsAsset{},
+	}
+	for _, a := range r.Assets {
+		details.Assets = append(details.Assets, ReleaseDetailsAsset{
+			ID:          a.ID,
+			Name:        a.Name,
+			Size:        a.Size,
+			DownloadURL: a.BrowserDownloadURL,
+		})
+	}
+	return details
+}
+
+type CreatedRelease struct {
+	ID        int    `json:"id"`
+	TagName   string `json:"tag_name"`
+	Draft     bool   `json:"draft"`
+	HTMLURL   string `json:"html_url"`
+	UploadURL string `json:"upload_url"`
+}
+
+func releasesList(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases?%s", owner, repo, strings.Join(paginationParams(args), "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing releases: ", u))
+
+	releases := []Release{}
+	if _, err := githubGetJSON(apiKey, u, &releases); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list releases: %s", err)),
+			}},
+		}
+	}
+
+	includeBody, _ := args["include_body"].(bool)
+	responseJSON, err := json.Marshal(summarizeReleases(releases, includeBody))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func releasesCreate(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	body, err := releaseCreateBody(args)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid release: %s", err)),
+			}},
+		}
+	}
+
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases", owner, repo)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Creating release: ", u))
+	var release Release
+	if err := githubSendJSON(apiKey, pdk.MethodPost, u, body, 201, &release); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to create release: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(CreatedRelease{
+		ID:        release.ID,
+		TagName:   release.TagName,
+		Draft:     release.Draft,
+		HTMLURL:   release.HTMLURL,
+		UploadURL: release.UploadURL,
+	})
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+// releaseGet fetches the latest release, or the release of tag when it
+// isn't empty.
+func releaseGet(apiKey, owner, repo, tag string) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/latest", owner, repo)
+	missing := fmt.Sprintf("%s/%s has no published releases", owner, repo)
+	if tag != "" {
+		u = fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/tags/%s", owner, repo, url.PathEscape(tag))
+		missing = fmt.Sprintf("%s/%s has no release for tag %s", owner, repo, tag)
+	}
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting release: ", u))
+
+	var release Release
+	status, err := githubGetJSON(apiKey, u, &release)
+	if status == 404 {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(missing),
+			}},
+		}
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to get release: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(releaseDetails(release))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func releaseNotesBody(args map[string]interface{}) (map[string]string, error) {
+	body := map[string]string{}
+	tag, _ := args["tag_name"].(string)
+	if strings.TrimSpace(tag) == "" {
+		return nil, fmt.Errorf("tag_name is required")
+	}
+	body["tag_name"] = tag
+	for _, key := range []string{"previous_tag_name", "target_commitish"} {
+		if value, _ := args[key].(string); value != "" {
+			body[key] = value
+		}
+	}
+	return body, nil
+}
+
+func releasesGenerateNotes(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	body, err := releaseNotesBody(args)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid release notes request: %s", err)),
+			}},
+		}
+	}
+
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/generate-notes", owner, repo)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Generating release notes: ", u))
+	var notes struct {
+		Name string `json:"name"`
+		Body string `json:"body"`
+	}
+	if err := githubSendJSON(apiKey, pdk.MethodPost, u, body, 200, &notes); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to generate release notes: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{
+			{Type: ContentTypeText, Text: some(notes.Name)},
+			{Type: ContentTypeText, Text: some(notes.Body)},
+		},
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSummarizeReleases(t *testing.T) {
+	var releases []Release
+	err := json.Unmarshal([]byte(`[
+		{"id":2,"tag_name":"v2.0.0","name":"Two","body":"`+strings.Repeat("n", releaseBodyMax+10)+`","draft":false,"prerelease":true,"published_at":"2024-05-01T00:00:00Z","assets":[{"id":1},{"id":2}]},
+		{"id":1,"tag_name":"v1.0.0","name":null,"body":null,"draft":true,"published_at":null,"assets":[]}
+	]`), &releases)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := summarizeReleases(releases, false)
+	if len(got) != 2 || got[0].TagName != "v2.0.0" || got[0].Assets != 2 || !got[0].Prerelease || got[0].Body != "" {
+		t.Errorf("unexpected summary without body: %+v", got)
+	}
+	if !got[1].Draft || got[1].Name != nil || got[1].PublishedAt != nil {
+		t.Errorf("draft release: %+v", got[1])
+	}
+
+	got = summarizeReleases(releases, true)
+	if n := len([]rune(got[0].Body)); n != releaseBodyMax+1 || !strings.HasSuffix(got[0].Body, "…") {
+		t.Errorf("body not truncated to %d runes: %d", releaseBodyMax, n)
+	}
+	if got[1].Body != "" {
+		t.Errorf("null body: got %q", got[1].Body)
+	}
+	out, _ := json.Marshal(got[1])
+	if strings.Contains(string(out), `"body"`) {
+		t.Errorf("empty body should be omitted: %s", out)
+	}
+}
+
+func TestReleaseCreateBody(t *testing.T) {
+	tests := []struct {
+		args    string
+		want    string
+		wantErr bool
+	}{
+		{
+			args: `{"tag_name":"v1.0.0"}`,
+			want: `{"tag_name":"v1.0.0"}`,
+		},
+		{
+			args: `{"tag_name":"v1.0.0","target_commitish":"main","name":"One","body":"Notes","draft":true,"prerelease":false,"make_latest":"legacy"}`,
+			want: `{"body":"Notes","draft":true,"make_latest":"legacy","name":"One","prerelease":false,"tag_name":"v1.0.0","target_commitish":"main"}`,
+		},
+		{
+			args: `{"tag_name":"v1.0.0","body":"","generate_release_notes":true,"make_latest":false}`,
+			want: `{"generate_release_notes":true,"make_latest":"false","tag_name":"v1.0.0"}`,
+		},
+		{args: `{"tag_name":" "}`, wantErr: true},
+		{args: `{"tag_name":"v1","make_latest":"yes"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		body, err := releaseCreateBody(args)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %v", tt.args, body)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.args, err)
+			continue
+		}
+		if got, _ := json.Marshal(body); string(got) != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestReleaseDetails(t *testing.T) {
+	var release Release
+	err := json.Unmarshal([]byte(`{"id":7,"tag_name":"v1.2.0","name":"1.2","body":"Fixes","published_at":"2024-05-01T00:00:00Z","html_url":"https://github.com/acme/api/releases/tag/v1.2.0","upload_url":"https://uploads.github.com/x{?name,label}",
+		"assets":[{"id":3,"name":"api.tar.gz","size":1024,"content_type":"application/gzip","browser_download_url":"https://github.com/acme/api/releases/download/v1.2.0/api.tar.gz"}]}`), &release)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(releaseDetails(release))
+	want := `{"id":7,"tag_name":"v1.2.0","name":"1.2","body":"Fixes","published_at":"2024-05-01T00:00:00Z","html_url":"https://github.com/acme/api/releases/tag/v1.2.0","assets":[{"id":3,"name":"api.tar.gz","size":1024,"download_url":"https://github.com/acme/api/releases/download/v1.2.0/api.tar.gz"}]}`
+	if string(got) != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+
+	got, _ = json.Marshal(releaseDetails(Release{TagName: "v0"}).Assets)
+	if string(got) != "[]" {
+		t.Errorf("assets without any: got %s", got)
+	}
+}
+
+func TestReleaseNotesBody(t *testing.T) {
+	body, err := releaseNotesBody(map[string]interface{}{"tag_name": "v1.1.0", "previous_tag_name": "v1.0.0", "target_commitish": ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := json.Marshal(body); string(got) != `{"previous_tag_name":"v1.0.0","tag_name":"v1.1.0"}` {
+		t.Errorf("got %s", got)
+	}
+	if _, err := releaseNotesBody(map[string]interface{}{}); err == nil {
+		t.Error("expected an error without tag_name")
+	}
+}
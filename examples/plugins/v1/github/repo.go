@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/extism/go-pdk"
 )
@@ -65,11 +66,94 @@ var (
 			"required": []string{"username"},
 		},
 	}
+	CreateRepoTool = ToolDescription{
+		Name:        "gh-create-repo",
+		Description: "Create a repository for the authenticated user, or in an organization",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"name":               prop("string", "The repository name: letters, digits, '.', '-' and '_' only"),
+				"org":                prop("string", "Create the repository in this organization instead of your account"),
+				"description":        prop("string", "A short description"),
+				"private":            prop("boolean", "Make the repository private (default false)"),
+				"auto_init":          prop("boolean", "Create an initial commit with a README (default false)"),
+				"gitignore_template": prop("string", "A .gitignore template to add, e.g. Go or Node"),
+				"license_template":   prop("string", "A license to add by its keyword, e.g. mit or apache-2.0"),
+				"default_branch":     prop("string", "Name of the initial branch; needs auto_init or a template"),
+			},
+			"required": []string{"name"},
+		},
+	}
+	DeleteRepoTool = ToolDescription{
+		Name:        "gh-delete-repo",
+		Description: "Delete a repository and everything in it. The user is asked to type the repository's full name to confirm; clients that can't ask can't delete.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner": prop("string", "The owner of the repository"),
+				"repo":  prop("string", "The repository name"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	ForkRepoTool = ToolDescription{
+		Name:        "gh-fork-repo",
+		Description: "Fork a repository into your account or an organization. GitHub copies it in the background; the result says whether the fork was ready yet.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":               prop("string", "The owner of the repository"),
+				"repo":                prop("string", "The repository name"),
+				"organization":        prop("string", "Fork into this organization instead of your account"),
+				"name":                prop("string", "Name of the fork (default: the repository's name)"),
+				"default_branch_only": prop("boolean", "Only copy the default branch (default false)"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	ListOrgReposTool = ToolDescription{
+		Name:        "gh-list-org-repos",
+		Description: "List the repositories of a GitHub organization, including private ones the token can see",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"org":       prop("string", "The organization name"),
+				"type":      prop("string", "The type of repositories to list (all, public, private, forks, sources, member)"),
+				"sort":      prop("string", "The sort field (created, updated, pushed, full_name)"),
+				"direction": prop("string", "The sort direction (asc or desc)"),
+				"per_page":  prop("integer", "Number of results per page (max 100)"),
+				"page":      prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"org"},
+		},
+	}
 	RepoTools = []ToolDescription{
 		GetRepositoryContributorsTool,
 		GetRepositoryCollaboratorsTool,
 		GetRepositoryDetailsTool,
 		ListReposTool,
+		ListOrgReposTool,
+		RepoHealthTool,
+		CreateRepoTool,
+		DeleteRepoTool,
+		ForkRepoTool,
+		GetRepoTopicsTool,
+		SetRepoTopicsTool,
+		StarRepoTool,
+		UnstarRepoTool,
+		ListStarredTool,
+		ListStargazersTool,
+		ListWatchersTool,
+		AddCollaboratorTool,
+		RemoveCollaboratorTool,
+		ListRepoInvitationsTool,
+		DeleteRepoInvitationTool,
+		AcceptInvitationTool,
+		RepoTrafficTool,
+		RepoLanguagesTool,
+		GetLicenseTool,
+		RepoStatsTool,
+		ListRepoEventsTool,
 	}
 )
 
@@ -124,31 +208,13 @@ func reposGetContributors(apiKey string, owner, repo string, args map[string]int
 
 	pdk.Log(pdk.LogDebug, fmt.Sprint("Fetching contributors: ", url))
 
-	// Make request
-	req := pdk.NewHTTPRequest(pdk.MethodGet, url)
-	req.SetHeader("Authorization", fmt.Sprint("token ", apiKey))
-	req.SetHeader("Accept", "application/vnd.github+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
-
-	resp := req.Send()
-	if resp.Status() != 200 {
-		return CallToolResult{
-			IsError: some(true),
-			Content: []Content{{
-				Type: ContentTypeText,
-				Text: some(fmt.Sprintf("Failed to fetch contributors: %d %s", resp.Status(), string(resp.Body()))),
-			}},
-		}, nil
-	}
-
-	// Parse the response
 	var contributors []Contributor
-	if err := json.Unmarshal(resp.Body(), &contributors); err != nil {
+	if _, err := githubGetJSON(apiKey, url, &contributors); err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprintf("Failed to parse contributors: %s", err)),
+				Text: some(fmt.Sprintf("Failed to fetch contributors: %s", err)),
 			}},
 		}, nil
 	}
@@ -228,31 +294,13 @@ func reposGetCollaborators(apiKey string, owner, repo string, args map[string]in
 
 	pdk.Log(pdk.LogDebug, fmt.Sprint("Fetching collaborators: ", url))
 
-	// Make request
-	req := pdk.NewHTTPRequest(pdk.MethodGet, url)
-	req.SetHeader("Authorization", fmt.Sprint("token ", apiKey))
-	req.SetHeader("Accept", "application/vnd.github+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
-
-	resp := req.Send()
-	if resp.Status() != 200 {
-		return CallToolResult{
-			IsError: some(true),
-			Content: []Content{{
-				Type: ContentTypeText,
-				Text: some(fmt.Sprintf("Failed to fetch collaborators: %d %s", resp.Status(), string(resp.Body()))),
-			}},
-		}, nil
-	}
-
-	// Parse the response
 	var collaborators []Collaborator
-	if err := json.Unmarshal(resp.Body(), &collaborators); err != nil {
+	if _, err := githubGetJSON(apiKey, url, &collaborators); err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprintf("Failed to parse collaborators: %s", err)),
+				Text: some(fmt.Sprintf("Failed to fetch collaborators: %s", err)),
 			}},
 		}, nil
 	}
@@ -300,40 +348,78 @@ func reposGetDetails(apiKey string, owner, repo string) (CallToolResult, error)
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s", owner, repo)
 	pdk.Log(pdk.LogDebug, fmt.Sprint("Fetching repository details: ", url))
 
-	req := pdk.NewHTTPRequest(pdk.MethodGet, url)
-	req.SetHeader("Authorization", fmt.Sprint("token ", apiKey))
-	req.SetHeader("Accept", "application/vnd.github+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
-
-	resp := req.Send()
-	if resp.Status() != 200 {
+	var repoDetails RepositoryDetails
+	if _, err := githubGetJSON(apiKey, url, &repoDetails); err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprintf("Failed to fetch repository details: %d %s", resp.Status(), string(resp.Body()))),
+				Text: some(fmt.Sprintf("Failed to fetch repository details: %s", err)),
 			}},
 		}, nil
 	}
 
-	var repoDetails RepositoryDetails
-	if err := json.Unmarshal(resp.Body(), &repoDetails); err != nil {
+	responseJSON, err := json.Marshal(repoDetails)
+	if err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprintf("Failed to parse repository details: %s", err)),
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
 			}},
 		}, nil
 	}
 
-	responseJSON, err := json.Marshal(repoDetails)
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}, nil
+}
+
+// repoListParams adds type, sort, direction and pagination.
+func repoListParams(args map[string]interface{}) []string {
+	params := make([]string, 0)
+	for _, key := range []string{"type", "sort", "direction"} {
+		if value, ok := args[key].(string); ok && value != "" {
+			params = append(params, fmt.Sprintf("%s=%s", key, value))
+		}
+	}
+	return append(params, paginationParams(args)...)
+}
+
+func reposListURL(username string, args map[string]interface{}) string {
+	return fmt.Sprintf("https://api.github.com/users/%s/repos?%s", username, strings.Join(repoListParams(args), "&"))
+}
+
+var orgRepoTypes = []string{"all", "public", "private", "forks", "sources", "member"}
+
+// orgReposURL lists an organization's repositories, including the private
+// ones the token can see, which the user endpoint leaves out.
+func orgReposURL(org string, args map[string]interface{}) (string, error) {
+	if value, _ := args["type"].(string); value != "" {
+		valid := false
+		for _, t := range orgRepoTypes {
+			valid = valid || value == t
+		}
+		if !valid {
+			return "", fmt.Errorf("type must be one of %s, got %q", strings.Join(orgRepoTypes, ", "), value)
+		}
+	}
+	return fmt.Sprintf("https://api.github.com/orgs/%s/repos?%s", org, strings.Join(repoListParams(args), "&")), nil
+}
+
+func reposList(apiKey string, url string) (CallToolResult, error) {
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Fetching repositories: ", url))
+
+	body, err := githubSendExpect(apiKey, pdk.MethodGet, url, nil, 200)
 	if err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+				Text: some(fmt.Sprintf("Failed to fetch repositories: %s", err)),
 			}},
 		}, nil
 	}
@@ -341,72 +427,244 @@ func reposGetDetails(apiKey string, owner, repo string) (CallToolResult, error)
 	return CallToolResult{
 		Content: []Content{{
 			Type: ContentTypeText,
-			Text: some(string(responseJSON)),
+			Text: some(string(body)),
 		}},
 	}, nil
 }
 
-func reposList(apiKey string, username string, args map[string]interface{}) (CallToolResult, error) {
-	baseURL := fmt.Sprintf("https://api.github.com/users/%s/repos", username)
-	params := make([]string, 0)
+// repoName checks a repository name against what GitHub accepts.
+func repoName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(name) > 100 {
+		return fmt.Errorf("name must be at most 100 characters, got %d", len(name))
+	}
+	if name == "." || name == ".." {
+		return fmt.Errorf("name can't be %q", name)
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_') {
+			return fmt.Errorf("name may only contain letters, digits, '.', '-' and '_', got %q", name)
+		}
+	}
+	return nil
+}
 
-	// Optional parameters
-	if value, ok := args["type"].(string); ok && value != "" {
-		params = append(params, fmt.Sprintf("type=%s", value))
+// repoCreateBody builds the POST /user/repos or /orgs/{org}/repos body.
+// GitHub has no field for the initial branch name, so it is returned
+// separately to rename the branch after creating the repository.
+func repoCreateBody(args map[string]interface{}) (map[string]interface{}, string, error) {
+	name, _ := args["name"].(string)
+	if err := repoName(name); err != nil {
+		return nil, "", err
 	}
-	if value, ok := args["sort"].(string); ok && value != "" {
-		params = append(params, fmt.Sprintf("sort=%s", value))
+	body := map[string]interface{}{"name": name}
+	for _, key := range []string{"description", "gitignore_template", "license_template"} {
+		if value, _ := args[key].(string); value != "" {
+			body[key] = value
+		}
+	}
+	for _, key := range []string{"private", "auto_init"} {
+		if value, ok := args[key].(bool); ok {
+			body[key] = value
+		}
 	}
-	if value, ok := args["direction"].(string); ok && value != "" {
-		params = append(params, fmt.Sprintf("direction=%s", value))
+	defaultBranch, _ := args["default_branch"].(string)
+	if defaultBranch != "" && body["auto_init"] != true && body["gitignore_template"] == nil && body["license_template"] == nil {
+		return nil, "", fmt.Errorf("default_branch needs auto_init, gitignore_template or license_template: an empty repository has no branch to name")
 	}
+	return body, defaultBranch, nil
+}
 
-	// Pagination parameters
-	perPage := 30 // Default value
-	if value, ok := args["per_page"].(float64); ok {
-		if value > 100 {
-			perPage = 100 // Max value
-		} else if value > 0 {
-			perPage = int(value)
+type CreatedRepository struct {
+	FullName      string `json:"full_name"`
+	HTMLURL       string `json:"html_url"`
+	CloneURL      string `json:"clone_url"`
+	SSHURL        string `json:"ssh_url"`
+	DefaultBranch string `json:"default_branch"`
+	Private       bool   `json:"private"`
+}
+
+func reposCreate(apiKey string, args map[string]interface{}) CallToolResult {
+	body, defaultBranch, err := repoCreateBody(args)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid repository: %s", err)),
+			}},
 		}
 	}
-	params = append(params, fmt.Sprintf("per_page=%d", perPage))
 
-	page := 1 // Default value
-	if value, ok := args["page"].(float64); ok && value > 0 {
-		page = int(value)
+	u := "https://api.github.com/user/repos"
+	if org, _ := args["org"].(string); org != "" {
+		u = fmt.Sprintf("https://api.github.com/orgs/%s/repos", org)
+	}
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Creating repository: ", u))
+	var created CreatedRepository
+	if err := githubSendJSON(apiKey, pdk.MethodPost, u, body, 201, &created); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to create repository: %s", err)),
+			}},
+		}
 	}
-	params = append(params, fmt.Sprintf("page=%d", page))
 
-	// Build final URL
-	url := baseURL
-	if len(params) > 0 {
-		url = fmt.Sprintf("%s?%s", baseURL, strings.Join(params, "&"))
+	if defaultBranch != "" && defaultBranch != created.DefaultBranch {
+		owner, repo, _ := strings.Cut(created.FullName, "/")
+		if renamed := branchRename(apiKey, owner, repo, created.DefaultBranch, defaultBranch); renamed.IsError != nil && *renamed.IsError {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Created %s, but its default branch is still %s: %s", created.FullName, created.DefaultBranch, *renamed.Content[0].Text)),
+				}},
+			}
+		}
+		created.DefaultBranch = defaultBranch
 	}
 
-	pdk.Log(pdk.LogDebug, fmt.Sprint("Fetching repositories: ", url))
+	responseJSON, err := json.Marshal(created)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
 
-	// Make request
-	req := pdk.NewHTTPRequest(pdk.MethodGet, url)
-	req.SetHeader("Authorization", fmt.Sprint("token ", apiKey))
-	req.SetHeader("Accept", "application/vnd.github+json")
-	req.SetHeader("User-Agent", "github-mcpx-servlet")
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func reposDelete(apiKey, owner, repo string) CallToolResult {
+	fullName := fmt.Sprintf("%s/%s", owner, repo)
+	if refused := confirmTyped(fmt.Sprintf("delete the repository %s with all its code, issues and pull requests", fullName), fullName, elicitTypedConfirmation); refused != nil {
+		return *refused
+	}
 
-	resp := req.Send()
-	if resp.Status() != 200 {
+	u := fmt.Sprintf("https://api.github.com/repos/%s", fullName)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Deleting repository: ", u))
+	_, err := githubSendExpect(apiKey, pdk.MethodDelete, u, nil, 204)
+	if err != nil {
 		return CallToolResult{
 			IsError: some(true),
 			Content: []Content{{
 				Type: ContentTypeText,
-				Text: some(fmt.Sprintf("Failed to fetch repositories: %d %s", resp.Status(), string(resp.Body()))),
+				Text: some(fmt.Sprintf("Failed to delete %s: %s", fullName, err)),
 			}},
-		}, nil
+		}
 	}
 
 	return CallToolResult{
 		Content: []Content{{
 			Type: ContentTypeText,
-			Text: some(string(resp.Body())),
+			Text: some(fmt.Sprintf("Deleted %s", fullName)),
 		}},
-	}, nil
+	}
+}
+
+const (
+	// Times the fork is checked after asking for it
+	forkPollAttempts = 3
+	forkPollInterval = 2 * time.Second
+)
+
+func forkBody(args map[string]interface{}) (map[string]interface{}, error) {
+	body := map[string]interface{}{}
+	if organization, _ := args["organization"].(string); organization != "" {
+		body["organization"] = organization
+	}
+	if name, _ := args["name"].(string); name != "" {
+		if err := repoName(name); err != nil {
+			return nil, err
+		}
+		body["name"] = name
+	}
+	if value, ok := args["default_branch_only"].(bool); ok {
+		body["default_branch_only"] = value
+	}
+	return body, nil
+}
+
+type ForkedRepository struct {
+	FullName      string `json:"full_name"`
+	HTMLURL       string `json:"html_url"`
+	DefaultBranch string `json:"default_branch"`
+	Ready         bool   `json:"ready"`
+}
+
+// forkReady reports whether the fork's default branch can be read yet,
+// which is when GitHub has finished copying it.
+func forkReady(apiKey string, fork ForkedRepository) bool {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/branches/%s", fork.FullName, fork.DefaultBranch)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Checking fork: ", u))
+	var branch json.RawMessage
+	_, err := githubGetJSON(apiKey, u, &branch)
+	return err == nil
+}
+
+func reposFork(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	body, err := forkBody(args)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid fork: %s", err)),
+			}},
+		}
+	}
+
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/forks", owner, repo)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Forking repository: ", u))
+	var fork ForkedRepository
+	if err := githubSendJSON(apiKey, pdk.MethodPost, u, body, 202, &fork); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to fork %s/%s: %s", owner, repo, err)),
+			}},
+		}
+	}
+
+	for attempt := 0; attempt < forkPollAttempts && !fork.Ready; attempt++ {
+		if attempt > 0 {
+			time.Sleep(forkPollInterval)
+		}
+		fork.Ready = forkReady(apiKey, fork)
+	}
+
+	status := fmt.Sprintf("Forked %s/%s to %s: %s", owner, repo, fork.FullName, fork.HTMLURL)
+	if !fork.Ready {
+		status += "\nGitHub is still copying it; it may take a few minutes before it can be used."
+	}
+	responseJSON, err := json.Marshal(fork)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{
+			{Type: ContentTypeText, Text: some(status)},
+			{Type: ContentTypeText, Text: some(string(responseJSON))},
+		},
+	}
 }
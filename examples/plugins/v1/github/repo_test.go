@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRepoName(t *testing.T) {
+	for _, name := range []string{"api", "hyper-mcp", "my_repo.v2", "A1", strings.Repeat("a", 100)} {
+		if err := repoName(name); err != nil {
+			t.Errorf("%q: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", ".", "..", "my repo", "héllo", "a/b", strings.Repeat("a", 101)} {
+		if err := repoName(name); err == nil {
+			t.Errorf("%q: expected an error", name)
+		}
+	}
+}
+
+func TestRepoCreateBody(t *testing.T) {
+	tests := []struct {
+		args          string
+		want          string
+		defaultBranch string
+		wantErr       bool
+	}{
+		{
+			args: `{"name":"api"}`,
+			want: `{"name":"api"}`,
+		},
+		{
+			args:          `{"name":"api","org":"acme","description":"The API","private":true,"auto_init":true,"gitignore_template":"Go","license_template":"mit","default_branch":"trunk"}`,
+			want:          `{"auto_init":true,"description":"The API","gitignore_template":"Go","license_template":"mit","name":"api","private":true}`,
+			defaultBranch: "trunk",
+		},
+		{
+			args:          `{"name":"api","license_template":"mit","default_branch":"trunk"}`,
+			want:          `{"license_template":"mit","name":"api"}`,
+			defaultBranch: "trunk",
+		},
+		{args: `{"name":"api","default_branch":"trunk"}`, wantErr: true},
+		{args: `{"name":"api","auto_init":false,"default_branch":"trunk"}`, wantErr: true},
+		{args: `{"name":"my api"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		body, defaultBranch, err := repoCreateBody(args)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", tt.args)
+			}
+			continue
+		}
+		got, _ := json.Marshal(body)
+		if err != nil || string(got) != tt.want || defaultBranch != tt.defaultBranch {
+			t.Errorf("%s:\n got %s %q %v\nwant %s %q", tt.args, got, defaultBranch, err, tt.want, tt.defaultBranch)
+		}
+	}
+}
+
+func TestForkBody(t *testing.T) {
+	body, err := forkBody(map[string]interface{}{"organization": "acme", "name": "api-fork", "default_branch_only": true})
+	got, _ := json.Marshal(body)
+	if err != nil || string(got) != `{"default_branch_only":true,"name":"api-fork","organization":"acme"}` {
+		t.Errorf("got %s, %v", got, err)
+	}
+	if body, err := forkBody(map[string]interface{}{}); err != nil || len(body) != 0 {
+		t.Errorf("empty: got %v, %v", body, err)
+	}
+	if _, err := forkBody(map[string]interface{}{"name": "api fork"}); err == nil {
+		t.Error("expected an invalid name to be refused")
+	}
+}
+
+func TestReposListURLs(t *testing.T) {
+	args := map[string]interface{}{"type": "member", "sort": "pushed", "direction": "desc", "per_page": float64(200), "page": float64(3)}
+	if got := reposListURL("octocat", args); got != "https://api.github.com/users/octocat/repos?type=member&sort=pushed&direction=desc&per_page=100&page=3" {
+		t.Errorf("user: got %s", got)
+	}
+	if got := reposListURL("octocat", map[string]interface{}{}); got != "https://api.github.com/users/octocat/repos?per_page=30&page=1" {
+		t.Errorf("user defaults: got %s", got)
+	}
+
+	got, err := orgReposURL("acme", map[string]interface{}{"type": "private", "sort": "full_name"})
+	if err != nil || got != "https://api.github.com/orgs/acme/repos?type=private&sort=full_name&per_page=30&page=1" {
+		t.Errorf("org: got %s, %v", got, err)
+	}
+	if _, err := orgReposURL("acme", map[string]interface{}{"type": "owner"}); err == nil || !strings.Contains(err.Error(), "all, public, private, forks, sources, member") {
+		t.Errorf("org with a user-only type: %v", err)
+	}
+}
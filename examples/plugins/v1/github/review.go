@@ -0,0 +1,331 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ReviewPullRequestChunkedTool = ToolDescription{
+		Name:        "gh-review-pr-chunked",
+		Description: "Read a large pull request diff in chunks that fit a size budget. Small files are grouped together and large files are split between hunks. Call without chunk_id to get the first chunk, then pass next_chunk_id to get the next one. Chunk ids are tied to the pull request head commit, so pushing new commits invalidates them.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":       prop("string", "The owner of the repository"),
+				"repo":        prop("string", "The repository name"),
+				"pull_number": prop("integer", "The pull request number"),
+				"chunk_id":    prop("string", "The chunk to return, as given by next_chunk_id of the previous call. Defaults to the first chunk."),
+				"chunk_bytes": prop("integer", "Maximum size of a chunk's diff in bytes (default 40000, between 2000 and 200000). Use the same value for every call."),
+			},
+			"required": []string{"owner", "repo", "pull_number"},
+		},
+	}
+)
+
+const (
+	reviewDefaultChunkBytes = 40000
+	reviewMinChunkBytes     = 2000
+	reviewMaxChunkBytes     = 200000
+	// A file header longer than the chunk leaves no room for its patch, so
+	// each part still gets at least this much of it.
+	reviewMinPatchBytes = 256
+	// GitHub lists at most 3000 files per pull request, 100 per page.
+	reviewFilePages = 30
+)
+
+// reviewPiece is one file, or one run of hunks of a file, inside a chunk.
+type reviewPiece struct {
+	File  CommitFile
+	Diff  string
+	Part  int
+	Parts int
+}
+
+type ReviewChunkFile struct {
+	Filename  string `json:"filename"`
+	Status    string `json:"status"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Part      int    `json:"part,omitempty"`
+	Parts     int    `json:"parts,omitempty"`
+}
+
+type ReviewChunk struct {
+	HeadSha        string            `json:"head_sha"`
+	ChunkID        string            `json:"chunk_id"`
+	Chunk          int               `json:"chunk"`
+	TotalChunks    int               `json:"total_chunks"`
+	NextChunkID    string            `json:"next_chunk_id,omitempty"`
+	FilesRemaining int               `json:"files_remaining"`
+	Files          []ReviewChunkFile `json:"files"`
+	Diff           string            `json:"diff"`
+}
+
+func reviewFileHeader(f CommitFile) string {
+	header := fmt.Sprintf("diff --git a/%s b/%s\n", f.Filename, f.Filename)
+	if f.PreviousName != "" {
+		header = fmt.Sprintf("diff --git a/%s b/%s\n", f.PreviousName, f.Filename)
+	}
+	return header + fmt.Sprintf("# status: %s, +%d -%d\n", f.Status, f.Additions, f.Deletions)
+}
+
+// splitPatch cuts a patch into pieces of at most budget bytes, breaking only
+// between hunks. A single hunk larger than the budget is broken between lines,
+// and a single line larger than the budget is cut on a character boundary.
+func splitPatch(patch string, budget int) []string {
+	var hunks []string
+	for _, line := range strings.SplitAfter(patch, "\n") {
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "@@") || len(hunks) == 0 {
+			hunks = append(hunks, line)
+		} else {
+			hunks[len(hunks)-1] += line
+		}
+	}
+
+	var units []string
+	for _, hunk := range hunks {
+		if len(hunk) <= budget {
+			units = append(units, hunk)
+			continue
+		}
+		for _, line := range strings.SplitAfter(hunk, "\n") {
+			for len(line) > budget {
+				cut := budget
+				for cut > 0 && !utf8.RuneStart(line[cut]) {
+					cut--
+				}
+				if cut == 0 {
+					cut = budget
+				}
+				units = append(units, line[:cut])
+				line = line[cut:]
+			}
+			if line != "" {
+				units = append(units, line)
+			}
+		}
+	}
+
+	var pieces []string
+	current := ""
+	for _, unit := range units {
+		if current != "" && len(current)+len(unit) > budget {
+			pieces = append(pieces, current)
+			current = ""
+		}
+		current += unit
+	}
+	if current != "" {
+		pieces = append(pieces, current)
+	}
+	return pieces
+}
+
+// chunkPullRequestFiles groups the files of a pull request into chunks whose
+// diffs stay within budget bytes. Files are ordered by path so files in the
+// same directory land next to each other, and the result only depends on the
+// files, so the same head commit always yields the same chunks.
+func chunkPullRequestFiles(files []CommitFile, budget int) [][]reviewPiece {
+	sorted := append([]CommitFile(nil), files...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Filename < sorted[j].Filename
+	})
+
+	var pieces []reviewPiece
+	for _, f := range sorted {
+		header := reviewFileHeader(f)
+		if f.Patch == "" {
+			pieces = append(pieces, reviewPiece{File: f, Diff: header + "# no textual diff (binary or too large)\n"})
+			continue
+		}
+		if len(header)+len(f.Patch)+1 <= budget {
+			pieces = append(pieces, reviewPiece{File: f, Diff: header + f.Patch + "\n"})
+			continue
+		}
+
+		// The 40 bytes leave room for the "# part i of n" line. A part can
+		// go over budget when the header alone nearly fills it; it then
+		// gets a chunk of its own.
+		parts := splitPatch(f.Patch, max(budget-len(header)-40, reviewMinPatchBytes))
+		for i, part := range parts {
+			diff := fmt.Sprintf("%s# part %d of %d\n%s", header, i+1, len(parts), part)
+			if !strings.HasSuffix(diff, "\n") {
+				diff += "\n"
+			}
+			pieces = append(pieces, reviewPiece{File: f, Diff: diff, Part: i + 1, Parts: len(parts)})
+		}
+	}
+
+	var chunks [][]reviewPiece
+	size := 0
+	for _, piece := range pieces {
+		if len(chunks) == 0 || size+len(piece.Diff) > budget {
+			chunks = append(chunks, nil)
+			size = 0
+		}
+		chunks[len(chunks)-1] = append(chunks[len(chunks)-1], piece)
+		size += len(piece.Diff)
+	}
+	return chunks
+}
+
+func reviewChunkID(headSha string, chunk int) string {
+	if len(headSha) > 12 {
+		headSha = headSha[:12]
+	}
+	return fmt.Sprintf("%s:%d", headSha, chunk)
+}
+
+// parseReviewChunkID splits a chunk id into the head sha prefix it was
+// issued for and the chunk index.
+func parseReviewChunkID(id string) (string, int, error) {
+	sha, index, ok := strings.Cut(id, ":")
+	n, err := strconv.Atoi(index)
+	if !ok || sha == "" || err != nil || n < 0 {
+		return "", 0, fmt.Errorf("invalid chunk_id %q, expected the next_chunk_id of a previous call", id)
+	}
+	return sha, n, nil
+}
+
+func buildReviewChunk(headSha string, chunks [][]reviewPiece, index int) ReviewChunk {
+	result := ReviewChunk{
+		HeadSha:     headSha,
+		ChunkID:     reviewChunkID(headSha, index),
+		Chunk:       index,
+		TotalChunks: len(chunks),
+		Files:       []ReviewChunkFile{},
+	}
+	if index+1 < len(chunks) {
+		result.NextChunkID = reviewChunkID(headSha, index+1)
+	}
+
+	var diff strings.Builder
+	for _, piece := range chunks[index] {
+		diff.WriteString(piece.Diff)
+		result.Files = append(result.Files, ReviewChunkFile{
+			Filename:  piece.File.Filename,
+			Status:    piece.File.Status,
+			Additions: piece.File.Additions,
+			Deletions: piece.File.Deletions,
+			Part:      piece.Part,
+			Parts:     piece.Parts,
+		})
+	}
+	result.Diff = diff.String()
+
+	// A file split across chunks counts as remaining until its last part
+	remaining := map[string]bool{}
+	for _, chunk := range chunks[index+1:] {
+		for _, piece := range chunk {
+			remaining[piece.File.Filename] = true
+		}
+	}
+	result.FilesRemaining = len(remaining)
+	return result
+}
+
+func pullRequestReviewChunked(apiKey, owner, repo string, pullNumber int, chunkID string, budget int) CallToolResult {
+	details, err := pullRequestGetDetails(apiKey, owner, repo, pullNumber)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to get pull request: %s", err)),
+			}},
+		}
+	}
+	headSha := details.Head.Sha
+
+	index := 0
+	if chunkID != "" {
+		sha, n, err := parseReviewChunkID(chunkID)
+		if err != nil {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(err.Error()),
+				}},
+			}
+		}
+		if !strings.HasPrefix(headSha, sha) {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Pull request #%d has new commits: chunk %s was issued for head %s but the head is now %s. Chunks from the old head are no longer valid; start again without chunk_id.", pullNumber, chunkID, sha, headSha)),
+				}},
+			}
+		}
+		index = n
+	}
+
+	files := []CommitFile{}
+	for page := 1; page <= reviewFilePages; page++ {
+		u := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/files?per_page=100&page=%d", owner, repo, pullNumber, page)
+		pdk.Log(pdk.LogDebug, fmt.Sprint("Fetching pull request files: ", u))
+
+		var batch []CommitFile
+		if _, err := githubGetJSON(apiKey, u, &batch); err != nil {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Failed to list pull request files: %s", err)),
+				}},
+			}
+		}
+		files = append(files, batch...)
+		if len(batch) < 100 {
+			break
+		}
+	}
+
+	chunks := chunkPullRequestFiles(files, budget)
+	if len(chunks) == 0 {
+		return CallToolResult{
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Pull request #%d has no changed files", pullNumber)),
+			}},
+		}
+	}
+	if index >= len(chunks) {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Chunk %d does not exist: pull request #%d has %d chunks at this size (0 to %d). Use the same chunk_bytes for every call.", index, pullNumber, len(chunks), len(chunks)-1)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(buildReviewChunk(headSha, chunks, index))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func hunk(start, lines int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", start, lines, start, lines)
+	for i := 0; i < lines; i++ {
+		fmt.Fprintf(&b, "+line %04d of a fairly long generated hunk\n", start+i)
+	}
+	return b.String()
+}
+
+func TestSplitPatch(t *testing.T) {
+	patch := hunk(1, 10) + hunk(100, 10) + hunk(200, 10)
+	one := len(hunk(1, 10))
+
+	pieces := splitPatch(patch, 2*one+10)
+	if len(pieces) != 2 || pieces[0] != hunk(1, 10)+hunk(100, 10) || pieces[1] != hunk(200, 10) {
+		t.Fatalf("expected hunks to be grouped up to the budget, got %q", pieces)
+	}
+
+	pieces = splitPatch(hunk(1, 40), 300)
+	if len(pieces) < 2 || strings.Join(pieces, "") != hunk(1, 40) {
+		t.Fatalf("an oversized hunk should be split between lines without losing content, got %d pieces", len(pieces))
+	}
+	for _, p := range pieces {
+		if len(p) > 300 {
+			t.Fatalf("piece over budget: %d bytes", len(p))
+		}
+		if !strings.HasSuffix(p, "\n") {
+			t.Fatalf("pieces should end on a line boundary: %q", p)
+		}
+	}
+
+	pieces = splitPatch("@@ -1 +1 @@\n+"+strings.Repeat("x", 250), 100)
+	for _, p := range pieces {
+		if len(p) > 100 {
+			t.Fatalf("a single long line should be cut to the budget, got %d bytes", len(p))
+		}
+	}
+
+	wide := "@@ -1 +1 @@\n+" + strings.Repeat("é", 200)
+	pieces = splitPatch(wide, 101)
+	for _, p := range pieces {
+		if !utf8.ValidString(p) {
+			t.Fatalf("a long line should be cut between characters, got %q", p)
+		}
+	}
+	if strings.Join(pieces, "") != wide {
+		t.Fatal("cutting a long line lost content")
+	}
+}
+
+func TestChunkPullRequestFilesLongHeader(t *testing.T) {
+	budget := reviewMinChunkBytes
+	f := CommitFile{Filename: strings.Repeat("d/", budget) + "x.go", Status: "modified", Patch: hunk(1, 60)}
+
+	chunks := chunkPullRequestFiles([]CommitFile{f}, budget)
+	var patch strings.Builder
+	for _, chunk := range chunks {
+		for _, piece := range chunk {
+			_, part, _ := strings.Cut(piece.Diff, fmt.Sprintf("# part %d of %d\n", piece.Part, piece.Parts))
+			patch.WriteString(part)
+		}
+	}
+	if got := strings.TrimSuffix(patch.String(), "\n"); got != strings.TrimSuffix(f.Patch, "\n") {
+		t.Fatalf("a header longer than the chunk should still carry the whole patch, got %d of %d bytes", len(got), len(f.Patch))
+	}
+}
+
+func TestChunkPullRequestFiles(t *testing.T) {
+	files := []CommitFile{
+		{Filename: "src/z.go", Status: "modified", Patch: hunk(1, 3)},
+		{Filename: "big.go", Status: "added", Patch: hunk(1, 60) + hunk(500, 60)},
+		{Filename: "src/a.go", Status: "modified", Patch: hunk(1, 3)},
+		{Filename: "logo.png", Status: "added"},
+	}
+	budget := 3000
+
+	chunks := chunkPullRequestFiles(files, budget)
+	var order []string
+	for i, chunk := range chunks {
+		size := 0
+		for _, piece := range chunk {
+			size += len(piece.Diff)
+			order = append(order, fmt.Sprintf("%s:%d/%d", piece.File.Filename, piece.Part, piece.Parts))
+		}
+		if size > budget {
+			t.Errorf("chunk %d is %d bytes, over the %d budget", i, size, budget)
+		}
+	}
+
+	if order[0] != "big.go:1/2" || order[1] != "big.go:2/2" || !strings.HasPrefix(order[len(order)-3], "logo.png") {
+		t.Fatalf("files should be ordered by path and big.go split by hunk, got %v", order)
+	}
+	if order[len(order)-2] != "src/a.go:0/0" || order[len(order)-1] != "src/z.go:0/0" {
+		t.Fatalf("small files should stay whole, got %v", order)
+	}
+
+	again := chunkPullRequestFiles([]CommitFile{files[3], files[2], files[1], files[0]}, budget)
+	if len(again) != len(chunks) {
+		t.Fatal("chunking should not depend on the order the API returned files in")
+	}
+	for i := range chunks {
+		if len(again[i]) != len(chunks[i]) || again[i][0].Diff != chunks[i][0].Diff {
+			t.Fatalf("chunk %d differs between runs", i)
+		}
+	}
+
+	last := chunks[len(chunks)-1]
+	if !strings.Contains(last[0].Diff, "diff --git") {
+		t.Fatalf("every piece should carry its file header: %q", last[0].Diff)
+	}
+}
+
+func TestBuildReviewChunk(t *testing.T) {
+	files := []CommitFile{
+		{Filename: "a.go", Patch: hunk(1, 60) + hunk(500, 60)},
+		{Filename: "b.go", Patch: hunk(1, 2)},
+	}
+	chunks := chunkPullRequestFiles(files, 3000)
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %d", len(chunks))
+	}
+
+	sha := "0123456789abcdef0123"
+	first := buildReviewChunk(sha, chunks, 0)
+	if first.ChunkID != "0123456789ab:0" || first.NextChunkID != "0123456789ab:1" || first.TotalChunks != len(chunks) {
+		t.Fatalf("unexpected navigation: %+v", first)
+	}
+	if first.FilesRemaining != 2 {
+		t.Fatalf("a.go is only partly shown, so both files remain, got %d", first.FilesRemaining)
+	}
+
+	last := buildReviewChunk(sha, chunks, len(chunks)-1)
+	if last.NextChunkID != "" || last.FilesRemaining != 0 {
+		t.Fatalf("the last chunk should have no next chunk: %+v", last)
+	}
+
+	gotSha, index, err := parseReviewChunkID(first.NextChunkID)
+	if err != nil || gotSha != "0123456789ab" || index != 1 {
+		t.Fatalf("chunk id did not round trip: %q %d %v", gotSha, index, err)
+	}
+	for _, bad := range []string{"", "abc", ":1", "abc:-1", "abc:x"} {
+		if _, _, err := parseReviewChunkID(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
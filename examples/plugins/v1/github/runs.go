@@ -0,0 +1,252 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ListWorkflowRunsTool = ToolDescription{
+		Name:        "gh-list-workflow-runs",
+		Description: "List GitHub Actions workflow runs of a repository, newest first, e.g. to check whether CI is green on main",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":          prop("string", "The owner of the repository"),
+				"repo":           prop("string", "The repository name"),
+				"workflow":       prop("string", "Only runs of this workflow, by id or file name, e.g. ci.yml"),
+				"branch":         prop("string", "Only runs on this branch"),
+				"event":          prop("string", "Only runs triggered by this event, e.g. push, pull_request, schedule"),
+				"status":         prop("string", "Only runs with this status or conclusion, e.g. in_progress, completed, success, failure"),
+				"actor":          prop("string", "Only runs started by this user"),
+				"created_after":  prop("string", "Only runs created on or after this ISO-8601 date or timestamp"),
+				"created_before": prop("string", "Only runs created on or before this ISO-8601 date or timestamp"),
+				"per_page":       prop("integer", "Number of results per page (max 100)"),
+				"page":           prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	ListRunJobsTool = ToolDescription{
+		Name:        "gh-list-run-jobs",
+		Description: "List the jobs of a GitHub Actions workflow run with the outcome of each of their steps, to find which step failed",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":       prop("string", "The owner of the repository"),
+				"repo":        prop("string", "The repository name"),
+				"run_id":      prop("integer", "The workflow run id"),
+				"failed_only": prop("boolean", "Only list failed jobs and their failed steps (default false)"),
+			},
+			"required": []string{"owner", "repo", "run_id"},
+		},
+	}
+)
+
+type WorkflowRunSummary struct {
+	ID         int     `json:"id"`
+	Name       string  `json:"name"`
+	HeadBranch string  `json:"head_branch"`
+	HeadSHA    string  `json:"head_sha"`
+	Status     string  `json:"status"`
+	Conclusion *string `json:"conclusion"`
+	RunNumber  int     `json:"run_number"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+type WorkflowRunsPage struct {
+	TotalCount   int                  `json:"total_count"`
+	WorkflowRuns []WorkflowRunSummary `json:"workflow_runs"`
+}
+
+// createdRange builds the created filter: from..to, >=from or <=to.
+func createdRange(after, before string) string {
+	switch {
+	case after != "" && before != "":
+		return after + ".." + before
+	case after != "":
+		return ">=" + after
+	case before != "":
+		return "<=" + before
+	}
+	return ""
+}
+
+func workflowRunsURL(owner, repo string, args map[string]interface{}) (string, error) {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/runs", owner, repo)
+	if _, ok := args["workflow"]; ok {
+		workflow, err := workflowRef(args)
+		if err != nil {
+			return "", err
+		}
+		u = fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/workflows/%s/runs", owner, repo, workflow)
+	}
+
+	params := []string{}
+	for _, key := range []string{"branch", "event", "status", "actor"} {
+		if value, _ := args[key].(string); value != "" {
+			params = append(params, fmt.Sprintf("%s=%s", key, url.QueryEscape(value)))
+		}
+	}
+	after, _ := args["created_after"].(string)
+	before, _ := args["created_before"].(string)
+	if created := createdRange(after, before); created != "" {
+		params = append(params, "created="+url.QueryEscape(created))
+	}
+	params = append(params, paginationParams(args)...)
+	return fmt.Sprintf("%s?%s", u, strings.Join(params, "&")), nil
+}
+
+func summarizeWorkflowRuns(runs []WorkflowRun) []WorkflowRunSummary {
+	summaries := make([]WorkflowRunSummary, 0, len(runs))
+	for _, r := range runs {
+		sha := r.HeadSHA
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		summaries = append(summaries, WorkflowRunSummary{
+			ID:         r.ID,
+			Name:       r.Name,
+			HeadBranch: r.HeadBranch,
+			HeadSHA:    sha,
+			Status:     r.Status,
+			Conclusion: r.Conclusion,
+			RunNumber:  r.RunNumber,
+			CreatedAt:  r.CreatedAt,
+		})
+	}
+	return summaries
+}
+
+func runsList(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	u, err := workflowRunsURL(owner, repo, args)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(err.Error()),
+			}},
+		}
+	}
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing workflow runs: ", u))
+
+	var page struct {
+		TotalCount   int           `json:"total_count"`
+		WorkflowRuns []WorkflowRun `json:"workflow_runs"`
+	}
+	if _, err := githubGetJSON(apiKey, u, &page); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list workflow runs: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(WorkflowRunsPage{
+		TotalCount:   page.TotalCount,
+		WorkflowRuns: summarizeWorkflowRuns(page.WorkflowRuns),
+	})
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+type RunStepSummary struct {
+	Number     int     `json:"number"`
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	Conclusion *string `json:"conclusion"`
+}
+
+type RunJobSummary struct {
+	ID          int              `json:"id"`
+	Name        string           `json:"name"`
+	Status      string           `json:"status"`
+	Conclusion  *string          `json:"conclusion"`
+	StartedAt   *string          `json:"started_at"`
+	CompletedAt *string          `json:"completed_at"`
+	Steps       []RunStepSummary `json:"steps"`
+}
+
+func summarizeRunJobs(jobs []WorkflowJob, failedOnly bool) []RunJobSummary {
+	summaries := []RunJobSummary{}
+	for _, j := range jobs {
+		if failedOnly && !failedConclusion(j.Conclusion) {
+			continue
+		}
+		summary := RunJobSummary{
+			ID:          j.ID,
+			Name:        j.Name,
+			Status:      j.Status,
+			Conclusion:  j.Conclusion,
+			StartedAt:   j.StartedAt,
+			CompletedAt: j.CompletedAt,
+			Steps:       []RunStepSummary{},
+		}
+		for _, step := range j.Steps {
+			if failedOnly && !failedConclusion(step.Conclusion) {
+				continue
+			}
+			summary.Steps = append(summary.Steps, RunStepSummary{
+				Number:     step.Number,
+				Name:       step.Name,
+				Status:     step.Status,
+				Conclusion: step.Conclusion,
+			})
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+func runsListJobs(apiKey, owner, repo string, runID int, args map[string]interface{}) CallToolResult {
+	jobs, err := runJobs(apiKey, owner, repo, runID)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list the jobs of run %d: %s", runID, err)),
+			}},
+		}
+	}
+
+	failedOnly, _ := args["failed_only"].(bool)
+	responseJSON, err := json.Marshal(summarizeRunJobs(jobs, failedOnly))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
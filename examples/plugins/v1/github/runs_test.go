@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWorkflowRunsURL(t *testing.T) {
+	tests := []struct {
+		args    string
+		want    string
+		wantErr bool
+	}{
+		{
+			args: `{}`,
+			want: "https://api.github.com/repos/acme/api/actions/runs?per_page=30&page=1",
+		},
+		{
+			args: `{"workflow":"ci.yml","branch":"main","event":"push","status":"failure","actor":"octocat","per_page":5}`,
+			want: "https://api.github.com/repos/acme/api/actions/workflows/ci.yml/runs?branch=main&event=push&status=failure&actor=octocat&per_page=5&page=1",
+		},
+		{
+			args: `{"created_after":"2024-05-01","created_before":"2024-05-31"}`,
+			want: "https://api.github.com/repos/acme/api/actions/runs?created=2024-05-01..2024-05-31&per_page=30&page=1",
+		},
+		{
+			args: `{"created_after":"2024-05-01T00:00:00Z"}`,
+			want: "https://api.github.com/repos/acme/api/actions/runs?created=%3E%3D2024-05-01T00%3A00%3A00Z&per_page=30&page=1",
+		},
+		{args: `{"workflow":""}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		got, err := workflowRunsURL("acme", "api", args)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%s:\n got %s, %v\nwant %s", tt.args, got, err, tt.want)
+		}
+	}
+}
+
+func TestSummarizeWorkflowRuns(t *testing.T) {
+	success := "success"
+	got := summarizeWorkflowRuns([]WorkflowRun{
+		{ID: 1, Name: "CI", HeadBranch: "main", HeadSHA: "0123456789abcdef", Status: "completed", Conclusion: &success, RunNumber: 12, CreatedAt: "2024-05-01T00:00:00Z", HTMLURL: "https://github.com/acme/api/actions/runs/1"},
+	})
+	out, _ := json.Marshal(got)
+	want := `[{"id":1,"name":"CI","head_branch":"main","head_sha":"0123456","status":"completed","conclusion":"success","run_number":12,"created_at":"2024-05-01T00:00:00Z"}]`
+	if string(out) != want {
+		t.Errorf("\n got %s\nwant %s", out, want)
+	}
+}
+
+func TestSummarizeRunJobs(t *testing.T) {
+	var jobs []WorkflowJob
+	err := json.Unmarshal([]byte(`[
+		{"id":1,"name":"build","status":"completed","conclusion":"success","started_at":"2024-05-01T00:00:00Z","completed_at":"2024-05-01T00:02:00Z",
+		 "steps":[{"number":1,"name":"Checkout","status":"completed","conclusion":"success"}]},
+		{"id":2,"name":"test","status":"completed","conclusion":"failure","started_at":"2024-05-01T00:00:00Z","completed_at":"2024-05-01T00:05:00Z",
+		 "steps":[{"number":1,"name":"Checkout","status":"completed","conclusion":"success"},{"number":2,"name":"Run tests","status":"completed","conclusion":"failure"},{"number":3,"name":"Upload","status":"completed","conclusion":"skipped"}]}
+	]`), &jobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	all := summarizeRunJobs(jobs, false)
+	if len(all) != 2 || len(all[1].Steps) != 3 {
+		t.Errorf("expected every job and step, got %+v", all)
+	}
+
+	out, _ := json.Marshal(summarizeRunJobs(jobs, true))
+	want := `[{"id":2,"name":"test","status":"completed","conclusion":"failure","started_at":"2024-05-01T00:00:00Z","completed_at":"2024-05-01T00:05:00Z","steps":[{"number":2,"name":"Run tests","status":"completed","conclusion":"failure"}]}]`
+	if string(out) != want {
+		t.Errorf("\n got %s\nwant %s", out, want)
+	}
+}
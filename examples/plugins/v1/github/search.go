@@ -0,0 +1,476 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	SearchIssuesTool = ToolDescription{
+		Name:        "gh-search-issues",
+		Description: "Search issues and pull requests across repositories, e.g. every open issue assigned to you in an organization. Takes GitHub's search syntax in query, plus shortcuts that are added as qualifiers.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"query":    prop("string", "Search terms and qualifiers in GitHub syntax, e.g. \"memory leak org:acme no:assignee\""),
+				"repo":     prop("string", "Only search this repository, as owner/name"),
+				"org":      prop("string", "Only search repositories of this organization or user"),
+				"author":   prop("string", "Only items opened by this user"),
+				"assignee": prop("string", "Only items assigned to this user, or @me"),
+				"label":    prop("string", "Only items with this label. Separate several labels with commas; all must match."),
+				"state":    prop("string", "open or closed"),
+				"is":       prop("string", "issue or pr"),
+				"created":  prop("string", "Creation date or range, e.g. >=2024-01-01 or 2024-01-01..2024-03-31"),
+				"updated":  prop("string", "Last update date or range, same format as created"),
+				"sort":     prop("string", "Sort by comments, reactions, created or updated (default: best match)"),
+				"order":    prop("string", "asc or desc (default desc)"),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+		},
+	}
+	SearchUsersTool = ToolDescription{
+		Name:        "gh-search-users",
+		Description: "Search GitHub users and organizations, e.g. to find someone's handle from part of their name. Takes GitHub's user search syntax in query.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"query":    prop("string", "Name, login or email fragment, optionally with qualifiers, e.g. \"jane doe type:user location:berlin\""),
+				"sort":     prop("string", "Sort by followers, repositories or joined (default: best match)"),
+				"order":    prop("string", "asc or desc (default desc)"),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"query"},
+		},
+	}
+	SearchCommitsTool = ToolDescription{
+		Name:        "gh-search-commits",
+		Description: "Search commit messages across repositories, e.g. to find the commit that introduced a feature or a fix. Takes GitHub's commit search syntax in query, plus shortcuts that are added as qualifiers.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"query":          prop("string", "Words from the commit message, optionally with qualifiers, e.g. \"retry backoff merge:false\""),
+				"repo":           prop("string", "Only search this repository, as owner/name"),
+				"org":            prop("string", "Only search repositories of this organization or user"),
+				"author":         prop("string", "Only commits authored by this user"),
+				"committer_date": prop("string", "Commit date or range, e.g. >=2024-01-01 or 2024-01-01..2024-03-31"),
+				"sort":           prop("string", "Sort by author-date or committer-date (default: best match)"),
+				"order":          prop("string", "asc or desc (default desc)"),
+				"per_page":       prop("integer", "Number of results per page (max 100)"),
+				"page":           prop("integer", "Page number for pagination"),
+			},
+		},
+	}
+	SearchTools = []ToolDescription{
+		SearchIssuesTool,
+		SearchUsersTool,
+		SearchCommitsTool,
+	}
+)
+
+// searchQualifier quotes values with spaces, as GitHub's syntax requires.
+func searchQualifier(name, value string) string {
+	if strings.ContainsAny(value, " \t") {
+		value = fmt.Sprintf("%q", value)
+	}
+	return name + ":" + value
+}
+
+// issueSearchQuery combines the free text query with the shortcut
+// arguments into one search string.
+func issueSearchQuery(args map[string]interface{}) (string, error) {
+	var parts []string
+	if query, _ := args["query"].(string); strings.TrimSpace(query) != "" {
+		parts = append(parts, strings.TrimSpace(query))
+	}
+
+	for _, key := range []string{"repo", "org", "author", "assignee", "created", "updated"} {
+		value, _ := args[key].(string)
+		if value == "" {
+			continue
+		}
+		if key == "repo" && strings.Count(value, "/") != 1 {
+			return "", fmt.Errorf("repo must be owner/name, got %q", value)
+		}
+		parts = append(parts, searchQualifier(key, value))
+	}
+
+	if labels, _ := args["label"].(string); labels != "" {
+		for _, label := range strings.Split(labels, ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				parts = append(parts, searchQualifier("label", label))
+			}
+		}
+	}
+
+	if state, _ := args["state"].(string); state != "" {
+		if state != "open" && state != "closed" {
+			return "", fmt.Errorf("state must be open or closed, got %q", state)
+		}
+		parts = append(parts, "state:"+state)
+	}
+	if is, _ := args["is"].(string); is != "" {
+		if is != "issue" && is != "pr" {
+			return "", fmt.Errorf("is must be issue or pr, got %q", is)
+		}
+		parts = append(parts, "is:"+is)
+	}
+
+	if len(parts) == 0 {
+		return "", fmt.Errorf("give a query or at least one of repo, org, author, assignee, label, state, is, created or updated")
+	}
+	return strings.Join(parts, " "), nil
+}
+
+type IssueSearchItem struct {
+	Number        int    `json:"number"`
+	Title         string `json:"title"`
+	State         string `json:"state"`
+	HTMLURL       string `json:"html_url"`
+	UpdatedAt     string `json:"updated_at"`
+	RepositoryURL string `json:"repository_url"`
+	PullRequest   *struct {
+		URL string `json:"url"`
+	} `json:"pull_request"`
+}
+
+type IssueSearchResult struct {
+	Number        int    `json:"number"`
+	Title         string `json:"title"`
+	State         string `json:"state"`
+	Repository    string `json:"repository"`
+	UpdatedAt     string `json:"updated_at"`
+	IsPullRequest bool   `json:"is_pull_request"`
+	URL           string `json:"url"`
+}
+
+type IssueSearchPage struct {
+	Query             string              `json:"query"`
+	TotalCount        int                 `json:"total_count"`
+	IncompleteResults bool                `json:"incomplete_results"`
+	Items             []IssueSearchResult `json:"items"`
+}
+
+func summarizeIssueSearch(query string, total int, incomplete bool, items []IssueSearchItem) IssueSearchPage {
+	page := IssueSearchPage{
+		Query:             query,
+		TotalCount:        total,
+		IncompleteResults: incomplete,
+		Items:             make([]IssueSearchResult, 0, len(items)),
+	}
+	for _, item := range items {
+		page.Items = append(page.Items, IssueSearchResult{
+			Number:        item.Number,
+			Title:         item.Title,
+			State:         item.State,
+			Repository:    strings.TrimPrefix(item.RepositoryURL, "https://api.github.com/repos/"),
+			UpdatedAt:     item.UpdatedAt,
+			IsPullRequest: item.PullRequest != nil,
+			URL:           item.HTMLURL,
+		})
+	}
+	return page
+}
+
+// issueSearchSorts are the sorts the issue search endpoint accepts.
+var issueSearchSorts = []string{
+	"comments", "reactions", "reactions-+1", "reactions--1", "reactions-smile", "reactions-thinking_face",
+	"reactions-heart", "reactions-tada", "interactions", "created", "updated",
+}
+
+func searchIssues(apiKey string, args map[string]interface{}) CallToolResult {
+	query, err := issueSearchQuery(args)
+	var sortParams []string
+	if err == nil {
+		sortParams, err = searchSortParams(args, issueSearchSorts...)
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid search: %s", err)),
+			}},
+		}
+	}
+
+	params := append([]string{"q=" + url.QueryEscape(query)}, paginationParams(args)...)
+	params = append(params, sortParams...)
+	u := fmt.Sprint("https://api.github.com/search/issues?", strings.Join(params, "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Searching issues: ", u))
+
+	var result struct {
+		TotalCount        int               `json:"total_count"`
+		IncompleteResults bool              `json:"incomplete_results"`
+		Items             []IssueSearchItem `json:"items"`
+	}
+	if _, err := githubGetJSON(apiKey, u, &result); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to search issues: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(summarizeIssueSearch(query, result.TotalCount, result.IncompleteResults, result.Items))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+// searchSortParams validates sort against the values the search endpoint
+// accepts and returns the sort and order parameters to add.
+func searchSortParams(args map[string]interface{}, sorts ...string) ([]string, error) {
+	var params []string
+	if sort, _ := args["sort"].(string); sort != "" {
+		valid := false
+		for _, s := range sorts {
+			valid = valid || sort == s
+		}
+		if !valid {
+			return nil, fmt.Errorf("sort must be one of %s, got %q", strings.Join(sorts, ", "), sort)
+		}
+		params = append(params, "sort="+url.QueryEscape(sort))
+	}
+	if order, _ := args["order"].(string); order != "" {
+		if order != "asc" && order != "desc" {
+			return nil, fmt.Errorf("order must be asc or desc, got %q", order)
+		}
+		params = append(params, "order="+order)
+	}
+	return params, nil
+}
+
+type UserSearchResult struct {
+	Login   string  `json:"login"`
+	Type    string  `json:"type"`
+	HTMLURL string  `json:"html_url"`
+	Score   float64 `json:"score"`
+}
+
+type UserSearchPage struct {
+	Query             string             `json:"query"`
+	TotalCount        int                `json:"total_count"`
+	IncompleteResults bool               `json:"incomplete_results"`
+	Items             []UserSearchResult `json:"items"`
+}
+
+func searchUsers(apiKey string, args map[string]interface{}) CallToolResult {
+	query, _ := args["query"].(string)
+	query = strings.TrimSpace(query)
+	sortParams, err := searchSortParams(args, "followers", "repositories", "joined")
+	if err == nil && query == "" {
+		err = fmt.Errorf("query is required")
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid search: %s", err)),
+			}},
+		}
+	}
+
+	params := append([]string{"q=" + url.QueryEscape(query)}, paginationParams(args)...)
+	params = append(params, sortParams...)
+	u := fmt.Sprint("https://api.github.com/search/users?", strings.Join(params, "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Searching users: ", u))
+
+	page := UserSearchPage{Query: query}
+	if _, err := githubGetJSON(apiKey, u, &page); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to search users: %s", err)),
+			}},
+		}
+	}
+	if page.Items == nil {
+		page.Items = []UserSearchResult{}
+	}
+
+	responseJSON, err := json.Marshal(page)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+// commitSearchQuery combines the free text query with the shortcut
+// arguments into one commit search string.
+func commitSearchQuery(args map[string]interface{}) (string, error) {
+	var parts []string
+	if query, _ := args["query"].(string); strings.TrimSpace(query) != "" {
+		parts = append(parts, strings.TrimSpace(query))
+	}
+	qualifiers := []struct{ arg, name string }{
+		{"repo", "repo"},
+		{"org", "org"},
+		{"author", "author"},
+		{"committer_date", "committer-date"},
+	}
+	for _, q := range qualifiers {
+		value, _ := args[q.arg].(string)
+		if value == "" {
+			continue
+		}
+		if q.arg == "repo" && strings.Count(value, "/") != 1 {
+			return "", fmt.Errorf("repo must be owner/name, got %q", value)
+		}
+		parts = append(parts, searchQualifier(q.name, value))
+	}
+	if len(parts) == 0 {
+		return "", fmt.Errorf("give a query or at least one of repo, org, author or committer_date")
+	}
+	return strings.Join(parts, " "), nil
+}
+
+type CommitSearchItem struct {
+	Sha     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name string `json:"name"`
+			Date string `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+	Author *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+type CommitSearchResult struct {
+	Sha        string `json:"sha"`
+	Repository string `json:"repository"`
+	Author     string `json:"author"`
+	Date       string `json:"date"`
+	Subject    string `json:"subject"`
+	URL        string `json:"url"`
+}
+
+type CommitSearchPage struct {
+	Query             string               `json:"query"`
+	TotalCount        int                  `json:"total_count"`
+	IncompleteResults bool                 `json:"incomplete_results"`
+	Items             []CommitSearchResult `json:"items"`
+}
+
+// summarizeCommitSearch keeps the first line of each message. The author is
+// the GitHub login when the commit email maps to an account, the git author
+// name otherwise.
+func summarizeCommitSearch(query string, total int, incomplete bool, items []CommitSearchItem) CommitSearchPage {
+	page := CommitSearchPage{
+		Query:             query,
+		TotalCount:        total,
+		IncompleteResults: incomplete,
+		Items:             make([]CommitSearchResult, 0, len(items)),
+	}
+	for _, item := range items {
+		author := item.Commit.Author.Name
+		if item.Author != nil && item.Author.Login != "" {
+			author = item.Author.Login
+		}
+		page.Items = append(page.Items, CommitSearchResult{
+			Sha:        item.Sha,
+			Repository: item.Repository.FullName,
+			Author:     author,
+			Date:       item.Commit.Author.Date,
+			Subject:    strings.TrimSpace(strings.SplitN(item.Commit.Message, "\n", 2)[0]),
+			URL:        item.HTMLURL,
+		})
+	}
+	return page
+}
+
+func searchCommits(apiKey string, args map[string]interface{}) CallToolResult {
+	query, err := commitSearchQuery(args)
+	var sortParams []string
+	if err == nil {
+		sortParams, err = searchSortParams(args, "author-date", "committer-date")
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid search: %s", err)),
+			}},
+		}
+	}
+
+	params := append([]string{"q=" + url.QueryEscape(query)}, paginationParams(args)...)
+	params = append(params, sortParams...)
+	u := fmt.Sprint("https://api.github.com/search/commits?", strings.Join(params, "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Searching commits: ", u))
+
+	var result struct {
+		TotalCount        int                `json:"total_count"`
+		IncompleteResults bool               `json:"incomplete_results"`
+		Items             []CommitSearchItem `json:"items"`
+	}
+	if _, err := githubGetJSON(apiKey, u, &result); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to search commits: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(summarizeCommitSearch(query, result.TotalCount, result.IncompleteResults, result.Items))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestIssueSearchQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "free text only",
+			args: `{"query":"  memory leak "}`,
+			want: "memory leak",
+		},
+		{
+			name: "shortcuts become qualifiers",
+			args: `{"query":"crash","org":"acme","assignee":"@me","state":"open","is":"issue","updated":">=2024-01-01"}`,
+			want: "crash org:acme assignee:@me updated:>=2024-01-01 state:open is:issue",
+		},
+		{
+			name: "labels are split and quoted",
+			args: `{"repo":"acme/api","label":"bug, good first issue"}`,
+			want: `repo:acme/api label:bug label:"good first issue"`,
+		},
+		{
+			name: "date range",
+			args: `{"author":"octocat","created":"2024-01-01..2024-03-31","is":"pr"}`,
+			want: "author:octocat created:2024-01-01..2024-03-31 is:pr",
+		},
+		{name: "nothing to search", args: `{"per_page":10}`, wantErr: "give a query"},
+		{name: "bad repo", args: `{"repo":"acme"}`, wantErr: "owner/name"},
+		{name: "bad state", args: `{"query":"x","state":"merged"}`, wantErr: "state must be open or closed"},
+		{name: "bad kind", args: `{"query":"x","is":"discussion"}`, wantErr: "is must be issue or pr"},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		got, err := issueSearchQuery(args)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error %v, want one containing %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestSummarizeIssueSearch(t *testing.T) {
+	var items []IssueSearchItem
+	fixture := `[
+		{"number":12,"title":"Leak in parser","state":"open","html_url":"https://github.com/acme/api/issues/12","updated_at":"2024-03-01T10:00:00Z","repository_url":"https://api.github.com/repos/acme/api"},
+		{"number":7,"title":"Fix leak","state":"closed","html_url":"https://github.com/acme/web/pull/7","updated_at":"2024-02-01T10:00:00Z","repository_url":"https://api.github.com/repos/acme/web","pull_request":{"url":"https://api.github.com/repos/acme/web/pulls/7"}}
+	]`
+	if err := json.Unmarshal([]byte(fixture), &items); err != nil {
+		t.Fatal(err)
+	}
+	out, _ := json.Marshal(summarizeIssueSearch("leak org:acme", 2, false, items))
+	want := `{"query":"leak org:acme","total_count":2,"incomplete_results":false,"items":[` +
+		`{"number":12,"title":"Leak in parser","state":"open","repository":"acme/api","updated_at":"2024-03-01T10:00:00Z","is_pull_request":false,"url":"https://github.com/acme/api/issues/12"},` +
+		`{"number":7,"title":"Fix leak","state":"closed","repository":"acme/web","updated_at":"2024-02-01T10:00:00Z","is_pull_request":true,"url":"https://github.com/acme/web/pull/7"}]}`
+	if string(out) != want {
+		t.Errorf("got  %s\nwant %s", out, want)
+	}
+}
+
+func TestSearchSortParams(t *testing.T) {
+	tests := []struct {
+		args    string
+		want    string
+		wantErr string
+	}{
+		{args: `{}`, want: ""},
+		{args: `{"sort":"followers","order":"asc"}`, want: "sort=followers&order=asc"},
+		{args: `{"sort":"stars"}`, wantErr: "sort must be one of followers, repositories, joined"},
+		{args: `{"order":"up"}`, wantErr: "order must be asc or desc"},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		got, err := searchSortParams(args, "followers", "repositories", "joined")
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error %v, want one containing %q", tt.args, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || strings.Join(got, "&") != tt.want {
+			t.Errorf("%s: got %q, %v, want %q", tt.args, got, err, tt.want)
+		}
+	}
+}
+
+func TestIssueSearchSorts(t *testing.T) {
+	got, err := searchSortParams(map[string]interface{}{"sort": "reactions-+1", "order": "desc"}, issueSearchSorts...)
+	if err != nil || strings.Join(got, "&") != "sort=reactions-%2B1&order=desc" {
+		t.Errorf("got %q, %v", got, err)
+	}
+	if _, err := searchSortParams(map[string]interface{}{"sort": "stars"}, issueSearchSorts...); err == nil || !strings.Contains(err.Error(), "sort must be one of comments, reactions") {
+		t.Errorf("invalid sort: %v", err)
+	}
+}
+
+func TestUserSearchPageKeepsSummaryFields(t *testing.T) {
+	fixture := `{"total_count":2,"incomplete_results":false,"items":[
+		{"login":"janedoe","id":1,"type":"User","html_url":"https://github.com/janedoe","score":1.0,"avatar_url":"https://avatars.githubusercontent.com/u/1"},
+		{"login":"doe-labs","id":2,"type":"Organization","html_url":"https://github.com/doe-labs","score":0.5}
+	]}`
+	page := UserSearchPage{Query: "doe"}
+	if err := json.Unmarshal([]byte(fixture), &page); err != nil {
+		t.Fatal(err)
+	}
+	out, _ := json.Marshal(page)
+	want := `{"query":"doe","total_count":2,"incomplete_results":false,"items":[` +
+		`{"login":"janedoe","type":"User","html_url":"https://github.com/janedoe","score":1},` +
+		`{"login":"doe-labs","type":"Organization","html_url":"https://github.com/doe-labs","score":0.5}]}`
+	if string(out) != want {
+		t.Errorf("got  %s\nwant %s", out, want)
+	}
+}
+
+func TestCommitSearchQuery(t *testing.T) {
+	tests := []struct {
+		args    string
+		want    string
+		wantErr string
+	}{
+		{args: `{"query":"retry backoff"}`, want: "retry backoff"},
+		{
+			args: `{"query":"fix leak","repo":"acme/api","author":"octocat","committer_date":"2024-01-01..2024-03-31"}`,
+			want: "fix leak repo:acme/api author:octocat committer-date:2024-01-01..2024-03-31",
+		},
+		{args: `{"org":"acme","committer_date":">=2024-06-01"}`, want: "org:acme committer-date:>=2024-06-01"},
+		{args: `{"page":2}`, wantErr: "give a query"},
+		{args: `{"query":"x","repo":"acme"}`, wantErr: "owner/name"},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		got, err := commitSearchQuery(args)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error %v, want one containing %q", tt.args, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %q, %v, want %q", tt.args, got, err, tt.want)
+		}
+	}
+}
+
+func TestSummarizeCommitSearch(t *testing.T) {
+	var items []CommitSearchItem
+	fixture := `[
+		{"sha":"abc123","html_url":"https://github.com/acme/api/commit/abc123","commit":{"message":"Add retry backoff\n\nRetries used a fixed delay.","author":{"name":"Mona","date":"2024-03-01T10:00:00Z"}},"author":{"login":"octocat"},"repository":{"full_name":"acme/api"}},
+		{"sha":"def456","html_url":"https://github.com/acme/web/commit/def456","commit":{"message":"Tune backoff","author":{"name":"Unlinked Dev","date":"2024-02-01T10:00:00Z"}},"author":null,"repository":{"full_name":"acme/web"}}
+	]`
+	if err := json.Unmarshal([]byte(fixture), &items); err != nil {
+		t.Fatal(err)
+	}
+	out, _ := json.Marshal(summarizeCommitSearch("backoff org:acme", 2, false, items))
+	want := `{"query":"backoff org:acme","total_count":2,"incomplete_results":false,"items":[` +
+		`{"sha":"abc123","repository":"acme/api","author":"octocat","date":"2024-03-01T10:00:00Z","subject":"Add retry backoff","url":"https://github.com/acme/api/commit/abc123"},` +
+		`{"sha":"def456","repository":"acme/web","author":"Unlinked Dev","date":"2024-02-01T10:00:00Z","subject":"Tune backoff","url":"https://github.com/acme/web/commit/def456"}]}`
+	if string(out) != want {
+		t.Errorf("got  %s\nwant %s", out, want)
+	}
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/extism/go-pdk"
+	"golang.org/x/crypto/nacl/box"
+)
+
+var (
+	ListActionsSecretsTool = ToolDescription{
+		Name:        "gh-list-actions-secrets",
+		Description: "List the names of a repository's GitHub Actions secrets. Secret values can't be read back.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":    prop("string", "The owner of the repository"),
+				"repo":     prop("string", "The repository name"),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	SetActionsSecretTool = ToolDescription{
+		Name:        "gh-set-actions-secret",
+		Description: "Create or update a GitHub Actions secret of a repository. The value is encrypted before it leaves the plugin.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner": prop("string", "The owner of the repository"),
+				"repo":  prop("string", "The repository name"),
+				"name":  prop("string", "The secret name: letters, digits and underscores, not starting with a digit or GITHUB_"),
+				"value": prop("string", "The secret value"),
+			},
+			"required": []string{"owner", "repo", "name", "value"},
+		},
+	}
+	ListActionsVariablesTool = ToolDescription{
+		Name:        "gh-list-actions-variables",
+		Description: "List a repository's GitHub Actions variables with their values",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":    prop("string", "The owner of the repository"),
+				"repo":     prop("string", "The repository name"),
+				"per_page": prop("integer", "Number of results per page (max 30)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	SetActionsVariableTool = ToolDescription{
+		Name:        "gh-set-actions-variable",
+		Description: "Create or update a GitHub Actions variable of a repository",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner": prop("string", "The owner of the repository"),
+				"repo":  prop("string", "The repository name"),
+				"name":  prop("string", "The variable name: letters, digits and underscores, not starting with a digit or GITHUB_"),
+				"value": prop("string", "The variable value"),
+			},
+			"required": []string{"owner", "repo", "name", "value"},
+		},
+	}
+)
+
+// GitHub's limit on the size of a secret value
+const secretValueMax = 48 * 1024
+
+var actionsNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type ActionsSecret struct {
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type ActionsVariable struct {
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type ActionsPublicKey struct {
+	KeyID string `json:"key_id"`
+	Key   string `json:"key"`
+}
+
+// actionsName checks a secret or variable name against GitHub's rules.
+// GitHub stores names in upper case.
+func actionsName(name string) (string, error) {
+	if !actionsNamePattern.MatchString(name) {
+		return "", fmt.Errorf("name may only contain letters, digits and underscores and must not start with a digit, got %q", name)
+	}
+	name = strings.ToUpper(name)
+	if strings.HasPrefix(name, "GITHUB_") {
+		return "", fmt.Errorf("names starting with GITHUB_ are reserved, got %q", name)
+	}
+	return name, nil
+}
+
+// sealSecret encrypts value for the repository's public key the way GitHub
+// expects: a libsodium sealed box, base64 encoded.
+func sealSecret(publicKey, value string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(raw) != 32 {
+		return "", fmt.Errorf("repository public key is not a base64 Curve25519 key")
+	}
+	var key [32]byte
+	copy(key[:], raw)
+	sealed, err := box.SealAnonymous(nil, []byte(value), &key, rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func actionsListSecrets(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/secrets?%s", owner, repo, strings.Join(paginationParams(args), "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing secrets: ", u))
+
+	var response struct {
+		TotalCount int             `json:"total_count"`
+		Secrets    []ActionsSecret `json:"secrets"`
+	}
+	if _, err := githubGetJSON(apiKey, u, &response); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list secrets: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+// actionsSetSecret never includes the value in what it returns, including
+// errors.
+func actionsSetSecret(apiKey, owner, repo, name, value string) CallToolResult {
+	failed := func(message string) CallToolResult {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(message),
+			}},
+		}
+	}
+	name, err := actionsName(name)
+	if err == nil && value == "" {
+		err = fmt.Errorf("value must not be empty")
+	}
+	if err == nil && len(value) > secretValueMax {
+		err = fmt.Errorf("value must be at most %d bytes", secretValueMax)
+	}
+	if err != nil {
+		return failed(fmt.Sprintf("Invalid secret: %s", err))
+	}
+
+	base := fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/secrets", owner, repo)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting public key: ", base, "/public-key"))
+	var key ActionsPublicKey
+	if _, err := githubGetJSON(apiKey, base+"/public-key", &key); err != nil {
+		return failed(fmt.Sprintf("Failed to get the repository public key: %s", err))
+	}
+	encrypted, err := sealSecret(key.Key, value)
+	if err != nil {
+		return failed(fmt.Sprintf("Failed to encrypt secret: %s", err))
+	}
+
+	u := fmt.Sprintf("%s/%s", base, name)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Setting secret: ", u))
+	status, body, err := githubSend(apiKey, pdk.MethodPut, u, map[string]string{
+		"encrypted_value": encrypted,
+		"key_id":          key.KeyID,
+	})
+	if err != nil {
+		return failed(fmt.Sprintf("Failed to set secret %s: %s", name, err))
+	}
+	var message string
+	switch status {
+	case 201:
+		message = fmt.Sprintf("Created secret %s in %s/%s", name, owner, repo)
+	case 204:
+		message = fmt.Sprintf("Updated secret %s in %s/%s", name, owner, repo)
+	default:
+		return failed(fmt.Sprintf("Failed to set secret %s: %s", name, githubStatusError(status, body)))
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(message),
+		}},
+	}
+}
+
+func actionsListVariables(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/variables?%s", owner, repo, strings.Join(paginationParams(args), "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing variables: ", u))
+
+	var response struct {
+		TotalCount int               `json:"total_count"`
+		Variables  []ActionsVariable `json:"variables"`
+	}
+	if _, err := githubGetJSON(apiKey, u, &response); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list variables: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+// actionsSetVariable updates the variable, and creates it when the update
+// finds none.
+func actionsSetVariable(apiKey, owner, repo, name, value string) CallToolResult {
+	failed := func(message string) CallToolResult {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(message),
+			}},
+		}
+	}
+	name, err := actionsName(name)
+	if err != nil {
+		return failed(fmt.Sprintf("Invalid variable: %s", err))
+	}
+
+	base := fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/variables", owner, repo)
+	variable := map[string]string{"name": name, "value": value}
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Updating variable: ", base, "/", name))
+	status, body, err := githubSend(apiKey, pdk.MethodPatch, fmt.Sprintf("%s/%s", base, name), variable)
+	message := fmt.Sprintf("Updated variable %s in %s/%s", name, owner, repo)
+	if err == nil && status == 404 {
+		pdk.Log(pdk.LogDebug, fmt.Sprint("Creating variable: ", base))
+		status, body, err = githubSend(apiKey, pdk.MethodPost, base, variable)
+		message = fmt.Sprintf("Created variable %s in %s/%s", name, owner, repo)
+	}
+	if err != nil {
+		return failed(fmt.Sprintf("Failed to set variable %s: %s", name, err))
+	}
+	if status != 201 && status != 204 {
+		return failed(fmt.Sprintf("Failed to set variable %s: %s", name, githubStatusError(status, body)))
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(message),
+		}},
+	}
+}
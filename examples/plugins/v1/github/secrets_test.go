@@ -0,0 +1,52 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"golang.org/x/crypto/nacl/box"
+)
+
+func TestActionsName(t *testing.T) {
+	for name, want := range map[string]string{
+		"deploy_token": "DEPLOY_TOKEN",
+		"_PRIVATE":     "_PRIVATE",
+		"NPM_TOKEN2":   "NPM_TOKEN2",
+	} {
+		if got, err := actionsName(name); err != nil || got != want {
+			t.Errorf("%q: got %q, %v", name, got, err)
+		}
+	}
+	for _, name := range []string{"", "2FA", "MY-SECRET", "github_token", "has space"} {
+		if _, err := actionsName(name); err == nil {
+			t.Errorf("%q: expected an error", name)
+		}
+	}
+}
+
+func TestSealSecret(t *testing.T) {
+	public, private, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := sealSecret(base64.StdEncoding.EncodeToString(public[:]), "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opened, ok := box.OpenAnonymous(nil, raw, public, private)
+	if !ok || string(opened) != "hunter2" {
+		t.Errorf("got %q, %v", opened, ok)
+	}
+
+	if _, err := sealSecret("not a key", "hunter2"); err == nil {
+		t.Error("expected an error for a malformed key")
+	}
+	if _, err := sealSecret(base64.StdEncoding.EncodeToString([]byte("short")), "hunter2"); err == nil {
+		t.Error("expected an error for a short key")
+	}
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	StarRepoTool = ToolDescription{
+		Name:        "gh-star-repo",
+		Description: "Star a repository as the authenticated user",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner": prop("string", "The owner of the repository"),
+				"repo":  prop("string", "The repository name"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	UnstarRepoTool = ToolDescription{
+		Name:        "gh-unstar-repo",
+		Description: "Remove the authenticated user's star from a repository",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner": prop("string", "The owner of the repository"),
+				"repo":  prop("string", "The repository name"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	ListStarredTool = ToolDescription{
+		Name:        "gh-list-starred",
+		Description: "List the repositories a user has starred",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"username":  prop("string", "The GitHub username (default: the authenticated user)"),
+				"sort":      prop("string", "created (when starred) or updated (when last pushed); default created"),
+				"direction": prop("string", "The sort direction (asc or desc)"),
+				"per_page":  prop("integer", "Number of results per page (max 100)"),
+				"page":      prop("integer", "Page number for pagination"),
+			},
+		},
+	}
+	ListStargazersTool = ToolDescription{
+		Name:        "gh-list-stargazers",
+		Description: "List who starred a repository and when, with the number of stars per week. Use recent_pages to see the latest stargazers.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":        prop("string", "The owner of the repository"),
+				"repo":         prop("string", "The repository name"),
+				"recent_pages": prop("integer", "Read this many pages of 100 from the most recent stars instead of page (at most 10)"),
+				"per_page":     prop("integer", "Number of results per page (max 100)"),
+				"page":         prop("integer", "Page number for pagination, oldest stars first"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	ListWatchersTool = ToolDescription{
+		Name:        "gh-list-watchers",
+		Description: "List the users watching a repository",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":    prop("string", "The owner of the repository"),
+				"repo":     prop("string", "The repository name"),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+)
+
+const (
+	stargazersMaxRecentPages = 10
+	// GitHub doesn't list stargazers past this page of 100
+	stargazersLastPage = 400
+)
+
+type StarredRepository struct {
+	FullName    string  `json:"full_name"`
+	Description *string `json:"description"`
+	Stars       int     `json:"stargazers_count"`
+	PushedAt    string  `json:"pushed_at"`
+}
+
+func starredURL(args map[string]interface{}) string {
+	u := "https://api.github.com/user/starred"
+	if username, _ := args["username"].(string); username != "" {
+		u = fmt.Sprintf("https://api.github.com/users/%s/starred", username)
+	}
+	params := []string{}
+	for _, key := range []string{"sort", "direction"} {
+		if value, _ := args[key].(string); value != "" {
+			params = append(params, fmt.Sprintf("%s=%s", key, value))
+		}
+	}
+	params = append(params, paginationParams(args)...)
+	return fmt.Sprintf("%s?%s", u, strings.Join(params, "&"))
+}
+
+// reposSetStarred stars or unstars the repository. GitHub answers 204
+// without a body, so the result describes what was done.
+func reposSetStarred(apiKey, owner, repo string, starred bool) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/user/starred/%s/%s", owner, repo)
+	method, action := pdk.MethodDelete, "unstar"
+	if starred {
+		method, action = pdk.MethodPut, "star"
+	}
+	pdk.Log(pdk.LogDebug, fmt.Sprintf("Repository %s: %s", action, u))
+	req := githubRequest(apiKey, method, u, githubMediaType)
+	if starred {
+		req.SetHeader("Content-Length", "0")
+	}
+
+	resp := req.Send()
+	if resp.Status() != 204 {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to %s %s/%s: %s", action, owner, repo, githubStatusError(resp.Status(), resp.Body()))),
+			}},
+		}
+	}
+
+	message := fmt.Sprintf("Starred %s/%s", owner, repo)
+	if !starred {
+		message = fmt.Sprintf("Unstarred %s/%s", owner, repo)
+	}
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(message),
+		}},
+	}
+}
+
+func reposListStarred(apiKey string, args map[string]interface{}) CallToolResult {
+	u := starredURL(args)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing starred repositories: ", u))
+
+	repos := []StarredRepository{}
+	if _, err := githubGetJSON(apiKey, u, &repos); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list starred repositories: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(repos)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+type Stargazer struct {
+	Login     string `json:"login"`
+	StarredAt string `json:"starred_at"`
+}
+
+type WeekStars struct {
+	// Monday of the week, as YYYY-MM-DD
+	Week  string `json:"week"`
+	Stars int    `json:"stars"`
+}
+
+type StargazersSummary struct {
+	TotalStars int         `json:"total_stars,omitempty"`
+	PerWeek    []WeekStars `json:"per_week"`
+	Stargazers []Stargazer `json:"stargazers"`
+}
+
+// recentStargazerPages returns the pages of 100 holding the most recent of
+// total stars, newest first.
+func recentStargazerPages(total, pages int) []int {
+	last := (total + 99) / 100
+	if last > stargazersLastPage {
+		last = stargazersLastPage
+	}
+	result := []int{}
+	for page := last; page >= 1 && len(result) < pages; page-- {
+		result = append(result, page)
+	}
+	return result
+}
+
+// starsPerWeek counts stars by the week, starting on Monday, they were
+// given in, newest week first.
+func starsPerWeek(stargazers []Stargazer) []WeekStars {
+	counts := map[string]int{}
+	for _, s := range stargazers {
+		t, err := time.Parse(time.RFC3339, s.StarredAt)
+		if err != nil {
+			continue
+		}
+		t = t.UTC()
+		monday := t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+		counts[monday.Format("2006-01-02")]++
+	}
+	weeks := make([]WeekStars, 0, len(counts))
+	for week, stars := range counts {
+		weeks = append(weeks, WeekStars{Week: week, Stars: stars})
+	}
+	sort.Slice(weeks, func(i, j int) bool { return weeks[i].Week > weeks[j].Week })
+	return weeks
+}
+
+func stargazersPage(apiKey, u string) ([]Stargazer, error) {
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing stargazers: ", u))
+	var page []struct {
+		StarredAt string `json:"starred_at"`
+		User      struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	}
+	if _, err := githubGetMediaJSON(apiKey, u, "application/vnd.github.star+json", &page); err != nil {
+		return nil, err
+	}
+	stargazers := make([]Stargazer, 0, len(page))
+	for _, s := range page {
+		stargazers = append(stargazers, Stargazer{Login: s.User.Login, StarredAt: s.StarredAt})
+	}
+	return stargazers, nil
+}
+
+func reposListStargazers(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	base := fmt.Sprintf("https://api.github.com/repos/%s/%s/stargazers", owner, repo)
+	summary := StargazersSummary{Stargazers: []Stargazer{}}
+	failed := func(err error) CallToolResult {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list stargazers: %s", err)),
+			}},
+		}
+	}
+
+	if recent, _ := args["recent_pages"].(float64); recent > 0 {
+		if recent > stargazersMaxRecentPages {
+			recent = stargazersMaxRecentPages
+		}
+		var details RepositoryDetails
+		if _, err := githubGetJSON(apiKey, fmt.Sprintf("https://api.github.com/repos/%s/%s", owner, repo), &details); err != nil {
+			return failed(err)
+		}
+		summary.TotalStars = details.Stargazers
+		for _, page := range recentStargazerPages(details.Stargazers, int(recent)) {
+			stargazers, err := stargazersPage(apiKey, fmt.Sprintf("%s?per_page=100&page=%d", base, page))
+			if err != nil {
+				return failed(err)
+			}
+			// Pages list the oldest stars first
+			for i := len(stargazers) - 1; i >= 0; i-- {
+				summary.Stargazers = append(summary.Stargazers, stargazers[i])
+			}
+		}
+	} else {
+		stargazers, err := stargazersPage(apiKey, fmt.Sprintf("%s?%s", base, strings.Join(paginationParams(args), "&")))
+		if err != nil {
+			return failed(err)
+		}
+		summary.Stargazers = stargazers
+	}
+	summary.PerWeek = starsPerWeek(summary.Stargazers)
+
+	responseJSON, err := json.Marshal(summary)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+// reposListWatchers lists the repository's subscribers, which is what the
+// GitHub UI calls watchers.
+func reposListWatchers(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/subscribers?%s", owner, repo, strings.Join(paginationParams(args), "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing watchers: ", u))
+
+	var users []struct {
+		Login string `json:"login"`
+	}
+	if _, err := githubGetJSON(apiKey, u, &users); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list watchers: %s", err)),
+			}},
+		}
+	}
+
+	logins := make([]string, 0, len(users))
+	for _, u := range users {
+		logins = append(logins, u.Login)
+	}
+	responseJSON, err := json.Marshal(logins)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
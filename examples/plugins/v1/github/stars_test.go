@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStarredURL(t *testing.T) {
+	if got := starredURL(map[string]interface{}{}); got != "https://api.github.com/user/starred?per_page=30&page=1" {
+		t.Errorf("authenticated user: got %s", got)
+	}
+	got := starredURL(map[string]interface{}{"username": "octocat", "sort": "updated", "direction": "asc", "per_page": float64(10)})
+	if got != "https://api.github.com/users/octocat/starred?sort=updated&direction=asc&per_page=10&page=1" {
+		t.Errorf("user: got %s", got)
+	}
+}
+
+func TestStarredRepository(t *testing.T) {
+	var repos []StarredRepository
+	err := json.Unmarshal([]byte(`[{"full_name":"acme/api","description":null,"stargazers_count":42,"pushed_at":"2024-05-01T00:00:00Z","forks_count":3,"owner":{"login":"acme"}}]`), &repos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(repos)
+	if string(got) != `[{"full_name":"acme/api","description":null,"stargazers_count":42,"pushed_at":"2024-05-01T00:00:00Z"}]` {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestRecentStargazerPages(t *testing.T) {
+	tests := []struct {
+		total, pages int
+		want         string
+	}{
+		{total: 250, pages: 2, want: "[3,2]"},
+		{total: 250, pages: 5, want: "[3,2,1]"},
+		{total: 300, pages: 1, want: "[3]"},
+		{total: 0, pages: 3, want: "[]"},
+		{total: 100000, pages: 2, want: "[400,399]"},
+	}
+	for _, tt := range tests {
+		got, _ := json.Marshal(recentStargazerPages(tt.total, tt.pages))
+		if string(got) != tt.want {
+			t.Errorf("%d stars, %d pages: got %s, want %s", tt.total, tt.pages, got, tt.want)
+		}
+	}
+}
+
+func TestStarsPerWeek(t *testing.T) {
+	got, _ := json.Marshal(starsPerWeek([]Stargazer{
+		{Login: "a", StarredAt: "2024-05-06T09:00:00Z"}, // Monday
+		{Login: "b", StarredAt: "2024-05-12T23:59:59Z"}, // Sunday, same week
+		{Login: "c", StarredAt: "2024-05-13T00:00:00Z"}, // next Monday
+		{Login: "d", StarredAt: "2024-04-30T12:00:00Z"},
+		{Login: "e", StarredAt: "not a date"},
+	}))
+	want := `[{"week":"2024-05-13","stars":1},{"week":"2024-05-06","stars":2},{"week":"2024-04-29","stars":1}]`
+	if string(got) != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+}
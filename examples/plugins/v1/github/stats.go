@@ -0,0 +1,254 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/extism/go-pdk"
+)
+
+var RepoStatsTool = ToolDescription{
+	Name:        "gh-repo-stats",
+	Description: "Summarize a repository's activity: top contributors by commits, weekly commit counts and lines added and deleted over the last 12 weeks",
+	InputSchema: schema{
+		"type": "object",
+		"properties": props{
+			"owner": prop("string", "The owner of the repository"),
+			"repo":  prop("string", "The repository name"),
+		},
+		"required": []string{"owner", "repo"},
+	},
+}
+
+const (
+	// GitHub answers 202 while it computes statistics, which usually takes
+	// a few seconds. Waits double from statsBackoff: 1s, 2s, 4s.
+	statsAttempts = 4
+	statsBackoff  = time.Second
+	// Weeks counted as recent activity
+	statsRecentWeeks  = 12
+	statsContributors = 25
+)
+
+type ContributorStats struct {
+	Author struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Total int `json:"total"`
+	Weeks []struct {
+		Week      int64 `json:"w"`
+		Additions int   `json:"a"`
+		Deletions int   `json:"d"`
+		Commits   int   `json:"c"`
+	} `json:"weeks"`
+}
+
+type CommitActivityWeek struct {
+	Total int   `json:"total"`
+	Week  int64 `json:"week"`
+}
+
+type ContributorSummary struct {
+	Login           string `json:"login"`
+	Commits         int    `json:"commits"`
+	RecentCommits   int    `json:"recent_commits"`
+	RecentAdditions int    `json:"recent_additions"`
+	RecentDeletions int    `json:"recent_deletions"`
+}
+
+type WeekCommits struct {
+	Week    string `json:"week"`
+	Commits int    `json:"commits"`
+}
+
+type CommitActivitySummary struct {
+	CommitsLastYear int           `json:"commits_last_year"`
+	RecentCommits   int           `json:"recent_commits"`
+	RecentWeeks     []WeekCommits `json:"recent_weeks"`
+}
+
+type CodeFrequencySummary struct {
+	RecentAdditions int `json:"recent_additions"`
+	RecentDeletions int `json:"recent_deletions"`
+}
+
+type RepoStats struct {
+	// "Recent" covers the last statsRecentWeeks weeks
+	RecentWeeks       int                    `json:"recent_weeks"`
+	ContributorsTotal int                    `json:"contributors_total,omitempty"`
+	Contributors      []ContributorSummary   `json:"contributors,omitempty"`
+	CommitActivity    *CommitActivitySummary `json:"commit_activity,omitempty"`
+	CodeFrequency     *CodeFrequencySummary  `json:"code_frequency,omitempty"`
+	// Parts GitHub was still generating when we gave up waiting
+	Pending []string          `json:"pending,omitempty"`
+	Errors  map[string]string `json:"errors,omitempty"`
+}
+
+// summarizeContributors sorts contributors by total commits and keeps the
+// top ones, with their activity over the recent weeks.
+func summarizeContributors(contributors []ContributorStats) []ContributorSummary {
+	summaries := []ContributorSummary{}
+	for _, c := range contributors {
+		summary := ContributorSummary{Login: c.Author.Login, Commits: c.Total}
+		weeks := c.Weeks
+		if len(weeks) > statsRecentWeeks {
+			weeks = weeks[len(weeks)-statsRecentWeeks:]
+		}
+		for _, w := range weeks {
+			summary.RecentCommits += w.Commits
+			summary.RecentAdditions += w.Additions
+			summary.RecentDeletions += w.Deletions
+		}
+		summaries = append(summaries, summary)
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].Commits != summaries[j].Commits {
+			return summaries[i].Commits > summaries[j].Commits
+		}
+		return summaries[i].Login < summaries[j].Login
+	})
+	if len(summaries) > statsContributors {
+		summaries = summaries[:statsContributors]
+	}
+	return summaries
+}
+
+func summarizeCommitActivity(weeks []CommitActivityWeek) *CommitActivitySummary {
+	summary := &CommitActivitySummary{RecentWeeks: []WeekCommits{}}
+	for i, w := range weeks {
+		summary.CommitsLastYear += w.Total
+		if i >= len(weeks)-statsRecentWeeks {
+			summary.RecentCommits += w.Total
+			summary.RecentWeeks = append(summary.RecentWeeks, WeekCommits{
+				Week:    time.Unix(w.Week, 0).UTC().Format("2006-01-02"),
+				Commits: w.Total,
+			})
+		}
+	}
+	return summary
+}
+
+// summarizeCodeFrequency adds up the recent [week, additions, deletions]
+// rows. GitHub reports deletions as negative numbers.
+func summarizeCodeFrequency(rows [][]int64) *CodeFrequencySummary {
+	summary := &CodeFrequencySummary{}
+	if len(rows) > statsRecentWeeks {
+		rows = rows[len(rows)-statsRecentWeeks:]
+	}
+	for _, row := range rows {
+		if len(row) != 3 {
+			continue
+		}
+		summary.RecentAdditions += int(row[1])
+		if row[2] < 0 {
+			row[2] = -row[2]
+		}
+		summary.RecentDeletions += int(row[2])
+	}
+	return summary
+}
+
+// repoStats fetches the statistics endpoints with get. All of them are
+// requested before waiting, since each 202 starts GitHub computing, and
+// only the ones still pending are retried.
+func repoStats(owner, repo string, get func(u string, out interface{}) (uint16, error), sleep func(time.Duration)) RepoStats {
+	base := fmt.Sprintf("https://api.github.com/repos/%s/%s/stats", owner, repo)
+	stats := RepoStats{RecentWeeks: statsRecentWeeks, Errors: map[string]string{}}
+	responses := map[string]*json.RawMessage{}
+	pending := []string{"contributors", "commit_activity", "code_frequency"}
+
+	backoff := statsBackoff
+	for attempt := 0; attempt < statsAttempts && len(pending) > 0; attempt++ {
+		if attempt > 0 {
+			sleep(backoff)
+			backoff *= 2
+		}
+		still := []string{}
+		for _, name := range pending {
+			var response json.RawMessage
+			status, err := get(fmt.Sprintf("%s/%s", base, name), &response)
+			switch {
+			case status == 202:
+				still = append(still, name)
+			case err != nil:
+				stats.Errors[name] = err.Error()
+			default:
+				responses[name] = &response
+			}
+		}
+		pending = still
+	}
+	stats.Pending = pending
+
+	if response := responses["contributors"]; response != nil {
+		var contributors []ContributorStats
+		if err := json.Unmarshal(*response, &contributors); err != nil {
+			stats.Errors["contributors"] = err.Error()
+		} else {
+			stats.ContributorsTotal = len(contributors)
+			stats.Contributors = summarizeContributors(contributors)
+		}
+	}
+	if response := responses["commit_activity"]; response != nil {
+		var weeks []CommitActivityWeek
+		if err := json.Unmarshal(*response, &weeks); err != nil {
+			stats.Errors["commit_activity"] = err.Error()
+		} else {
+			stats.CommitActivity = summarizeCommitActivity(weeks)
+		}
+	}
+	if response := responses["code_frequency"]; response != nil {
+		var rows [][]int64
+		if err := json.Unmarshal(*response, &rows); err != nil {
+			stats.Errors["code_frequency"] = err.Error()
+		} else {
+			stats.CodeFrequency = summarizeCodeFrequency(rows)
+		}
+	}
+	return stats
+}
+
+func reposStats(apiKey, owner, repo string) CallToolResult {
+	stats := repoStats(owner, repo, func(u string, out interface{}) (uint16, error) {
+		pdk.Log(pdk.LogDebug, fmt.Sprint("Fetching stats: ", u))
+		return githubGetJSON(apiKey, u, out)
+	}, time.Sleep)
+
+	if len(stats.Pending) == 3 {
+		return CallToolResult{
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("GitHub stats for %s/%s are still being generated; try again in a minute", owner, repo)),
+			}},
+		}
+	}
+	if len(stats.Errors) == 3 {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to fetch stats of %s/%s: %s", owner, repo, stats.Errors["contributors"])),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(stats)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
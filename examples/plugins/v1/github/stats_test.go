@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSummarizeContributors(t *testing.T) {
+	var contributors []ContributorStats
+	weeks := []string{}
+	for i := 0; i < 20; i++ {
+		weeks = append(weeks, `{"w":0,"a":10,"d":1,"c":1}`)
+	}
+	body := fmt.Sprintf(`[
+		{"author":{"login":"bob"},"total":5,"weeks":[{"w":0,"a":3,"d":2,"c":5}]},
+		{"author":{"login":"alice"},"total":20,"weeks":[%s]},
+		{"author":{"login":"carol"},"total":5,"weeks":[]}
+	]`, strings.Join(weeks, ","))
+	if err := json.Unmarshal([]byte(body), &contributors); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := json.Marshal(summarizeContributors(contributors))
+	want := `[{"login":"alice","commits":20,"recent_commits":12,"recent_additions":120,"recent_deletions":12},` +
+		`{"login":"bob","commits":5,"recent_commits":5,"recent_additions":3,"recent_deletions":2},` +
+		`{"login":"carol","commits":5,"recent_commits":0,"recent_additions":0,"recent_deletions":0}]`
+	if string(got) != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+
+	many := make([]ContributorStats, 30)
+	if got := summarizeContributors(many); len(got) != statsContributors {
+		t.Errorf("expected %d contributors, got %d", statsContributors, len(got))
+	}
+}
+
+func TestSummarizeCommitActivity(t *testing.T) {
+	weeks := []CommitActivityWeek{}
+	start := time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 52; i++ {
+		weeks = append(weeks, CommitActivityWeek{Total: i, Week: start.AddDate(0, 0, 7*i).Unix()})
+	}
+	summary := summarizeCommitActivity(weeks)
+	if summary.CommitsLastYear != 1326 || summary.RecentCommits != 546 {
+		t.Errorf("got %d last year, %d recent", summary.CommitsLastYear, summary.RecentCommits)
+	}
+	if len(summary.RecentWeeks) != 12 || summary.RecentWeeks[0] != (WeekCommits{Week: "2024-10-13", Commits: 40}) {
+		t.Errorf("got %+v", summary.RecentWeeks)
+	}
+}
+
+func TestSummarizeCodeFrequency(t *testing.T) {
+	rows := [][]int64{{0, 1000, -1000}}
+	for i := 0; i < 12; i++ {
+		rows = append(rows, []int64{int64(i), 10, -3})
+	}
+	if got := summarizeCodeFrequency(rows); *got != (CodeFrequencySummary{RecentAdditions: 120, RecentDeletions: 36}) {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestRepoStatsRetries(t *testing.T) {
+	calls := map[string]int{}
+	var waits []time.Duration
+	get := func(u string, out interface{}) (uint16, error) {
+		name := u[strings.LastIndex(u, "/")+1:]
+		calls[name]++
+		switch {
+		case name == "contributors" && calls[name] < 3:
+			return 202, fmt.Errorf("202 {}")
+		case name == "code_frequency":
+			return 202, fmt.Errorf("202 {}")
+		case name == "commit_activity" && calls[name] == 1:
+			return 500, fmt.Errorf("500 oops")
+		}
+		*out.(*json.RawMessage) = json.RawMessage(`[]`)
+		return 200, nil
+	}
+
+	stats := repoStats("o", "r", get, func(d time.Duration) { waits = append(waits, d) })
+	if calls["contributors"] != 3 || calls["commit_activity"] != 1 || calls["code_frequency"] != statsAttempts {
+		t.Errorf("calls: %v", calls)
+	}
+	if fmt.Sprint(waits) != "[1s 2s 4s]" {
+		t.Errorf("waits: %v", waits)
+	}
+	if stats.Contributors == nil || stats.CommitActivity != nil {
+		t.Errorf("got %+v", stats)
+	}
+	if fmt.Sprint(stats.Pending) != "[code_frequency]" || stats.Errors["commit_activity"] != "500 oops" {
+		t.Errorf("pending %v, errors %v", stats.Pending, stats.Errors)
+	}
+}
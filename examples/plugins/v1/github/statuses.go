@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"unicode/utf8"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	CreateCommitStatusTool = ToolDescription{
+		Name:        "gh-create-commit-status",
+		Description: "Set a commit status, the pass or fail marks shown next to a commit and on pull requests",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":       prop("string", "The owner of the repository"),
+				"repo":        prop("string", "The repository name"),
+				"sha":         prop("string", "The full SHA of the commit"),
+				"state":       prop("string", "error, failure, pending or success"),
+				"context":     prop("string", "Label that tells this status apart from others, e.g. ci/lint (default: default)"),
+				"description": prop("string", "Short description of the status, up to 140 characters"),
+				"target_url":  prop("string", "URL with the details, e.g. the build log"),
+			},
+			"required": []string{"owner", "repo", "sha", "state"},
+		},
+	}
+	GetCombinedStatusTool = ToolDescription{
+		Name:        "gh-get-combined-status",
+		Description: "Get the combined commit status of a commit, branch or tag, with the state and description of each context. Check runs from GitHub Actions are not included; use gh-list-check-runs for those.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner": prop("string", "The owner of the repository"),
+				"repo":  prop("string", "The repository name"),
+				"ref":   prop("string", "The commit sha, branch name or tag name"),
+			},
+			"required": []string{"owner", "repo", "ref"},
+		},
+	}
+)
+
+const statusDescriptionMax = 140
+
+type CommitStatus struct {
+	Context     string  `json:"context"`
+	State       string  `json:"state"`
+	Description *string `json:"description"`
+	TargetURL   *string `json:"target_url"`
+	UpdatedAt   string  `json:"updated_at"`
+}
+
+type CombinedStatus struct {
+	SHA        string         `json:"sha"`
+	State      string         `json:"state"`
+	TotalCount int            `json:"total_count"`
+	Statuses   []CommitStatus `json:"statuses"`
+}
+
+// statusBody builds the POST body, checking what GitHub would otherwise
+// reject with a bare 422.
+func statusBody(args map[string]interface{}) (map[string]interface{}, error) {
+	state, _ := args["state"].(string)
+	switch state {
+	case "error", "failure", "pending", "success":
+	default:
+		return nil, fmt.Errorf("state must be error, failure, pending or success, got %q", state)
+	}
+	body := map[string]interface{}{"state": state}
+	if context, _ := args["context"].(string); context != "" {
+		body["context"] = context
+	}
+	if description, _ := args["description"].(string); description != "" {
+		if utf8.RuneCountInString(description) > statusDescriptionMax {
+			return nil, fmt.Errorf("description must be at most %d characters", statusDescriptionMax)
+		}
+		body["description"] = description
+	}
+	if targetURL, _ := args["target_url"].(string); targetURL != "" {
+		if u, err := url.Parse(targetURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("target_url must be an http or https URL, got %q", targetURL)
+		}
+		body["target_url"] = targetURL
+	}
+	return body, nil
+}
+
+func statusesCreate(apiKey, owner, repo, sha string, args map[string]interface{}) CallToolResult {
+	body, err := statusBody(args)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid status: %s", err)),
+			}},
+		}
+	}
+
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/statuses/%s", owner, repo, sha)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Creating commit status: ", u))
+	var status CommitStatus
+	if err := githubSendJSON(apiKey, pdk.MethodPost, u, body, 201, &status); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to create commit status: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(status)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func statusesGetCombined(apiKey, owner, repo, ref string) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/commits/%s/status?per_page=100", owner, repo, ref)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting combined status: ", u))
+
+	var combined CombinedStatus
+	if _, err := githubGetJSON(apiKey, u, &combined); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to get combined status: %s", err)),
+			}},
+		}
+	}
+	if combined.Statuses == nil {
+		combined.Statuses = []CommitStatus{}
+	}
+
+	responseJSON, err := json.Marshal(combined)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStatusBody(t *testing.T) {
+	body, err := statusBody(map[string]interface{}{
+		"state":       "success",
+		"context":     "ci/lint",
+		"description": "All good",
+		"target_url":  "https://ci.example.com/builds/1",
+	})
+	if err != nil || fmt.Sprint(body) != "map[context:ci/lint description:All good state:success target_url:https://ci.example.com/builds/1]" {
+		t.Errorf("got %v, %v", body, err)
+	}
+
+	body, err = statusBody(map[string]interface{}{"state": "pending"})
+	if err != nil || fmt.Sprint(body) != "map[state:pending]" {
+		t.Errorf("minimal: got %v, %v", body, err)
+	}
+
+	for name, args := range map[string]map[string]interface{}{
+		"missing state":    {},
+		"unknown state":    {"state": "passed"},
+		"long description": {"state": "success", "description": strings.Repeat("x", 141)},
+		"bad target_url":   {"state": "success", "target_url": "ci.example.com/builds/1"},
+	} {
+		if _, err := statusBody(args); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
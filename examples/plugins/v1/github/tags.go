@@ -0,0 +1,278 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ListTagsTool = ToolDescription{
+		Name:        "gh-list-tags",
+		Description: "List the tags of a repository with their commit sha and tarball/zipball URLs. Set latest_only to get just the first tag GitHub returns, which is normally the most recent one.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":       prop("string", "The owner of the repository"),
+				"repo":        prop("string", "The repository name"),
+				"latest_only": prop("boolean", "Return only the first tag instead of a list"),
+				"per_page":    prop("integer", "Number of results per page (max 100)"),
+				"page":        prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	CreateTagTool = ToolDescription{
+		Name:        "gh-create-tag",
+		Description: "Create a tag pointing at a commit. By default this creates an annotated tag (a tag object with a message and tagger) and then the refs/tags ref pointing at it. Set lightweight to create only the ref.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":        prop("string", "The owner of the repository"),
+				"repo":         prop("string", "The repository name"),
+				"tag":          prop("string", "The tag name, e.g. v1.2.0"),
+				"sha":          prop("string", "The sha of the commit to tag"),
+				"message":      prop("string", "The tag message (required unless lightweight is set)"),
+				"tagger_name":  prop("string", "Name of the tagger. Defaults to the authenticated user."),
+				"tagger_email": prop("string", "Email of the tagger, required with tagger_name"),
+				"tagger_date":  prop("string", "When the tag was made, as an ISO 8601 timestamp. Defaults to now."),
+				"lightweight":  prop("boolean", "Create only the ref, without a tag object (message and tagger are ignored)"),
+			},
+			"required": []string{"owner", "repo", "tag", "sha"},
+		},
+	}
+	TagTools = []ToolDescription{
+		ListTagsTool,
+		CreateTagTool,
+	}
+)
+
+type Tag struct {
+	Name   string `json:"name"`
+	Commit struct {
+		Sha string `json:"sha"`
+	} `json:"commit"`
+	TarballURL string `json:"tarball_url"`
+	ZipballURL string `json:"zipball_url"`
+}
+
+type TagSummary struct {
+	Name       string `json:"name"`
+	Sha        string `json:"sha"`
+	TarballURL string `json:"tarball_url"`
+	ZipballURL string `json:"zipball_url"`
+}
+
+func summarizeTags(tags []Tag) []TagSummary {
+	summaries := make([]TagSummary, 0, len(tags))
+	for _, t := range tags {
+		summaries = append(summaries, TagSummary{
+			Name:       t.Name,
+			Sha:        t.Commit.Sha,
+			TarballURL: t.TarballURL,
+			ZipballURL: t.ZipballURL,
+		})
+	}
+	return summaries
+}
+
+func tagsList(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	latestOnly, _ := args["latest_only"].(bool)
+	params := paginationParams(args)
+	if latestOnly {
+		params = []string{"per_page=1", "page=1"}
+	}
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/tags?%s", owner, repo, strings.Join(params, "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing tags: ", u))
+
+	var tags []Tag
+	if _, err := githubGetJSON(apiKey, u, &tags); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list tags: %s", err)),
+			}},
+		}
+	}
+
+	var response interface{} = summarizeTags(tags)
+	if latestOnly {
+		if len(tags) == 0 {
+			return CallToolResult{
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("%s/%s has no tags", owner, repo)),
+				}},
+			}
+		}
+		response = summarizeTags(tags)[0]
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+type Tagger struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Date  string `json:"date,omitempty"`
+}
+
+// TagObject is the body of POST .../git/tags.
+type TagObject struct {
+	Tag     string  `json:"tag"`
+	Message string  `json:"message"`
+	Object  string  `json:"object"`
+	Type    string  `json:"type"`
+	Tagger  *Tagger `json:"tagger,omitempty"`
+}
+
+type CreatedTag struct {
+	Tag          string `json:"tag"`
+	Ref          string `json:"ref"`
+	Sha          string `json:"sha"`
+	TagObjectSha string `json:"tag_object_sha,omitempty"`
+	Lightweight  bool   `json:"lightweight"`
+}
+
+// tagObjectFromArgs builds the tag object for an annotated tag.
+func tagObjectFromArgs(tag, sha string, args map[string]interface{}) (TagObject, error) {
+	message, _ := args["message"].(string)
+	if message == "" {
+		return TagObject{}, fmt.Errorf("message is required for an annotated tag, or set lightweight to create only the ref")
+	}
+	object := TagObject{Tag: tag, Message: message, Object: sha, Type: "commit"}
+
+	name, _ := args["tagger_name"].(string)
+	email, _ := args["tagger_email"].(string)
+	date, _ := args["tagger_date"].(string)
+	if name == "" && email == "" && date == "" {
+		return object, nil
+	}
+	if name == "" || email == "" {
+		return TagObject{}, fmt.Errorf("tagger_name and tagger_email must be given together")
+	}
+	object.Tagger = &Tagger{Name: name, Email: email, Date: date}
+	return object, nil
+}
+
+// tagRefFailure explains a failed ref creation. For an annotated tag the tag
+// object already exists at that point, so the message says which object is
+// left without a ref and how to finish or abandon the tag.
+func tagRefFailure(tag, tagObjectSha string, status uint16, body string) string {
+	reason := fmt.Sprintf("%d %s", status, body)
+	if status == 422 && strings.Contains(body, "already exists") {
+		reason = fmt.Sprintf("tag %s already exists", tag)
+	}
+	if tagObjectSha == "" {
+		return fmt.Sprintf("Failed to create tag ref refs/tags/%s: %s", tag, reason)
+	}
+	return fmt.Sprintf("Created tag object %s but failed to create ref refs/tags/%s: %s. "+
+		"The tag object is not referenced by anything. To finish the tag, create the ref refs/tags/%s pointing at %s; "+
+		"to abandon it, do nothing, since GitHub garbage collects unreferenced tag objects.", tagObjectSha, tag, reason, tag, tagObjectSha)
+}
+
+func tagsCreate(apiKey, owner, repo, tag, sha string, args map[string]interface{}) CallToolResult {
+	lightweight, _ := args["lightweight"].(bool)
+	created := CreatedTag{Tag: tag, Ref: "refs/tags/" + tag, Sha: sha, Lightweight: lightweight}
+
+	target := sha
+	if !lightweight {
+		object, err := tagObjectFromArgs(tag, sha, args)
+		if err != nil {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Invalid tag: %s", err)),
+				}},
+			}
+		}
+
+		url := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/tags", owner, repo)
+		pdk.Log(pdk.LogDebug, fmt.Sprint("Creating tag object: ", url))
+		body, err := githubSendExpect(apiKey, pdk.MethodPost, url, object, 201)
+		if err != nil {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Failed to create tag object: %s", err)),
+				}},
+			}
+		}
+
+		var tagObject struct {
+			Sha string `json:"sha"`
+		}
+		if err := json.Unmarshal(body, &tagObject); err != nil || tagObject.Sha == "" {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Created tag object for %s but could not read its sha from the response: %s", tag, string(body))),
+				}},
+			}
+		}
+		created.TagObjectSha = tagObject.Sha
+		target = tagObject.Sha
+	}
+
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/refs", owner, repo)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Creating tag ref: ", url))
+	status, body, err := githubSend(apiKey, pdk.MethodPost, url, map[string]string{"ref": created.Ref, "sha": target})
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to create tag ref: %s", err)),
+			}},
+		}
+	}
+	if status != 201 {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(tagRefFailure(tag, created.TagObjectSha, status, githubErrorMessage(body))),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(created)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTagObjectBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "message only",
+			args: `{"message":"Release 1.2.0"}`,
+			want: `{"tag":"v1.2.0","message":"Release 1.2.0","object":"abc123","type":"commit"}`,
+		},
+		{
+			name: "with tagger",
+			args: `{"message":"Release 1.2.0","tagger_name":"Ana","tagger_email":"ana@example.com","tagger_date":"2024-03-05T09:00:00Z"}`,
+			want: `{"tag":"v1.2.0","message":"Release 1.2.0","object":"abc123","type":"commit","tagger":{"name":"Ana","email":"ana@example.com","date":"2024-03-05T09:00:00Z"}}`,
+		},
+		{
+			name:    "missing message",
+			args:    `{}`,
+			wantErr: "message is required",
+		},
+		{
+			name:    "tagger without email",
+			args:    `{"message":"m","tagger_name":"Ana"}`,
+			wantErr: "must be given together",
+		},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		object, err := tagObjectFromArgs("v1.2.0", "abc123", args)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error %v, want one containing %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		got, _ := json.Marshal(object)
+		if string(got) != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTagRefFailureReportsDanglingObject(t *testing.T) {
+	msg := tagRefFailure("v1.2.0", "0f1e2d", 422, `{"message":"Reference already exists"}`)
+	for _, want := range []string{"Created tag object 0f1e2d", "tag v1.2.0 already exists", "refs/tags/v1.2.0 pointing at 0f1e2d"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q does not contain %q", msg, want)
+		}
+	}
+
+	msg = tagRefFailure("v1.2.0", "", 404, `{"message":"Not Found"}`)
+	if msg != `Failed to create tag ref refs/tags/v1.2.0: 404 {"message":"Not Found"}` {
+		t.Errorf("lightweight failure message %q", msg)
+	}
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ListTeamMembersTool = ToolDescription{
+		Name:        "gh-list-team-members",
+		Description: "List the members of an organization team, including members of its child teams",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"org":       prop("string", "The organization name"),
+				"team_slug": prop("string", "The team slug, e.g. platform-team"),
+				"role":      prop("string", "all, maintainer or member (default all)"),
+				"per_page":  prop("integer", "Number of results per page (max 100)"),
+				"page":      prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"org", "team_slug"},
+		},
+	}
+	ListTeamReposTool = ToolDescription{
+		Name:        "gh-list-team-repos",
+		Description: "List the repositories an organization team can access, with the team's permission on each",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"org":       prop("string", "The organization name"),
+				"team_slug": prop("string", "The team slug, e.g. platform-team"),
+				"per_page":  prop("integer", "Number of results per page (max 100)"),
+				"page":      prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"org", "team_slug"},
+		},
+	}
+	AddTeamRepoTool = ToolDescription{
+		Name:        "gh-add-team-repo",
+		Description: "Give an organization team access to a repository, or change the team's permission on it",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"org":        prop("string", "The organization name"),
+				"team_slug":  prop("string", "The team slug, e.g. platform-team"),
+				"owner":      prop("string", "The owner of the repository"),
+				"repo":       prop("string", "The repository name"),
+				"permission": prop("string", "pull, triage, push, maintain or admin (default push)"),
+			},
+			"required": []string{"org", "team_slug", "owner", "repo"},
+		},
+	}
+	RemoveTeamRepoTool = ToolDescription{
+		Name:        "gh-remove-team-repo",
+		Description: "Remove an organization team's access to a repository. Members keep any access they have otherwise.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"org":       prop("string", "The organization name"),
+				"team_slug": prop("string", "The team slug, e.g. platform-team"),
+				"owner":     prop("string", "The owner of the repository"),
+				"repo":      prop("string", "The repository name"),
+			},
+			"required": []string{"org", "team_slug", "owner", "repo"},
+		},
+	}
+)
+
+type TeamRepo struct {
+	FullName    string `json:"full_name"`
+	Private     bool   `json:"private"`
+	HTMLURL     string `json:"html_url"`
+	RoleName    string `json:"role_name"`
+	Permissions struct {
+		Admin    bool `json:"admin"`
+		Maintain bool `json:"maintain"`
+		Push     bool `json:"push"`
+		Triage   bool `json:"triage"`
+		Pull     bool `json:"pull"`
+	} `json:"permissions"`
+}
+
+type TeamRepoSummary struct {
+	FullName   string `json:"full_name"`
+	Permission string `json:"permission"`
+	Private    bool   `json:"private"`
+	HTMLURL    string `json:"html_url"`
+}
+
+type TeamMember struct {
+	Login   string `json:"login"`
+	HTMLURL string `json:"html_url"`
+}
+
+// teamURL returns the API URL of the team, escaping the slug.
+func teamURL(org, slug string) (string, error) {
+	slug = strings.TrimSpace(slug)
+	if slug == "" || slug == "." || slug == ".." {
+		return "", fmt.Errorf("invalid team slug %q", slug)
+	}
+	return fmt.Sprintf("https://api.github.com/orgs/%s/teams/%s", url.PathEscape(org), url.PathEscape(slug)), nil
+}
+
+// teamRepoPermission is the team's highest permission on the repository.
+// role_name also covers custom repository roles, so it is preferred.
+func teamRepoPermission(repo TeamRepo) string {
+	if repo.RoleName != "" {
+		return repo.RoleName
+	}
+	p := repo.Permissions
+	switch {
+	case p.Admin:
+		return "admin"
+	case p.Maintain:
+		return "maintain"
+	case p.Push:
+		return "push"
+	case p.Triage:
+		return "triage"
+	case p.Pull:
+		return "pull"
+	}
+	return "none"
+}
+
+func summarizeTeamRepos(repos []TeamRepo) []TeamRepoSummary {
+	summaries := []TeamRepoSummary{}
+	for _, r := range repos {
+		summaries = append(summaries, TeamRepoSummary{
+			FullName:   r.FullName,
+			Permission: teamRepoPermission(r),
+			Private:    r.Private,
+			HTMLURL:    r.HTMLURL,
+		})
+	}
+	return summaries
+}
+
+func teamsListMembers(apiKey, org, slug string, args map[string]interface{}) CallToolResult {
+	base, err := teamURL(org, slug)
+	role, _ := args["role"].(string)
+	if role == "" {
+		role = "all"
+	}
+	if err == nil && role != "all" && role != "maintainer" && role != "member" {
+		err = fmt.Errorf("role must be all, maintainer or member, got %q", role)
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid member listing: %s", err)),
+			}},
+		}
+	}
+
+	u := fmt.Sprintf("%s/members?%s&role=%s", base, strings.Join(paginationParams(args), "&"), role)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing team members: ", u))
+	members := []TeamMember{}
+	if _, err := githubGetJSON(apiKey, u, &members); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list members of team %s: %s", slug, err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(members)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func teamsListRepos(apiKey, org, slug string, args map[string]interface{}) CallToolResult {
+	base, err := teamURL(org, slug)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(err.Error()),
+			}},
+		}
+	}
+
+	u := fmt.Sprintf("%s/repos?%s", base, strings.Join(paginationParams(args), "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing team repositories: ", u))
+	repos := []TeamRepo{}
+	if _, err := githubGetJSON(apiKey, u, &repos); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list repositories of team %s: %s", slug, err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(summarizeTeamRepos(repos))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+// teamsSetRepo adds the repository to the team with a PUT, or removes it
+// when remove is set.
+func teamsSetRepo(apiKey, org, slug, owner, repo string, remove bool, args map[string]interface{}) CallToolResult {
+	failed := func(message string) CallToolResult {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(message),
+			}},
+		}
+	}
+	base, err := teamURL(org, slug)
+	if err != nil {
+		return failed(err.Error())
+	}
+	u := fmt.Sprintf("%s/repos/%s/%s", base, owner, repo)
+
+	method, body, action := pdk.MethodDelete, interface{}(nil), "remove"
+	message := fmt.Sprintf("Removed team %s's access to %s/%s", slug, owner, repo)
+	if !remove {
+		permission, err := collaboratorPermission(args)
+		if err != nil {
+			return failed(fmt.Sprintf("Invalid permission: %s", err))
+		}
+		method, body, action = pdk.MethodPut, map[string]string{"permission": permission}, "add"
+		message = fmt.Sprintf("Gave team %s %s access to %s/%s", slug, permission, owner, repo)
+	}
+
+	pdk.Log(pdk.LogDebug, fmt.Sprintf("Team repository %s: %s", action, u))
+	status, response, err := githubSend(apiKey, method, u, body)
+	if err != nil {
+		return failed(fmt.Sprintf("Failed to %s repository: %s", action, err))
+	}
+	if status != 204 {
+		return failed(fmt.Sprintf("Failed to %s repository: %s", action, githubStatusError(status, response)))
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(message),
+		}},
+	}
+}
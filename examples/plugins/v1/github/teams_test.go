@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTeamURL(t *testing.T) {
+	got, err := teamURL("acme", "platform.core-team")
+	if err != nil || got != "https://api.github.com/orgs/acme/teams/platform.core-team" {
+		t.Errorf("got %s, %v", got, err)
+	}
+	got, err = teamURL("acme", "a/b c")
+	if err != nil || got != "https://api.github.com/orgs/acme/teams/a%2Fb%20c" {
+		t.Errorf("escaping: got %s, %v", got, err)
+	}
+	for _, slug := range []string{"", "..", " . "} {
+		if _, err := teamURL("acme", slug); err == nil {
+			t.Errorf("%q: expected an error", slug)
+		}
+	}
+}
+
+func TestSummarizeTeamRepos(t *testing.T) {
+	var repos []TeamRepo
+	if err := json.Unmarshal([]byte(`[
+		{"full_name":"acme/api","private":true,"html_url":"https://github.com/acme/api","role_name":"maintain","permissions":{"admin":false,"maintain":true,"push":true,"triage":true,"pull":true}},
+		{"full_name":"acme/web","private":false,"html_url":"https://github.com/acme/web","permissions":{"admin":false,"maintain":false,"push":true,"triage":true,"pull":true}},
+		{"full_name":"acme/docs","private":false,"html_url":"https://github.com/acme/docs","role_name":"docs-writer","permissions":{"pull":true}}
+	]`), &repos); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(summarizeTeamRepos(repos))
+	want := `[{"full_name":"acme/api","permission":"maintain","private":true,"html_url":"https://github.com/acme/api"},` +
+		`{"full_name":"acme/web","permission":"push","private":false,"html_url":"https://github.com/acme/web"},` +
+		`{"full_name":"acme/docs","permission":"docs-writer","private":false,"html_url":"https://github.com/acme/docs"}]`
+	if string(got) != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+	if got := teamRepoPermission(TeamRepo{}); got != "none" {
+		t.Errorf("no permissions: got %q", got)
+	}
+}
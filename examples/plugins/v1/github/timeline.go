@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+var (
+	IssueTimelineTool = ToolDescription{
+		Name:        "gh-get-issue-timeline",
+		Description: "Get the history of an issue or pull request in one call: comments, labels, assignments, references from commits and other issues, closing and reopening, in chronological order with actor and time. Comment bodies are shortened to a preview.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":          prop("string", "The owner of the repository"),
+				"repo":           prop("string", "The repository name"),
+				"issue":          prop("integer", "The issue or pull request number"),
+				"preview_length": prop("integer", fmt.Sprintf("Characters of each comment body to keep (default %d, max %d)", timelineDefaultPreview, timelineMaxPreview)),
+			},
+			"required": []string{"owner", "repo", "issue"},
+		},
+	}
+)
+
+const (
+	timelineDefaultPreview = 200
+	timelineMaxPreview     = 2000
+)
+
+type timelineUser struct {
+	Login string `json:"login"`
+}
+
+// TimelineEvent holds the fields of the timeline event types the timeline
+// and export tools read; each type only fills some of them.
+type TimelineEvent struct {
+	Event       string        `json:"event"`
+	Actor       *timelineUser `json:"actor"`
+	User        *timelineUser `json:"user"`
+	CreatedAt   string        `json:"created_at"`
+	SubmittedAt string        `json:"submitted_at"`
+	Body        string        `json:"body"`
+	HTMLURL     string        `json:"html_url"`
+	CommitID    string        `json:"commit_id"`
+	StateReason string        `json:"state_reason"`
+	State       string        `json:"state"`
+	Label       *struct {
+		Name string `json:"name"`
+	} `json:"label"`
+	Assignee *timelineUser `json:"assignee"`
+	Rename   *struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	} `json:"rename"`
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	Source *struct {
+		Issue *struct {
+			Number      int    `json:"number"`
+			Title       string `json:"title"`
+			State       string `json:"state"`
+			HTMLURL     string `json:"html_url"`
+			PullRequest *struct {
+				MergedAt *string `json:"merged_at"`
+			} `json:"pull_request"`
+			Repository struct {
+				FullName string `json:"full_name"`
+			} `json:"repository"`
+		} `json:"issue"`
+	} `json:"source"`
+	// committed events
+	Sha     string `json:"sha"`
+	Message string `json:"message"`
+	Author  *struct {
+		Name string `json:"name"`
+		Date string `json:"date"`
+	} `json:"author"`
+}
+
+type TimelineEntry struct {
+	Event  string `json:"event"`
+	Actor  string `json:"actor,omitempty"`
+	At     string `json:"at,omitempty"`
+	Detail string `json:"detail,omitempty"`
+	URL    string `json:"url,omitempty"`
+}
+
+type IssueTimeline struct {
+	Issue     int             `json:"issue"`
+	Events    []TimelineEntry `json:"events"`
+	Truncated bool            `json:"truncated"`
+}
+
+func shortSha(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// condenseTimelineEvent keeps who did what and when, with a one-line
+// detail for the event types that have one.
+func condenseTimelineEvent(e TimelineEvent, preview int) TimelineEntry {
+	entry := TimelineEntry{Event: e.Event, At: e.CreatedAt}
+	switch {
+	case e.Actor != nil:
+		entry.Actor = e.Actor.Login
+	case e.User != nil:
+		entry.Actor = e.User.Login
+	}
+
+	switch e.Event {
+	case "commented":
+		entry.Detail = truncateText(strings.TrimSpace(e.Body), preview)
+		entry.URL = e.HTMLURL
+	case "reviewed":
+		entry.At = e.SubmittedAt
+		entry.Detail = strings.ToLower(e.State)
+		if body := strings.TrimSpace(e.Body); body != "" {
+			entry.Detail += ": " + truncateText(body, preview)
+		}
+		entry.URL = e.HTMLURL
+	case "labeled", "unlabeled":
+		if e.Label != nil {
+			entry.Detail = e.Label.Name
+		}
+	case "assigned", "unassigned":
+		if e.Assignee != nil {
+			entry.Detail = e.Assignee.Login
+		}
+	case "milestoned", "demilestoned":
+		if e.Milestone != nil {
+			entry.Detail = e.Milestone.Title
+		}
+	case "renamed":
+		if e.Rename != nil {
+			entry.Detail = fmt.Sprintf("%q → %q", e.Rename.From, e.Rename.To)
+		}
+	case "referenced":
+		entry.Detail = "commit " + shortSha(e.CommitID)
+	case "closed":
+		entry.Detail = e.StateReason
+		if e.CommitID != "" {
+			entry.Detail = strings.TrimSpace(entry.Detail + " by commit " + shortSha(e.CommitID))
+		}
+	case "cross-referenced":
+		if e.Source != nil && e.Source.Issue != nil {
+			entry.Detail = fmt.Sprintf("%s#%d %s", e.Source.Issue.Repository.FullName, e.Source.Issue.Number, e.Source.Issue.Title)
+			entry.URL = e.Source.Issue.HTMLURL
+		}
+	case "committed":
+		if e.Author != nil {
+			entry.Actor = e.Author.Name
+			entry.At = e.Author.Date
+		}
+		entry.Detail = shortSha(e.Sha) + " " + firstLine(e.Message)
+		entry.URL = e.HTMLURL
+	}
+	return entry
+}
+
+func summarizeTimeline(issue int, events []TimelineEvent, preview int, truncated bool) IssueTimeline {
+	timeline := IssueTimeline{Issue: issue, Events: make([]TimelineEntry, 0, len(events)), Truncated: truncated}
+	for _, e := range events {
+		timeline.Events = append(timeline.Events, condenseTimelineEvent(e, preview))
+	}
+	return timeline
+}
+
+func issueTimeline(apiKey, owner, repo string, issue int, args map[string]interface{}) CallToolResult {
+	preview := timelineDefaultPreview
+	if value, ok := args["preview_length"].(float64); ok {
+		preview = int(value)
+	}
+	if preview < 0 || preview > timelineMaxPreview {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("preview_length must be between 0 and %d", timelineMaxPreview)),
+			}},
+		}
+	}
+
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d/timeline", owner, repo, issue)
+	events, truncated, err := exportFetchPages[TimelineEvent](apiKey, u)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to get timeline of #%d: %s", issue, err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(summarizeTimeline(issue, events, preview, truncated))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const timelineFixture = `[
+	{"event":"labeled","actor":{"login":"octocat"},"created_at":"2024-03-01T10:00:00Z","label":{"name":"bug","color":"d73a4a"}},
+	{"event":"assigned","actor":{"login":"octocat"},"created_at":"2024-03-01T10:01:00Z","assignee":{"login":"hubot"}},
+	{"event":"commented","actor":{"login":"hubot"},"user":{"login":"hubot"},"created_at":"2024-03-02T09:00:00Z","html_url":"https://github.com/acme/api/issues/7#issuecomment-1","body":"  I can reproduce this on every release since 1.2, here is the trace  "},
+	{"event":"cross-referenced","actor":{"login":"mona"},"created_at":"2024-03-03T12:00:00Z","source":{"type":"issue","issue":{"number":9,"title":"Fix parser crash","html_url":"https://github.com/acme/api/pull/9","repository":{"full_name":"acme/api"}}}},
+	{"event":"committed","sha":"0123456789abcdef","message":"Fix parser crash\n\nCloses #7","author":{"name":"Mona","date":"2024-03-03T11:00:00Z"},"html_url":"https://github.com/acme/api/commit/0123456"},
+	{"event":"referenced","actor":{"login":"mona"},"created_at":"2024-03-04T08:00:00Z","commit_id":"fedcba9876543210"},
+	{"event":"closed","actor":{"login":"mona"},"created_at":"2024-03-04T08:00:01Z","commit_id":"fedcba9876543210","state_reason":"completed"},
+	{"event":"subscribed","actor":{"login":"octocat"},"created_at":"2024-03-04T09:00:00Z"}
+]`
+
+func TestSummarizeTimeline(t *testing.T) {
+	var events []TimelineEvent
+	if err := json.Unmarshal([]byte(timelineFixture), &events); err != nil {
+		t.Fatal(err)
+	}
+	timeline := summarizeTimeline(7, events, 20, false)
+
+	want := []TimelineEntry{
+		{Event: "labeled", Actor: "octocat", At: "2024-03-01T10:00:00Z", Detail: "bug"},
+		{Event: "assigned", Actor: "octocat", At: "2024-03-01T10:01:00Z", Detail: "hubot"},
+		{Event: "commented", Actor: "hubot", At: "2024-03-02T09:00:00Z", Detail: "I can reproduce this…", URL: "https://github.com/acme/api/issues/7#issuecomment-1"},
+		{Event: "cross-referenced", Actor: "mona", At: "2024-03-03T12:00:00Z", Detail: "acme/api#9 Fix parser crash", URL: "https://github.com/acme/api/pull/9"},
+		{Event: "committed", Actor: "Mona", At: "2024-03-03T11:00:00Z", Detail: "0123456 Fix parser crash", URL: "https://github.com/acme/api/commit/0123456"},
+		{Event: "referenced", Actor: "mona", At: "2024-03-04T08:00:00Z", Detail: "commit fedcba9"},
+		{Event: "closed", Actor: "mona", At: "2024-03-04T08:00:01Z", Detail: "completed by commit fedcba9"},
+		{Event: "subscribed", Actor: "octocat", At: "2024-03-04T09:00:00Z"},
+	}
+	if len(timeline.Events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(timeline.Events), len(want), timeline.Events)
+	}
+	for i := range want {
+		if timeline.Events[i] != want[i] {
+			t.Errorf("event %d:\n got %+v\nwant %+v", i, timeline.Events[i], want[i])
+		}
+	}
+}
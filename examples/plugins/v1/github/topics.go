@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	GetRepoTopicsTool = ToolDescription{
+		Name:        "gh-get-repo-topics",
+		Description: "Get the topics of a repository",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner": prop("string", "The owner of the repository"),
+				"repo":  prop("string", "The repository name"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	SetRepoTopicsTool = ToolDescription{
+		Name:        "gh-set-repo-topics",
+		Description: "Replace the topics of a repository, or add or remove some of them",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner": prop("string", "The owner of the repository"),
+				"repo":  prop("string", "The repository name"),
+				"names": arrprop("array", "Topics: lowercase letters, digits and hyphens, at most 50 characters each", "string"),
+				"mode":  prop("string", "replace sets exactly these topics, add and remove change the current ones (default replace)"),
+			},
+			"required": []string{"owner", "repo", "names"},
+		},
+	}
+)
+
+const (
+	topicMaxLength = 50
+	// GitHub refuses more topics than this on a repository
+	topicsMax = 20
+)
+
+type RepoTopics struct {
+	Names []string `json:"names"`
+}
+
+// topicNames lowercases the names and checks them against GitHub's rules:
+// letters, digits and hyphens, starting with a letter or digit.
+func topicNames(args map[string]interface{}) ([]string, error) {
+	raw, ok := args["names"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("names must be an array of topics")
+	}
+	names := []string{}
+	seen := map[string]bool{}
+	for _, value := range raw {
+		name, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("topics must be strings, got %#v", value)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || len(name) > topicMaxLength {
+			return nil, fmt.Errorf("topic %q must be 1 to %d characters", name, topicMaxLength)
+		}
+		for i, r := range name {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' && i > 0) {
+				return nil, fmt.Errorf("topic %q may only contain lowercase letters, digits and hyphens, and can't start with a hyphen", name)
+			}
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// applyTopics works out the full set of topics to PUT.
+func applyTopics(current, names []string, mode string) ([]string, error) {
+	var topics []string
+	switch mode {
+	case "", "replace":
+		topics = names
+	case "add":
+		topics = append([]string{}, current...)
+		for _, name := range names {
+			if !containsString(topics, name) {
+				topics = append(topics, name)
+			}
+		}
+	case "remove":
+		topics = []string{}
+		for _, topic := range current {
+			if !containsString(names, topic) {
+				topics = append(topics, topic)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("mode must be replace, add or remove, got %q", mode)
+	}
+	if len(topics) > topicsMax {
+		return nil, fmt.Errorf("a repository can have at most %d topics, this would give it %d", topicsMax, len(topics))
+	}
+	return topics, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func topicsGet(apiKey, owner, repo string) (RepoTopics, error) {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/topics", owner, repo)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting topics: ", u))
+	var topics RepoTopics
+	_, err := githubGetJSON(apiKey, u, &topics)
+	return topics, err
+}
+
+func reposGetTopics(apiKey, owner, repo string) CallToolResult {
+	topics, err := topicsGet(apiKey, owner, repo)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to get topics: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(topics)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func reposSetTopics(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	mode, _ := args["mode"].(string)
+	names, err := topicNames(args)
+	var current RepoTopics
+	if err == nil && (mode == "add" || mode == "remove") {
+		if current, err = topicsGet(apiKey, owner, repo); err != nil {
+			return CallToolResult{
+				IsError: some(true),
+				Content: []Content{{
+					Type: ContentTypeText,
+					Text: some(fmt.Sprintf("Failed to get topics: %s", err)),
+				}},
+			}
+		}
+	}
+	var topics []string
+	if err == nil {
+		topics, err = applyTopics(current.Names, names, mode)
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid topics: %s", err)),
+			}},
+		}
+	}
+
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/topics", owner, repo)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Setting topics: ", u))
+	var updated RepoTopics
+	if err := githubSendJSON(apiKey, pdk.MethodPut, u, RepoTopics{Names: topics}, 200, &updated); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to set topics: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(updated)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTopicNames(t *testing.T) {
+	var args map[string]interface{}
+	_ = json.Unmarshal([]byte(`{"names":[" Go ","mcp","wasm-plugins","go"]}`), &args)
+	got, err := topicNames(args)
+	if err != nil || strings.Join(got, ",") != "go,mcp,wasm-plugins" {
+		t.Errorf("got %v, %v", got, err)
+	}
+
+	for _, names := range []string{`"go"`, `["-go"]`, `["c++"]`, `["two words"]`, `[""]`, `[1]`, `["` + strings.Repeat("a", topicMaxLength+1) + `"]`} {
+		_ = json.Unmarshal([]byte(`{"names":`+names+`}`), &args)
+		if got, err := topicNames(args); err == nil {
+			t.Errorf("%s: expected an error, got %v", names, got)
+		}
+	}
+}
+
+func TestApplyTopics(t *testing.T) {
+	current := []string{"go", "mcp"}
+	tests := []struct {
+		mode    string
+		names   []string
+		want    string
+		wantErr bool
+	}{
+		{mode: "", names: []string{"wasm"}, want: "wasm"},
+		{mode: "replace", names: []string{}, want: ""},
+		{mode: "add", names: []string{"mcp", "wasm"}, want: "go,mcp,wasm"},
+		{mode: "remove", names: []string{"go", "rust"}, want: "mcp"},
+		{mode: "merge", names: []string{"go"}, wantErr: true},
+		{mode: "add", names: strings.Split("a,b,c,d,e,f,g,h,i,j,k,l,m,n,o,p,q,r,s", ","), wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := applyTopics(current, tt.names, tt.mode)
+		if (err != nil) != tt.wantErr || strings.Join(got, ",") != tt.want {
+			t.Errorf("%s %v: got %v, %v", tt.mode, tt.names, got, err)
+		}
+	}
+	if strings.Join(current, ",") != "go,mcp" {
+		t.Errorf("current topics were modified: %v", current)
+	}
+}
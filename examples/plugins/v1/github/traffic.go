@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/extism/go-pdk"
+)
+
+var RepoTrafficTool = ToolDescription{
+	Name:        "gh-repo-traffic",
+	Description: "Summarize the last 14 days of traffic to a repository: views, clones, top referrers and top pages. Needs push access to the repository.",
+	InputSchema: schema{
+		"type": "object",
+		"properties": props{
+			"owner": prop("string", "The owner of the repository"),
+			"repo":  prop("string", "The repository name"),
+			"per":   prop("string", "Group views and clones by day or week (default day)"),
+		},
+		"required": []string{"owner", "repo"},
+	},
+}
+
+type TrafficPoint struct {
+	Timestamp string `json:"timestamp"`
+	Count     int    `json:"count"`
+	Uniques   int    `json:"uniques"`
+}
+
+type TrafficSeries struct {
+	Count   int            `json:"count"`
+	Uniques int            `json:"uniques"`
+	Points  []TrafficPoint `json:"points"`
+}
+
+type TrafficReferrer struct {
+	Referrer string `json:"referrer"`
+	Count    int    `json:"count"`
+	Uniques  int    `json:"uniques"`
+}
+
+type TrafficPath struct {
+	Path    string `json:"path"`
+	Title   string `json:"title"`
+	Count   int    `json:"count"`
+	Uniques int    `json:"uniques"`
+}
+
+type RepoTraffic struct {
+	Per       string            `json:"per"`
+	Views     *TrafficSeries    `json:"views,omitempty"`
+	Clones    *TrafficSeries    `json:"clones,omitempty"`
+	Referrers []TrafficReferrer `json:"referrers,omitempty"`
+	Paths     []TrafficPath     `json:"paths,omitempty"`
+	// Parts that couldn't be fetched, by name, so the rest is still useful
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+const trafficForbidden = "403: reading traffic needs push access to the repository, and a token with the repo scope (or Administration read for fine-grained tokens)"
+
+// repoTraffic reads the four traffic endpoints with get. A part that fails
+// is reported under Errors instead of failing the whole summary.
+func repoTraffic(owner, repo, per string, get func(u string, out interface{}) (uint16, error)) RepoTraffic {
+	base := fmt.Sprintf("https://api.github.com/repos/%s/%s/traffic", owner, repo)
+	traffic := RepoTraffic{Per: per, Errors: map[string]string{}}
+	fetch := func(name, u string, out interface{}) bool {
+		status, err := get(u, out)
+		switch {
+		case status == 403:
+			traffic.Errors[name] = trafficForbidden
+		case err != nil:
+			traffic.Errors[name] = err.Error()
+		}
+		return err == nil
+	}
+	series := func(name, key string) *TrafficSeries {
+		var response map[string]json.RawMessage
+		if !fetch(name, fmt.Sprintf("%s/%s?per=%s", base, name, per), &response) {
+			return nil
+		}
+		s := &TrafficSeries{Points: []TrafficPoint{}}
+		_ = json.Unmarshal(response["count"], &s.Count)
+		_ = json.Unmarshal(response["uniques"], &s.Uniques)
+		_ = json.Unmarshal(response[key], &s.Points)
+		return s
+	}
+
+	traffic.Views = series("views", "views")
+	traffic.Clones = series("clones", "clones")
+	var referrers []TrafficReferrer
+	if fetch("referrers", base+"/popular/referrers", &referrers) {
+		traffic.Referrers = referrers
+	}
+	var paths []TrafficPath
+	if fetch("paths", base+"/popular/paths", &paths) {
+		traffic.Paths = paths
+	}
+	return traffic
+}
+
+func reposTraffic(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	per, _ := args["per"].(string)
+	if per == "" {
+		per = "day"
+	}
+	if per != "day" && per != "week" {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("per must be day or week, got %q", per)),
+			}},
+		}
+	}
+
+	traffic := repoTraffic(owner, repo, per, func(u string, out interface{}) (uint16, error) {
+		pdk.Log(pdk.LogDebug, fmt.Sprint("Fetching traffic: ", u))
+		return githubGetJSON(apiKey, u, out)
+	})
+	if len(traffic.Errors) == 4 {
+		text := fmt.Sprintf("Failed to fetch traffic of %s/%s: %s", owner, repo, traffic.Errors["views"])
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(text),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(traffic)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
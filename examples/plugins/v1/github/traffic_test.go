@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func fakeTrafficGet(responses map[string]string, statuses map[string]uint16) func(string, interface{}) (uint16, error) {
+	return func(u string, out interface{}) (uint16, error) {
+		for suffix, status := range statuses {
+			if strings.Contains(u, suffix) {
+				return status, fmt.Errorf("%d failed", status)
+			}
+		}
+		for suffix, body := range responses {
+			if strings.Contains(u, suffix) {
+				return 200, json.Unmarshal([]byte(body), out)
+			}
+		}
+		return 404, fmt.Errorf("404 not found: %s", u)
+	}
+}
+
+func TestRepoTraffic(t *testing.T) {
+	responses := map[string]string{
+		"/traffic/views?per=week":    `{"count":120,"uniques":40,"views":[{"timestamp":"2024-05-06T00:00:00Z","count":120,"uniques":40}]}`,
+		"/traffic/clones?per=week":   `{"count":9,"uniques":3,"clones":[{"timestamp":"2024-05-06T00:00:00Z","count":9,"uniques":3}]}`,
+		"/traffic/popular/referrers": `[{"referrer":"github.com","count":80,"uniques":30}]`,
+		"/traffic/popular/paths":     `[{"path":"/acme/api","title":"acme/api","count":100,"uniques":35}]`,
+	}
+
+	got := repoTraffic("acme", "api", "week", fakeTrafficGet(responses, nil))
+	out, _ := json.Marshal(got)
+	want := `{"per":"week","views":{"count":120,"uniques":40,"points":[{"timestamp":"2024-05-06T00:00:00Z","count":120,"uniques":40}]},` +
+		`"clones":{"count":9,"uniques":3,"points":[{"timestamp":"2024-05-06T00:00:00Z","count":9,"uniques":3}]},` +
+		`"referrers":[{"referrer":"github.com","count":80,"uniques":30}],"paths":[{"path":"/acme/api","title":"acme/api","count":100,"uniques":35}]}`
+	if string(out) != want {
+		t.Errorf("\n got %s\nwant %s", out, want)
+	}
+
+	partial := repoTraffic("acme", "api", "week", fakeTrafficGet(responses, map[string]uint16{"/traffic/clones": 500, "/popular/paths": 403}))
+	if partial.Views == nil || partial.Referrers == nil {
+		t.Errorf("parts that worked are missing: %+v", partial)
+	}
+	if partial.Clones != nil || partial.Paths != nil || len(partial.Errors) != 2 {
+		t.Errorf("failed parts: %+v", partial)
+	}
+	if partial.Errors["paths"] != trafficForbidden || partial.Errors["clones"] != "500 failed" {
+		t.Errorf("errors: %v", partial.Errors)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var TransferIssueTool = ToolDescription{
+	Name:        "gh-transfer-issue",
+	Description: "Move an issue to another repository of the same owner. The issue gets a new number there, and links to the old one redirect to it.",
+	InputSchema: schema{
+		"type": "object",
+		"properties": props{
+			"owner":          prop("string", "The owner of the repository"),
+			"repo":           prop("string", "The repository the issue is in now"),
+			"issue":          prop("integer", "The issue number"),
+			"new_repository": prop("string", "The repository to move the issue to, as name or owner/name. It must belong to the same owner."),
+		},
+		"required": []string{"owner", "repo", "issue", "new_repository"},
+	},
+}
+
+// transferTarget returns the repository name to send as new_repository.
+// An owner/name target keeps its owner when it differs, so GitHub rejects
+// it with its own explanation.
+func transferTarget(owner, repo, target string) (string, error) {
+	target = strings.Trim(strings.TrimSpace(target), "/")
+	if target == "" {
+		return "", fmt.Errorf("new_repository is required")
+	}
+	if targetOwner, name, ok := strings.Cut(target, "/"); ok && strings.EqualFold(targetOwner, owner) {
+		target = name
+	}
+	if strings.EqualFold(target, repo) {
+		return "", fmt.Errorf("the issue is already in %s/%s", owner, repo)
+	}
+	return target, nil
+}
+
+type TransferredIssue struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	Title   string `json:"title"`
+}
+
+func transferMessage(owner, repo string, number int, issue TransferredIssue) string {
+	return fmt.Sprintf("Moved %s/%s#%d to #%d: %s\nThe issue now lives there as #%d; links to %s/%s#%d redirect to it.",
+		owner, repo, number, issue.Number, issue.HTMLURL, issue.Number, owner, repo, number)
+}
+
+func issueTransfer(apiKey, owner, repo string, number int, args map[string]interface{}) CallToolResult {
+	newRepository, _ := args["new_repository"].(string)
+	target, err := transferTarget(owner, repo, newRepository)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid transfer: %s", err)),
+			}},
+		}
+	}
+
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d/transfer", owner, repo, number)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Transferring issue: ", u))
+	status, body, err := githubSend(apiKey, pdk.MethodPost, u, map[string]string{"new_repository": target})
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to transfer #%d: %s", number, err)),
+			}},
+		}
+	}
+	if status == 422 {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("GitHub refused to move #%d to %s: %s. Issues can only move between repositories of the same owner.", number, target, githubErrorMessage(body))),
+			}},
+		}
+	}
+	if status != 201 && status != 200 {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to transfer #%d: %s", number, githubStatusError(status, body))),
+			}},
+		}
+	}
+	var issue TransferredIssue
+	if err := json.Unmarshal(body, &issue); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to parse response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{
+			{Type: ContentTypeText, Text: some(transferMessage(owner, repo, number, issue))},
+			{Type: ContentTypeText, Text: some(string(body))},
+		},
+	}
+}
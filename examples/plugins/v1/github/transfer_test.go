@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTransferTarget(t *testing.T) {
+	tests := []struct {
+		target  string
+		want    string
+		wantErr string
+	}{
+		{target: "tools", want: "tools"},
+		{target: "acme/tools", want: "tools"},
+		{target: " ACME/tools/ ", want: "tools"},
+		{target: "other/tools", want: "other/tools"},
+		{target: "", wantErr: "required"},
+		{target: "acme/api", wantErr: "already in acme/api"},
+	}
+	for _, tt := range tests {
+		got, err := transferTarget("acme", "api", tt.target)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%q: got %q, %v, want error containing %q", tt.target, got, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%q: got %q, %v, want %q", tt.target, got, err, tt.want)
+		}
+	}
+}
+
+func TestGithubErrorMessage(t *testing.T) {
+	body := `{"message":"Validation Failed","errors":[{"message":"Cannot transfer an issue to a repository owned by a different user"}]}`
+	want := "Validation Failed: Cannot transfer an issue to a repository owned by a different user"
+	if got := githubErrorMessage([]byte(body)); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := githubErrorMessage([]byte("bad gateway")); got != "bad gateway" {
+		t.Errorf("non-JSON body: got %q", got)
+	}
+}
+
+func TestTransferMessage(t *testing.T) {
+	got := transferMessage("acme", "api", 12, TransferredIssue{Number: 40, HTMLURL: "https://github.com/acme/tools/issues/40"})
+	for _, want := range []string{"#40", "https://github.com/acme/tools/issues/40", "links to acme/api#12 redirect"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("message %q doesn't mention %q", got, want)
+		}
+	}
+	if strings.Contains(got, "no longer exists") {
+		t.Errorf("message %q says the old issue is gone, but its URL redirects", got)
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	GetTreeTool = ToolDescription{
+		Name:        "gh-get-tree",
+		Description: "List every file and directory of a repository at a ref in one call, as a flat list of paths with type, size and sha. Use path_prefix to only list what is under a directory, e.g. src/, instead of walking it with gh-get-file-contents.",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":       prop("string", "The owner of the repository"),
+				"repo":        prop("string", "The repository name"),
+				"ref":         prop("string", "Branch, tag or commit sha to list (defaults to the default branch)"),
+				"path_prefix": prop("string", "Only return entries under this directory, e.g. src/components"),
+				"max_entries": prop("integer", "Maximum number of entries to return (default 1000, max 10000)"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+)
+
+const (
+	treeDefaultMaxEntries = 1000
+	treeMaxEntries        = 10000
+)
+
+type GitTree struct {
+	Sha       string `json:"sha"`
+	Truncated bool   `json:"truncated"`
+	Tree      []struct {
+		Path string `json:"path"`
+		Mode string `json:"mode"`
+		Type string `json:"type"`
+		Size *int   `json:"size"`
+		Sha  string `json:"sha"`
+	} `json:"tree"`
+}
+
+type TreeListEntry struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+	Size *int   `json:"size,omitempty"`
+	Sha  string `json:"sha"`
+}
+
+// TreeListing is the tool output. Truncated means max_entries cut the list;
+// Incomplete means GitHub itself stopped short (its limit is 100,000 entries),
+// so even Total undercounts.
+type TreeListing struct {
+	Ref        string          `json:"ref"`
+	Sha        string          `json:"sha"`
+	PathPrefix string          `json:"path_prefix,omitempty"`
+	Total      int             `json:"total"`
+	Truncated  bool            `json:"truncated"`
+	Incomplete bool            `json:"incomplete,omitempty"`
+	Entries    []TreeListEntry `json:"entries"`
+}
+
+// normalizeTreePrefix turns "/src/", "./src" and "src" into "src".
+func normalizeTreePrefix(prefix string) string {
+	prefix = strings.TrimPrefix(prefix, "./")
+	return strings.Trim(prefix, "/")
+}
+
+// listTree keeps the entries under prefix, up to max of them. Total counts
+// every match so callers can tell how much was left out.
+func listTree(ref string, tree GitTree, prefix string, max int) TreeListing {
+	prefix = normalizeTreePrefix(prefix)
+	listing := TreeListing{
+		Ref:        ref,
+		Sha:        tree.Sha,
+		PathPrefix: prefix,
+		Incomplete: tree.Truncated,
+		Entries:    []TreeListEntry{},
+	}
+	for _, e := range tree.Tree {
+		if prefix != "" && !strings.HasPrefix(e.Path, prefix+"/") {
+			continue
+		}
+		listing.Total++
+		if len(listing.Entries) < max {
+			listing.Entries = append(listing.Entries, TreeListEntry{Path: e.Path, Type: e.Type, Size: e.Size, Sha: e.Sha})
+		}
+	}
+	listing.Truncated = listing.Total > len(listing.Entries)
+	return listing
+}
+
+func treeGet(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	ref, _ := args["ref"].(string)
+	if ref == "" {
+		// The trees endpoint resolves HEAD to the default branch
+		ref = "HEAD"
+	}
+	prefix, _ := args["path_prefix"].(string)
+	max := treeDefaultMaxEntries
+	if value, ok := args["max_entries"].(float64); ok {
+		max = int(value)
+	}
+	if max < 1 || max > treeMaxEntries {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("max_entries must be between 1 and %d", treeMaxEntries)),
+			}},
+		}
+	}
+
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/trees/%s?recursive=1", owner, repo, url.PathEscape(ref))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting tree: ", u))
+
+	var tree GitTree
+	if status, err := githubGetJSON(apiKey, u, &tree); err != nil {
+		message := fmt.Sprintf("Failed to get tree: %s", err)
+		if status == 404 || status == 409 {
+			message = fmt.Sprintf("Failed to get tree: ref %s not found, or the repository is empty (%s)", ref, err)
+		}
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(message),
+			}},
+		}
+	}
+
+	listing := listTree(ref, tree, prefix, max)
+	if prefix != "" && listing.Total == 0 && !tree.Truncated {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Nothing found under %s at %s", listing.PathPrefix, ref)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(listing)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
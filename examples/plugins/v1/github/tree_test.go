@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const treeFixture = `{
+	"sha": "9fb037999f264ba9a7fc6274d15fa3ae2ab98312",
+	"truncated": false,
+	"tree": [
+		{"path": "README.md", "type": "blob", "size": 120, "sha": "a1"},
+		{"path": "src", "type": "tree", "sha": "b1"},
+		{"path": "src/main.rs", "type": "blob", "size": 2048, "sha": "b2"},
+		{"path": "src/plugin", "type": "tree", "sha": "b3"},
+		{"path": "src/plugin/mod.rs", "type": "blob", "size": 512, "sha": "b4"},
+		{"path": "srcgen/out.rs", "type": "blob", "size": 10, "sha": "c1"},
+		{"path": "vendor", "type": "commit", "sha": "d1"}
+	]
+}`
+
+func TestListTree(t *testing.T) {
+	var tree GitTree
+	if err := json.Unmarshal([]byte(treeFixture), &tree); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		prefix    string
+		max       int
+		want      []string
+		total     int
+		truncated bool
+	}{
+		{name: "everything", max: 100, want: []string{"README.md", "src", "src/main.rs", "src/plugin", "src/plugin/mod.rs", "srcgen/out.rs", "vendor"}, total: 7},
+		{name: "prefix matches whole directory names", prefix: "src", max: 100, want: []string{"src/main.rs", "src/plugin", "src/plugin/mod.rs"}, total: 3},
+		{name: "prefix is normalized", prefix: "./src/plugin/", max: 100, want: []string{"src/plugin/mod.rs"}, total: 1},
+		{name: "capped", max: 2, want: []string{"README.md", "src"}, total: 7, truncated: true},
+		{name: "capped under prefix", prefix: "/src", max: 1, want: []string{"src/main.rs"}, total: 3, truncated: true},
+		{name: "nothing under prefix", prefix: "docs", max: 100, want: []string{}, total: 0},
+	}
+	for _, tt := range tests {
+		listing := listTree("main", tree, tt.prefix, tt.max)
+		got := []string{}
+		for _, e := range listing.Entries {
+			got = append(got, e.Path)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+		if listing.Total != tt.total || listing.Truncated != tt.truncated {
+			t.Errorf("%s: total %d truncated %v, want %d %v", tt.name, listing.Total, listing.Truncated, tt.total, tt.truncated)
+		}
+	}
+}
+
+func TestListTreeKeepsSizesOfBlobsOnly(t *testing.T) {
+	var tree GitTree
+	if err := json.Unmarshal([]byte(treeFixture), &tree); err != nil {
+		t.Fatal(err)
+	}
+	out, _ := json.Marshal(listTree("main", tree, "src", 2).Entries)
+	want := `[{"path":"src/main.rs","type":"blob","size":2048,"sha":"b2"},{"path":"src/plugin","type":"tree","sha":"b3"}]`
+	if string(out) != want {
+		t.Errorf("got %s\nwant %s", out, want)
+	}
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	WhoamiTool = ToolDescription{
+		Name:        "gh-whoami",
+		Description: "Show which GitHub account the configured token belongs to and the OAuth scopes it was granted. Use it to debug 401 and 403 errors.",
+		InputSchema: schema{
+			"type":       "object",
+			"properties": props{},
+		},
+	}
+	GetUserTool = ToolDescription{
+		Name:        "gh-get-user",
+		Description: "Get a user's public profile: name, company, location, bio, repository and follower counts",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"username": prop("string", "The user's login"),
+			},
+			"required": []string{"username"},
+		},
+	}
+	ListFollowersTool = ToolDescription{
+		Name:        "gh-list-followers",
+		Description: "List the users following a user, with the total number of followers",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"username": prop("string", "The user's login"),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"username"},
+		},
+	}
+	ListFollowingTool = ToolDescription{
+		Name:        "gh-list-following",
+		Description: "List the users a user follows, with the total number they follow",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"username": prop("string", "The user's login"),
+				"per_page": prop("integer", "Number of results per page (max 100)"),
+				"page":     prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"username"},
+		},
+	}
+	CheckFollowingTool = ToolDescription{
+		Name:        "gh-check-following",
+		Description: "Check whether a user follows another user",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"username": prop("string", "The user's login"),
+				"target":   prop("string", "The login of the user who may be followed"),
+			},
+			"required": []string{"username", "target"},
+		},
+	}
+	UserTools = []ToolDescription{
+		WhoamiTool,
+		GetUserTool,
+		ListFollowersTool,
+		ListFollowingTool,
+		CheckFollowingTool,
+		ListUserEventsTool,
+	}
+)
+
+type AuthenticatedUser struct {
+	Login string `json:"login"`
+	Name  string `json:"name"`
+	Plan  *struct {
+		Name string `json:"name"`
+	} `json:"plan"`
+}
+
+type Whoami struct {
+	Login     string `json:"login"`
+	Name      string `json:"name"`
+	Plan      string `json:"plan,omitempty"`
+	TokenType string `json:"token_type"`
+	// nil when GitHub sent no X-OAuth-Scopes header
+	Scopes []string `json:"scopes"`
+	Note   string   `json:"note,omitempty"`
+}
+
+// tokenType names the kind of token from its documented prefix. The token
+// itself is never returned.
+func tokenType(token string) string {
+	for prefix, name := range map[string]string{
+		"github_pat_": "fine-grained personal access token",
+		"ghp_":        "classic personal access token",
+		"gho_":        "OAuth app token",
+		"ghu_":        "GitHub App user token",
+		"ghs_":        "GitHub App installation token",
+	} {
+		if strings.HasPrefix(token, prefix) {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// oauthScopes parses X-OAuth-Scopes, e.g. "repo, read:org". The header is
+// present but empty for a classic token without scopes.
+func oauthScopes(headers map[string]string) []string {
+	value, ok := responseHeader(headers, "X-OAuth-Scopes")
+	if !ok {
+		return nil
+	}
+	scopes := []string{}
+	for _, scope := range strings.Split(value, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+func whoami(user AuthenticatedUser, token string, headers map[string]string) Whoami {
+	result := Whoami{
+		Login:     user.Login,
+		Name:      user.Name,
+		TokenType: tokenType(token),
+		Scopes:    oauthScopes(headers),
+	}
+	if user.Plan != nil {
+		result.Plan = user.Plan.Name
+	}
+	if result.Scopes == nil {
+		result.Note = "GitHub reports scopes only for classic personal access tokens and OAuth app tokens; other tokens have fine-grained permissions, listed in the token's settings"
+	} else if len(result.Scopes) == 0 {
+		result.Note = "the token has no scopes, so it can only read public data"
+	}
+	return result
+}
+
+type UserProfile struct {
+	Login       string  `json:"login"`
+	Type        string  `json:"type"`
+	Name        *string `json:"name"`
+	Company     *string `json:"company"`
+	Location    *string `json:"location"`
+	Bio         *string `json:"bio"`
+	Blog        *string `json:"blog"`
+	PublicRepos int     `json:"public_repos"`
+	Followers   int     `json:"followers"`
+	Following   int     `json:"following"`
+	CreatedAt   string  `json:"created_at"`
+	HTMLURL     string  `json:"html_url"`
+}
+
+func usersGet(apiKey, username string) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/users/%s", url.PathEscape(username))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting user: ", u))
+
+	var profile UserProfile
+	status, err := githubGetJSON(apiKey, u, &profile)
+	if status == 404 {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("User %q not found", username)),
+			}},
+		}
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to get user: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(profile)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+type FollowUser struct {
+	Login   string `json:"login"`
+	HTMLURL string `json:"html_url"`
+}
+
+type FollowList struct {
+	Username string       `json:"username"`
+	Total    int          `json:"total"`
+	Users    []FollowUser `json:"users"`
+}
+
+// presenceResult maps the 204 and 404 that GitHub's yes-or-no check
+// endpoints answer with to a boolean.
+func presenceResult(status uint16, body []byte) (bool, error) {
+	switch status {
+	case 204:
+		return true, nil
+	case 404:
+		return false, nil
+	}
+	return false, githubStatusError(status, body)
+}
+
+// usersListFollows lists followers, or the users followed when direction
+// is "following". The total comes from the profile, so callers needn't
+// page through everything to learn it.
+func usersListFollows(apiKey, username, direction string, args map[string]interface{}) CallToolResult {
+	failed := func(message string) CallToolResult {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(message),
+			}},
+		}
+	}
+	base := fmt.Sprintf("https://api.github.com/users/%s", url.PathEscape(username))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting user: ", base))
+	var profile UserProfile
+	if status, err := githubGetJSON(apiKey, base, &profile); status == 404 {
+		return failed(fmt.Sprintf("User %q not found", username))
+	} else if err != nil {
+		return failed(fmt.Sprintf("Failed to get user: %s", err))
+	}
+
+	u := fmt.Sprintf("%s/%s?%s", base, direction, strings.Join(paginationParams(args), "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprintf("Listing %s: %s", direction, u))
+	list := FollowList{Username: profile.Login, Total: profile.Followers, Users: []FollowUser{}}
+	if direction == "following" {
+		list.Total = profile.Following
+	}
+	if _, err := githubGetJSON(apiKey, u, &list.Users); err != nil {
+		return failed(fmt.Sprintf("Failed to list %s: %s", direction, err))
+	}
+
+	responseJSON, err := json.Marshal(list)
+	if err != nil {
+		return failed(fmt.Sprintf("Failed to marshal response: %s", err))
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func usersCheckFollowing(apiKey, username, target string) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/users/%s/following/%s", url.PathEscape(username), url.PathEscape(target))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Checking following: ", u))
+
+	status, body, err := githubSend(apiKey, pdk.MethodGet, u, nil)
+	following := false
+	if err == nil {
+		following, err = presenceResult(status, body)
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to check whether %s follows %s: %s", username, target, err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(map[string]interface{}{
+		"username":  username,
+		"target":    target,
+		"following": following,
+	})
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func usersWhoami(apiKey string) CallToolResult {
+	u := "https://api.github.com/user"
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting authenticated user: ", u))
+	resp := githubRequest(apiKey, pdk.MethodGet, u, githubMediaType).Send()
+
+	if resp.Status() != 200 {
+		text := fmt.Sprintf("Failed to get the authenticated user: %s", githubStatusError(resp.Status(), resp.Body()))
+		if resp.Status() == 401 {
+			text = fmt.Sprintf("The configured api-key was rejected (401 %s); it may be expired or revoked", githubErrorMessage(resp.Body()))
+		}
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(text),
+			}},
+		}
+	}
+
+	var user AuthenticatedUser
+	if err := json.Unmarshal(resp.Body(), &user); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to unmarshal user: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(whoami(user, apiKey, resp.Headers()))
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTokenType(t *testing.T) {
+	for token, want := range map[string]string{
+		"ghp_abc":            "classic personal access token",
+		"github_pat_11ABC":   "fine-grained personal access token",
+		"ghs_abc":            "GitHub App installation token",
+		"0123456789abcdef01": "unknown",
+	} {
+		if got := tokenType(token); got != want {
+			t.Errorf("%s: got %q", token, got)
+		}
+	}
+}
+
+func TestOauthScopes(t *testing.T) {
+	if got, _ := json.Marshal(oauthScopes(map[string]string{"x-oauth-scopes": "repo, read:org,  gist"})); string(got) != `["repo","read:org","gist"]` {
+		t.Errorf("got %s", got)
+	}
+	if got := oauthScopes(map[string]string{"X-OAuth-Scopes": ""}); got == nil || len(got) != 0 {
+		t.Errorf("empty header: got %#v", got)
+	}
+	if got := oauthScopes(map[string]string{}); got != nil {
+		t.Errorf("no header: got %#v", got)
+	}
+}
+
+func TestWhoami(t *testing.T) {
+	var user AuthenticatedUser
+	if err := json.Unmarshal([]byte(`{"login":"octocat","name":"The Octocat","plan":{"name":"pro"}}`), &user); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := json.Marshal(whoami(user, "ghp_secret", map[string]string{"X-OAuth-Scopes": "repo"}))
+	if want := `{"login":"octocat","name":"The Octocat","plan":"pro","token_type":"classic personal access token","scopes":["repo"]}`; string(got) != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+	if strings.Contains(string(got), "ghp_secret") {
+		t.Error("the token must not be returned")
+	}
+
+	fineGrained := whoami(AuthenticatedUser{Login: "octocat"}, "github_pat_secret", map[string]string{})
+	if fineGrained.Scopes != nil || !strings.Contains(fineGrained.Note, "fine-grained") {
+		t.Errorf("fine-grained: got %+v", fineGrained)
+	}
+	if none := whoami(user, "ghp_x", map[string]string{"X-OAuth-Scopes": ""}); !strings.Contains(none.Note, "no scopes") {
+		t.Errorf("no scopes: got %+v", none)
+	}
+}
+
+func TestPresenceResult(t *testing.T) {
+	if following, err := presenceResult(204, nil); err != nil || !following {
+		t.Errorf("204: got %v, %v", following, err)
+	}
+	if following, err := presenceResult(404, []byte(`{"message":"Not Found"}`)); err != nil || following {
+		t.Errorf("404: got %v, %v", following, err)
+	}
+	if _, err := presenceResult(403, []byte(`{"message":"Forbidden"}`)); err == nil || err.Error() != "403 Forbidden" {
+		t.Errorf("403: got %v", err)
+	}
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/extism/go-pdk"
+)
+
+var (
+	ListWorkflowsTool = ToolDescription{
+		Name:        "gh-list-workflows",
+		Description: "List the GitHub Actions workflows of a repository with their id, name, file path and state",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":         prop("string", "The owner of the repository"),
+				"repo":          prop("string", "The repository name"),
+				"hide_disabled": prop("boolean", "Leave out disabled workflows (default false)"),
+				"per_page":      prop("integer", "Number of results per page (max 100)"),
+				"page":          prop("integer", "Page number for pagination"),
+			},
+			"required": []string{"owner", "repo"},
+		},
+	}
+	GetWorkflowTool = ToolDescription{
+		Name:        "gh-get-workflow",
+		Description: "Get a GitHub Actions workflow by id or file name",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":    prop("string", "The owner of the repository"),
+				"repo":     prop("string", "The repository name"),
+				"workflow": prop("string", "The workflow id or file name, e.g. 161335 or ci.yml"),
+			},
+			"required": []string{"owner", "repo", "workflow"},
+		},
+	}
+	DispatchWorkflowTool = ToolDescription{
+		Name:        "gh-dispatch-workflow",
+		Description: "Start a run of a workflow that has a workflow_dispatch trigger, and return the id and URL of the run it started once GitHub shows it",
+		InputSchema: schema{
+			"type": "object",
+			"properties": props{
+				"owner":    prop("string", "The owner of the repository"),
+				"repo":     prop("string", "The repository name"),
+				"workflow": prop("string", "The workflow id or file name, e.g. 161335 or ci.yml"),
+				"ref":      prop("string", "The branch or tag to run the workflow on"),
+				"inputs":   prop("object", "Values for the workflow's inputs, by input name"),
+			},
+			"required": []string{"owner", "repo", "workflow", "ref"},
+		},
+	}
+	ActionsTools = []ToolDescription{
+		ListWorkflowsTool,
+		GetWorkflowTool,
+		DispatchWorkflowTool,
+		ListWorkflowRunsTool,
+		GetRunLogsTool,
+		ListRunJobsTool,
+		ListArtifactsTool,
+		DownloadArtifactTool,
+		ListActionsSecretsTool,
+		SetActionsSecretTool,
+		ListActionsVariablesTool,
+		SetActionsVariableTool,
+	}
+)
+
+type Workflow struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	State   string `json:"state"`
+	HTMLURL string `json:"html_url"`
+}
+
+type WorkflowsPage struct {
+	TotalCount int        `json:"total_count"`
+	Workflows  []Workflow `json:"workflows"`
+}
+
+type WorkflowRun struct {
+	ID         int     `json:"id"`
+	Name       string  `json:"name"`
+	HeadBranch string  `json:"head_branch"`
+	HeadSHA    string  `json:"head_sha"`
+	Event      string  `json:"event"`
+	Status     string  `json:"status"`
+	Conclusion *string `json:"conclusion"`
+	RunNumber  int     `json:"run_number"`
+	CreatedAt  string  `json:"created_at"`
+	HTMLURL    string  `json:"html_url"`
+}
+
+const (
+	// Times the runs are listed after a dispatch to find the new one
+	dispatchPollAttempts = 3
+	dispatchPollInterval = 2 * time.Second
+	// Allowance for the clock of this machine being ahead of GitHub's
+	dispatchClockSkew = time.Minute
+)
+
+// workflowRef reads the workflow argument, an id or a file name such as
+// ci.yml or .github/workflows/ci.yml, as the API path expects it.
+func workflowRef(args map[string]interface{}) (string, error) {
+	switch value := args["workflow"].(type) {
+	case float64:
+		return fmt.Sprint(int(value)), nil
+	case string:
+		value = strings.TrimSpace(value)
+		if value == "" {
+			break
+		}
+		return url.PathEscape(value[strings.LastIndex(value, "/")+1:]), nil
+	}
+	return "", fmt.Errorf("workflow must be a workflow id or file name, e.g. ci.yml")
+}
+
+// filterWorkflows drops the disabled ones when hideDisabled is set. GitHub
+// reports them as disabled_manually, disabled_inactivity or disabled_fork.
+func filterWorkflows(workflows []Workflow, hideDisabled bool) []Workflow {
+	filtered := []Workflow{}
+	for _, w := range workflows {
+		if hideDisabled && strings.HasPrefix(w.State, "disabled") {
+			continue
+		}
+		filtered = append(filtered, w)
+	}
+	return filtered
+}
+
+func workflowsList(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/workflows?%s", owner, repo, strings.Join(paginationParams(args), "&"))
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing workflows: ", u))
+
+	var page WorkflowsPage
+	if _, err := githubGetJSON(apiKey, u, &page); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to list workflows: %s", err)),
+			}},
+		}
+	}
+
+	hideDisabled, _ := args["hide_disabled"].(bool)
+	page.Workflows = filterWorkflows(page.Workflows, hideDisabled)
+	responseJSON, err := json.Marshal(page)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+func workflowsGet(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	ref, err := workflowRef(args)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(err.Error()),
+			}},
+		}
+	}
+
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/workflows/%s", owner, repo, ref)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Getting workflow: ", u))
+	var workflow Workflow
+	if _, err := githubGetJSON(apiKey, u, &workflow); err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to get workflow: %s", err)),
+			}},
+		}
+	}
+
+	responseJSON, err := json.Marshal(workflow)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to marshal response: %s", err)),
+			}},
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(string(responseJSON)),
+		}},
+	}
+}
+
+// dispatchRunsURL lists the workflow's dispatched runs created since the
+// given time, newest first.
+func dispatchRunsURL(owner, repo, workflow string, since time.Time) string {
+	created := url.QueryEscape(">=" + since.UTC().Format(time.RFC3339))
+	return fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/workflows/%s/runs?event=workflow_dispatch&created=%s&per_page=20", owner, repo, workflow, created)
+}
+
+// dispatchedRun picks the run a dispatch started: the newest run on ref
+// that wasn't there before the dispatch. GitHub doesn't return the run, and
+// dispatches made at the same time by others can't be told apart.
+func dispatchedRun(runs []WorkflowRun, ref string, known map[int]bool) *WorkflowRun {
+	branch := strings.TrimPrefix(strings.TrimPrefix(ref, "refs/heads/"), "refs/tags/")
+	var found *WorkflowRun
+	for i, r := range runs {
+		if known[r.ID] || r.Event != "workflow_dispatch" || r.HeadBranch != branch {
+			continue
+		}
+		if found == nil || r.ID > found.ID {
+			found = &runs[i]
+		}
+	}
+	return found
+}
+
+func dispatchListRuns(apiKey, u string) ([]WorkflowRun, error) {
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Listing dispatched runs: ", u))
+	var page struct {
+		WorkflowRuns []WorkflowRun `json:"workflow_runs"`
+	}
+	if _, err := githubGetJSON(apiKey, u, &page); err != nil {
+		return nil, err
+	}
+	return page.WorkflowRuns, nil
+}
+
+func workflowsDispatch(apiKey, owner, repo string, args map[string]interface{}) CallToolResult {
+	workflow, err := workflowRef(args)
+	ref, _ := args["ref"].(string)
+	if err == nil && strings.TrimSpace(ref) == "" {
+		err = fmt.Errorf("ref is required")
+	}
+	body := map[string]interface{}{"ref": ref}
+	if inputs, ok := args["inputs"]; ok && inputs != nil {
+		if _, isObject := inputs.(map[string]interface{}); !isObject && err == nil {
+			err = fmt.Errorf("inputs must be an object of input names to values")
+		}
+		body["inputs"] = inputs
+	}
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Invalid dispatch: %s", err)),
+			}},
+		}
+	}
+
+	// Remember the runs that already exist so the new one stands out
+	runsURL := dispatchRunsURL(owner, repo, workflow, time.Now().Add(-dispatchClockSkew))
+	known := map[int]bool{}
+	before, _ := dispatchListRuns(apiKey, runsURL)
+	for _, r := range before {
+		known[r.ID] = true
+	}
+
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/workflows/%s/dispatches", owner, repo, workflow)
+	pdk.Log(pdk.LogDebug, fmt.Sprint("Dispatching workflow: ", u))
+	_, err = githubSendExpect(apiKey, pdk.MethodPost, u, body, 204)
+	if err != nil {
+		return CallToolResult{
+			IsError: some(true),
+			Content: []Content{{
+				Type: ContentTypeText,
+				Text: some(fmt.Sprintf("Failed to dispatch %s on %s: %s", workflow, ref, err)),
+			}},
+		}
+	}
+
+	for attempt := 0; attempt < dispatchPollAttempts; attempt++ {
+		time.Sleep(dispatchPollInterval)
+		runs, err := dispatchListRuns(apiKey, runsURL)
+		if err != nil {
+			continue
+		}
+		if run := dispatchedRun(runs, ref, known); run != nil {
+			responseJSON, err := json.Marshal(run)
+			if err != nil {
+				break
+			}
+			return CallToolResult{
+				Content: []Content{
+					{Type: ContentTypeText, Text: some(fmt.Sprintf("Started run %d: %s", run.ID, run.HTMLURL))},
+					{Type: ContentTypeText, Text: some(string(responseJSON))},
+				},
+			}
+		}
+	}
+
+	return CallToolResult{
+		Content: []Content{{
+			Type: ContentTypeText,
+			Text: some(fmt.Sprintf("Dispatched %s on %s, but the run didn't show up within %s; list the workflow's runs to find it", workflow, ref, time.Duration(dispatchPollAttempts)*dispatchPollInterval)),
+		}},
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWorkflowRef(t *testing.T) {
+	tests := []struct {
+		workflow interface{}
+		want     string
+		wantErr  bool
+	}{
+		{workflow: float64(161335), want: "161335"},
+		{workflow: "ci.yml", want: "ci.yml"},
+		{workflow: ".github/workflows/release.yaml", want: "release.yaml"},
+		{workflow: " ", wantErr: true},
+		{workflow: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := workflowRef(map[string]interface{}{"workflow": tt.workflow})
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%v: got %q, %v", tt.workflow, got, err)
+		}
+	}
+}
+
+func TestFilterWorkflows(t *testing.T) {
+	workflows := []Workflow{
+		{ID: 1, State: "active"},
+		{ID: 2, State: "disabled_manually"},
+		{ID: 3, State: "disabled_inactivity"},
+	}
+	if got := filterWorkflows(workflows, false); len(got) != 3 {
+		t.Errorf("expected all workflows, got %+v", got)
+	}
+	if got := filterWorkflows(workflows, true); len(got) != 1 || got[0].ID != 1 {
+		t.Errorf("expected only the active workflow, got %+v", got)
+	}
+}
+
+func TestDispatchRunsURL(t *testing.T) {
+	since := time.Date(2024, 5, 1, 10, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	got := dispatchRunsURL("acme", "api", "ci.yml", since)
+	want := "https://api.github.com/repos/acme/api/actions/workflows/ci.yml/runs?event=workflow_dispatch&created=%3E%3D2024-05-01T08%3A00%3A00Z&per_page=20"
+	if got != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestDispatchedRun(t *testing.T) {
+	runs := []WorkflowRun{
+		{ID: 30, Event: "workflow_dispatch", HeadBranch: "release"},
+		{ID: 20, Event: "workflow_dispatch", HeadBranch: "main"},
+		{ID: 25, Event: "push", HeadBranch: "main"},
+		{ID: 10, Event: "workflow_dispatch", HeadBranch: "main"},
+	}
+	if got := dispatchedRun(runs, "refs/heads/main", map[int]bool{10: true}); got == nil || got.ID != 20 {
+		t.Errorf("expected run 20, got %+v", got)
+	}
+	if got := dispatchedRun(runs, "main", map[int]bool{10: true, 20: true}); got != nil {
+		t.Errorf("expected no run, got %+v", got)
+	}
+}
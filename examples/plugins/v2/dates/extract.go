@@ -0,0 +1,714 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	// Embed the IANA database so results don't depend on the host having one.
+	_ "time/tzdata"
+)
+
+// Extraction is one date or time expression found in the text. Start and End
+// are character (not byte) offsets into the text, End exclusive.
+type Extraction struct {
+	Text       string      `json:"text"`
+	Start      int         `json:"start"`
+	End        int         `json:"end"`
+	Kind       string      `json:"kind"`
+	Value      string      `json:"value,omitempty"`
+	Range      *Range      `json:"range,omitempty"`
+	Ambiguous  bool        `json:"ambiguous,omitempty"`
+	Candidates []Candidate `json:"candidates,omitempty"`
+}
+
+type Range struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Candidate is one reading of an ambiguous numeric date, with the field
+// order it assumes and locales that write dates that way.
+type Candidate struct {
+	Value   string   `json:"value"`
+	Order   string   `json:"order"`
+	Locales []string `json:"locales"`
+}
+
+const (
+	KindDate          = "date"
+	KindDateTime      = "datetime"
+	KindDateRange     = "date_range"
+	KindDateTimeRange = "datetime_range"
+)
+
+var orderLocales = map[string][]string{
+	"MDY": {"en-US"},
+	"DMY": {"en-GB", "de-DE", "fr-FR", "es-ES"},
+	"YMD": {"ja-JP", "zh-CN", "ko-KR"},
+}
+
+// atom is an expression found by one of the patterns, before it is combined
+// with its neighbours. Offsets are in bytes.
+type atom struct {
+	start, end int
+
+	// A day (hasTime false) or an instant, or the first of a range
+	t       time.Time
+	until   time.Time
+	hasTime bool
+	isRange bool
+	// The year was not written and upcoming picked it
+	yearInferred bool
+
+	// A time of day, or a range of them, that still needs a date
+	timeOnly    bool
+	clock       [2]int // minutes after midnight
+	clockRanged bool
+
+	candidates []Candidate
+}
+
+type resolver struct {
+	ref time.Time
+	loc *time.Location
+}
+
+func (r resolver) today() time.Time {
+	y, m, d := r.ref.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, r.loc)
+}
+
+// upcoming returns the next month/day on or after today, skipping years in
+// which the day doesn't exist (February 29).
+func (r resolver) upcoming(month time.Month, day int) (time.Time, bool) {
+	today := r.today()
+	for year := today.Year(); year < today.Year()+8; year++ {
+		t, ok := validDate(year, month, day, r.loc)
+		if ok && !t.Before(today) {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func validDate(year int, month time.Month, day int, loc *time.Location) (time.Time, bool) {
+	if month < 1 || month > 12 || day < 1 {
+		return time.Time{}, false
+	}
+	t := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	return t, t.Month() == month && t.Day() == day
+}
+
+// addMonths moves a date by whole months, clamping to the end of the month
+// instead of overflowing: January 31 plus one month is February 28 or 29.
+func addMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location()).AddDate(0, months, 0)
+	last := first.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// expandYear reads a two digit year the way POSIX strptime does: 69-99 are
+// 1969-1999 and 00-68 are 2000-2068.
+func expandYear(s string) int {
+	y, _ := strconv.Atoi(s)
+	if len(s) > 2 {
+		return y
+	}
+	if y >= 69 {
+		return 1900 + y
+	}
+	return 2000 + y
+}
+
+const (
+	monthPattern   = `(january|february|march|april|may|june|july|august|september|october|november|december|jan|feb|mar|apr|jun|jul|aug|sept|sep|oct|nov|dec)`
+	ordinalPattern = `(?:st|nd|rd|th)?`
+	countPattern   = `(\d+|an|a|one|two|three|four|five|six|seven|eight|nine|ten|eleven|twelve)`
+	unitPattern    = `(minute|hour|day|week|fortnight|month|year)s?`
+	weekdayPattern = `(monday|tuesday|wednesday|thursday|friday|saturday|sunday)`
+)
+
+var months = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+	"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+	"sep": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+}
+
+func parseMonth(s string) time.Month {
+	return months[strings.ToLower(s)[:3]]
+}
+
+var counts = map[string]int{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+}
+
+func parseCount(s string) int {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+	return counts[strings.ToLower(s)]
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+// startOfWeek returns the Monday of the week containing t.
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return t.AddDate(0, 0, -offset)
+}
+
+// offset applies "in 3 weeks" style offsets. Offsets of a day or more keep
+// day granularity; hours and minutes give an instant.
+func (r resolver) offset(n int, unit string) atom {
+	switch unit {
+	case "minute":
+		return atom{t: r.ref.Add(time.Duration(n) * time.Minute), hasTime: true}
+	case "hour":
+		return atom{t: r.ref.Add(time.Duration(n) * time.Hour), hasTime: true}
+	case "day":
+		return atom{t: r.today().AddDate(0, 0, n)}
+	case "week":
+		return atom{t: r.today().AddDate(0, 0, 7*n)}
+	case "fortnight":
+		return atom{t: r.today().AddDate(0, 0, 14*n)}
+	case "month":
+		return atom{t: addMonths(r.today(), n)}
+	default:
+		return atom{t: addMonths(r.today(), 12*n)}
+	}
+}
+
+type matcher struct {
+	re    *regexp.Regexp
+	build func(r resolver, m []string) (atom, bool)
+}
+
+var matchers = []matcher{
+	// 2024-03-05, 2024-03-05T14:30, 2024-03-05 14:30:00+02:00
+	{
+		regexp.MustCompile(`(?i)\b(\d{4})-(\d{1,2})-(\d{1,2})(?:[t ](\d{1,2}):(\d{2})(?::(\d{2}))?(z|[+-]\d{2}:?\d{2})?)?`),
+		func(r resolver, m []string) (atom, bool) {
+			year, _ := strconv.Atoi(m[1])
+			month, _ := strconv.Atoi(m[2])
+			day, _ := strconv.Atoi(m[3])
+			date, ok := validDate(year, time.Month(month), day, r.loc)
+			if !ok {
+				return atom{}, false
+			}
+			if m[4] == "" {
+				return atom{t: date}, true
+			}
+			hour, _ := strconv.Atoi(m[4])
+			minute, _ := strconv.Atoi(m[5])
+			second, _ := strconv.Atoi(m[6])
+			if hour > 23 || minute > 59 || second > 59 {
+				return atom{}, false
+			}
+			loc := r.loc
+			if zone := strings.ToUpper(m[7]); zone == "Z" {
+				loc = time.UTC
+			} else if zone != "" {
+				zone = strings.Replace(zone, ":", "", 1)
+				hours, _ := strconv.Atoi(zone[1:3])
+				minutes, _ := strconv.Atoi(zone[3:5])
+				seconds := hours*3600 + minutes*60
+				if zone[0] == '-' {
+					seconds = -seconds
+				}
+				loc = time.FixedZone("", seconds)
+			}
+			return atom{t: time.Date(year, time.Month(month), day, hour, minute, second, 0, loc), hasTime: true}, true
+		},
+	},
+	// 03/04/05, 3/4/2024, 04.03.2024, 3/4
+	{
+		regexp.MustCompile(`\b(\d{1,4})([/.])(\d{1,2})(?:([/.])(\d{2,4}))?\b`),
+		func(r resolver, m []string) (atom, bool) {
+			return r.numericDate(m[1], m[2], m[3], m[4], m[5])
+		},
+	},
+	// March 5-8, Mar 5 to 8th, 2024
+	{
+		regexp.MustCompile(`(?i)\b` + monthPattern + `\.?\s+(\d{1,2})` + ordinalPattern + `\s*(?:-|–|to|through|thru|until)\s*(\d{1,2})` + ordinalPattern + `(?:,?\s+(\d{4}))?\b`),
+		func(r resolver, m []string) (atom, bool) {
+			from, ok := r.monthDay(m[1], m[2], m[4])
+			if !ok {
+				return atom{}, false
+			}
+			lastDay, _ := strconv.Atoi(m[3])
+			if from.yearInferred {
+				// A range that is still running is this year's, as in combine
+				if earlier, ok := yearEarlier(from.t); ok {
+					if end, ok := validDate(earlier.Year(), earlier.Month(), lastDay, r.loc); ok && !end.Before(r.today()) {
+						from.t = earlier
+					}
+				}
+			}
+			until, ok := validDate(from.t.Year(), from.t.Month(), lastDay, r.loc)
+			if !ok || until.Before(from.t) {
+				return atom{}, false
+			}
+			return atom{t: from.t, until: until, isRange: true}, true
+		},
+	},
+	// March 5, Mar. 5th, 2024
+	{
+		regexp.MustCompile(`(?i)\b` + monthPattern + `\.?\s+(\d{1,2})` + ordinalPattern + `(?:,?\s+(\d{4}))?\b`),
+		func(r resolver, m []string) (atom, bool) {
+			return r.monthDay(m[1], m[2], m[3])
+		},
+	},
+	// 5 March, 5th of March 2024
+	{
+		regexp.MustCompile(`(?i)\b(\d{1,2})` + ordinalPattern + `(?:\s+of)?\s+` + monthPattern + `\b\.?(?:,?\s+(\d{4})\b)?`),
+		func(r resolver, m []string) (atom, bool) {
+			return r.monthDay(m[2], m[1], m[3])
+		},
+	},
+	// March 2024
+	{
+		regexp.MustCompile(`(?i)\b` + monthPattern + `\.?\s+(\d{4})\b`),
+		func(r resolver, m []string) (atom, bool) {
+			year, _ := strconv.Atoi(m[2])
+			first := time.Date(year, parseMonth(m[1]), 1, 0, 0, 0, 0, r.loc)
+			return atom{t: first, until: first.AddDate(0, 1, -1), isRange: true}, true
+		},
+	},
+	// today, tomorrow, the day after tomorrow
+	{
+		regexp.MustCompile(`(?i)\b(?:the\s+)?(day\s+after\s+tomorrow|day\s+before\s+yesterday|today|tonight|tomorrow|yesterday)\b`),
+		func(r resolver, m []string) (atom, bool) {
+			days := map[string]int{"today": 0, "tonight": 0, "tomorrow": 1, "yesterday": -1}
+			word := strings.ToLower(strings.Join(strings.Fields(m[1]), " "))
+			switch word {
+			case "day after tomorrow":
+				return atom{t: r.today().AddDate(0, 0, 2)}, true
+			case "day before yesterday":
+				return atom{t: r.today().AddDate(0, 0, -2)}, true
+			default:
+				return atom{t: r.today().AddDate(0, 0, days[word])}, true
+			}
+		},
+	},
+	// Tuesday, this Tuesday, next Tuesday, last Tuesday
+	{
+		regexp.MustCompile(`(?i)\b(?:(this|next|last|coming)\s+)?` + weekdayPattern + `\b`),
+		func(r resolver, m []string) (atom, bool) {
+			return atom{t: r.weekday(strings.ToLower(m[1]), weekdays[strings.ToLower(m[2])])}, true
+		},
+	},
+	// in 3 weeks, in an hour
+	{
+		regexp.MustCompile(`(?i)\bin\s+` + countPattern + `\s+` + unitPattern + `\b`),
+		func(r resolver, m []string) (atom, bool) {
+			return r.offset(parseCount(m[1]), strings.ToLower(m[2])), true
+		},
+	},
+	// 2 days ago, a week from now
+	{
+		regexp.MustCompile(`(?i)\b` + countPattern + `\s+` + unitPattern + `\s+(ago|from\s+now)\b`),
+		func(r resolver, m []string) (atom, bool) {
+			n := parseCount(m[1])
+			if strings.ToLower(m[3]) == "ago" {
+				n = -n
+			}
+			return r.offset(n, strings.ToLower(m[2])), true
+		},
+	},
+	// next week, this weekend, last month, next year
+	{
+		regexp.MustCompile(`(?i)\b(this|next|last)\s+(week|weekend|month|year)\b`),
+		func(r resolver, m []string) (atom, bool) {
+			return r.period(strings.ToLower(m[1]), strings.ToLower(m[2])), true
+		},
+	},
+	// 3pm, 3:30 p.m., 11 am
+	{
+		regexp.MustCompile(`(?i)\b(\d{1,2})(?::(\d{2}))?\s*(a\.m\.|p\.m\.|am|pm)`),
+		func(r resolver, m []string) (atom, bool) {
+			hour, _ := strconv.Atoi(m[1])
+			minute, _ := strconv.Atoi(m[2])
+			if hour < 1 || hour > 12 || minute > 59 {
+				return atom{}, false
+			}
+			hour %= 12
+			if strings.HasPrefix(strings.ToLower(m[3]), "p") {
+				hour += 12
+			}
+			return atom{timeOnly: true, clock: [2]int{hour*60 + minute}}, true
+		},
+	},
+	// 14:30
+	{
+		regexp.MustCompile(`\b(\d{1,2}):(\d{2})\b`),
+		func(r resolver, m []string) (atom, bool) {
+			hour, _ := strconv.Atoi(m[1])
+			minute, _ := strconv.Atoi(m[2])
+			if hour > 23 || minute > 59 {
+				return atom{}, false
+			}
+			return atom{timeOnly: true, clock: [2]int{hour*60 + minute}}, true
+		},
+	},
+	// noon, midnight
+	{
+		regexp.MustCompile(`(?i)\b(noon|midday|midnight)\b`),
+		func(r resolver, m []string) (atom, bool) {
+			if strings.ToLower(m[1]) == "midnight" {
+				return atom{timeOnly: true}, true
+			}
+			return atom{timeOnly: true, clock: [2]int{12 * 60}}, true
+		},
+	},
+}
+
+func (r resolver) monthDay(monthName, dayText, yearText string) (atom, bool) {
+	month := parseMonth(monthName)
+	day, _ := strconv.Atoi(dayText)
+	if yearText == "" {
+		t, ok := r.upcoming(month, day)
+		return atom{t: t, yearInferred: true}, ok
+	}
+	year, _ := strconv.Atoi(yearText)
+	t, ok := validDate(year, month, day, r.loc)
+	return atom{t: t}, ok
+}
+
+// numericDate reads all-digit dates. Four digit leading fields are
+// year-month-day. Otherwise every field order that gives a real date is kept,
+// and when more than one does the date is ambiguous: 03/04/05 is March 4 in
+// the US, 3 April in the UK and 2003-04-05 in Japan. Dotted dates are never
+// month first.
+func (r resolver) numericDate(a, sep1, b, sep2, c string) (atom, bool) {
+	if len(a) == 3 || len(c) == 3 || (c != "" && sep1 != sep2) || (c == "" && (sep1 != "/" || len(a) > 2)) {
+		return atom{}, false
+	}
+	x, _ := strconv.Atoi(a)
+	y, _ := strconv.Atoi(b)
+
+	if c == "" {
+		orders := []string{"MDY", "DMY"}
+		var candidates []Candidate
+		for _, order := range orders {
+			month, day := x, y
+			if order == "DMY" {
+				month, day = y, x
+			}
+			if month < 1 || month > 12 {
+				continue
+			}
+			if t, ok := r.upcoming(time.Month(month), day); ok {
+				candidates = append(candidates, Candidate{Value: t.Format("2006-01-02"), Order: order, Locales: orderLocales[order]})
+			}
+		}
+		return r.fromCandidates(candidates)
+	}
+
+	if len(a) == 4 {
+		z, _ := strconv.Atoi(c)
+		t, ok := validDate(x, time.Month(y), z, r.loc)
+		return atom{t: t}, ok
+	}
+
+	orders := []string{"MDY", "DMY", "YMD"}
+	if sep1 == "." {
+		orders = []string{"DMY", "YMD"}
+	}
+	var candidates []Candidate
+	for _, order := range orders {
+		var year, month, day int
+		switch order {
+		case "MDY":
+			year, month, day = expandYear(c), x, y
+		case "DMY":
+			year, month, day = expandYear(c), y, x
+		case "YMD":
+			if len(c) != 2 {
+				continue
+			}
+			z, _ := strconv.Atoi(c)
+			year, month, day = expandYear(a), y, z
+		}
+		if t, ok := validDate(year, time.Month(month), day, r.loc); ok {
+			candidates = append(candidates, Candidate{Value: t.Format("2006-01-02"), Order: order, Locales: orderLocales[order]})
+		}
+	}
+	return r.fromCandidates(candidates)
+}
+
+// fromCandidates turns the valid readings of a numeric date into an atom.
+// Readings that agree collapse into one, and a single reading is not
+// ambiguous.
+func (r resolver) fromCandidates(candidates []Candidate) (atom, bool) {
+	distinct := map[string]bool{}
+	for _, c := range candidates {
+		distinct[c.Value] = true
+	}
+	switch len(distinct) {
+	case 0:
+		return atom{}, false
+	case 1:
+		t, _ := time.ParseInLocation("2006-01-02", candidates[0].Value, r.loc)
+		return atom{t: t}, true
+	default:
+		return atom{candidates: candidates}, true
+	}
+}
+
+func (r resolver) weekday(modifier string, day time.Weekday) time.Time {
+	today := r.today()
+	switch modifier {
+	case "this":
+		return startOfWeek(today).AddDate(0, 0, (int(day)+6)%7)
+	case "next":
+		return startOfWeek(today).AddDate(0, 0, 7+(int(day)+6)%7)
+	case "last":
+		back := (int(today.Weekday()) - int(day) + 7) % 7
+		if back == 0 {
+			back = 7
+		}
+		return today.AddDate(0, 0, -back)
+	default:
+		ahead := (int(day) - int(today.Weekday()) + 7) % 7
+		if ahead == 0 {
+			ahead = 7
+		}
+		return today.AddDate(0, 0, ahead)
+	}
+}
+
+func (r resolver) period(modifier, unit string) atom {
+	shift := map[string]int{"this": 0, "next": 1, "last": -1}[modifier]
+	today := r.today()
+	switch unit {
+	case "week":
+		start := startOfWeek(today).AddDate(0, 0, 7*shift)
+		return atom{t: start, until: start.AddDate(0, 0, 6), isRange: true}
+	case "weekend":
+		saturday := startOfWeek(today).AddDate(0, 0, 7*shift+5)
+		return atom{t: saturday, until: saturday.AddDate(0, 0, 1), isRange: true}
+	case "month":
+		start := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, r.loc).AddDate(0, shift, 0)
+		return atom{t: start, until: start.AddDate(0, 1, -1), isRange: true}
+	default:
+		start := time.Date(today.Year()+shift, time.January, 1, 0, 0, 0, 0, r.loc)
+		return atom{t: start, until: start.AddDate(1, 0, -1), isRange: true}
+	}
+}
+
+func (a atom) isDay() bool {
+	return !a.timeOnly && !a.isRange && !a.hasTime && a.candidates == nil
+}
+
+func (a atom) isInstant() bool {
+	return !a.timeOnly && !a.isRange && a.hasTime
+}
+
+var (
+	rangeSeparator    = regexp.MustCompile(`(?i)^\s*(?:-|–|—|to|until|till|through|thru|and)\s*$`)
+	rangeIntroduction = regexp.MustCompile(`(?i)\b(from|between)\s+$`)
+	dateTimeJoin      = regexp.MustCompile(`(?i)^\s*(?:,|at|@|from)?\s*$`)
+	timeDateJoin      = regexp.MustCompile(`(?i)^\s*(?:,|on)?\s*$`)
+)
+
+// findAtoms runs every pattern over the text and keeps the earliest, then
+// longest, non-overlapping matches.
+func findAtoms(text string, r resolver) []atom {
+	var found []atom
+	for _, m := range matchers {
+		for _, idx := range m.re.FindAllStringSubmatchIndex(text, -1) {
+			// Patterns ending in a dot (p.m.) can't use \b, so check the
+			// boundary here
+			if idx[1] < len(text) {
+				next, _ := utf8.DecodeRuneInString(text[idx[1]:])
+				if unicode.IsLetter(next) || unicode.IsDigit(next) {
+					continue
+				}
+			}
+			groups := make([]string, len(idx)/2)
+			for i := range groups {
+				if idx[2*i] >= 0 {
+					groups[i] = text[idx[2*i]:idx[2*i+1]]
+				}
+			}
+			a, ok := m.build(r, groups)
+			if !ok {
+				continue
+			}
+			a.start, a.end = idx[0], idx[1]
+			found = append(found, a)
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].start != found[j].start {
+			return found[i].start < found[j].start
+		}
+		return found[i].end > found[j].end
+	})
+	var atoms []atom
+	for _, a := range found {
+		if len(atoms) > 0 && a.start < atoms[len(atoms)-1].end {
+			continue
+		}
+		atoms = append(atoms, a)
+	}
+	return atoms
+}
+
+// yearEarlier is t one year back, unless that day doesn't exist.
+func yearEarlier(t time.Time) (time.Time, bool) {
+	earlier := time.Date(t.Year()-1, t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, t.Location())
+	return earlier, earlier.Day() == t.Day()
+}
+
+// at puts a time of day on a day. A clock time that falls in a DST gap is
+// normalised by time.Date.
+func at(day time.Time, minutes int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, minutes, 0, 0, day.Location())
+}
+
+// combine merges neighbouring atoms: times into time ranges ("3pm to 5pm"),
+// days with times ("tomorrow at 3pm", "noon on Friday"), lone times onto
+// today, and finally days or instants into ranges ("from May 1 to May 3").
+func combine(text string, atoms []atom, r resolver) []atom {
+	merge := func(atoms []atom, join func(a, b atom, between string) (atom, bool)) []atom {
+		var out []atom
+		for _, a := range atoms {
+			if n := len(out); n > 0 {
+				if merged, ok := join(out[n-1], a, text[out[n-1].end:a.start]); ok {
+					out[n-1] = merged
+					continue
+				}
+			}
+			out = append(out, a)
+		}
+		return out
+	}
+
+	atoms = merge(atoms, func(a, b atom, between string) (atom, bool) {
+		if !a.timeOnly || a.clockRanged || !b.timeOnly || b.clockRanged || !rangeSeparator.MatchString(between) || strings.Contains(strings.ToLower(between), "and") {
+			return atom{}, false
+		}
+		a.clock[1] = b.clock[0]
+		a.clockRanged = true
+		a.end = b.end
+		return a, true
+	})
+
+	withTime := func(day, clock atom, start, end int) atom {
+		merged := atom{start: start, end: end, t: at(day.t, clock.clock[0]), hasTime: true, yearInferred: day.yearInferred}
+		if clock.clockRanged {
+			merged.until = at(day.t, clock.clock[1])
+			merged.isRange = true
+		}
+		return merged
+	}
+	atoms = merge(atoms, func(a, b atom, between string) (atom, bool) {
+		switch {
+		case a.isDay() && b.timeOnly && dateTimeJoin.MatchString(between):
+			return withTime(a, b, a.start, b.end), true
+		case a.timeOnly && b.isDay() && timeDateJoin.MatchString(between):
+			return withTime(b, a, a.start, b.end), true
+		}
+		return atom{}, false
+	})
+
+	for i, a := range atoms {
+		if a.timeOnly {
+			atoms[i] = withTime(atom{t: r.today()}, a, a.start, a.end)
+		}
+	}
+
+	return merge(atoms, func(a, b atom, between string) (atom, bool) {
+		sameKind := (a.isDay() && b.isDay()) || (a.isInstant() && b.isInstant())
+		if !sameKind || !rangeSeparator.MatchString(between) {
+			return atom{}, false
+		}
+		if b.t.Before(a.t) && a.yearInferred {
+			// "from March 5 to March 8" on March 6 is the range in progress,
+			// not one starting next March
+			if earlier, ok := yearEarlier(a.t); ok {
+				a.t = earlier
+			}
+		}
+		if b.t.Before(a.t) {
+			return atom{}, false
+		}
+		intro := rangeIntroduction.FindStringIndex(text[:a.start])
+		isAnd := strings.EqualFold(strings.TrimSpace(between), "and")
+		if isAnd && (intro == nil || !strings.EqualFold(strings.TrimSpace(text[intro[0]:a.start]), "between")) {
+			return atom{}, false
+		}
+		merged := atom{start: a.start, end: b.end, t: a.t, until: b.t, hasTime: a.hasTime, isRange: true}
+		if intro != nil {
+			merged.start = intro[0]
+		}
+		return merged, true
+	})
+}
+
+func render(t time.Time, hasTime bool) string {
+	if !hasTime {
+		return t.Format("2006-01-02")
+	}
+	return t.Format(time.RFC3339)
+}
+
+// ExtractDates finds date and time expressions in text and resolves them
+// against the reference time, in the reference time's location. A locale
+// such as "en-GB" picks a value for ambiguous numeric dates; the other
+// readings are still listed.
+func ExtractDates(text string, ref time.Time, locale string) []Extraction {
+	r := resolver{ref: ref, loc: ref.Location()}
+	atoms := combine(text, findAtoms(text, r), r)
+
+	extractions := make([]Extraction, 0, len(atoms))
+	for _, a := range atoms {
+		e := Extraction{
+			Text:  text[a.start:a.end],
+			Start: utf8.RuneCountInString(text[:a.start]),
+			End:   utf8.RuneCountInString(text[:a.end]),
+		}
+		switch {
+		case a.candidates != nil:
+			e.Kind = KindDate
+			e.Ambiguous = true
+			e.Candidates = a.candidates
+			for _, c := range a.candidates {
+				for _, l := range c.Locales {
+					if locale != "" && strings.EqualFold(l, locale) {
+						e.Value = c.Value
+					}
+				}
+			}
+		case a.isRange:
+			e.Kind = KindDateRange
+			if a.hasTime {
+				e.Kind = KindDateTimeRange
+			}
+			e.Range = &Range{Start: render(a.t, a.hasTime), End: render(a.until, a.hasTime)}
+		case a.hasTime:
+			e.Kind = KindDateTime
+			e.Value = render(a.t, true)
+		default:
+			e.Kind = KindDate
+			e.Value = render(a.t, false)
+		}
+		extractions = append(extractions, e)
+	}
+	return extractions
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// reference is Wednesday 2024-03-06 10:00 in New York, four days before the
+// clocks go forward.
+func reference(t *testing.T) time.Time {
+	t.Helper()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return time.Date(2024, 3, 6, 10, 0, 0, 0, loc)
+}
+
+// describe renders an extraction as "text => value" or "text => start/end".
+func describe(e Extraction) string {
+	switch {
+	case e.Ambiguous:
+		values := make([]string, len(e.Candidates))
+		for i, c := range e.Candidates {
+			values[i] = c.Order + ":" + c.Value
+		}
+		return fmt.Sprintf("%s => ambiguous %s", e.Text, strings.Join(values, " "))
+	case e.Range != nil:
+		return fmt.Sprintf("%s => %s/%s", e.Text, e.Range.Start, e.Range.End)
+	default:
+		return fmt.Sprintf("%s => %s", e.Text, e.Value)
+	}
+}
+
+func TestExtractDates(t *testing.T) {
+	ref := reference(t)
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Ship on 2024-03-15.", []string{"2024-03-15 => 2024-03-15"}},
+		{"Deploy at 2024-03-15T14:30:00Z", []string{"2024-03-15T14:30:00Z => 2024-03-15T14:30:00Z"}},
+		{"Deploy at 2024-03-15 14:30", []string{"2024-03-15 14:30 => 2024-03-15T14:30:00-04:00"}},
+		{"due 03/04/05", []string{"03/04/05 => ambiguous MDY:2005-03-04 DMY:2005-04-03 YMD:2003-04-05"}},
+		{"due 13/04/2024", []string{"13/04/2024 => 2024-04-13"}},
+		{"due 04/05/2024", []string{"04/05/2024 => ambiguous MDY:2024-04-05 DMY:2024-05-04"}},
+		{"due 04.05.2024", []string{"04.05.2024 => 2024-05-04"}},
+		{"due 2024.05.04", []string{"2024.05.04 => 2024-05-04"}},
+		{"party on 12/25", []string{"12/25 => 2024-12-25"}},
+		{"version 1.2.3 and page 3 of 10", nil},
+		{"March 5th", []string{"March 5th => 2025-03-05"}},
+		{"the 3rd of May", []string{"3rd of May => 2024-05-03"}},
+		{"5 March 2025", []string{"5 March 2025 => 2025-03-05"}},
+		{"Mar. 7, 2024", []string{"Mar. 7, 2024 => 2024-03-07"}},
+		{"all of March 2024", []string{"March 2024 => 2024-03-01/2024-03-31"}},
+		{"today", []string{"today => 2024-03-06"}},
+		{"day after tomorrow", []string{"day after tomorrow => 2024-03-08"}},
+		{"Friday", []string{"Friday => 2024-03-08"}},
+		{"this Friday", []string{"this Friday => 2024-03-08"}},
+		{"next Tuesday", []string{"next Tuesday => 2024-03-12"}},
+		{"last Monday", []string{"last Monday => 2024-03-04"}},
+		{"in 3 weeks", []string{"in 3 weeks => 2024-03-27"}},
+		{"2 days ago", []string{"2 days ago => 2024-03-04"}},
+		{"in a month", []string{"in a month => 2024-04-06"}},
+		{"next week", []string{"next week => 2024-03-11/2024-03-17"}},
+		{"this weekend", []string{"this weekend => 2024-03-09/2024-03-10"}},
+		{"next month", []string{"next month => 2024-04-01/2024-04-30"}},
+		{"last year", []string{"last year => 2023-01-01/2023-12-31"}},
+		{"tomorrow at 3pm", []string{"tomorrow at 3pm => 2024-03-07T15:00:00-05:00"}},
+		{"3pm tomorrow", []string{"3pm tomorrow => 2024-03-07T15:00:00-05:00"}},
+		{"lunch at noon", []string{"noon => 2024-03-06T12:00:00-05:00"}},
+		{"9am-5pm on Friday", []string{"9am-5pm on Friday => 2024-03-08T09:00:00-05:00/2024-03-08T17:00:00-05:00"}},
+		// The clocks go forward on March 10
+		{"Monday at 09:00", []string{"Monday at 09:00 => 2024-03-11T09:00:00-04:00"}},
+		{"from March 5 to March 8", []string{"from March 5 to March 8 => 2024-03-05/2024-03-08"}},
+		{"March 5-8", []string{"March 5-8 => 2024-03-05/2024-03-08"}},
+		{"between May 1 and May 3", []string{"between May 1 and May 3 => 2024-05-01/2024-05-03"}},
+		{"May 1 and May 3", []string{"May 1 => 2024-05-01", "May 3 => 2024-05-03"}},
+		{"Out Friday, back 2024-03-18", []string{"Friday => 2024-03-08", "2024-03-18 => 2024-03-18"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			var got []string
+			for _, e := range ExtractDates(tt.text, ref, "") {
+				got = append(got, describe(e))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractDates(%q)\n got %q\nwant %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractDatesOffsetsCountCharacters(t *testing.T) {
+	text := "Réunion prévue le 2024-04-01 ☕"
+	got := ExtractDates(text, reference(t), "")
+	if len(got) != 1 {
+		t.Fatalf("got %d extractions, want 1", len(got))
+	}
+	runes := []rune(text)
+	if got[0].Start != 18 || got[0].End != 28 || string(runes[got[0].Start:got[0].End]) != "2024-04-01" {
+		t.Errorf("span [%d,%d), want [18,28) covering 2024-04-01", got[0].Start, got[0].End)
+	}
+}
+
+func TestExtractDatesLocalePicksValue(t *testing.T) {
+	ref := reference(t)
+	for locale, want := range map[string]string{
+		"":      "",
+		"en-US": "2005-03-04",
+		"en-gb": "2005-04-03",
+		"ja-JP": "2003-04-05",
+	} {
+		got := ExtractDates("03/04/05", ref, locale)
+		if len(got) != 1 {
+			t.Fatalf("locale %q: got %d extractions, want 1", locale, len(got))
+		}
+		if got[0].Value != want || !got[0].Ambiguous || len(got[0].Candidates) != 3 {
+			t.Errorf("locale %q: value %q ambiguous %v with %d candidates, want %q, ambiguous, 3 candidates", locale, got[0].Value, got[0].Ambiguous, len(got[0].Candidates), want)
+		}
+	}
+}
+
+func TestExtractDatesInReferenceTimezone(t *testing.T) {
+	// 02:00 UTC on the 7th is still the 6th in New York
+	ref := time.Date(2024, 3, 7, 2, 0, 0, 0, time.UTC)
+	result := extractDates(map[string]any{
+		"text":           "tomorrow",
+		"reference_time": ref.Format(time.RFC3339),
+		"timezone":       "America/New_York",
+	}, time.Now())
+	if result.IsError != nil && *result.IsError {
+		t.Fatalf("unexpected error: %s", result.Content[0].Text.Text)
+	}
+	dates := result.StructuredContent["dates"].([]any)
+	if value := dates[0].(map[string]any)["value"]; value != "2024-03-07" {
+		t.Errorf("tomorrow = %v, want 2024-03-07", value)
+	}
+	if got := result.StructuredContent["reference_time"]; got != "2024-03-06T21:00:00-05:00" {
+		t.Errorf("reference_time = %v, want 2024-03-06T21:00:00-05:00", got)
+	}
+}
+
+func TestExtractDatesRejectsBadInput(t *testing.T) {
+	for _, args := range []map[string]any{
+		{},
+		{"text": "today", "reference_time": "yesterday"},
+		{"text": "today", "timezone": "Mars/Olympus_Mons"},
+	} {
+		result := extractDates(args, time.Now())
+		if result.IsError == nil || !*result.IsError {
+			t.Errorf("extractDates(%v) succeeded, want an error", args)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Execute a tool call. This is the primary entry point for tool execution in plugins.
+//
+// The plugin receives a tool call request with the tool name and arguments, along with request context information. The plugin should execute the requested tool and return the result with content blocks and optional structured output.
+// It takes CallToolRequest as input ()
+// And returns CallToolResult ()
+func CallTool(input CallToolRequest) (*CallToolResult, error) {
+	switch input.Request.Name {
+	case "extract-dates":
+		return extractDates(input.Request.Arguments, time.Now()), nil
+	default:
+		return nil, fmt.Errorf("unknown tool %q", input.Request.Name)
+	}
+}
+
+func extractDates(args map[string]any, now time.Time) *CallToolResult {
+	text, _ := args["text"].(string)
+	if text == "" {
+		return errorResult(errors.New("text must be provided"))
+	}
+
+	ref, err := referenceTime(args, now)
+	if err != nil {
+		return errorResult(err)
+	}
+	locale, _ := args["locale"].(string)
+
+	out, err := json.Marshal(map[string]any{
+		"reference_time": ref.Format(time.RFC3339),
+		"dates":          ExtractDates(text, ref, locale),
+	})
+	if err != nil {
+		return errorResult(err)
+	}
+
+	var structured map[string]any
+	if err := json.Unmarshal(out, &structured); err != nil {
+		return errorResult(err)
+	}
+	return &CallToolResult{
+		Content:           []ContentBlock{{Text: &TextContent{Text: string(out)}}},
+		StructuredContent: structured,
+	}
+}
+
+func errorResult(err error) *CallToolResult {
+	isError := true
+	return &CallToolResult{
+		Content: []ContentBlock{{Text: &TextContent{Text: "Error: " + err.Error()}}},
+		IsError: &isError,
+	}
+}
+
+// referenceTime resolves the time relative expressions are measured from:
+// reference_time if given (else now), seen from timezone if given (else the
+// reference time's own offset, else UTC).
+func referenceTime(args map[string]any, now time.Time) (time.Time, error) {
+	ref := now.UTC()
+	if value, ok := args["reference_time"].(string); ok && value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return ref, fmt.Errorf("reference_time must be an RFC 3339 timestamp such as 2024-03-05T09:00:00Z, got %q", value)
+		}
+		ref = parsed
+	}
+	if name, ok := args["timezone"].(string); ok && name != "" {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return ref, fmt.Errorf("unknown timezone %q", name)
+		}
+		ref = ref.In(loc)
+	}
+	return ref, nil
+}
+
+// Provide completion suggestions for a partially-typed input.
+//
+// This function is called when the user requests autocompletion. The plugin should analyze the partial input and return matching completion suggestions based on the reference (prompt or resource) and argument context.
+// It takes CompleteRequest as input ()
+// And returns CompleteResult ()
+func Complete(input CompleteRequest) (*CompleteResult, error) {
+	return &CompleteResult{}, nil
+}
+
+// Retrieve a specific prompt by name.
+//
+// This function is called when the user requests a specific prompt. The plugin should return the prompt details including messages and optional description.
+// It takes GetPromptRequest as input ()
+// And returns GetPromptResult ()
+func GetPrompt(input GetPromptRequest) (*GetPromptResult, error) {
+	// TODO: fill out your implementation here
+	return nil, fmt.Errorf("GetPrompt not implemented.")
+}
+
+// List all available prompts.
+//
+// This function should return a list of prompts that the plugin provides. Each prompt should include its name and a brief description of what it does. Supports pagination via cursor.
+// It takes ListPromptsRequest as input ()
+// And returns ListPromptsResult ()
+func ListPrompts(input ListPromptsRequest) (*ListPromptsResult, error) {
+	// TODO: fill out your implementation here
+	return &ListPromptsResult{}, nil
+}
+
+// List all available resource templates.
+//
+// This function should return a list of resource templates that the plugin provides. Templates are URI patterns that can match multiple resources. Supports pagination via cursor.
+// It takes ListResourceTemplatesRequest as input ()
+// And returns ListResourceTemplatesResult ()
+func ListResourceTemplates(input ListResourceTemplatesRequest) (*ListResourceTemplatesResult, error) {
+	// TODO: fill out your implementation here
+	return &ListResourceTemplatesResult{}, nil
+}
+
+// List all available resources.
+//
+// This function should return a list of resources that the plugin provides. Resources are URI-based references to files, data, or services. Supports pagination via cursor.
+// It takes ListResourcesRequest as input ()
+// And returns ListResourcesResult ()
+func ListResources(input ListResourcesRequest) (*ListResourcesResult, error) {
+	// TODO: fill out your implementation here
+	return &ListResourcesResult{}, nil
+}
+
+// List all available tools.
+//
+// This function should return a list of all tools that the plugin provides. Each tool should include its name, description, and input schema. Supports pagination via cursor.
+// It takes ListToolsRequest as input ()
+// And returns ListToolsResult ()
+func ListTools(input ListToolsRequest) (*ListToolsResult, error) {
+	description := "Find date and time expressions in text (2024-03-05, March 5th, next Tuesday, in 3 weeks, tomorrow at 3pm, from May 1 to May 3, ...) " +
+		"and resolve each to ISO 8601 relative to a reference time and timezone, with its character offsets in the text. " +
+		"Numeric dates such as 03/04/05 that read differently by locale are flagged ambiguous and list every reading instead of guessing."
+	stringProp := func(description string) map[string]any {
+		return map[string]any{"type": "string", "description": description}
+	}
+
+	return &ListToolsResult{
+		Tools: []Tool{
+			{
+				Name:        "extract-dates",
+				Description: &description,
+				InputSchema: ToolSchema{
+					Type: "object",
+					Properties: map[string]any{
+						"text":           stringProp("The text to scan"),
+						"reference_time": stringProp("RFC 3339 time that relative expressions are resolved from (defaults to now)"),
+						"timezone":       stringProp("IANA timezone the text is written in, e.g. Europe/Paris (defaults to the reference time's offset, or UTC)"),
+						"locale":         stringProp("Locale such as en-US or en-GB used to pick a value for ambiguous numeric dates. Every reading is still listed."),
+					},
+					Required: []string{"text"},
+				},
+				OutputSchema: &ToolSchema{
+					Type: "object",
+					Properties: map[string]any{
+						"reference_time": map[string]any{"type": "string", "format": "date-time"},
+						"dates": map[string]any{
+							"type": "array",
+							"items": map[string]any{
+								"type": "object",
+								"properties": map[string]any{
+									"text":      map[string]any{"type": "string"},
+									"start":     map[string]any{"type": "integer", "description": "Character offset of the expression"},
+									"end":       map[string]any{"type": "integer", "description": "Character offset just past the expression"},
+									"kind":      map[string]any{"type": "string", "enum": []string{KindDate, KindDateTime, KindDateRange, KindDateTimeRange}},
+									"value":     map[string]any{"type": "string", "description": "ISO 8601 date or date-time. Missing for ranges and for ambiguous dates without a matching locale."},
+									"range":     map[string]any{"type": "object", "properties": map[string]any{"start": map[string]any{"type": "string"}, "end": map[string]any{"type": "string"}}},
+									"ambiguous": map[string]any{"type": "boolean"},
+									"candidates": map[string]any{
+										"type": "array",
+										"items": map[string]any{
+											"type": "object",
+											"properties": map[string]any{
+												"value":   map[string]any{"type": "string"},
+												"order":   map[string]any{"type": "string", "enum": []string{"MDY", "DMY", "YMD"}},
+												"locales": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					Required: []string{"reference_time", "dates"},
+				},
+			},
+		},
+	}, nil
+}
+
+// Notification that the list of roots has changed.
+//
+// This is an optional notification handler. If implemented, the plugin will be notified whenever the roots list changes on the client side. This allows plugins to react to changes in the file system roots or other root resources.
+// It takes PluginNotificationContext as input ()
+func OnRootsListChanged(input PluginNotificationContext) error {
+	// TODO: fill out your implementation here
+	return nil
+}
+
+// Read the contents of a resource by its URI.
+//
+// This function is called when the user wants to read the contents of a specific resource. The plugin should retrieve and return the resource data with appropriate MIME type information.
+// It takes ReadResourceRequest as input ()
+// And returns ReadResourceResult ()
+func ReadResource(input ReadResourceRequest) (*ReadResourceResult, error) {
+	// TODO: fill out your implementation here
+	return nil, fmt.Errorf("ReadResource not implemented.")
+}
+
+// Note: leave this in place, as the Go compiler will find the `export` function as the entrypoint.
+func main() {}
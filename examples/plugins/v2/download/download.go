@@ -0,0 +1,195 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultMaxSize = 10 << 20
+	// Upper bound for max_size. The host reads the whole response into
+	// plugin memory before the plugin sees it.
+	maxMaxSize = 50 << 20
+	// Artifacts are kept in plugin memory; the oldest are dropped past this.
+	storeCapacity = 100 << 20
+)
+
+var (
+	errChecksumMismatch = errors.New("checksum mismatch")
+	errTooLarge         = errors.New("file too large")
+)
+
+// Artifact is a downloaded file, served as a resource at URI.
+type Artifact struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	SourceURL   string `json:"source_url"`
+	ContentType string `json:"content_type"`
+	Size        int    `json:"size"`
+	SHA256      string `json:"sha256"`
+	Verified    bool   `json:"verified"`
+	data        []byte
+}
+
+// fetcher performs a GET. Header names in the returned map may use any case.
+type fetcher func(url string) (status uint16, headers map[string]string, body []byte, err error)
+
+type fetchRequest struct {
+	URL            string
+	ExpectedSHA256 string
+	MaxSize        int
+}
+
+func fetchRequestFromArgs(args map[string]any) (fetchRequest, error) {
+	req := fetchRequest{MaxSize: defaultMaxSize}
+
+	req.URL, _ = args["url"].(string)
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return req, fmt.Errorf("url must be an absolute http or https URL, got %q", req.URL)
+	}
+
+	if expected, _ := args["sha256"].(string); expected != "" {
+		expected = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(expected)), "sha256:")
+		if decoded, err := hex.DecodeString(expected); err != nil || len(decoded) != sha256.Size {
+			return req, fmt.Errorf("sha256 must be 64 hex characters, got %q", args["sha256"])
+		}
+		req.ExpectedSHA256 = expected
+	}
+
+	if value, ok := args["max_size"]; ok && value != nil {
+		size, ok := value.(float64)
+		if !ok || size != float64(int(size)) || size < 1 || size > maxMaxSize {
+			return req, fmt.Errorf("max_size must be a whole number of bytes from 1 to %d, got %v", maxMaxSize, value)
+		}
+		req.MaxSize = int(size)
+	}
+	return req, nil
+}
+
+func header(headers map[string]string, name string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// artifactName picks a display name from the last segment of the URL path.
+func artifactName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "download"
+	}
+	name := path.Base(u.Path)
+	if name == "." || name == "/" || name == "" {
+		return "download"
+	}
+	return name
+}
+
+// download fetches the file and checks it against the request. Nothing is
+// stored: the caller only registers the artifact once this succeeds.
+func download(req fetchRequest, get fetcher) (*Artifact, error) {
+	status, headers, body, err := get(req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", req.URL, err)
+	}
+	if status < 200 || status > 299 {
+		return nil, fmt.Errorf("fetching %s: server answered %d", req.URL, status)
+	}
+
+	// The body is already in memory, but a declared length over the limit
+	// is reported as such rather than as whatever arrived
+	if declared, err := strconv.Atoi(header(headers, "Content-Length")); err == nil && declared > req.MaxSize {
+		return nil, fmt.Errorf("%w: %s declares %d bytes, more than max_size %d", errTooLarge, req.URL, declared, req.MaxSize)
+	}
+	if len(body) > req.MaxSize {
+		return nil, fmt.Errorf("%w: %s is more than max_size %d bytes", errTooLarge, req.URL, req.MaxSize)
+	}
+
+	sum := sha256.Sum256(body)
+	actual := hex.EncodeToString(sum[:])
+	if req.ExpectedSHA256 != "" && actual != req.ExpectedSHA256 {
+		return nil, fmt.Errorf("%w for %s: expected sha256 %s, got %s. The file was discarded", errChecksumMismatch, req.URL, req.ExpectedSHA256, actual)
+	}
+
+	contentType := header(headers, "Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &Artifact{
+		URI:         "artifact://sha256/" + actual,
+		Name:        artifactName(req.URL),
+		SourceURL:   req.URL,
+		ContentType: contentType,
+		Size:        len(body),
+		SHA256:      actual,
+		Verified:    req.ExpectedSHA256 != "",
+		data:        body,
+	}, nil
+}
+
+// artifactStore keeps downloaded files by URI, oldest first. Identical
+// content downloaded twice is stored once.
+type artifactStore struct {
+	capacity int
+	size     int
+	order    []string
+	byURI    map[string]*Artifact
+}
+
+func newArtifactStore(capacity int) *artifactStore {
+	return &artifactStore{capacity: capacity, byURI: map[string]*Artifact{}}
+}
+
+// put stores the artifact, dropping the oldest ones to make room, and
+// returns the URIs it dropped. Content that is already stored is kept as
+// it is.
+func (s *artifactStore) put(a *Artifact) []string {
+	if _, ok := s.byURI[a.URI]; ok {
+		return nil
+	}
+	var evicted []string
+	for len(s.order) > 0 && s.size+a.Size > s.capacity {
+		oldest := s.byURI[s.order[0]]
+		s.size -= oldest.Size
+		delete(s.byURI, oldest.URI)
+		evicted = append(evicted, oldest.URI)
+		s.order = s.order[1:]
+	}
+	s.byURI[a.URI] = a
+	s.order = append(s.order, a.URI)
+	s.size += a.Size
+	return evicted
+}
+
+func (s *artifactStore) get(uri string) (*Artifact, bool) {
+	a, ok := s.byURI[uri]
+	return a, ok
+}
+
+func (s *artifactStore) list() []*Artifact {
+	artifacts := make([]*Artifact, 0, len(s.order))
+	for _, uri := range s.order {
+		artifacts = append(artifacts, s.byURI[uri])
+	}
+	return artifacts
+}
+
+// isText reports whether an artifact can be returned as text contents.
+func isText(contentType string) bool {
+	mediaType := strings.TrimSpace(strings.ToLower(strings.SplitN(contentType, ";", 2)[0]))
+	return strings.HasPrefix(mediaType, "text/") ||
+		mediaType == "application/json" ||
+		mediaType == "application/xml" ||
+		strings.HasSuffix(mediaType, "+json") ||
+		strings.HasSuffix(mediaType, "+xml")
+}
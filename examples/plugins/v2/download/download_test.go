@@ -0,0 +1,195 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var fileBody = []byte("hello, artifact\n")
+
+func fileSum() string {
+	sum := sha256.Sum256(fileBody)
+	return hex.EncodeToString(sum[:])
+}
+
+// serve answers every request with the given response.
+func serve(status uint16, headers map[string]string, body []byte) fetcher {
+	return func(string) (uint16, map[string]string, []byte, error) {
+		return status, headers, body, nil
+	}
+}
+
+var textFile = serve(200, map[string]string{"content-type": "text/plain; charset=utf-8", "content-length": "16"}, fileBody)
+
+func resultText(result *CallToolResult) string {
+	return result.Content[0].Text.Text
+}
+
+func TestFetchFileStoresVerifiedArtifact(t *testing.T) {
+	store := newArtifactStore(storeCapacity)
+	notified := 0
+	result := fetchFile(map[string]any{
+		"url":    "https://example.com/files/hello.txt",
+		"sha256": "SHA256:" + strings.ToUpper(fileSum()),
+	}, textFile, store, func([]string) { notified++ })
+
+	if result.IsError != nil && *result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(result))
+	}
+	uri := "artifact://sha256/" + fileSum()
+	want := `{"uri":"` + uri + `","name":"hello.txt","source_url":"https://example.com/files/hello.txt","content_type":"text/plain; charset=utf-8","size":16,"sha256":"` + fileSum() + `","verified":true}`
+	if got := resultText(result); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+	link := result.Content[1].ResourceLink
+	if link == nil || link.URI != uri || *link.Size != 16 {
+		t.Errorf("resource link %+v", link)
+	}
+	if result.StructuredContent["sha256"] != fileSum() {
+		t.Errorf("structured content %v", result.StructuredContent)
+	}
+	if notified != 1 {
+		t.Errorf("resource list change notified %d times, want 1", notified)
+	}
+
+	read, err := readArtifact(store, uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ := json.Marshal(read)
+	if !strings.Contains(string(out), `"text":"hello, artifact\n"`) {
+		t.Errorf("text artifact read as %s", out)
+	}
+}
+
+func TestFetchFileReportsThisCall(t *testing.T) {
+	store := newArtifactStore(storeCapacity)
+	fetchFile(map[string]any{
+		"url":    "https://example.com/files/hello.txt",
+		"sha256": fileSum(),
+	}, textFile, store, func([]string) {})
+
+	result := fetchFile(map[string]any{"url": "https://mirror.example.org/hello.txt"}, textFile, store, func([]string) {})
+	if result.IsError != nil && *result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(result))
+	}
+	if result.StructuredContent["verified"] != false || result.StructuredContent["source_url"] != "https://mirror.example.org/hello.txt" {
+		t.Errorf("second download reported as %s", resultText(result))
+	}
+	if len(store.list()) != 1 || !store.list()[0].Verified {
+		t.Errorf("stored artifacts %+v", store.list())
+	}
+}
+
+func TestFetchFileFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]any
+		get     fetcher
+		wantErr string
+	}{
+		{
+			name:    "checksum mismatch",
+			args:    map[string]any{"url": "https://example.com/a", "sha256": strings.Repeat("0", 64)},
+			get:     textFile,
+			wantErr: "checksum mismatch for https://example.com/a: expected sha256 " + strings.Repeat("0", 64) + ", got " + fileSum(),
+		},
+		{
+			name:    "declared length over max_size",
+			args:    map[string]any{"url": "https://example.com/a", "max_size": float64(10)},
+			get:     textFile,
+			wantErr: "file too large: https://example.com/a declares 16 bytes, more than max_size 10",
+		},
+		{
+			name:    "body over max_size without a declared length",
+			args:    map[string]any{"url": "https://example.com/a", "max_size": float64(10)},
+			get:     serve(200, nil, fileBody),
+			wantErr: "file too large: https://example.com/a is more than max_size 10 bytes",
+		},
+		{
+			name:    "server error",
+			args:    map[string]any{"url": "https://example.com/missing"},
+			get:     serve(404, nil, []byte("not found")),
+			wantErr: "server answered 404",
+		},
+		{
+			name: "request failure",
+			args: map[string]any{"url": "https://example.com/a"},
+			get: func(string) (uint16, map[string]string, []byte, error) {
+				return 0, nil, nil, errors.New("host not allowed")
+			},
+			wantErr: "fetching https://example.com/a: host not allowed",
+		},
+		{name: "missing url", args: map[string]any{}, wantErr: "url must be an absolute http or https URL"},
+		{name: "unsupported scheme", args: map[string]any{"url": "file:///etc/passwd"}, wantErr: "url must be an absolute http or https URL"},
+		{name: "relative url", args: map[string]any{"url": "/a/b"}, wantErr: "url must be an absolute http or https URL"},
+		{name: "short checksum", args: map[string]any{"url": "https://example.com/a", "sha256": "abc123"}, wantErr: "sha256 must be 64 hex characters"},
+		{name: "zero max_size", args: map[string]any{"url": "https://example.com/a", "max_size": float64(0)}, wantErr: "max_size must be a whole number"},
+		{name: "max_size over the cap", args: map[string]any{"url": "https://example.com/a", "max_size": float64(maxMaxSize + 1)}, wantErr: "max_size must be a whole number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newArtifactStore(storeCapacity)
+			get := tt.get
+			if get == nil {
+				get = func(string) (uint16, map[string]string, []byte, error) {
+					t.Fatal("fetched despite invalid arguments")
+					return 0, nil, nil, nil
+				}
+			}
+			result := fetchFile(tt.args, get, store, func([]string) { t.Error("resource list changed on failure") })
+			if result.IsError == nil || !*result.IsError {
+				t.Fatalf("succeeded, want error containing %q", tt.wantErr)
+			}
+			if !strings.Contains(resultText(result), tt.wantErr) {
+				t.Errorf("error %q does not contain %q", resultText(result), tt.wantErr)
+			}
+			if len(store.list()) != 0 {
+				t.Errorf("artifact registered despite the failure: %+v", store.list())
+			}
+		})
+	}
+}
+
+func TestArtifactStore(t *testing.T) {
+	store := newArtifactStore(10)
+	artifact := func(sum string, size int) *Artifact {
+		return &Artifact{URI: "artifact://sha256/" + sum, Size: size, data: make([]byte, size)}
+	}
+
+	if evicted := store.put(artifact("a", 4)); evicted != nil {
+		t.Errorf("evicted %v from an empty store", evicted)
+	}
+	store.put(artifact("b", 4))
+	// Same content again is stored once
+	store.put(artifact("a", 4))
+	if len(store.list()) != 2 || store.size != 8 {
+		t.Fatalf("store has %d artifacts, %d bytes", len(store.list()), store.size)
+	}
+
+	evicted := store.put(artifact("c", 5))
+	if len(evicted) != 1 || evicted[0] != "artifact://sha256/a" {
+		t.Errorf("evicted %v, want the oldest artifact", evicted)
+	}
+	if _, err := readArtifact(store, "artifact://sha256/a"); err == nil || !strings.Contains(err.Error(), "dropped") {
+		t.Errorf("reading an evicted artifact: %v", err)
+	}
+}
+
+func TestReadArtifactBinary(t *testing.T) {
+	store := newArtifactStore(storeCapacity)
+	store.put(&Artifact{URI: "artifact://sha256/x", ContentType: "text/plain", Size: 3, data: []byte{0xff, 0x00, 0xfe}})
+	result, err := readArtifact(store, "artifact://sha256/x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ := json.Marshal(result)
+	want := `{"contents":[{"blob":"/wD+","mimeType":"text/plain","uri":"artifact://sha256/x"}]}`
+	if string(out) != want {
+		t.Errorf("invalid UTF-8 labelled as text read as %s, want %s", out, want)
+	}
+}
@@ -0,0 +1,5 @@
+module github.com/tuananh/hyper-mcp/download
+
+go 1.25
+
+require github.com/extism/go-pdk v1.1.3
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/extism/go-pdk"
+)
+
+var artifacts = newArtifactStore(storeCapacity)
+
+func httpGet(url string) (uint16, map[string]string, []byte, error) {
+	resp := pdk.NewHTTPRequest(pdk.MethodGet, url).Send()
+	return resp.Status(), resp.Headers(), resp.Body(), nil
+}
+
+// Execute a tool call. This is the primary entry point for tool execution in plugins.
+//
+// The plugin receives a tool call request with the tool name and arguments, along with request context information. The plugin should execute the requested tool and return the result with content blocks and optional structured output.
+// It takes CallToolRequest as input ()
+// And returns CallToolResult ()
+func CallTool(input CallToolRequest) (*CallToolResult, error) {
+	switch input.Request.Name {
+	case "fetch-file":
+		return fetchFile(input.Request.Arguments, httpGet, artifacts, resourcesChanged), nil
+	default:
+		return nil, fmt.Errorf("unknown tool %q", input.Request.Name)
+	}
+}
+
+// resourcesChanged tells the client to refresh its resource list.
+func resourcesChanged(evicted []string) {
+	for _, uri := range evicted {
+		pdk.Log(pdk.LogInfo, fmt.Sprintf("dropped artifact %s to make room", uri))
+	}
+	// Hosts without notification support just won't refresh their list
+	_ = NotifyResourceListChanged()
+}
+
+func fetchFile(args map[string]any, get fetcher, store *artifactStore, changed func(evicted []string)) *CallToolResult {
+	req, err := fetchRequestFromArgs(args)
+	if err != nil {
+		return errorResult(err)
+	}
+	artifact, err := download(req, get)
+	if err != nil {
+		return errorResult(err)
+	}
+
+	// The result describes this call, even when the same content was
+	// stored by an earlier one from another URL or without a checksum
+	changed(store.put(artifact))
+
+	out, err := json.Marshal(artifact)
+	if err != nil {
+		return errorResult(err)
+	}
+	var structured map[string]any
+	if err := json.Unmarshal(out, &structured); err != nil {
+		return errorResult(err)
+	}
+
+	size := int64(artifact.Size)
+	return &CallToolResult{
+		Content: []ContentBlock{
+			{Text: &TextContent{Text: string(out)}},
+			{ResourceLink: &ResourceLinkContent{
+				URI:      artifact.URI,
+				Name:     artifact.Name,
+				MimeType: &artifact.ContentType,
+				Size:     &size,
+			}},
+		},
+		StructuredContent: structured,
+	}
+}
+
+func errorResult(err error) *CallToolResult {
+	isError := true
+	return &CallToolResult{
+		Content: []ContentBlock{{Text: &TextContent{Text: "Error: " + err.Error()}}},
+		IsError: &isError,
+	}
+}
+
+// Provide completion suggestions for a partially-typed input.
+//
+// This function is called when the user requests autocompletion. The plugin should analyze the partial input and return matching completion suggestions based on the reference (prompt or resource) and argument context.
+// It takes CompleteRequest as input ()
+// And returns CompleteResult ()
+func Complete(input CompleteRequest) (*CompleteResult, error) {
+	return &CompleteResult{}, nil
+}
+
+// Retrieve a specific prompt by name.
+//
+// This function is called when the user requests a specific prompt. The plugin should return the prompt details including messages and optional description.
+// It takes GetPromptRequest as input ()
+// And returns GetPromptResult ()
+func GetPrompt(input GetPromptRequest) (*GetPromptResult, error) {
+	// TODO: fill out your implementation here
+	return nil, fmt.Errorf("GetPrompt not implemented.")
+}
+
+// List all available prompts.
+//
+// This function should return a list of prompts that the plugin provides. Each prompt should include its name and a brief description of what it does. Supports pagination via cursor.
+// It takes ListPromptsRequest as input ()
+// And returns ListPromptsResult ()
+func ListPrompts(input ListPromptsRequest) (*ListPromptsResult, error) {
+	// TODO: fill out your implementation here
+	return &ListPromptsResult{}, nil
+}
+
+// List all available resource templates.
+//
+// This function should return a list of resource templates that the plugin provides. Templates are URI patterns that can match multiple resources. Supports pagination via cursor.
+// It takes ListResourceTemplatesRequest as input ()
+// And returns ListResourceTemplatesResult ()
+func ListResourceTemplates(input ListResourceTemplatesRequest) (*ListResourceTemplatesResult, error) {
+	// TODO: fill out your implementation here
+	return &ListResourceTemplatesResult{}, nil
+}
+
+// List all available resources.
+//
+// This function should return a list of resources that the plugin provides. Resources are URI-based references to files, data, or services. Supports pagination via cursor.
+// It takes ListResourcesRequest as input ()
+// And returns ListResourcesResult ()
+func ListResources(input ListResourcesRequest) (*ListResourcesResult, error) {
+	resources := []Resource{}
+	for _, a := range artifacts.list() {
+		description := fmt.Sprintf("Downloaded from %s, sha256 %s", a.SourceURL, a.SHA256)
+		size := int64(a.Size)
+		resources = append(resources, Resource{
+			URI:         a.URI,
+			Name:        a.Name,
+			Description: &description,
+			MimeType:    &a.ContentType,
+			Size:        &size,
+		})
+	}
+	return &ListResourcesResult{Resources: resources}, nil
+}
+
+// List all available tools.
+//
+// This function should return a list of all tools that the plugin provides. Each tool should include its name, description, and input schema. Supports pagination via cursor.
+// It takes ListToolsRequest as input ()
+// And returns ListToolsResult ()
+func ListTools(input ListToolsRequest) (*ListToolsResult, error) {
+	description := "Download a file over HTTP(S) and keep it as a resource. Returns a resource link plus the size, content type and computed sha256. " +
+		"Pass the expected sha256 to verify the file: on a mismatch the call fails and nothing is kept."
+
+	return &ListToolsResult{
+		Tools: []Tool{
+			{
+				Name:        "fetch-file",
+				Description: &description,
+				InputSchema: ToolSchema{
+					Type: "object",
+					Properties: map[string]any{
+						"url":      map[string]any{"type": "string", "description": "http or https URL of the file. The host must be in the plugin's allowed_hosts."},
+						"sha256":   map[string]any{"type": "string", "description": "Expected SHA-256 of the file as 64 hex characters"},
+						"max_size": map[string]any{"type": "integer", "description": fmt.Sprintf("Largest file accepted, in bytes (default %d, at most %d)", defaultMaxSize, maxMaxSize)},
+					},
+					Required: []string{"url"},
+				},
+				OutputSchema: &ToolSchema{
+					Type: "object",
+					Properties: map[string]any{
+						"uri":          map[string]any{"type": "string"},
+						"name":         map[string]any{"type": "string"},
+						"source_url":   map[string]any{"type": "string"},
+						"content_type": map[string]any{"type": "string"},
+						"size":         map[string]any{"type": "integer"},
+						"sha256":       map[string]any{"type": "string"},
+						"verified":     map[string]any{"type": "boolean", "description": "Whether the file matched the sha256 given in the call"},
+					},
+					Required: []string{"uri", "name", "source_url", "content_type", "size", "sha256", "verified"},
+				},
+			},
+		},
+	}, nil
+}
+
+// Notification that the list of roots has changed.
+//
+// This is an optional notification handler. If implemented, the plugin will be notified whenever the roots list changes on the client side. This allows plugins to react to changes in the file system roots or other root resources.
+// It takes PluginNotificationContext as input ()
+func OnRootsListChanged(input PluginNotificationContext) error {
+	// TODO: fill out your implementation here
+	return nil
+}
+
+// Read the contents of a resource by its URI.
+//
+// This function is called when the user wants to read the contents of a specific resource. The plugin should retrieve and return the resource data with appropriate MIME type information.
+// It takes ReadResourceRequest as input ()
+// And returns ReadResourceResult ()
+func ReadResource(input ReadResourceRequest) (*ReadResourceResult, error) {
+	return readArtifact(artifacts, input.Request.URI)
+}
+
+func readArtifact(store *artifactStore, uri string) (*ReadResourceResult, error) {
+	a, ok := store.get(uri)
+	if !ok {
+		return nil, fmt.Errorf("unknown artifact %s; it may have been dropped to make room for newer downloads", uri)
+	}
+	part := BlobPart(a.URI, a.ContentType, a.data)
+	if isText(a.ContentType) && utf8.Valid(a.data) {
+		part = TextPart(a.URI, a.ContentType, string(a.data))
+	}
+	return MultiPartResult(a.URI, []ResourcePart{part})
+}
+
+// Note: leave this in place, as the Go compiler will find the `export` function as the entrypoint.
+func main() {}
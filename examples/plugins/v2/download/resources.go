@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ResourceErrorsMimeType marks the part of a multi-part ReadResource result
+// that lists the parts which could not be read. It is always the last part.
+const ResourceErrorsMimeType = "application/vnd.hyper-mcp.resource-errors+json"
+
+// ResourcePart is one part of a multi-part resource, such as a file of a
+// directory or a chunk of a large file. Build it with TextPart, BlobPart or
+// FailedPart.
+type ResourcePart struct {
+	URI      string
+	MimeType string
+	text     *string
+	blob     []byte
+	err      error
+}
+
+func TextPart(uri, mimeType, text string) ResourcePart {
+	return ResourcePart{URI: uri, MimeType: mimeType, text: &text}
+}
+
+func BlobPart(uri, mimeType string, data []byte) ResourcePart {
+	return ResourcePart{URI: uri, MimeType: mimeType, blob: data}
+}
+
+// FailedPart records a part that could not be read. It is reported in the
+// error summary instead of failing the whole read.
+func FailedPart(uri string, err error) ResourcePart {
+	return ResourcePart{URI: uri, err: err}
+}
+
+// ChildURI is the URI of an entry inside a container resource, e.g.
+// ChildURI("file:///logs", "app.log") is "file:///logs/app.log".
+func ChildURI(base, name string) string {
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(name, "/")
+}
+
+// ChunkURI is the URI of the index-th chunk (from 0) of a resource.
+func ChunkURI(base string, index int) string {
+	return fmt.Sprintf("%s#chunk=%d", base, index)
+}
+
+// ResourceErrorSummary is the body of the ResourceErrorsMimeType part.
+type ResourceErrorSummary struct {
+	URI    string          `json:"uri"`
+	Parts  int             `json:"parts"`
+	Failed int             `json:"failed"`
+	Errors []ResourceError `json:"errors"`
+}
+
+type ResourceError struct {
+	URI   string `json:"uri"`
+	Error string `json:"error"`
+}
+
+// MultiPartResult builds the ReadResource result for the resource at uri
+// from its parts, in order. When some parts failed, the ones that were read
+// are returned followed by a text part with MIME type ResourceErrorsMimeType
+// and URI uri, holding a ResourceErrorSummary as JSON. When every part
+// failed there is nothing to return and the joined errors are returned
+// instead. No parts (an empty directory) is an empty, successful result.
+func MultiPartResult(uri string, parts []ResourcePart) (*ReadResourceResult, error) {
+	result := &ReadResourceResult{Contents: []ResourceContents{}}
+	summary := ResourceErrorSummary{URI: uri, Parts: len(parts), Errors: []ResourceError{}}
+	var errs []error
+
+	for _, part := range parts {
+		var mimeType *string
+		if part.MimeType != "" {
+			mimeType = &part.MimeType
+		}
+		switch {
+		case part.err != nil:
+			summary.Errors = append(summary.Errors, ResourceError{URI: part.URI, Error: part.err.Error()})
+			errs = append(errs, fmt.Errorf("%s: %w", part.URI, part.err))
+		case part.text != nil:
+			result.Contents = append(result.Contents, ResourceContents{Text: &TextResourceContents{URI: part.URI, MimeType: mimeType, Text: *part.text}})
+		default:
+			blob := base64.StdEncoding.EncodeToString(part.blob)
+			result.Contents = append(result.Contents, ResourceContents{Blob: &BlobResourceContents{URI: part.URI, MimeType: mimeType, Blob: blob}})
+		}
+	}
+
+	summary.Failed = len(summary.Errors)
+	if summary.Failed == 0 {
+		return result, nil
+	}
+	if summary.Failed == len(parts) {
+		return nil, fmt.Errorf("reading %s: all %d parts failed: %w", uri, len(parts), errors.Join(errs...))
+	}
+
+	body, err := json.Marshal(summary)
+	if err != nil {
+		return nil, err
+	}
+	mimeType := ResourceErrorsMimeType
+	result.Contents = append(result.Contents, ResourceContents{Text: &TextResourceContents{URI: uri, MimeType: &mimeType, Text: string(body)}})
+	return result, nil
+}
+
+// ResourceErrors returns the error summary of a multi-part result, if it
+// has one.
+func ResourceErrors(result *ReadResourceResult) (*ResourceErrorSummary, bool) {
+	if result == nil || len(result.Contents) == 0 {
+		return nil, false
+	}
+	last := result.Contents[len(result.Contents)-1].Text
+	if last == nil || last.MimeType == nil || *last.MimeType != ResourceErrorsMimeType {
+		return nil, false
+	}
+	var summary ResourceErrorSummary
+	if err := json.Unmarshal([]byte(last.Text), &summary); err != nil {
+		return nil, false
+	}
+	return &summary, true
+}
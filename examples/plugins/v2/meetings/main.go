@@ -0,0 +1,320 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Execute a tool call. This is the primary entry point for tool execution in plugins.
+//
+// The plugin receives a tool call request with the tool name and arguments, along with request context information. The plugin should execute the requested tool and return the result with content blocks and optional structured output.
+// It takes CallToolRequest as input ()
+// And returns CallToolResult ()
+func CallTool(input CallToolRequest) (*CallToolResult, error) {
+	switch input.Request.Name {
+	case "find-meeting-time":
+		return findMeetingTime(input.Request.Arguments), nil
+	default:
+		return nil, fmt.Errorf("unknown tool %q", input.Request.Name)
+	}
+}
+
+func findMeetingTime(args map[string]any) *CallToolResult {
+	query, err := queryFromArgs(args)
+	if err != nil {
+		return errorResult(err)
+	}
+
+	slots := FindMeetingTimes(query)
+	out, err := json.Marshal(map[string]any{"slots": slots})
+	if err != nil {
+		return errorResult(err)
+	}
+
+	var structured map[string]any
+	if err := json.Unmarshal(out, &structured); err != nil {
+		return errorResult(err)
+	}
+
+	text := string(out)
+	if len(slots) == 0 {
+		text = "No slot fits every participant's working hours in this range. Try a longer range, a shorter meeting or wider working hours."
+	}
+	return &CallToolResult{
+		Content:           []ContentBlock{{Text: &TextContent{Text: text}}},
+		StructuredContent: structured,
+	}
+}
+
+func errorResult(err error) *CallToolResult {
+	isError := true
+	return &CallToolResult{
+		Content: []ContentBlock{{Text: &TextContent{Text: "Error: " + err.Error()}}},
+		IsError: &isError,
+	}
+}
+
+// queryFromArgs validates the tool arguments. Dates are read in `timezone`,
+// or in the first participant's timezone when it isn't given.
+func queryFromArgs(args map[string]any) (MeetingQuery, error) {
+	var q MeetingQuery
+
+	list, _ := args["participants"].([]any)
+	if len(list) == 0 {
+		return q, errors.New("participants must be a non-empty array")
+	}
+	if len(list) > maxParticipants {
+		return q, fmt.Errorf("at most %d participants are supported", maxParticipants)
+	}
+	for i, item := range list {
+		entry, ok := item.(map[string]any)
+		if !ok {
+			return q, fmt.Errorf("participants[%d] must be an object", i)
+		}
+		p, err := participantFromArgs(entry)
+		if err != nil {
+			return q, fmt.Errorf("participants[%d]: %w", i, err)
+		}
+		if p.Name == "" {
+			p.Name = fmt.Sprintf("participant %d", i+1)
+		}
+		q.Participants = append(q.Participants, p)
+	}
+
+	duration, ok := args["duration_minutes"].(float64)
+	if !ok || duration <= 0 || duration != float64(int(duration)) {
+		return q, fmt.Errorf("duration_minutes must be a positive whole number, got %v", args["duration_minutes"])
+	}
+	q.Duration = time.Duration(duration) * time.Minute
+
+	loc := q.Participants[0].Location
+	if name, ok := args["timezone"].(string); ok && name != "" {
+		var err error
+		if loc, err = time.LoadLocation(name); err != nil {
+			return q, fmt.Errorf("unknown timezone %q", name)
+		}
+	}
+
+	startDate, _ := args["start_date"].(string)
+	from, err := parseDate(startDate, loc)
+	if err != nil {
+		return q, fmt.Errorf("start_date: %w", err)
+	}
+	endDate := startDate
+	if value, ok := args["end_date"].(string); ok && value != "" {
+		endDate = value
+	}
+	to, err := parseDate(endDate, loc)
+	if err != nil {
+		return q, fmt.Errorf("end_date: %w", err)
+	}
+	to = to.AddDate(0, 0, 1)
+	if !to.After(from) {
+		return q, errors.New("end_date must not be before start_date")
+	}
+	if to.AddDate(0, 0, -maxRangeDays).After(from) {
+		return q, fmt.Errorf("the date range can cover at most %d days", maxRangeDays)
+	}
+	q.From, q.To = from, to
+
+	q.Step = defaultStepMinutes * time.Minute
+	if step, ok := args["step_minutes"].(float64); ok {
+		if step < 5 || step > 240 || step != float64(int(step)) {
+			return q, fmt.Errorf("step_minutes must be a whole number between 5 and 240, got %v", step)
+		}
+		q.Step = time.Duration(step) * time.Minute
+	}
+
+	q.MaxResults = defaultMaxResults
+	if max, ok := args["max_results"].(float64); ok {
+		if max < 1 || max != float64(int(max)) {
+			return q, fmt.Errorf("max_results must be a positive whole number, got %v", max)
+		}
+		q.MaxResults = int(max)
+	}
+	return q, nil
+}
+
+func participantFromArgs(args map[string]any) (Participant, error) {
+	p := Participant{}
+	p.Name, _ = args["name"].(string)
+
+	zone, _ := args["timezone"].(string)
+	if zone == "" {
+		return p, errors.New("timezone must be provided")
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return p, fmt.Errorf("unknown timezone %q", zone)
+	}
+	p.Location = loc
+
+	workStart, workEnd := "09:00", "17:00"
+	if value, ok := args["work_start"].(string); ok && value != "" {
+		workStart = value
+	}
+	if value, ok := args["work_end"].(string); ok && value != "" {
+		workEnd = value
+	}
+	if p.WorkStart, err = parseClock(workStart); err != nil {
+		return p, fmt.Errorf("work_start: %w", err)
+	}
+	if p.WorkEnd, err = parseClock(workEnd); err != nil {
+		return p, fmt.Errorf("work_end: %w", err)
+	}
+	if p.WorkEnd <= p.WorkStart {
+		return p, fmt.Errorf("work_end %s must be after work_start %s", workEnd, workStart)
+	}
+
+	p.Weekends, _ = args["weekends"].(bool)
+	return p, nil
+}
+
+// Provide completion suggestions for a partially-typed input.
+//
+// This function is called when the user requests autocompletion. The plugin should analyze the partial input and return matching completion suggestions based on the reference (prompt or resource) and argument context.
+// It takes CompleteRequest as input ()
+// And returns CompleteResult ()
+func Complete(input CompleteRequest) (*CompleteResult, error) {
+	return &CompleteResult{}, nil
+}
+
+// Retrieve a specific prompt by name.
+//
+// This function is called when the user requests a specific prompt. The plugin should return the prompt details including messages and optional description.
+// It takes GetPromptRequest as input ()
+// And returns GetPromptResult ()
+func GetPrompt(input GetPromptRequest) (*GetPromptResult, error) {
+	// TODO: fill out your implementation here
+	return nil, fmt.Errorf("GetPrompt not implemented.")
+}
+
+// List all available prompts.
+//
+// This function should return a list of prompts that the plugin provides. Each prompt should include its name and a brief description of what it does. Supports pagination via cursor.
+// It takes ListPromptsRequest as input ()
+// And returns ListPromptsResult ()
+func ListPrompts(input ListPromptsRequest) (*ListPromptsResult, error) {
+	// TODO: fill out your implementation here
+	return &ListPromptsResult{}, nil
+}
+
+// List all available resource templates.
+//
+// This function should return a list of resource templates that the plugin provides. Templates are URI patterns that can match multiple resources. Supports pagination via cursor.
+// It takes ListResourceTemplatesRequest as input ()
+// And returns ListResourceTemplatesResult ()
+func ListResourceTemplates(input ListResourceTemplatesRequest) (*ListResourceTemplatesResult, error) {
+	// TODO: fill out your implementation here
+	return &ListResourceTemplatesResult{}, nil
+}
+
+// List all available resources.
+//
+// This function should return a list of resources that the plugin provides. Resources are URI-based references to files, data, or services. Supports pagination via cursor.
+// It takes ListResourcesRequest as input ()
+// And returns ListResourcesResult ()
+func ListResources(input ListResourcesRequest) (*ListResourcesResult, error) {
+	// TODO: fill out your implementation here
+	return &ListResourcesResult{}, nil
+}
+
+// List all available tools.
+//
+// This function should return a list of all tools that the plugin provides. Each tool should include its name, description, and input schema. Supports pagination via cursor.
+// It takes ListToolsRequest as input ()
+// And returns ListToolsResult ()
+func ListTools(input ListToolsRequest) (*ListToolsResult, error) {
+	description := "Find meeting slots that fall inside every participant's working hours. " +
+		"Returns slots ranked by how central they are in the least convenient participant's day, " +
+		"each rendered in every participant's local time. Daylight saving changes inside the range are taken into account."
+	localTime := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"participant": map[string]any{"type": "string"},
+			"timezone":    map[string]any{"type": "string"},
+			"start":       map[string]any{"type": "string", "format": "date-time"},
+			"end":         map[string]any{"type": "string", "format": "date-time"},
+			"utc_offset":  map[string]any{"type": "string"},
+			"weekday":     map[string]any{"type": "string"},
+		},
+	}
+
+	return &ListToolsResult{
+		Tools: []Tool{
+			{
+				Name:        "find-meeting-time",
+				Description: &description,
+				InputSchema: ToolSchema{
+					Type: "object",
+					Properties: map[string]any{
+						"participants": map[string]any{
+							"type":        "array",
+							"description": "The people who need to attend",
+							"items": map[string]any{
+								"type": "object",
+								"properties": map[string]any{
+									"name":       map[string]any{"type": "string", "description": "Label used in the results"},
+									"timezone":   map[string]any{"type": "string", "description": "IANA timezone, e.g. Asia/Kolkata"},
+									"work_start": map[string]any{"type": "string", "description": "Start of working hours as HH:MM local time (default 09:00)"},
+									"work_end":   map[string]any{"type": "string", "description": "End of working hours as HH:MM local time, 24:00 for midnight (default 17:00)"},
+									"weekends":   map[string]any{"type": "boolean", "description": "Whether this participant can meet on Saturday and Sunday (default false)"},
+								},
+								"required": []string{"timezone"},
+							},
+						},
+						"duration_minutes": map[string]any{"type": "integer", "description": "Length of the meeting in minutes"},
+						"start_date":       map[string]any{"type": "string", "description": "First day to search, YYYY-MM-DD"},
+						"end_date":         map[string]any{"type": "string", "description": "Last day to search, YYYY-MM-DD (defaults to start_date, at most 31 days after it)"},
+						"timezone":         map[string]any{"type": "string", "description": "Timezone the dates are read in (defaults to the first participant's)"},
+						"step_minutes":     map[string]any{"type": "integer", "description": "Spacing between candidate start times (default 15)"},
+						"max_results":      map[string]any{"type": "integer", "description": "Maximum number of slots to return (default 10)"},
+					},
+					Required: []string{"participants", "duration_minutes", "start_date"},
+				},
+				OutputSchema: &ToolSchema{
+					Type: "object",
+					Properties: map[string]any{
+						"slots": map[string]any{
+							"type": "array",
+							"items": map[string]any{
+								"type": "object",
+								"properties": map[string]any{
+									"start_utc": map[string]any{"type": "string", "format": "date-time"},
+									"end_utc":   map[string]any{"type": "string", "format": "date-time"},
+									"score":     map[string]any{"type": "number", "description": "1 when the slot is centred in everyone's day, 0 when it touches someone's working hours edge"},
+									"local":     map[string]any{"type": "array", "items": localTime},
+								},
+							},
+						},
+					},
+					Required: []string{"slots"},
+				},
+			},
+		},
+	}, nil
+}
+
+// Notification that the list of roots has changed.
+//
+// This is an optional notification handler. If implemented, the plugin will be notified whenever the roots list changes on the client side. This allows plugins to react to changes in the file system roots or other root resources.
+// It takes PluginNotificationContext as input ()
+func OnRootsListChanged(input PluginNotificationContext) error {
+	// TODO: fill out your implementation here
+	return nil
+}
+
+// Read the contents of a resource by its URI.
+//
+// This function is called when the user wants to read the contents of a specific resource. The plugin should retrieve and return the resource data with appropriate MIME type information.
+// It takes ReadResourceRequest as input ()
+// And returns ReadResourceResult ()
+func ReadResource(input ReadResourceRequest) (*ReadResourceResult, error) {
+	// TODO: fill out your implementation here
+	return nil, fmt.Errorf("ReadResource not implemented.")
+}
+
+// Note: leave this in place, as the Go compiler will find the `export` function as the entrypoint.
+func main() {}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	// Embed the IANA database so results don't depend on the host having one.
+	_ "time/tzdata"
+)
+
+const (
+	defaultStepMinutes = 15
+	defaultMaxResults  = 10
+	maxRangeDays       = 31
+	maxParticipants    = 50
+)
+
+// Participant is someone who has to attend, with the hours they are willing
+// to meet in their own timezone.
+type Participant struct {
+	Name      string
+	Location  *time.Location
+	WorkStart int // minutes after local midnight
+	WorkEnd   int // minutes after local midnight, up to 24*60
+	Weekends  bool
+}
+
+// MeetingQuery describes the search. From and To are instants; every
+// candidate slot lies entirely within [From, To).
+type MeetingQuery struct {
+	Participants []Participant
+	Duration     time.Duration
+	From         time.Time
+	To           time.Time
+	Step         time.Duration
+	MaxResults   int
+}
+
+type LocalTime struct {
+	Participant string `json:"participant"`
+	Timezone    string `json:"timezone"`
+	Start       string `json:"start"`
+	End         string `json:"end"`
+	UTCOffset   string `json:"utc_offset"`
+	Weekday     string `json:"weekday"`
+}
+
+type Slot struct {
+	StartUTC string      `json:"start_utc"`
+	EndUTC   string      `json:"end_utc"`
+	Score    float64     `json:"score"`
+	Local    []LocalTime `json:"local"`
+
+	start time.Time
+}
+
+// workingWindow returns the participant's working hours on the local day
+// containing t. time.Date normalises wall clock times that fall in a DST gap,
+// so the window is always expressed as real instants.
+func (p Participant) workingWindow(t time.Time) (time.Time, time.Time) {
+	local := t.In(p.Location)
+	y, m, d := local.Date()
+	start := time.Date(y, m, d, 0, p.WorkStart, 0, 0, p.Location)
+	end := time.Date(y, m, d, 0, p.WorkEnd, 0, 0, p.Location)
+	return start, end
+}
+
+// fit reports whether [start, end) lies inside the participant's working
+// hours, and how far from the middle of them it sits: 0 is centred, 1 is
+// touching an edge.
+func (p Participant) fit(start, end time.Time) (bool, float64) {
+	local := start.In(p.Location)
+	if !p.Weekends && (local.Weekday() == time.Saturday || local.Weekday() == time.Sunday) {
+		return false, 0
+	}
+
+	windowStart, windowEnd := p.workingWindow(start)
+	if start.Before(windowStart) || end.After(windowEnd) {
+		return false, 0
+	}
+
+	slack := windowEnd.Sub(windowStart) - end.Sub(start)
+	if slack <= 0 {
+		return true, 0
+	}
+	before := start.Sub(windowStart)
+	return true, math.Abs(float64(2*before-slack)) / float64(slack)
+}
+
+// FindMeetingTimes walks the range in fixed steps and keeps every slot that
+// fits all participants. Slots are ranked by how comfortable they are for the
+// least comfortable participant, earliest first on ties.
+func FindMeetingTimes(q MeetingQuery) []Slot {
+	step := q.Step
+	if step <= 0 {
+		step = defaultStepMinutes * time.Minute
+	}
+
+	slots := []Slot{}
+	for start := q.From.Truncate(step); !start.Add(q.Duration).After(q.To); start = start.Add(step) {
+		if start.Before(q.From) {
+			continue
+		}
+		end := start.Add(q.Duration)
+
+		worst := 0.0
+		ok := true
+		for _, p := range q.Participants {
+			fits, distance := p.fit(start, end)
+			if !fits {
+				ok = false
+				break
+			}
+			worst = math.Max(worst, distance)
+		}
+		if !ok {
+			continue
+		}
+
+		slots = append(slots, Slot{
+			StartUTC: start.UTC().Format(time.RFC3339),
+			EndUTC:   end.UTC().Format(time.RFC3339),
+			Score:    math.Round((1-worst)*100) / 100,
+			Local:    localTimes(q.Participants, start, end),
+			start:    start,
+		})
+	}
+
+	sort.SliceStable(slots, func(i, j int) bool {
+		if slots[i].Score != slots[j].Score {
+			return slots[i].Score > slots[j].Score
+		}
+		return slots[i].start.Before(slots[j].start)
+	})
+
+	if q.MaxResults > 0 && len(slots) > q.MaxResults {
+		slots = slots[:q.MaxResults]
+	}
+	return slots
+}
+
+func localTimes(participants []Participant, start, end time.Time) []LocalTime {
+	local := make([]LocalTime, 0, len(participants))
+	for _, p := range participants {
+		s := start.In(p.Location)
+		local = append(local, LocalTime{
+			Participant: p.Name,
+			Timezone:    p.Location.String(),
+			Start:       s.Format(time.RFC3339),
+			End:         end.In(p.Location).Format(time.RFC3339),
+			UTCOffset:   s.Format("-07:00"),
+			Weekday:     s.Weekday().String(),
+		})
+	}
+	return local
+}
+
+// parseClock parses a HH:MM wall clock time into minutes after midnight.
+// 24:00 is accepted as the end of the day.
+func parseClock(s string) (int, error) {
+	var h, m int
+	if n, err := fmt.Sscanf(s, "%d:%d", &h, &m); err != nil || n != 2 || len(s) != 5 {
+		return 0, fmt.Errorf("%q is not a HH:MM time", s)
+	}
+	if h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("%q is not a valid time of day", s)
+	}
+	return h*60 + m, nil
+}
+
+// parseDate parses a YYYY-MM-DD date as midnight in loc.
+func parseDate(s string, loc *time.Location) (time.Time, error) {
+	t, err := time.ParseInLocation("2006-01-02", s, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a YYYY-MM-DD date", s)
+	}
+	return t, nil
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func participant(t *testing.T, name, zone, start, end string) Participant {
+	t.Helper()
+	p, err := participantFromArgs(map[string]any{"name": name, "timezone": zone, "work_start": start, "work_end": end})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func day(t *testing.T, date, zone string) (time.Time, time.Time) {
+	t.Helper()
+	from, err := parseDate(date, mustLoad(t, zone))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return from, from.AddDate(0, 0, 1)
+}
+
+func startsUTC(slots []Slot) []string {
+	starts := make([]string, 0, len(slots))
+	for _, s := range slots {
+		starts = append(starts, s.StartUTC)
+	}
+	return starts
+}
+
+func TestHalfAndQuarterHourOffsets(t *testing.T) {
+	from, to := day(t, "2024-06-03", "UTC")
+	slots := FindMeetingTimes(MeetingQuery{
+		Participants: []Participant{
+			participant(t, "kolkata", "Asia/Kolkata", "09:00", "17:00"),
+			participant(t, "kathmandu", "Asia/Kathmandu", "09:00", "17:00"),
+		},
+		Duration: 7*time.Hour + 45*time.Minute,
+		From:     from,
+		To:       to,
+		Step:     15 * time.Minute,
+	})
+
+	// Kathmandu is 15 minutes ahead of Kolkata, so only one 7h45m slot fits:
+	// 09:15-17:00 in Kathmandu, 09:00-16:45 in Kolkata.
+	if len(slots) != 1 {
+		t.Fatalf("expected exactly one slot, got %v", startsUTC(slots))
+	}
+	slot := slots[0]
+	if slot.StartUTC != "2024-06-03T03:30:00Z" || slot.EndUTC != "2024-06-03T11:15:00Z" {
+		t.Fatalf("unexpected slot %s - %s", slot.StartUTC, slot.EndUTC)
+	}
+	if slot.Local[0].Start != "2024-06-03T09:00:00+05:30" || slot.Local[0].UTCOffset != "+05:30" {
+		t.Errorf("unexpected Kolkata rendering %+v", slot.Local[0])
+	}
+	if slot.Local[1].Start != "2024-06-03T09:15:00+05:45" || slot.Local[1].End != "2024-06-03T17:00:00+05:45" {
+		t.Errorf("unexpected Kathmandu rendering %+v", slot.Local[1])
+	}
+}
+
+func TestOverlapMovesWhenOnlyOneSideChangesClocks(t *testing.T) {
+	newYork := participant(t, "ny", "America/New_York", "09:00", "17:00")
+	london := participant(t, "london", "Europe/London", "09:00", "17:00")
+
+	overlap := func(date string) (string, string) {
+		from, to := day(t, date, "UTC")
+		slots := FindMeetingTimes(MeetingQuery{
+			Participants: []Participant{newYork, london},
+			Duration:     time.Hour,
+			From:         from,
+			To:           to,
+			Step:         15 * time.Minute,
+		})
+		if len(slots) == 0 {
+			t.Fatalf("%s: no slots", date)
+		}
+		first, last := slots[0].start, slots[0].start
+		for _, s := range slots {
+			if s.start.Before(first) {
+				first = s.start
+			}
+			if s.start.After(last) {
+				last = s.start
+			}
+		}
+		return first.UTC().Format("15:04"), last.UTC().Format("15:04")
+	}
+
+	// US clocks go forward on 2024-03-10, UK clocks not until 2024-03-31.
+	if first, last := overlap("2024-03-08"); first != "14:00" || last != "16:00" {
+		t.Errorf("before the US change: got %s..%s, want 14:00..16:00", first, last)
+	}
+	if first, last := overlap("2024-03-11"); first != "13:00" || last != "16:00" {
+		t.Errorf("after the US change: got %s..%s, want 13:00..16:00", first, last)
+	}
+}
+
+func TestDSTBoundaryDayUsesRealDurations(t *testing.T) {
+	p := participant(t, "night", "America/New_York", "00:00", "06:00")
+	p.Weekends = true
+	from, to := day(t, "2024-03-10", "America/New_York")
+
+	slots := FindMeetingTimes(MeetingQuery{
+		Participants: []Participant{p},
+		Duration:     time.Hour,
+		From:         from,
+		To:           to,
+		Step:         15 * time.Minute,
+		MaxResults:   100,
+	})
+
+	// 00:00-06:00 on the spring-forward day is only five real hours (05:00Z to
+	// 10:00Z), which leaves 17 one hour starts at 15 minute steps.
+	if len(slots) != 17 {
+		t.Fatalf("expected 17 slots, got %d: %v", len(slots), startsUTC(slots))
+	}
+	for _, s := range slots {
+		if s.StartUTC < "2024-03-10T05:00:00Z" || s.EndUTC > "2024-03-10T10:00:00Z" {
+			t.Errorf("slot outside working hours: %s - %s", s.StartUTC, s.EndUTC)
+		}
+		if strings.Contains(s.Local[0].Start, "T02:") {
+			t.Errorf("slot starts at a wall clock time that doesn't exist: %s", s.Local[0].Start)
+		}
+	}
+
+	// A slot across the jump is rendered with both offsets.
+	for _, s := range slots {
+		if s.StartUTC == "2024-03-10T06:30:00Z" {
+			if s.Local[0].Start != "2024-03-10T01:30:00-05:00" || s.Local[0].End != "2024-03-10T03:30:00-04:00" {
+				t.Errorf("unexpected rendering across the jump: %+v", s.Local[0])
+			}
+			return
+		}
+	}
+	t.Error("missing the slot that spans the clock change")
+}
+
+func TestLordHoweHalfHourDSTShift(t *testing.T) {
+	// Lord Howe Island moves its clocks by 30 minutes, from +10:30 to +11:00,
+	// at 02:00 on 2024-10-06.
+	p := participant(t, "lhi", "Australia/Lord_Howe", "01:00", "04:00")
+	p.Weekends = true
+	from, to := day(t, "2024-10-06", "Australia/Lord_Howe")
+
+	slots := FindMeetingTimes(MeetingQuery{
+		Participants: []Participant{p},
+		Duration:     150 * time.Minute,
+		From:         from,
+		To:           to,
+		Step:         15 * time.Minute,
+	})
+	if len(slots) != 1 {
+		t.Fatalf("expected the 2h30m window to hold exactly one slot, got %v", startsUTC(slots))
+	}
+	if got := slots[0].Local[0]; got.Start != "2024-10-06T01:00:00+10:30" || got.End != "2024-10-06T04:00:00+11:00" {
+		t.Errorf("unexpected rendering %+v", got)
+	}
+}
+
+func TestWeekendsAndRanking(t *testing.T) {
+	p := participant(t, "utc", "UTC", "09:00", "17:00")
+	from, _ := day(t, "2024-06-01", "UTC") // Saturday
+	to := from.AddDate(0, 0, 3)
+
+	slots := FindMeetingTimes(MeetingQuery{
+		Participants: []Participant{p},
+		Duration:     2 * time.Hour,
+		From:         from,
+		To:           to,
+		Step:         time.Hour,
+		MaxResults:   3,
+	})
+	if len(slots) != 3 {
+		t.Fatalf("expected 3 slots, got %v", startsUTC(slots))
+	}
+	if slots[0].StartUTC != "2024-06-03T12:00:00Z" || slots[0].Score != 1 {
+		t.Errorf("the centred Monday slot should rank first, got %+v", slots[0])
+	}
+	for _, s := range slots {
+		if s.Local[0].Weekday != "Monday" {
+			t.Errorf("weekend slot returned: %+v", s)
+		}
+	}
+	if slots[1].Score != slots[2].Score || slots[1].StartUTC > slots[2].StartUTC {
+		t.Errorf("ties should be ordered by start time: %v", startsUTC(slots))
+	}
+}
+
+func TestQueryFromArgs(t *testing.T) {
+	q, err := queryFromArgs(map[string]any{
+		"participants": []any{
+			map[string]any{"timezone": "Asia/Tokyo"},
+			map[string]any{"name": "ana", "timezone": "Europe/Lisbon", "work_start": "08:30", "work_end": "24:00"},
+		},
+		"duration_minutes": float64(30),
+		"start_date":       "2024-06-03",
+		"end_date":         "2024-06-04",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Participants[0].Name != "participant 1" || q.Participants[0].WorkStart != 9*60 || q.Participants[0].WorkEnd != 17*60 {
+		t.Errorf("unexpected defaults %+v", q.Participants[0])
+	}
+	if q.Participants[1].WorkStart != 8*60+30 || q.Participants[1].WorkEnd != 24*60 {
+		t.Errorf("unexpected working hours %+v", q.Participants[1])
+	}
+	if got := q.From.Format(time.RFC3339); got != "2024-06-03T00:00:00+09:00" {
+		t.Errorf("dates should be read in the first participant's timezone, got %s", got)
+	}
+	if got := q.To.Sub(q.From); got != 48*time.Hour {
+		t.Errorf("end_date should be inclusive, got a %s range", got)
+	}
+
+	base := func() map[string]any {
+		return map[string]any{
+			"participants":     []any{map[string]any{"timezone": "UTC"}},
+			"duration_minutes": float64(30),
+			"start_date":       "2024-06-03",
+		}
+	}
+	failures := []struct {
+		key   string
+		value any
+		want  string
+	}{
+		{"participants", []any{}, "participants must be a non-empty array"},
+		{"participants", []any{map[string]any{"timezone": "Mars/Olympus"}}, `participants[0]: unknown timezone "Mars/Olympus"`},
+		{"participants", []any{map[string]any{"timezone": "UTC", "work_start": "18:00"}}, "participants[0]: work_end 17:00 must be after work_start 18:00"},
+		{"participants", []any{map[string]any{"timezone": "UTC", "work_end": "24:30"}}, `participants[0]: work_end: "24:30" is not a valid time of day`},
+		{"duration_minutes", float64(0), "duration_minutes must be a positive whole number"},
+		{"start_date", "03/06/2024", `start_date: "03/06/2024" is not a YYYY-MM-DD date`},
+		{"end_date", "2024-06-02", "end_date must not be before start_date"},
+		{"end_date", "2024-07-05", "at most 31 days"},
+		{"step_minutes", float64(1), "step_minutes must be a whole number between 5 and 240"},
+	}
+	for _, f := range failures {
+		args := base()
+		args[f.key] = f.value
+		if _, err := queryFromArgs(args); err == nil || !strings.Contains(err.Error(), f.want) {
+			t.Errorf("%s=%v: got error %v, want %q", f.key, f.value, err, f.want)
+		}
+	}
+}
@@ -154,6 +154,16 @@ impl Deref for PluginService {
     }
 }
 
+/// The answer to a plugin's elicitation when the client can't show forms: a
+/// decline with content `{"unsupported": true}`, so plugins can tell it apart
+/// from a user declining.
+fn elicitation_unsupported() -> CreateElicitationResult {
+    CreateElicitationResult {
+        action: ElicitationAction::Decline,
+        content: Some(serde_json::json!({ "unsupported": true })),
+    }
+}
+
 impl PluginService {
     pub async fn new(config: &Config) -> Result<Self> {
         let inner = Arc::new(PluginServiceInner {
@@ -204,10 +214,7 @@ impl PluginService {
                         }
                     } else {
                         tracing::info!("Peer does not support elicitation, declining from {}", ctx.plugin_name);
-                        Ok(Json(CreateElicitationResult {
-                            action: ElicitationAction::Decline,
-                            content: None,
-                        }))
+                        Ok(Json(elicitation_unsupported()))
                     }
                 },
                 None => Err(anyhow::anyhow!("No peer available")),
@@ -412,9 +419,11 @@ impl PluginService {
                     }
                 }
             }
-            let extism_plugin = extism::Plugin::new(
-                &manifest,
-                [
+            let extism_plugin = extism::PluginBuilder::new(&manifest)
+                .with_wasi(true)
+                // Plugins read headers such as X-OAuth-Scopes or rate limits
+                .with_http_response_headers(true)
+                .with_functions([
                     Function::new(
                         "create_elicitation",
                         [extism::PTR],
@@ -523,10 +532,9 @@ impl PluginService {
                         notify_tool_list_changed,
                     )
                     .with_namespace(EXTISM_USER_MODULE),
-                ],
-                true,
-            )
-            .unwrap();
+                ])
+                .build()
+                .unwrap();
 
             let plugin_id = extism_plugin.id;
             let plugin: Box<dyn Plugin> = if extism_plugin.function_exists("call")
@@ -1804,6 +1812,15 @@ plugins:
         assert_ok!(client.cancel().await);
     }
 
+    #[test]
+    fn test_elicitation_unsupported_is_marked_decline() {
+        let result = serde_json::to_value(elicitation_unsupported()).unwrap();
+        assert_eq!(
+            result,
+            serde_json::json!({ "action": "decline", "content": { "unsupported": true } })
+        );
+    }
+
     #[test]
     fn test_plugin_service_ping() {
         let config = Config::default();
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/extism/go-pdk"
+)
+
+// DoctorToolName is the name of the self-check tool added by EnableDoctor.
+const DoctorToolName = "doctor"
+
+const (
+	DoctorPass = "pass"
+	DoctorWarn = "warn"
+	DoctorFail = "fail"
+)
+
+// DoctorOptions describes what the doctor tool checks.
+type DoctorOptions struct {
+	// Config keys the plugin cannot work without, set through
+	// runtime_config.env_vars in the hyper-mcp configuration.
+	RequiredConfig []string
+	// A URL that should answer when outbound HTTP works, e.g. an API's
+	// health endpoint. Leave empty to skip the check.
+	ProbeURL string
+}
+
+type DoctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+type DoctorReport struct {
+	Status string        `json:"status"`
+	Checks []DoctorCheck `json:"checks"`
+}
+
+// doctorEnv is what the checks need from the host, so they can run against
+// fakes in tests.
+type doctorEnv struct {
+	getConfig func(key string) (string, bool)
+	get       func(url string) (uint16, error)
+	listTools func() ([]Tool, error)
+}
+
+var doctorOptions *DoctorOptions
+
+// EnableDoctor adds the doctor tool, which reports on the plugin's
+// configuration and environment. Call it from an init function.
+func EnableDoctor(options DoctorOptions) {
+	doctorOptions = &options
+}
+
+// DoctorTools returns the doctor tool when it is enabled. Append it to the
+// tools returned by ListTools.
+func DoctorTools() []Tool {
+	if doctorOptions == nil {
+		return nil
+	}
+	description := "Check this plugin's setup: required configuration, outbound HTTP access and tool declarations. Run it first when the plugin doesn't work."
+	return []Tool{{
+		Name:        DoctorToolName,
+		Description: &description,
+		InputSchema: ToolSchema{Type: "object"},
+	}}
+}
+
+// CallDoctor runs the doctor tool if input calls it. Check it at the top of
+// CallTool.
+func CallDoctor(input CallToolRequest) (*CallToolResult, bool) {
+	if !isDoctorCall(input) {
+		return nil, false
+	}
+	env := doctorEnv{
+		getConfig: pdk.GetConfig,
+		get: func(url string) (uint16, error) {
+			return pdk.NewHTTPRequest(pdk.MethodGet, url).Send().Status(), nil
+		},
+		listTools: func() ([]Tool, error) {
+			result, err := ListTools(ListToolsRequest{})
+			if err != nil {
+				return nil, err
+			}
+			return result.Tools, nil
+		},
+	}
+	return doctorResult(runDoctor(*doctorOptions, env)), true
+}
+
+func isDoctorCall(input CallToolRequest) bool {
+	return doctorOptions != nil && input.Request.Name == DoctorToolName
+}
+
+func checkConfig(keys []string, getConfig func(string) (string, bool)) DoctorCheck {
+	check := DoctorCheck{Name: "config", Status: DoctorPass, Detail: "no required config keys"}
+	if len(keys) == 0 {
+		return check
+	}
+	var missing []string
+	for _, key := range keys {
+		if value, ok := getConfig(key); !ok || value == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		check.Status = DoctorFail
+		check.Detail = fmt.Sprintf("missing %q; set them in runtime_config.env_vars", missing)
+		return check
+	}
+	check.Detail = fmt.Sprintf("all %d required keys are set", len(keys))
+	return check
+}
+
+// checkHTTP probes the URL. A host that isn't in allowed_hosts makes
+// hyper-mcp reject the whole call instead of returning a status, so the
+// doctor call itself failing with that error is also an answer.
+func checkHTTP(probeURL string, get func(string) (uint16, error)) DoctorCheck {
+	check := DoctorCheck{Name: "outbound_http"}
+	if probeURL == "" {
+		check.Status = DoctorPass
+		check.Detail = "no probe URL configured, skipped"
+		return check
+	}
+	status, err := get(probeURL)
+	switch {
+	case err != nil:
+		check.Status = DoctorFail
+		check.Detail = fmt.Sprintf("GET %s failed: %s; check allowed_hosts", probeURL, err)
+	case status == 0:
+		check.Status = DoctorFail
+		check.Detail = fmt.Sprintf("GET %s got no response; check allowed_hosts and network access", probeURL)
+	case status >= 500:
+		check.Status = DoctorWarn
+		check.Detail = fmt.Sprintf("GET %s reached the server but it answered %d", probeURL, status)
+	default:
+		check.Status = DoctorPass
+		check.Detail = fmt.Sprintf("GET %s answered %d", probeURL, status)
+	}
+	return check
+}
+
+func checkTools(listTools func() ([]Tool, error)) DoctorCheck {
+	check := DoctorCheck{Name: "tools"}
+	tools, err := listTools()
+	if err != nil {
+		check.Status = DoctorFail
+		check.Detail = fmt.Sprintf("ListTools failed: %s", err)
+		return check
+	}
+	if err := VerifyTools(tools); err != nil {
+		check.Status = DoctorFail
+		check.Detail = err.Error()
+		return check
+	}
+	check.Status = DoctorPass
+	check.Detail = fmt.Sprintf("%d tools declared correctly", len(tools))
+	return check
+}
+
+// runDoctor runs every check. The report's status is the worst of them.
+func runDoctor(options DoctorOptions, env doctorEnv) DoctorReport {
+	report := DoctorReport{
+		Status: DoctorPass,
+		Checks: []DoctorCheck{
+			checkConfig(options.RequiredConfig, env.getConfig),
+			checkHTTP(options.ProbeURL, env.get),
+			checkTools(env.listTools),
+		},
+	}
+	for _, check := range report.Checks {
+		if check.Status == DoctorFail || (check.Status == DoctorWarn && report.Status == DoctorPass) {
+			report.Status = check.Status
+		}
+	}
+	return report
+}
+
+func doctorResult(report DoctorReport) *CallToolResult {
+	out, _ := json.Marshal(report)
+	var structured map[string]any
+	_ = json.Unmarshal(out, &structured)
+	return &CallToolResult{
+		Content:           []ContentBlock{{Text: &TextContent{Text: string(out)}}},
+		StructuredContent: structured,
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func config(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+}
+
+func TestCheckConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		keys   []string
+		values map[string]string
+		status string
+		detail string
+	}{
+		{"nothing required", nil, nil, DoctorPass, "no required config keys"},
+		{"all set", []string{"api-key", "region"}, map[string]string{"api-key": "k", "region": "eu"}, DoctorPass, "all 2 required keys are set"},
+		{"missing and empty", []string{"api-key", "region", "org"}, map[string]string{"region": "", "org": "acme"}, DoctorFail, `missing ["api-key" "region"]`},
+	}
+	for _, tt := range tests {
+		check := checkConfig(tt.keys, config(tt.values))
+		if check.Status != tt.status || !strings.Contains(check.Detail, tt.detail) {
+			t.Errorf("%s: %+v, want %s containing %q", tt.name, check, tt.status, tt.detail)
+		}
+	}
+}
+
+func TestCheckHTTP(t *testing.T) {
+	answer := func(status uint16, err error) func(string) (uint16, error) {
+		return func(string) (uint16, error) { return status, err }
+	}
+	tests := []struct {
+		name   string
+		url    string
+		get    func(string) (uint16, error)
+		status string
+		detail string
+	}{
+		{"skipped", "", nil, DoctorPass, "skipped"},
+		{"reachable", "https://api.example.com/health", answer(200, nil), DoctorPass, "answered 200"},
+		{"client error still reaches the server", "https://api.example.com/health", answer(401, nil), DoctorPass, "answered 401"},
+		{"server error", "https://api.example.com/health", answer(503, nil), DoctorWarn, "answered 503"},
+		{"no response", "https://api.example.com/health", answer(0, nil), DoctorFail, "check allowed_hosts"},
+		{"request error", "https://api.example.com/health", answer(0, errors.New("host not allowed")), DoctorFail, "host not allowed"},
+	}
+	for _, tt := range tests {
+		check := checkHTTP(tt.url, tt.get)
+		if check.Status != tt.status || !strings.Contains(check.Detail, tt.detail) {
+			t.Errorf("%s: %+v, want %s containing %q", tt.name, check, tt.status, tt.detail)
+		}
+	}
+}
+
+func TestCheckTools(t *testing.T) {
+	object := ToolSchema{Type: "object"}
+	tests := []struct {
+		name   string
+		list   func() ([]Tool, error)
+		status string
+		detail string
+	}{
+		{"valid", func() ([]Tool, error) { return []Tool{{Name: "a", InputSchema: object}}, nil }, DoctorPass, "1 tools declared correctly"},
+		{"duplicate", func() ([]Tool, error) {
+			return []Tool{{Name: "a", InputSchema: object}, {Name: "a", InputSchema: object}}, nil
+		}, DoctorFail, "duplicate tool name"},
+		{"list fails", func() ([]Tool, error) { return nil, errors.New("boom") }, DoctorFail, "ListTools failed: boom"},
+	}
+	for _, tt := range tests {
+		check := checkTools(tt.list)
+		if check.Status != tt.status || !strings.Contains(check.Detail, tt.detail) {
+			t.Errorf("%s: %+v, want %s containing %q", tt.name, check, tt.status, tt.detail)
+		}
+	}
+}
+
+func TestRunDoctorReportsWorstStatus(t *testing.T) {
+	env := doctorEnv{
+		getConfig: config(map[string]string{"api-key": "k"}),
+		get:       func(string) (uint16, error) { return 502, nil },
+		listTools: func() ([]Tool, error) { return DoctorTools(), nil },
+	}
+	report := runDoctor(DoctorOptions{RequiredConfig: []string{"api-key"}, ProbeURL: "https://example.com"}, env)
+	if report.Status != DoctorWarn || len(report.Checks) != 3 {
+		t.Errorf("report %+v, want warn with 3 checks", report)
+	}
+
+	env.getConfig = config(nil)
+	if report := runDoctor(DoctorOptions{RequiredConfig: []string{"api-key"}}, env); report.Status != DoctorFail {
+		t.Errorf("report %+v, want fail", report)
+	}
+}
+
+func TestDoctorIsOptional(t *testing.T) {
+	defer func() { doctorOptions = nil }()
+
+	if tools := DoctorTools(); tools != nil {
+		t.Fatalf("doctor listed without EnableDoctor: %+v", tools)
+	}
+	if isDoctorCall(CallToolRequest{Request: CallToolRequestParam{Name: DoctorToolName}}) {
+		t.Fatal("doctor answered without EnableDoctor")
+	}
+
+	EnableDoctor(DoctorOptions{})
+	tools := DoctorTools()
+	if len(tools) != 1 || tools[0].Name != DoctorToolName {
+		t.Fatalf("tools %+v", tools)
+	}
+	if err := VerifyTools(tools); err != nil {
+		t.Error(err)
+	}
+	if !isDoctorCall(CallToolRequest{Request: CallToolRequestParam{Name: DoctorToolName}}) {
+		t.Error("doctor call not recognized")
+	}
+	if isDoctorCall(CallToolRequest{Request: CallToolRequestParam{Name: "other"}}) {
+		t.Error("doctor answered another tool's call")
+	}
+}
@@ -10,7 +10,20 @@ import (
 // It takes CallToolRequest as input ()
 // And returns CallToolResult ()
 func CallTool(input CallToolRequest) (*CallToolResult, error) {
-	return nil, fmt.Errorf("CallTool not implemented.")
+	// Answers the doctor tool when EnableDoctor was called
+	if result, ok := CallDoctor(input); ok {
+		return result, nil
+	}
+	if handler, ok := toolHandlers[input.Request.Name]; ok {
+		return handler(input)
+	}
+	return nil, fmt.Errorf("unknown tool %q", input.Request.Name)
+}
+
+// toolHandlers runs each tool ListTools declares, by name. Add an entry for
+// every tool you list; VerifyHandlers fails the tests when the two drift.
+var toolHandlers = map[string]ToolHandler{
+	// "search": callSearch,
 }
 
 // Provide completion suggestions for a partially-typed input.
@@ -69,7 +82,7 @@ func ListResources(input ListResourcesRequest) (*ListResourcesResult, error) {
 // And returns ListToolsResult ()
 func ListTools(input ListToolsRequest) (*ListToolsResult, error) {
 	// TODO: fill out your implementation here
-	return &ListToolsResult{}, nil
+	return &ListToolsResult{Tools: DoctorTools()}, nil
 }
 
 // Notification that the list of roots has changed.
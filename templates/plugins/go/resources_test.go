@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func marshalResult(t *testing.T, result *ReadResourceResult) string {
+	t.Helper()
+	out, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestMultiPartResultAllRead(t *testing.T) {
+	result, err := MultiPartResult("file:///logs", []ResourcePart{
+		TextPart(ChildURI("file:///logs/", "app.log"), "text/plain", "started"),
+		BlobPart(ChildURI("file:///logs", "/core.gz"), "application/gzip", []byte{0x1f, 0x8b}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"contents":[{"mimeType":"text/plain","text":"started","uri":"file:///logs/app.log"},{"blob":"H4s=","mimeType":"application/gzip","uri":"file:///logs/core.gz"}]}`
+	if got := marshalResult(t, result); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+	if _, ok := ResourceErrors(result); ok {
+		t.Error("a fully read result has an error summary")
+	}
+}
+
+func TestMultiPartResultPartialFailure(t *testing.T) {
+	result, err := MultiPartResult("file:///big.csv", []ResourcePart{
+		TextPart(ChunkURI("file:///big.csv", 0), "", "a,b"),
+		FailedPart(ChunkURI("file:///big.csv", 1), errors.New("read timed out")),
+		TextPart(ChunkURI("file:///big.csv", 2), "", "e,f"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"contents":[` +
+		`{"text":"a,b","uri":"file:///big.csv#chunk=0"},` +
+		`{"text":"e,f","uri":"file:///big.csv#chunk=2"},` +
+		`{"mimeType":"application/vnd.hyper-mcp.resource-errors+json","text":"{\"uri\":\"file:///big.csv\",\"parts\":3,\"failed\":1,\"errors\":[{\"uri\":\"file:///big.csv#chunk=1\",\"error\":\"read timed out\"}]}","uri":"file:///big.csv"}]}`
+	if got := marshalResult(t, result); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+
+	summary, ok := ResourceErrors(result)
+	if !ok || summary.Failed != 1 || summary.Parts != 3 || summary.Errors[0].URI != "file:///big.csv#chunk=1" {
+		t.Errorf("summary %+v, %v", summary, ok)
+	}
+}
+
+func TestMultiPartResultTotalFailure(t *testing.T) {
+	denied := errors.New("permission denied")
+	result, err := MultiPartResult("file:///secret", []ResourcePart{
+		FailedPart("file:///secret/a", denied),
+		FailedPart("file:///secret/b", errors.New("not found")),
+	})
+	if result != nil {
+		t.Errorf("got a result when every part failed: %+v", result)
+	}
+	if err == nil || !errors.Is(err, denied) {
+		t.Fatalf("error %v does not wrap the part errors", err)
+	}
+	for _, want := range []string{"all 2 parts failed", "file:///secret/a: permission denied", "file:///secret/b: not found"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestMultiPartResultNoParts(t *testing.T) {
+	result, err := MultiPartResult("file:///empty", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := marshalResult(t, result); got != `{"contents":[]}` {
+		t.Errorf("got %s", got)
+	}
+}
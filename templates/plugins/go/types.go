@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -108,6 +109,64 @@ type CallToolRequest struct {
 type CallToolRequestParam struct {
 	Arguments map[string]any `json:"arguments,omitempty"`
 	Name      string         `json:"name"`
+	// RawArguments is the arguments object exactly as the client sent it, for
+	// tools that need the original JSON without float64 coercion or key reordering.
+	RawArguments json.RawMessage `json:"-"`
+}
+
+func (c *CallToolRequestParam) UnmarshalJSON(data []byte) error {
+	type alias CallToolRequestParam
+	aux := struct {
+		RawArguments json.RawMessage `json:"arguments,omitempty"`
+		*alias
+	}{
+		alias: (*alias)(c),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	c.Arguments = nil
+	c.RawArguments = nil
+	if len(aux.RawArguments) > 0 && string(aux.RawArguments) != "null" {
+		if err := json.Unmarshal(aux.RawArguments, &c.Arguments); err != nil {
+			return err
+		}
+		c.RawArguments = append(json.RawMessage(nil), aux.RawArguments...)
+	}
+	return nil
+}
+
+// RawArg returns the exact JSON of a single argument. When the client sent the
+// same key more than once the last occurrence wins, matching Arguments.
+func (c CallToolRequestParam) RawArg(name string) (json.RawMessage, bool) {
+	if len(c.RawArguments) == 0 {
+		return nil, false
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(c.RawArguments))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, false
+	}
+
+	var found json.RawMessage
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, false
+		}
+		key, _ := tok.(string)
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, false
+		}
+		if key == name {
+			found = value
+		}
+	}
+	return found, found != nil
 }
 
 // CallToolResult represents the result of calling a tool
@@ -419,14 +478,14 @@ type EnumSchema struct {
 	Title       *string  `json:"title,omitempty"`
 }
 
-func (e EnumSchema) MarshallJSON() ([]byte, error) {
+func (e EnumSchema) MarshalJSON() ([]byte, error) {
 	type alias EnumSchema
 
 	return json.Marshal(&struct {
 		Type string `json:"type"`
 		alias
 	}{
-		Type:  "resource",
+		Type:  "string",
 		alias: (alias)(e),
 	})
 }
@@ -476,7 +535,7 @@ type ImageContent struct {
 	MimeType    string       `json:"mimeType"`
 }
 
-func (i ImageContent) MarshallJSON() ([]byte, error) {
+func (i ImageContent) MarshalJSON() ([]byte, error) {
 	type alias ImageContent
 
 	return json.Marshal(&struct {
@@ -949,7 +1008,7 @@ type ResourceLinkContent struct {
 	URI         string       `json:"uri"`
 }
 
-func (r ResourceLinkContent) MarshallJSON() ([]byte, error) {
+func (r ResourceLinkContent) MarshalJSON() ([]byte, error) {
 	type alias ResourceLinkContent
 	return json.Marshal(&struct {
 		Type string `json:"type"`
@@ -994,7 +1053,7 @@ type ResourceTemplateReference struct {
 	URI string `json:"uri"`
 }
 
-func (r ResourceTemplateReference) MarshallJSON() ([]byte, error) {
+func (r ResourceTemplateReference) MarshalJSON() ([]byte, error) {
 	type alias ResourceTemplateReference
 	return json.Marshal(&struct {
 		Type string `json:"type"`
@@ -1126,7 +1185,7 @@ type Schema struct {
 	Required   []string                             `json:"required,omitempty"`
 }
 
-func (s Schema) MarshallJSON() ([]byte, error) {
+func (s Schema) MarshalJSON() ([]byte, error) {
 	type alias Schema
 	return json.Marshal(&struct {
 		Type string `json:"type"`
@@ -1166,7 +1225,7 @@ type StringSchema struct {
 	Title       *string             `json:"title,omitempty"`
 }
 
-func (s StringSchema) MarshallJSON() ([]byte, error) {
+func (s StringSchema) MarshalJSON() ([]byte, error) {
 	type alias StringSchema
 	return json.Marshal(&struct {
 		Type string `json:"type"`
@@ -1238,7 +1297,7 @@ type TextContent struct {
 	Text        string       `json:"text"`
 }
 
-func (t TextContent) MarshallJSON() ([]byte, error) {
+func (t TextContent) MarshalJSON() ([]byte, error) {
 	type alias TextContent
 	return json.Marshal(&struct {
 		Type string `json:"type"`
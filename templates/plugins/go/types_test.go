@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func decodeCallToolRequest(t *testing.T, input string) CallToolRequest {
+	t.Helper()
+	var req CallToolRequest
+	if err := json.Unmarshal([]byte(input), &req); err != nil {
+		t.Fatalf("failed to decode request: %s", err)
+	}
+	return req
+}
+
+func TestCallToolRequestParamRawArguments(t *testing.T) {
+	args := `{"query":"q","variables":{"b":1,"a":[1,2,{"z":null}]},"id":12345678901234567890}`
+	req := decodeCallToolRequest(t, `{"context":{"_meta":{},"id":1},"request":{"name":"gh-graphql","arguments":`+args+`}}`)
+
+	if req.Request.Name != "gh-graphql" {
+		t.Fatalf("unexpected name %q", req.Request.Name)
+	}
+	if string(req.Request.RawArguments) != args {
+		t.Fatalf("raw arguments not preserved:\n got %s\nwant %s", req.Request.RawArguments, args)
+	}
+	if req.Request.Arguments["query"] != "q" {
+		t.Fatalf("decoded arguments missing query: %v", req.Request.Arguments)
+	}
+}
+
+func TestCallToolRequestParamRawArg(t *testing.T) {
+	args := `{"nested": {"b": 1, "a": {"deep": [1.50, "x"]}},"big":12345678901234567890, "str":"aéb"}`
+	req := decodeCallToolRequest(t, `{"context":{"_meta":{},"id":"r"},"request":{"name":"t","arguments":`+args+`}}`)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"nested", `{"b": 1, "a": {"deep": [1.50, "x"]}}`},
+		{"big", `12345678901234567890`},
+		{"str", `"aéb"`},
+	}
+	for _, tt := range tests {
+		got, ok := req.Request.RawArg(tt.name)
+		if !ok {
+			t.Fatalf("RawArg(%q) not found", tt.name)
+		}
+		if string(got) != tt.want {
+			t.Errorf("RawArg(%q) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	// The decoded map loses precision on big integers, the raw value does not
+	if f, ok := req.Request.Arguments["big"].(float64); !ok || f != 1.2345678901234567e19 {
+		t.Fatalf("unexpected decoded big value %v", req.Request.Arguments["big"])
+	}
+
+	if _, ok := req.Request.RawArg("missing"); ok {
+		t.Fatal("expected missing argument to report false")
+	}
+}
+
+func TestCallToolRequestParamRawArgDuplicateKeys(t *testing.T) {
+	req := decodeCallToolRequest(t, `{"context":{"_meta":{},"id":1},"request":{"name":"t","arguments":{"a":1,"b":true,"a":{"x":2}}}}`)
+
+	got, ok := req.Request.RawArg("a")
+	if !ok || string(got) != `{"x":2}` {
+		t.Fatalf("RawArg(a) = %s, %v; want last occurrence", got, ok)
+	}
+	if _, isMap := req.Request.Arguments["a"].(map[string]any); !isMap {
+		t.Fatalf("decoded map should also keep the last occurrence, got %v", req.Request.Arguments["a"])
+	}
+}
+
+func TestCallToolRequestParamNoArguments(t *testing.T) {
+	for _, input := range []string{
+		`{"context":{"_meta":{},"id":1},"request":{"name":"t"}}`,
+		`{"context":{"_meta":{},"id":1},"request":{"name":"t","arguments":null}}`,
+	} {
+		req := decodeCallToolRequest(t, input)
+		if req.Request.RawArguments != nil || req.Request.Arguments != nil {
+			t.Fatalf("expected no arguments for %s", input)
+		}
+		if _, ok := req.Request.RawArg("a"); ok {
+			t.Fatalf("expected RawArg to report false for %s", input)
+		}
+	}
+}
+
+func TestTaggedTypesMarshalTheirType(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{ContentBlock{Text: &TextContent{Text: "hi"}}, `{"type":"text","text":"hi"}`},
+		{ContentBlock{Image: &ImageContent{Data: "AA==", MimeType: "image/png"}}, `{"type":"image","data":"AA==","mimeType":"image/png"}`},
+		{ContentBlock{ResourceLink: &ResourceLinkContent{Name: "a", URI: "file:///a"}}, `{"type":"resource_link","name":"a","uri":"file:///a"}`},
+		{PrimitiveSchemaDefinition{Enum: &EnumSchema{Enum: []string{"a", "b"}}}, `{"type":"string","enum":["a","b"]}`},
+		{PrimitiveSchemaDefinition{String: &StringSchema{}}, `{"type":"string"}`},
+		{Schema{Required: []string{"x"}}, `{"type":"object","required":["x"]}`},
+	}
+	for _, tt := range tests {
+		got, err := json.Marshal(tt.value)
+		if err != nil {
+			t.Fatalf("marshal %T: %s", tt.value, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("marshal %T:\n got %s\nwant %s", tt.value, got, tt.want)
+		}
+	}
+}
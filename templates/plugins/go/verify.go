@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+var toolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,128}$`)
+
+// VerifyTools checks the tools a plugin declares: names must be unique and
+// match the MCP naming rules, and each input schema must be an object schema
+// whose required fields are all declared properties. Every problem found is
+// reported, not just the first.
+func VerifyTools(tools []Tool) error {
+	var errs []error
+	seen := make(map[string]bool, len(tools))
+	for i, tool := range tools {
+		label := fmt.Sprintf("tools[%d] %q", i, tool.Name)
+		if !toolNamePattern.MatchString(tool.Name) {
+			errs = append(errs, fmt.Errorf("%s: name must match %s", label, toolNamePattern))
+		}
+		if seen[tool.Name] {
+			errs = append(errs, fmt.Errorf("%s: duplicate tool name", label))
+		}
+		seen[tool.Name] = true
+
+		if err := verifyToolSchema(tool.InputSchema); err != nil {
+			errs = append(errs, fmt.Errorf("%s: inputSchema: %w", label, err))
+		}
+		if tool.OutputSchema != nil {
+			if err := verifyToolSchema(*tool.OutputSchema); err != nil {
+				errs = append(errs, fmt.Errorf("%s: outputSchema: %w", label, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func verifyToolSchema(schema ToolSchema) error {
+	if schema.Type != "object" {
+		return fmt.Errorf("type must be \"object\", got %q", schema.Type)
+	}
+	if schema.Properties == nil && len(schema.Required) > 0 {
+		return errors.New("required fields listed without properties")
+	}
+	for _, name := range schema.Required {
+		if _, ok := schema.Properties[name]; !ok {
+			return fmt.Errorf("required field %q is not a declared property", name)
+		}
+	}
+	for name, property := range schema.Properties {
+		if _, ok := property.(map[string]any); !ok {
+			return fmt.Errorf("property %q must be a schema object, got %T", name, property)
+		}
+	}
+	return nil
+}
+
+// ToolHandler runs one tool. CallTool looks handlers up by tool name in
+// toolHandlers.
+type ToolHandler func(input CallToolRequest) (*CallToolResult, error)
+
+// VerifyHandlers checks that every declared tool has a handler and every
+// handler serves a declared tool. The doctor tool is answered by CallDoctor
+// and needs no handler. Every mismatch is reported, not just the first.
+func VerifyHandlers(tools []Tool, handlers map[string]ToolHandler) error {
+	var errs []error
+	declared := make(map[string]bool, len(tools))
+	for i, tool := range tools {
+		declared[tool.Name] = true
+		if tool.Name == DoctorToolName && doctorOptions != nil {
+			continue
+		}
+		if handlers[tool.Name] == nil {
+			errs = append(errs, fmt.Errorf("tools[%d] %q: declared but has no handler", i, tool.Name))
+		}
+	}
+	var undeclared []string
+	for name := range handlers {
+		if !declared[name] {
+			undeclared = append(undeclared, name)
+		}
+	}
+	sort.Strings(undeclared)
+	for _, name := range undeclared {
+		errs = append(errs, fmt.Errorf("handler %q: no tool with this name is declared", name))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Fails as soon as ListTools declares a tool that VerifyTools rejects, or
+// the tools and toolHandlers drift apart.
+func TestListToolsVerifies(t *testing.T) {
+	result, err := ListTools(ListToolsRequest{})
+	if err != nil {
+		t.Fatalf("ListTools: %s", err)
+	}
+	if err := VerifyTools(result.Tools); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyHandlers(result.Tools, toolHandlers); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyTools(t *testing.T) {
+	valid := func(name string) Tool {
+		return Tool{
+			Name: name,
+			InputSchema: ToolSchema{
+				Type:       "object",
+				Properties: map[string]any{"q": map[string]any{"type": "string"}},
+				Required:   []string{"q"},
+			},
+		}
+	}
+	if err := VerifyTools([]Tool{valid("search"), valid("get_item-2"), {Name: "ping", InputSchema: ToolSchema{Type: "object"}}}); err != nil {
+		t.Fatalf("valid tools rejected: %s", err)
+	}
+
+	badName := valid("search tool")
+	wrongType := valid("wrong-type")
+	wrongType.InputSchema.Type = "array"
+	missingRequired := valid("missing-required")
+	missingRequired.InputSchema.Required = []string{"q", "limit"}
+	noProperties := valid("no-properties")
+	noProperties.InputSchema.Properties = nil
+	badProperty := valid("bad-property")
+	badProperty.InputSchema.Properties["limit"] = "integer"
+	badOutput := valid("bad-output")
+	badOutput.OutputSchema = &ToolSchema{}
+
+	tests := []struct {
+		tools []Tool
+		want  string
+	}{
+		{[]Tool{badName}, `tools[0] "search tool": name must match`},
+		{[]Tool{valid(strings.Repeat("a", 129))}, "name must match"},
+		{[]Tool{valid("")}, `tools[0] "": name must match`},
+		{[]Tool{valid("search"), valid("search")}, `tools[1] "search": duplicate tool name`},
+		{[]Tool{wrongType}, `inputSchema: type must be "object", got "array"`},
+		{[]Tool{missingRequired}, `inputSchema: required field "limit" is not a declared property`},
+		{[]Tool{noProperties}, "inputSchema: required fields listed without properties"},
+		{[]Tool{badProperty}, `inputSchema: property "limit" must be a schema object, got string`},
+		{[]Tool{badOutput}, `outputSchema: type must be "object", got ""`},
+	}
+	for _, tt := range tests {
+		err := VerifyTools(tt.tools)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("got %v, want an error containing %q", err, tt.want)
+		}
+	}
+
+	err := VerifyTools([]Tool{badName, wrongType})
+	if err == nil || !strings.Contains(err.Error(), "name must match") || !strings.Contains(err.Error(), "type must be") {
+		t.Errorf("expected every problem to be reported, got %v", err)
+	}
+}
+
+func TestVerifyHandlers(t *testing.T) {
+	handler := func(CallToolRequest) (*CallToolResult, error) { return &CallToolResult{}, nil }
+	tools := []Tool{{Name: "search"}, {Name: "get_item"}}
+
+	if err := VerifyHandlers(tools, map[string]ToolHandler{"search": handler, "get_item": handler}); err != nil {
+		t.Fatalf("matching handlers rejected: %s", err)
+	}
+
+	tests := []struct {
+		handlers map[string]ToolHandler
+		want     []string
+	}{
+		{map[string]ToolHandler{"search": handler}, []string{`tools[1] "get_item": declared but has no handler`}},
+		{map[string]ToolHandler{"search": handler, "get_item": nil}, []string{`tools[1] "get_item": declared but has no handler`}},
+		{map[string]ToolHandler{"search": handler, "get_item": handler, "delete": handler}, []string{`handler "delete": no tool with this name is declared`}},
+		{map[string]ToolHandler{"search": handler, "gte_item": handler}, []string{`"get_item": declared but has no handler`, `handler "gte_item": no tool`}},
+	}
+	for _, tt := range tests {
+		err := VerifyHandlers(tools, tt.handlers)
+		for _, want := range tt.want {
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("got %v, want an error containing %q", err, want)
+			}
+		}
+	}
+}